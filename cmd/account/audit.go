@@ -0,0 +1,328 @@
+package account
+
+//go:generate mockgen -source=audit.go -package=mock -destination=mock/audit.go
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	iamTypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	hiveapiv1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/osdctl/cmd/common"
+	outputflag "github.com/openshift/osdctl/cmd/getoutput"
+	"github.com/openshift/osdctl/internal/utils/globalflags"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	auditOwnerTag = "owner"
+
+	findingOrphaned         = "orphaned-account"
+	findingOutsideOU        = "account-outside-ou"
+	findingUnknownOwner     = "unknown-owner"
+	findingStaleAccessKey   = "stale-access-key"
+	findingClaimedNoCluster = "claimed-without-cluster"
+	findingUnreachable      = "unreachable-account"
+)
+
+const defaultAuditAccessKeyAge = 90
+
+// newCmdAudit implements the audit command which cross-checks the accounts of an OU with AAO and hive
+func newCmdAudit(streams genericclioptions.IOStreams, client client.Client, globalOpts *globalflags.GlobalOptions) *cobra.Command {
+	ops := newAuditOptions(streams, client, globalOpts)
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit the AWS accounts of an OU against AAO Account CRs, owner tags, IAM users and ClusterDeployments",
+		Long: `When logged into a hive shard, audits the AWS accounts under an OU, recursively, and the AAO Account CRs.
+
+It reports:
+  - ` + findingOrphaned + `: accounts under the OU without Account CR nor owner tag
+  - ` + findingOutsideOU + `: non-BYOC Account CRs whose AWS account isn't under the OU
+  - ` + findingUnknownOwner + `: accounts whose owner tag names a user who no longer exists, having no IAM user in the
+    payer account. Hive-managed accounts, whose owner tag starts with "hive", are exempt
+  - ` + findingStaleAccessKey + `: active access keys older than --access-key-age days
+  - ` + findingClaimedNoCluster + `: claimed Account CRs without ClusterDeployment in the namespace of their claim
+  - ` + findingUnreachable + `: accounts whose IAM users can't be listed
+
+The IAM users of every account under the OU are listed through its OrganizationAccountAccessRole.`,
+		Example: `  # Audit the accounts of the osd-staging-2 pool
+  osdctl account audit -p osd-staging-2 --ou ou-rs3h-ry0hn2l9
+
+  # Without the IAM checks, as json
+  osdctl account audit -p osd-staging-2 --ou ou-rs3h-ry0hn2l9 --skip-iam -o json`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete(cmd, args))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	auditCmd.Flags().StringVarP(&ops.payerAccount, "payer-account", "p", "", "AWS profile of the payer account of the OU")
+	auditCmd.Flags().StringVar(&ops.ou, "ou", "", "OU ID of the account pool, accounts of child OUs are included")
+	auditCmd.Flags().StringVar(&ops.accountNamespace, "account-namespace", common.AWSAccountNamespace,
+		"The namespace to keep AWS accounts. The default value is aws-account-operator.")
+	auditCmd.Flags().IntVar(&ops.accessKeyAge, "access-key-age", defaultAuditAccessKeyAge, "age in days above which an active access key is stale")
+	auditCmd.Flags().BoolVar(&ops.skipIAM, "skip-iam", false, "skip the owner and access key checks, which assume a role in every account under the OU")
+	_ = auditCmd.MarkFlagRequired("ou")
+
+	return auditCmd
+}
+
+// auditOptions defines the struct for running the audit command
+type auditOptions struct {
+	payerAccount     string
+	ou               string
+	accountNamespace string
+	accessKeyAge     int
+	skipIAM          bool
+	output           string
+
+	// now is the time the access keys are aged at
+	now     time.Time
+	clients AuditClients
+
+	genericclioptions.IOStreams
+	kubeCli       client.Client
+	GlobalOptions *globalflags.GlobalOptions
+}
+
+func newAuditOptions(streams genericclioptions.IOStreams, client client.Client, globalOpts *globalflags.GlobalOptions) *auditOptions {
+	return &auditOptions{
+		clients:       awsAuditClients{},
+		IOStreams:     streams,
+		kubeCli:       client,
+		GlobalOptions: globalOpts,
+	}
+}
+
+// AuditClients creates the AWS clients of the audit
+type AuditClients interface {
+	// PayerClient returns the client of the payer account of the OU
+	PayerClient(profile string) (awsprovider.Client, error)
+	// AccountClient returns the client of an account under the OU, through its OrganizationAccountAccessRole
+	AccountClient(payerClient awsprovider.Client, accountID string) (awsprovider.Client, error)
+}
+
+type awsAuditClients struct{}
+
+func (awsAuditClients) PayerClient(profile string) (awsprovider.Client, error) {
+	return awsprovider.NewAwsClient(profile, "us-east-1", "")
+}
+
+func (awsAuditClients) AccountClient(payerClient awsprovider.Client, accountID string) (awsprovider.Client, error) {
+	return osdCloud.GenerateOrganizationAccountAWSClient(payerClient, accountID, "us-east-1")
+}
+
+func (o *auditOptions) complete(cmd *cobra.Command, _ []string) error {
+	if o.ou == "" {
+		return cmdutil.UsageErrorf(cmd, "OU ID must be provided")
+	}
+	if o.accessKeyAge < 1 {
+		return cmdutil.UsageErrorf(cmd, "--access-key-age must be at least 1 day")
+	}
+
+	o.output = o.GlobalOptions.Output
+	o.now = time.Now()
+	return nil
+}
+
+type auditFinding struct {
+	Kind      string `json:"kind" yaml:"kind"`
+	AccountID string `json:"accountId" yaml:"accountId"`
+	AccountCR string `json:"accountCR,omitempty" yaml:"accountCR,omitempty"`
+	Owner     string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Detail    string `json:"detail" yaml:"detail"`
+}
+
+type auditResponse struct {
+	OU       string         `json:"ou" yaml:"ou"`
+	Accounts int            `json:"accounts" yaml:"accounts"`
+	Findings []auditFinding `json:"findings" yaml:"findings"`
+}
+
+func (f auditResponse) String() string {
+	if len(f.Findings) == 0 {
+		return fmt.Sprintf("No findings for the %d accounts under %s", f.Accounts, f.OU)
+	}
+
+	var b strings.Builder
+	counts := map[string]int{}
+	p := printer.NewTablePrinter(&b, 20, 1, 3, ' ')
+	p.AddRow([]string{"KIND", "AWS ACCOUNT ID", "ACCOUNT CR", "OWNER", "DETAIL"})
+	for _, finding := range f.Findings {
+		counts[finding.Kind]++
+		p.AddRow([]string{finding.Kind, finding.AccountID, orDash(finding.AccountCR), orDash(finding.Owner), finding.Detail})
+	}
+	_ = p.Flush()
+
+	var kinds []string
+	for kind, count := range counts {
+		kinds = append(kinds, fmt.Sprintf("%d %s", count, kind))
+	}
+	slices.Sort(kinds)
+	fmt.Fprintf(&b, "%d finding(s) for the %d accounts under %s: %s", len(f.Findings), f.Accounts, f.OU, strings.Join(kinds, ", "))
+	return b.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func (o *auditOptions) run() error {
+	ctx := context.TODO()
+
+	payerClient, err := o.clients.PayerClient(o.payerAccount)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.audit(ctx, payerClient)
+	if err != nil {
+		return err
+	}
+	return outputflag.PrintResponse(o.output, resp)
+}
+
+// audit cross-checks the accounts of the OU, their owner tags and IAM users with the Account CRs and ClusterDeployments
+func (o *auditOptions) audit(ctx context.Context, payerClient awsprovider.Client) (*auditResponse, error) {
+	ouAccounts, err := awsprovider.ListAccountsUnderOU(payerClient, o.ou)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the accounts under %s: %w", o.ou, err)
+	}
+
+	var accounts awsv1alpha1.AccountList
+	if err := o.kubeCli.List(ctx, &accounts, &client.ListOptions{Namespace: o.accountNamespace}); err != nil {
+		return nil, err
+	}
+	var clusterDeployments hiveapiv1.ClusterDeploymentList
+	if err := o.kubeCli.List(ctx, &clusterDeployments); err != nil {
+		return nil, err
+	}
+	clusterNamespaces := map[string]bool{}
+	for _, cd := range clusterDeployments.Items {
+		clusterNamespaces[cd.Namespace] = true
+	}
+
+	resp := &auditResponse{OU: o.ou, Accounts: len(ouAccounts), Findings: []auditFinding{}}
+
+	accountCRs := map[string]string{}
+	for _, account := range accounts.Items {
+		awsAccountID := account.Spec.AwsAccountID
+		if awsAccountID != "" {
+			accountCRs[awsAccountID] = account.Name
+		}
+		if awsAccountID != "" && !account.IsBYOC() && !slices.Contains(ouAccounts, awsAccountID) {
+			resp.Findings = append(resp.Findings, auditFinding{Kind: findingOutsideOU, AccountID: awsAccountID, AccountCR: account.Name,
+				Detail: fmt.Sprintf("not under %s", o.ou)})
+		}
+		if account.Status.Claimed {
+			switch {
+			case account.Spec.ClaimLinkNamespace == "":
+				resp.Findings = append(resp.Findings, auditFinding{Kind: findingClaimedNoCluster, AccountID: awsAccountID, AccountCR: account.Name,
+					Detail: "claimed without claim link"})
+			case !clusterNamespaces[account.Spec.ClaimLinkNamespace]:
+				resp.Findings = append(resp.Findings, auditFinding{Kind: findingClaimedNoCluster, AccountID: awsAccountID, AccountCR: account.Name,
+					Detail: fmt.Sprintf("no ClusterDeployment in namespace %s", account.Spec.ClaimLinkNamespace)})
+			}
+		}
+	}
+
+	// Owners own several accounts, whether they exist is only checked once
+	ownerExists := map[string]bool{}
+	for _, accountID := range ouAccounts {
+		tags, err := awsprovider.ListAccountTags(payerClient, accountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the tags of account %s: %w", accountID, err)
+		}
+		owner := ownerTag(tags)
+		if _, ok := accountCRs[accountID]; !ok && owner == "" {
+			resp.Findings = append(resp.Findings, auditFinding{Kind: findingOrphaned, AccountID: accountID,
+				Detail: "no Account CR and no owner tag"})
+		}
+		if o.skipIAM {
+			continue
+		}
+
+		// The owner of hive-managed accounts is the hive shard, not a user
+		if owner != "" && !strings.HasPrefix(owner, "hive") {
+			exists, ok := ownerExists[owner]
+			if !ok {
+				if exists, err = awsprovider.CheckIAMUserExists(payerClient, &owner); err != nil {
+					return nil, fmt.Errorf("failed to check the IAM user of owner %s: %w", owner, err)
+				}
+				ownerExists[owner] = exists
+			}
+			if !exists {
+				resp.Findings = append(resp.Findings, auditFinding{Kind: findingUnknownOwner, AccountID: accountID, AccountCR: accountCRs[accountID], Owner: owner,
+					Detail: fmt.Sprintf("no IAM user %s in the payer account", owner)})
+			}
+		}
+		resp.Findings = append(resp.Findings, o.auditAccessKeys(payerClient, accountID, accountCRs[accountID], owner)...)
+	}
+
+	slices.SortStableFunc(resp.Findings, func(a, b auditFinding) int {
+		if c := strings.Compare(a.Kind, b.Kind); c != 0 {
+			return c
+		}
+		return strings.Compare(a.AccountID, b.AccountID)
+	})
+	return resp, nil
+}
+
+// auditAccessKeys checks that no access key of the account is stale
+func (o *auditOptions) auditAccessKeys(payerClient awsprovider.Client, accountID string, accountCR string, owner string) []auditFinding {
+	unreachable := func(err error) []auditFinding {
+		return []auditFinding{{Kind: findingUnreachable, AccountID: accountID, AccountCR: accountCR, Owner: owner, Detail: err.Error()}}
+	}
+
+	accountClient, err := o.clients.AccountClient(payerClient, accountID)
+	if err != nil {
+		return unreachable(err)
+	}
+	users, err := awsprovider.ListIAMUsers(accountClient)
+	if err != nil {
+		return unreachable(err)
+	}
+
+	var findings []auditFinding
+	staleBefore := o.now.AddDate(0, 0, -o.accessKeyAge)
+	for _, user := range users {
+		keys, err := awsprovider.ListIAMUserAccessKeys(accountClient, *user.UserName)
+		if err != nil {
+			return append(findings, unreachable(err)...)
+		}
+		for _, key := range keys {
+			if key.Status != iamTypes.StatusTypeActive || key.CreateDate == nil || !key.CreateDate.Before(staleBefore) {
+				continue
+			}
+			findings = append(findings, auditFinding{Kind: findingStaleAccessKey, AccountID: accountID, AccountCR: accountCR, Owner: owner,
+				Detail: fmt.Sprintf("access key %s of %s created %s", *key.AccessKeyId, *user.UserName, key.CreateDate.Format(time.DateOnly))})
+		}
+	}
+	return findings
+}
+
+// ownerTag returns the value of the owner tag, empty without owner tag
+func ownerTag(tags []organizationTypes.Tag) string {
+	for _, tag := range tags {
+		if tag.Key != nil && *tag.Key == auditOwnerTag && tag.Value != nil {
+			return *tag.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,195 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamTypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	hiveapiv1 "github.com/openshift/hive/apis/hive/v1"
+	accountMock "github.com/openshift/osdctl/cmd/account/mock"
+	"github.com/openshift/osdctl/internal/utils/globalflags"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var auditNow = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func auditAccount(name string, awsAccountID string, claimNamespace string) *awsv1alpha1.Account {
+	return &awsv1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "aws-account-operator"},
+		Spec:       awsv1alpha1.AccountSpec{AwsAccountID: awsAccountID, ClaimLinkNamespace: claimNamespace},
+		Status:     awsv1alpha1.AccountStatus{Claimed: claimNamespace != ""},
+	}
+}
+
+// mockPool mocks an OU with a child OU, the owner tags of their accounts and the IAM users of the payer account
+func mockPool(payerClient *mock.MockClient, owners map[string]string, users ...string) {
+	payerClient.EXPECT().ListAccountsForParent(gomock.Any()).DoAndReturn(func(input *organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error) {
+		accounts := map[string][]organizationTypes.Account{
+			"ou-pool":  {{Id: awsSdk.String("111111111111")}, {Id: awsSdk.String("222222222222")}},
+			"ou-child": {{Id: awsSdk.String("333333333333")}, {Id: awsSdk.String("444444444444")}},
+		}
+		return &organizations.ListAccountsForParentOutput{Accounts: accounts[*input.ParentId]}, nil
+	}).AnyTimes()
+	payerClient.EXPECT().ListOrganizationalUnitsForParent(gomock.Any()).DoAndReturn(func(input *organizations.ListOrganizationalUnitsForParentInput) (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+		if *input.ParentId == "ou-pool" {
+			return &organizations.ListOrganizationalUnitsForParentOutput{OrganizationalUnits: []organizationTypes.OrganizationalUnit{{Id: awsSdk.String("ou-child")}}}, nil
+		}
+		return &organizations.ListOrganizationalUnitsForParentOutput{}, nil
+	}).AnyTimes()
+	payerClient.EXPECT().ListTagsForResource(gomock.Any()).DoAndReturn(func(input *organizations.ListTagsForResourceInput) (*organizations.ListTagsForResourceOutput, error) {
+		output := &organizations.ListTagsForResourceOutput{Tags: []organizationTypes.Tag{{Key: awsSdk.String("team"), Value: awsSdk.String("sre")}}}
+		if owner, ok := owners[*input.ResourceId]; ok {
+			output.Tags = append(output.Tags, organizationTypes.Tag{Key: awsSdk.String("owner"), Value: awsSdk.String(owner)})
+		}
+		return output, nil
+	}).AnyTimes()
+	payerClient.EXPECT().GetUser(gomock.Any()).DoAndReturn(func(input *iam.GetUserInput) (*iam.GetUserOutput, error) {
+		if !slices.Contains(users, *input.UserName) {
+			return nil, &iamTypes.NoSuchEntityException{}
+		}
+		return &iam.GetUserOutput{User: &iamTypes.User{UserName: input.UserName}}, nil
+	}).AnyTimes()
+}
+
+// newAccountClient mocks the client of an account with IAM users and their access keys
+func newAccountClient(ctrl *gomock.Controller, keys map[string][]iamTypes.AccessKeyMetadata) *mock.MockClient {
+	accountClient := mock.NewMockClient(ctrl)
+	var users []iamTypes.User
+	for user := range keys {
+		users = append(users, iamTypes.User{UserName: awsSdk.String(user)})
+	}
+	accountClient.EXPECT().ListUsers(gomock.Any()).Return(&iam.ListUsersOutput{Users: users}, nil).AnyTimes()
+	accountClient.EXPECT().ListAccessKeys(gomock.Any()).DoAndReturn(func(input *iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error) {
+		return &iam.ListAccessKeysOutput{AccessKeyMetadata: keys[*input.UserName]}, nil
+	}).AnyTimes()
+	return accountClient
+}
+
+// newTestAuditOptions returns audit options reaching the accounts of accountClients, other accounts are denied
+func newTestAuditOptions(t *testing.T, accountClients map[string]awsprovider.Client) *auditOptions {
+	scheme := runtime.NewScheme()
+	require.NoError(t, awsv1alpha1.AddToScheme(scheme))
+	require.NoError(t, hiveapiv1.AddToScheme(scheme))
+	kubeCli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		auditAccount("osd-creds-mgmt-aaa", "111111111111", "uhc-production-abc"),
+		auditAccount("osd-creds-mgmt-bbb", "555555555555", "uhc-production-gone"),
+		&hiveapiv1.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "uhc-production-abc"}},
+	).Build()
+
+	clients := accountMock.NewMockAuditClients(gomock.NewController(t))
+	clients.EXPECT().AccountClient(gomock.Any(), gomock.Any()).DoAndReturn(func(_ awsprovider.Client, accountID string) (awsprovider.Client, error) {
+		accountClient, ok := accountClients[accountID]
+		if !ok {
+			return nil, errors.New("AccessDenied")
+		}
+		return accountClient, nil
+	}).AnyTimes()
+
+	o := newAuditOptions(genericclioptions.IOStreams{}, kubeCli, &globalflags.GlobalOptions{})
+	o.ou = "ou-pool"
+	o.accountNamespace = "aws-account-operator"
+	o.accessKeyAge = 90
+	o.now = auditNow
+	o.clients = clients
+	return o
+}
+
+func staleKey(id string) iamTypes.AccessKeyMetadata {
+	return iamTypes.AccessKeyMetadata{AccessKeyId: awsSdk.String(id), Status: iamTypes.StatusTypeActive, CreateDate: awsSdk.Time(auditNow.AddDate(0, 0, -200))}
+}
+
+func TestAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	payerClient := mock.NewMockClient(ctrl)
+	mockPool(payerClient, map[string]string{"333333333333": "alice", "444444444444": "bob"}, "alice")
+
+	o := newTestAuditOptions(t, map[string]awsprovider.Client{
+		"111111111111": newAccountClient(ctrl, nil),
+		// Keys are checked in orphaned accounts too
+		"222222222222": newAccountClient(ctrl, map[string][]iamTypes.AccessKeyMetadata{"leftover": {staleKey("AKIALEFT")}}),
+		"333333333333": newAccountClient(ctrl, map[string][]iamTypes.AccessKeyMetadata{"alice": {
+			staleKey("AKIAOLD"),
+			{AccessKeyId: awsSdk.String("AKIAINACTIVE"), Status: iamTypes.StatusTypeInactive, CreateDate: awsSdk.Time(auditNow.AddDate(0, 0, -200))},
+			{AccessKeyId: awsSdk.String("AKIANEW"), Status: iamTypes.StatusTypeActive, CreateDate: awsSdk.Time(auditNow.AddDate(0, 0, -10))},
+		}}),
+	})
+	resp, err := o.audit(context.Background(), payerClient)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, resp.Accounts)
+	assert.Equal(t, []auditFinding{
+		{Kind: findingOutsideOU, AccountID: "555555555555", AccountCR: "osd-creds-mgmt-bbb", Detail: "not under ou-pool"},
+		{Kind: findingClaimedNoCluster, AccountID: "555555555555", AccountCR: "osd-creds-mgmt-bbb", Detail: "no ClusterDeployment in namespace uhc-production-gone"},
+		{Kind: findingOrphaned, AccountID: "222222222222", Detail: "no Account CR and no owner tag"},
+		{Kind: findingStaleAccessKey, AccountID: "222222222222", Detail: "access key AKIALEFT of leftover created 2025-11-13"},
+		{Kind: findingStaleAccessKey, AccountID: "333333333333", Owner: "alice", Detail: "access key AKIAOLD of alice created 2025-11-13"},
+		{Kind: findingUnknownOwner, AccountID: "444444444444", Owner: "bob", Detail: "no IAM user bob in the payer account"},
+		{Kind: findingUnreachable, AccountID: "444444444444", Owner: "bob", Detail: "AccessDenied"},
+	}, resp.Findings)
+
+	assert.Contains(t, resp.String(), "7 finding(s) for the 4 accounts under ou-pool: 1 account-outside-ou, 1 claimed-without-cluster, 1 orphaned-account, 1 unknown-owner, 1 unreachable-account, 2 stale-access-key")
+}
+
+func TestAuditUnknownOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	payerClient := mock.NewMockClient(ctrl)
+	mockPool(payerClient, map[string]string{"111111111111": "dave", "222222222222": "carol"}, "dave")
+
+	// Owners are users of the payer account, whether or not they have an IAM user in their account
+	accountClients := map[string]awsprovider.Client{}
+	for _, accountID := range []string{"111111111111", "222222222222", "333333333333", "444444444444"} {
+		accountClients[accountID] = newAccountClient(ctrl, nil)
+	}
+	o := newTestAuditOptions(t, accountClients)
+	resp, err := o.audit(context.Background(), payerClient)
+	require.NoError(t, err)
+	assert.Equal(t, []auditFinding{
+		{Kind: findingOutsideOU, AccountID: "555555555555", AccountCR: "osd-creds-mgmt-bbb", Detail: "not under ou-pool"},
+		{Kind: findingClaimedNoCluster, AccountID: "555555555555", AccountCR: "osd-creds-mgmt-bbb", Detail: "no ClusterDeployment in namespace uhc-production-gone"},
+		{Kind: findingOrphaned, AccountID: "333333333333", Detail: "no Account CR and no owner tag"},
+		{Kind: findingOrphaned, AccountID: "444444444444", Detail: "no Account CR and no owner tag"},
+		{Kind: findingUnknownOwner, AccountID: "222222222222", Owner: "carol", Detail: "no IAM user carol in the payer account"},
+	}, resp.Findings)
+
+	// Without the IAM checks, neither the owners nor the accounts are looked up
+	o.skipIAM = true
+	o.clients = accountMock.NewMockAuditClients(ctrl)
+	resp, err = o.audit(context.Background(), payerClient)
+	require.NoError(t, err)
+	for _, finding := range resp.Findings {
+		assert.NotEqual(t, findingUnknownOwner, finding.Kind)
+	}
+}
+
+func TestAuditHiveOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	payerClient := mock.NewMockClient(ctrl)
+	mockPool(payerClient, map[string]string{"222222222222": "hivep01ue1"})
+
+	// Hive-managed accounts have no owner user, but their keys are still checked
+	o := newTestAuditOptions(t, map[string]awsprovider.Client{
+		"222222222222": newAccountClient(ctrl, map[string][]iamTypes.AccessKeyMetadata{"osdManagedAdmin": {staleKey("AKIAOLD")}}),
+	})
+	resp, err := o.audit(context.Background(), payerClient)
+	require.NoError(t, err)
+	for _, finding := range resp.Findings {
+		assert.NotEqual(t, findingUnknownOwner, finding.Kind)
+	}
+	assert.Contains(t, resp.Findings, auditFinding{Kind: findingStaleAccessKey, AccountID: "222222222222", Owner: "hivep01ue1", Detail: "access key AKIAOLD of osdManagedAdmin created 2025-11-13"})
+}
@@ -93,38 +93,12 @@ func (o *iamOptions) run() error {
 		return fmt.Errorf("could not build AWS Client: %s", err)
 	}
 
-	// Get the right partition for the final ARN
-	partition, err := awsprovider.GetAwsPartition(awsClient)
-	if err != nil {
-		return fmt.Errorf("could not get AWS partition: %s", err)
-	}
-
-	// Generate a session name using the SRE's kerberos ID
-	sessionName, err := osdCloud.GenerateRoleSessionName(awsClient)
-	if err != nil {
-		return fmt.Errorf("could not generate Session Name: %s", err)
-	}
-
-	// Use OrganizationAccountAccessRole
-	assumedRoleCreds, err := osdCloud.GenerateOrganizationAccountAccessCredentials(awsClient, o.awsAccountID, sessionName, partition)
+	// Use OrganizationAccountAccessRole to create an impersonated AWS client
+	impersonateAwsClient, err := osdCloud.GenerateOrganizationAccountAWSClient(awsClient, o.awsAccountID, o.awsRegion)
 	if err != nil {
 		return fmt.Errorf("could not build AWS Client for OrganizationAccountAccessRole: %s", err)
 	}
 
-	// Variable with credential to be used by the impersonate aws client
-	impersonateAwsCredentials := awsprovider.ClientInput{
-		AccessKeyID:     *assumedRoleCreds.AccessKeyId,
-		SecretAccessKey: *assumedRoleCreds.SecretAccessKey,
-		SessionToken:    *assumedRoleCreds.SessionToken,
-		Region:          o.awsRegion,
-	}
-
-	// Create a new impersonated AWS client
-	impersonateAwsClient, err := awsprovider.NewAwsClientWithInput(&impersonateAwsCredentials)
-	if err != nil {
-		return fmt.Errorf("could create impersonated AWS Client: %s", err)
-	}
-
 	// Check if IAM user already exists
 	iamUserExist, err := awsprovider.CheckIAMUserExists(impersonateAwsClient, &o.kerberosUser)
 	if !iamUserExist {
@@ -148,19 +148,16 @@ var ErrNoTagsOnAccount = fmt.Errorf("No tags on aws account")
 
 func (o *accountListOptions) listUserName(accountIdInput string) (string, error) {
 
-	input := &organizations.ListTagsForResourceInput{
-		ResourceId: &accountIdInput,
-	}
-	val, err := o.awsClient.ListTagsForResource(input)
+	tags, err := awsprovider.ListAccountTags(o.awsClient, accountIdInput)
 	if err != nil {
 		return "", err
 	}
 
-	if len(val.Tags) == 0 {
+	if len(tags) == 0 {
 		return "", ErrNoTagsOnAccount
 	}
 
-	for _, t := range val.Tags {
+	for _, t := range tags {
 		if *t.Key == "owner" {
 			return *t.Value, nil
 		}
@@ -235,16 +235,14 @@ func (o *accountUnassignOptions) assumeRoleForAccount(accountId string) (awsprov
 
 func listUsersFromAccount(newAWSClient awsprovider.Client) ([]string, error) {
 
-	listInput := &iam.ListUsersInput{}
-
-	users, err := newAWSClient.ListUsers(listInput)
+	users, err := awsprovider.ListIAMUsers(newAWSClient)
 	if err != nil {
 		return []string{}, err
 	}
 
 	var userList []string
 
-	for _, u := range users.Users {
+	for _, u := range users {
 		userList = append(userList, *u.UserName)
 	}
 
@@ -256,19 +254,16 @@ var ErrAccountPartiallyTagged = fmt.Errorf("account is only partially tagged")
 
 func (o *accountUnassignOptions) checkForHiveNameTag(id string) (string, error) {
 
-	inputListTags := &organizations.ListTagsForResourceInput{
-		ResourceId: &id,
-	}
-	tags, err := o.awsClient.ListTagsForResource(inputListTags)
+	tags, err := awsprovider.ListAccountTags(o.awsClient, id)
 	if err != nil {
 		return "", err
 	}
 
-	if len(tags.Tags) == 0 {
+	if len(tags) == 0 {
 		return "", ErrNoTagsOnAccount
 	}
 
-	for _, t := range tags.Tags {
+	for _, t := range tags {
 		if *t.Key == "owner" && strings.HasPrefix(*t.Value, "hive") {
 			return "", ErrHiveNameProvided
 		}
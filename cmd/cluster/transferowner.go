@@ -50,9 +50,15 @@ type transferOwnerOptions struct {
 	newOwnerName string
 	reason       string
 	dryrun       bool
+	plan         bool
+	resume       bool
+	rollback     bool
 	hypershift   bool
 	cluster      *cmv1.Cluster
 
+	// checkpointPath overrides where the progress of the transfer is saved
+	checkpointPath string
+
 	genericclioptions.IOStreams
 	GlobalOptions *globalflags.GlobalOptions
 }
@@ -60,8 +66,23 @@ type transferOwnerOptions struct {
 func newCmdTransferOwner(streams genericclioptions.IOStreams, globalOpts *globalflags.GlobalOptions) *cobra.Command {
 	ops := newTransferOwnerOptions(streams, globalOpts)
 	transferOwnerCmd := &cobra.Command{
-		Use:               "transfer-owner",
-		Short:             "Transfer cluster ownership to a new user (to be done by Region Lead)",
+		Use:   "transfer-owner",
+		Short: "Transfer cluster ownership to a new user (to be done by Region Lead)",
+		Long: `Transfer cluster ownership to a new user (to be done by Region Lead).
+
+The transfer runs as a sequence of steps: updating the pull secret, rolling out pods, patching the subscription,
+replacing the role binding, re-registering the cluster and sending service logs. Its progress is saved to a
+checkpoint file after every step, so a failed transfer can be continued with --resume once the issue is fixed,
+or undone with --rollback, which runs the compensating action of every completed step in reverse order.`,
+		Example: `
+  # Print every change the transfer would make
+  osdctl cluster transfer-owner -C $CLUSTER_ID --old-owner old-user --new-owner new-user --reason OHSS-1234 --plan
+
+  # Continue a transfer which failed midway
+  osdctl cluster transfer-owner -C $CLUSTER_ID --old-owner old-user --new-owner new-user --reason OHSS-1234 --resume
+
+  # Undo the completed steps of a failed transfer
+  osdctl cluster transfer-owner -C $CLUSTER_ID --old-owner old-user --new-owner new-user --reason OHSS-1234 --rollback`,
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -72,13 +93,18 @@ func newCmdTransferOwner(streams genericclioptions.IOStreams, globalOpts *global
 	transferOwnerCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "The Internal Cluster ID/External Cluster ID/ Cluster Name")
 	transferOwnerCmd.Flags().StringVar(&ops.oldOwnerName, "old-owner", ops.oldOwnerName, "The old owner's username to transfer the cluster from")
 	transferOwnerCmd.Flags().StringVar(&ops.newOwnerName, "new-owner", ops.newOwnerName, "The new owner's username to transfer the cluster to")
-	transferOwnerCmd.Flags().BoolVarP(&ops.dryrun, "dry-run", "d", false, "Dry-run - show all changes but do not apply them")
+	transferOwnerCmd.Flags().BoolVarP(&ops.dryrun, "dry-run", "d", false, "Dry-run - show all changes but do not apply them, same as --plan")
+	transferOwnerCmd.Flags().BoolVar(&ops.plan, "plan", false, "Print every change the transfer would make without applying them")
+	transferOwnerCmd.Flags().BoolVar(&ops.resume, "resume", false, "Continue a previous transfer of the cluster from its checkpoint, skipping completed steps")
+	transferOwnerCmd.Flags().BoolVar(&ops.rollback, "rollback", false, "Undo the completed steps of a previous transfer of the cluster")
+	transferOwnerCmd.Flags().StringVar(&ops.checkpointPath, "checkpoint-file", "", "File saving the progress of the transfer (default: <user config dir>/osdctl-transfer-owner/<cluster-id>.json)")
 	transferOwnerCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for this command, which requires elevation, to be run (usualy an OHSS or PD ticket)")
 
 	_ = transferOwnerCmd.MarkFlagRequired("cluster-id")
 	_ = transferOwnerCmd.MarkFlagRequired("old-owner")
 	_ = transferOwnerCmd.MarkFlagRequired("new-owner")
 	_ = transferOwnerCmd.MarkFlagRequired("reason")
+	transferOwnerCmd.MarkFlagsMutuallyExclusive("resume", "rollback")
 
 	return transferOwnerCmd
 }
@@ -455,37 +481,209 @@ func (o *transferOwnerOptions) run() error {
 		}
 	}()
 
+	transfer, err := o.gatherTransfer(ocm)
+	if err != nil {
+		return err
+	}
+
+	pipeline, err := o.newPipeline(transfer)
+	if err != nil {
+		return err
+	}
+
+	// Confirm if the ownership transfer looks correct
+	fmt.Printf("Transfer cluster: \t\t'%v' (%v)\n", transfer.externalClusterID, transfer.clusterName)
+	fmt.Printf("from user \t\t\t'%v' ('%v') to '%v ('%v')'\n", transfer.oldOwnerAccountID, transfer.oldOwnerUsername, transfer.newOwnerAccountID, transfer.newOwnerUsername)
+	if transfer.orgChanged {
+		fmt.Printf("with organization change from \t'%v' to '%v'\n", transfer.oldOrganizationID, transfer.newOrganizationID)
+	}
+
+	if o.plan || o.dryrun {
+		pipeline.plan()
+		return nil
+	}
+
+	if o.rollback {
+		fmt.Printf("Roll back the completed steps of the transfer: %s\n", strings.Join(pipeline.checkpoint.Completed, ", "))
+		if !utils.ConfirmPrompt() {
+			return nil
+		}
+		return pipeline.rollback()
+	}
+
+	if !utils.ConfirmPrompt() {
+		return nil
+	}
+
+	if !o.resume {
+		ok := validateOldOwner(transfer.oldOrganizationID, transfer.subscription, transfer.oldOwnerAccount)
+		if !ok {
+			fmt.Print("can't validate this is old owners cluster, this could be because of a previously failed run\n")
+			if !utils.ConfirmPrompt() {
+				return nil
+			}
+		}
+	}
+
+	if err := pipeline.run(); err != nil {
+		return err
+	}
+	fmt.Print("Transfer complete\n")
+	return nil
+}
+
+// newPipeline returns the pipeline of the transfer, with a new checkpoint or the checkpoint of
+// a previous transfer for --resume and --rollback
+func (o *transferOwnerOptions) newPipeline(transfer *ownerTransfer) (*transferPipeline, error) {
+	checkpointPath := o.checkpointPath
+	if checkpointPath == "" {
+		var err error
+		checkpointPath, err = defaultTransferCheckpointPath(transfer.clusterID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	previous, err := loadTransferCheckpoint(checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := o.selectCheckpoint(previous, transfer, checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The steps describe the subscription as it was before the transfer, which is the checkpoint
+	// of the first run on --resume
+	transfer.checkpoint = checkpoint
+	return &transferPipeline{
+		steps:          transfer.steps(),
+		checkpoint:     checkpoint,
+		checkpointPath: checkpointPath,
+		out:            o.Out,
+		now:            time.Now,
+	}, nil
+}
+
+// selectCheckpoint validates the previous checkpoint of the cluster against the flags, returning
+// the checkpoint to continue or a new one
+func (o *transferOwnerOptions) selectCheckpoint(previous *transferCheckpoint, transfer *ownerTransfer, checkpointPath string) (*transferCheckpoint, error) {
+	if o.resume || o.rollback {
+		if previous == nil {
+			return nil, fmt.Errorf("no transfer checkpoint found at %s", checkpointPath)
+		}
+		if previous.OldOwner != o.oldOwnerName || previous.NewOwner != o.newOwnerName {
+			return nil, fmt.Errorf("the checkpoint at %s is a transfer from %s to %s, not from %s to %s", checkpointPath, previous.OldOwner, previous.NewOwner, o.oldOwnerName, o.newOwnerName)
+		}
+		if o.resume && previous.Finished {
+			return nil, fmt.Errorf("the transfer recorded in %s already finished", checkpointPath)
+		}
+		return previous, nil
+	}
+
+	if previous != nil && previous.inProgress() {
+		if o.plan || o.dryrun {
+			return previous, nil
+		}
+		msg := fmt.Sprintf("a previous transfer of this cluster from %s to %s did not finish, completed steps: %s", previous.OldOwner, previous.NewOwner, strings.Join(previous.Completed, ", "))
+		if previous.FailedStep != "" {
+			msg += fmt.Sprintf(", failed step %s: %s", previous.FailedStep, previous.Error)
+		}
+		return nil, fmt.Errorf("%s\nRerun with --resume to continue it or --rollback to undo it, the checkpoint is %s", msg, checkpointPath)
+	}
+
+	return &transferCheckpoint{
+		ClusterID:              transfer.clusterID,
+		OldOwner:               o.oldOwnerName,
+		NewOwner:               o.newOwnerName,
+		OriginalOrganizationID: transfer.subscription.OrganizationID(),
+		OriginalCreatorID:      transfer.subscription.Creator().ID(),
+		StartedAt:              time.Now(),
+	}, nil
+}
+
+// ownerTransfer holds everything the steps of an owner transfer need
+type ownerTransfer struct {
+	ocm        *sdk.Connection
+	checkpoint *transferCheckpoint
+
+	clusterID         string
+	clusterName       string
+	externalClusterID string
+	clusterURL        string
+	hypershift        bool
+	masterCluster     *cmv1.Cluster
+	mgmtCluster       *cmv1.Cluster
+	elevationReasons  []string
+
+	subscription   *amv1.Subscription
+	subscriptionID string
+	displayName    string
+
+	oldOwnerAccount   *amv1.Account
+	oldOwnerAccountID string
+	oldOwnerUsername  string
+	oldOrganizationID string
+	newOwnerAccountID string
+	newOwnerUsername  string
+	newOrganizationID string
+	orgChanged        bool
+	slParams          serviceLogParameters
+
+	// Clients and the pull secret are created by the first step needing them, so --resume
+	// and --plan don't need access to clusters they won't use
+	masterKubeCli       client.Client
+	masterKubeClientSet *kubernetes.Clientset
+	targetClientSet     *kubernetes.Clientset
+	newPullSecret       []byte
+
+	// Cluster changes made by the steps, overridden in tests
+	setPullSecret func(username string) error
+	restartPods   func(namespace, selector string) error
+}
+
+// gatherTransfer reads everything needed for the transfer without changing anything
+func (o *transferOwnerOptions) gatherTransfer(ocm *sdk.Connection) (*ownerTransfer, error) {
+	t := &ownerTransfer{ocm: ocm}
+	t.setPullSecret = t.updatePullSecret
+	t.restartPods = t.rolloutPods
+
 	// Gather all required data
 	cluster, err := utils.GetClusterAnyStatus(ocm, o.clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", o.clusterID, err)
+	}
 	o.cluster = cluster
 	o.clusterID = cluster.ID()
-
-	var mgmtCluster, svcCluster, hiveCluster, masterCluster *cmv1.Cluster
+	t.clusterID = cluster.ID()
+	t.clusterName = cluster.Name()
 
 	o.hypershift, err = utils.IsHostedCluster(o.clusterID)
 	if err != nil {
-		return fmt.Errorf("failed to check if the given cluster is HCP: %w", err)
+		return nil, fmt.Errorf("failed to check if the given cluster is HCP: %w", err)
 	}
+	t.hypershift = o.hypershift
 
 	// Find and setup all resources that are needed
 	if o.hypershift {
 		fmt.Println("Given cluster is HCP, start to proceed the HCP owner transfer")
-		mgmtCluster, err = utils.GetManagementCluster(o.clusterID)
-		svcCluster, err = utils.GetServiceCluster(o.clusterID)
+		t.mgmtCluster, err = utils.GetManagementCluster(o.clusterID)
+		if err != nil {
+			return nil, err
+		}
+		t.masterCluster, err = utils.GetServiceCluster(o.clusterID)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		masterCluster = svcCluster
 	} else {
 		fmt.Println("Given cluster is OSD/ROSA classic, start to proceed the classic owner transfer")
-		hiveCluster, err = utils.GetHiveCluster(o.clusterID)
+		t.masterCluster, err = utils.GetHiveCluster(o.clusterID)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		masterCluster = hiveCluster
 	}
 
-	elevationReasons := []string{
+	t.elevationReasons = []string{
 		o.reason,
 		fmt.Sprintf("Updating pull secret using osdctl to tranfert owner to %s", o.newOwnerName),
 	}
@@ -494,155 +692,318 @@ func (o *transferOwnerOptions) run() error {
 	fmt.Println("Gathering all required information for the cluster transfer...")
 	cluster, err = utils.GetCluster(ocm, o.clusterID)
 	if err != nil {
-		return fmt.Errorf("failed to get cluster information for cluster with ID %s: %w", o.clusterID, err)
+		return nil, fmt.Errorf("failed to get cluster information for cluster with ID %s: %w", o.clusterID, err)
 	}
 
-	externalClusterID, ok := cluster.GetExternalID()
+	var ok bool
+	t.externalClusterID, ok = cluster.GetExternalID()
 	if !ok {
-		return fmt.Errorf("cluster has no external id")
+		return nil, fmt.Errorf("cluster has no external id")
 	}
 
-	subscription, err := utils.GetSubscription(ocm, o.clusterID)
+	t.subscription, err = utils.GetSubscription(ocm, o.clusterID)
 	if err != nil {
-		return fmt.Errorf("could not get subscription: %w", err)
+		return nil, fmt.Errorf("could not get subscription: %w", err)
 	}
 
-	subscriptionID, ok := subscription.GetID()
+	t.subscriptionID, ok = t.subscription.GetID()
 	if !ok {
-		return fmt.Errorf("Could not get subscription id")
+		return nil, fmt.Errorf("Could not get subscription id")
 	}
 
 	oldOwnerAccount, err := utils.GetAccount(ocm, o.oldOwnerName)
-	if !ok {
-		return fmt.Errorf("could not get current owner's account, ask the user to log into http://console.redhat.com/ and try again: %w", err)
+	if err != nil {
+		return nil, fmt.Errorf("could not get current owner's account, ask the user to log into http://console.redhat.com/ and try again: %w", err)
 	}
 
 	oldOwnerOrganization, ok := oldOwnerAccount.GetOrganization()
 	if !ok {
-		return fmt.Errorf("current owner has no organization")
+		return nil, fmt.Errorf("current owner has no organization")
 	}
 
-	oldOrganizationId, ok := oldOwnerOrganization.GetID()
+	t.oldOrganizationID, ok = oldOwnerOrganization.GetID()
 	if !ok {
-		return fmt.Errorf("current owner's organization has no ID")
+		return nil, fmt.Errorf("current owner's organization has no ID")
 	}
 
 	newOwnerAccount, err := utils.GetAccount(ocm, o.newOwnerName)
 	if err != nil {
-		return fmt.Errorf("could not get new owner's account, ask the user to log into http://console.redhat.com/ and try again: %w", err)
+		return nil, fmt.Errorf("could not get new owner's account, ask the user to log into http://console.redhat.com/ and try again: %w", err)
 	}
 
 	newOwnerOrganization, ok := newOwnerAccount.GetOrganization()
 	if !ok {
-		return fmt.Errorf("new owner has no organization")
+		return nil, fmt.Errorf("new owner has no organization")
 	}
 
-	newOwnerOrganizationId, ok := newOwnerOrganization.GetID()
+	t.newOrganizationID, ok = newOwnerOrganization.GetID()
 	if !ok {
-		return fmt.Errorf("new owner's organization has no ID")
+		return nil, fmt.Errorf("new owner's organization has no ID")
 	}
 
-	newOwnerAccountID, ok := newOwnerAccount.GetID()
+	t.newOwnerAccountID, ok = newOwnerAccount.GetID()
 	if !ok {
-		return fmt.Errorf("new owner's account has no id")
+		return nil, fmt.Errorf("new owner's account has no id")
 	}
 
 	clusterConsole, ok := cluster.GetConsole()
 	if !ok {
-		return fmt.Errorf("cluster has no console url")
+		return nil, fmt.Errorf("cluster has no console url")
 	}
 
-	clusterURL, ok := clusterConsole.GetURL()
+	t.clusterURL, ok = clusterConsole.GetURL()
 	if !ok {
-		return fmt.Errorf("cluster has no console url")
+		return nil, fmt.Errorf("cluster has no console url")
 	}
 
-	displayName, ok := subscription.GetDisplayName()
+	t.displayName, ok = t.subscription.GetDisplayName()
 	if !ok {
-		return fmt.Errorf("subscription has no displayName")
+		return nil, fmt.Errorf("subscription has no displayName")
 	}
 
-	oldOwnerAccountID, ok := oldOwnerAccount.GetID()
+	t.oldOwnerAccountID, ok = oldOwnerAccount.GetID()
 	if !ok {
-		return fmt.Errorf("cannot get old owner account id")
+		return nil, fmt.Errorf("cannot get old owner account id")
 	}
 
-	oldOwnerAccount, err = utils.GetAccount(ocm, oldOwnerAccountID)
+	t.oldOwnerAccount, err = utils.GetAccount(ocm, t.oldOwnerAccountID)
 	if err != nil {
-		return fmt.Errorf("cannot get old owner account")
+		return nil, fmt.Errorf("cannot get old owner account")
 	}
 
-	oldOwnerUsername, ok := oldOwnerAccount.GetUsername()
+	t.oldOwnerUsername, ok = t.oldOwnerAccount.GetUsername()
 	if !ok {
-		return fmt.Errorf("cannot get old owner username")
+		return nil, fmt.Errorf("cannot get old owner username")
 	}
 
 	oldOwnerOrganizationEbsAccountID, ok := oldOwnerOrganization.GetEbsAccountID()
 	if !ok {
-		return fmt.Errorf("cannot get old org ebs id")
+		return nil, fmt.Errorf("cannot get old org ebs id")
 	}
 
-	newOwnerUsername, ok := newOwnerAccount.GetUsername()
+	t.newOwnerUsername, ok = newOwnerAccount.GetUsername()
 	if !ok {
-		return fmt.Errorf("cannot get new owner username")
+		return nil, fmt.Errorf("cannot get new owner username")
 	}
 
 	newOrganizationEbsAccountID, ok := newOwnerOrganization.GetEbsAccountID()
 	if !ok {
-		return fmt.Errorf("cannot get new org ebs id")
+		return nil, fmt.Errorf("cannot get new org ebs id")
 	}
 
-	// Confirm if the ownership transfer looks correct
-	fmt.Printf("Transfer cluster: \t\t'%v' (%v)\n", externalClusterID, cluster.Name())
-	fmt.Printf("from user \t\t\t'%v' ('%v') to '%v ('%v')'\n", oldOwnerAccount.ID(), oldOwnerUsername, newOwnerAccountID, newOwnerUsername)
-	if !utils.ConfirmPrompt() {
-		return nil
-	}
-
-	orgChanged := oldOrganizationId != newOwnerOrganizationId
+	t.orgChanged = t.oldOrganizationID != t.newOrganizationID
 
 	// build common SL parameters struct
-	slParams := serviceLogParameters{
+	t.slParams = serviceLogParameters{
 		ClusterID:             o.clusterID,
-		OldOwnerName:          oldOwnerUsername,
+		OldOwnerName:          t.oldOwnerUsername,
 		OldOwnerID:            oldOwnerOrganizationEbsAccountID,
-		NewOwnerName:          newOwnerUsername,
+		NewOwnerName:          t.newOwnerUsername,
 		NewOwnerID:            newOrganizationEbsAccountID,
-		IsExternalOrgTransfer: orgChanged,
+		IsExternalOrgTransfer: t.orgChanged,
+	}
+
+	return t, nil
+}
+
+// steps returns the mutations of the transfer in the order they run. The subscription org has
+// to be patched before its creator.
+func (t *ownerTransfer) steps() []transferStep {
+	pullSecretTarget := fmt.Sprintf("Hive SyncSet on hive shard %s", t.masterCluster.Name())
+	if t.hypershift {
+		pullSecretTarget = fmt.Sprintf("ManifestWork %s on service cluster %s", t.clusterID, t.masterCluster.Name())
+	}
+
+	steps := []transferStep{
+		{
+			name: "notify-start",
+			mutations: []string{
+				fmt.Sprintf("Send service log %s", SL_TRANSFER_INITIATED),
+				fmt.Sprintf("Send internal service log: from user '%s' to user '%s'", t.oldOwnerUsername, t.newOwnerUsername),
+			},
+			run:            t.notifyStart,
+			manualRollback: "send a service log telling the customer the transfer was cancelled",
+		},
+		{
+			name:      "update-pull-secret",
+			mutations: []string{fmt.Sprintf("Replace the cluster pull secret with the pull secret of %s through the %s", t.newOwnerUsername, pullSecretTarget)},
+			run:       func() error { return t.setPullSecret(t.newOwnerUsername) },
+			// Telemetry keeps reporting with the token of the new owner until telemeter-client restarts
+			rollback: func() error {
+				if err := t.setPullSecret(t.oldOwnerUsername); err != nil {
+					return err
+				}
+				if t.hypershift {
+					return nil
+				}
+				return t.rolloutTelemeterClient()
+			},
+		},
+	}
+
+	if !t.hypershift {
+		steps = append(steps, transferStep{
+			name:      "rollout-telemeter-client",
+			mutations: []string{"Delete the telemeter-client pods in openshift-monitoring"},
+			run:       t.rolloutTelemeterClient,
+			// The pods are rolled out again by the rollback of update-pull-secret, once the pull secret is restored
+			rollback: noTransferRollback,
+		})
+	}
+
+	steps = append(steps, transferStep{
+		name:      "verify-pull-secret",
+		mutations: []string{"None, asks to confirm the pull secret of the cluster"},
+		run:       t.verifyPullSecret,
+		rollback:  noTransferRollback,
+	})
+
+	if t.orgChanged {
+		steps = append(steps, transferStep{
+			name:      "patch-subscription-organization",
+			mutations: []string{fmt.Sprintf("Patch the organization of subscription %s from %s to %s", t.subscriptionID, t.checkpoint.OriginalOrganizationID, t.newOrganizationID)},
+			run:       func() error { return t.patchSubscriptionOrganization(t.newOrganizationID) },
+			rollback:  func() error { return t.patchSubscriptionOrganization(t.checkpoint.OriginalOrganizationID) },
+		})
+	}
+
+	steps = append(steps,
+		transferStep{
+			name:      "patch-subscription-creator",
+			mutations: []string{fmt.Sprintf("Patch the creator of subscription %s from %s to %s", t.subscriptionID, t.checkpoint.OriginalCreatorID, t.newOwnerAccountID)},
+			run:       func() error { return t.patchSubscriptionCreator(t.newOwnerAccountID) },
+			rollback:  func() error { return t.patchSubscriptionCreator(t.checkpoint.OriginalCreatorID) },
+		},
+		transferStep{
+			name: "replace-role-binding",
+			mutations: []string{
+				fmt.Sprintf("Delete the ClusterOwner role binding of subscription %s", t.subscriptionID),
+				fmt.Sprintf("Create a ClusterOwner role binding for account %s", t.newOwnerAccountID),
+			},
+			run:      func() error { return t.replaceRoleBinding(t.newOwnerAccountID) },
+			rollback: func() error { return t.replaceRoleBinding(t.oldOwnerAccountID) },
+		},
+	)
+
+	if t.orgChanged {
+		steps = append(steps, transferStep{
+			name:      "re-register-cluster",
+			mutations: []string{fmt.Sprintf("Re-register cluster %s with organization %s", t.externalClusterID, t.newOrganizationID)},
+			run:       func() error { return t.registerCluster(t.newOrganizationID) },
+			rollback:  func() error { return t.registerCluster(t.checkpoint.OriginalOrganizationID) },
+		})
+	}
+
+	if !t.hypershift {
+		steps = append(steps, transferStep{
+			name:      "rollout-ocm-agent",
+			mutations: []string{"Delete the ocm-agent pods in openshift-ocm-agent-operator"},
+			run: func() error {
+				return t.restartPods("openshift-ocm-agent-operator", "app=ocm-agent")
+			},
+			rollback: noTransferRollback,
+		})
 	}
 
+	return append(steps,
+		transferStep{
+			name:      "validate-transfer",
+			mutations: []string{fmt.Sprintf("None, checks that the cluster belongs to organization %s", t.newOrganizationID)},
+			run: func() error {
+				if err := validateTransfer(t.ocm, t.subscription.ClusterID(), t.newOrganizationID); err != nil {
+					return fmt.Errorf("error while validating transfer %w", err)
+				}
+				return nil
+			},
+			rollback: noTransferRollback,
+		},
+		transferStep{
+			name:           "notify-complete",
+			mutations:      []string{fmt.Sprintf("Send service log %s", SL_TRANSFER_COMPLETE)},
+			run:            t.notifyComplete,
+			manualRollback: "send a service log telling the customer the transfer was reverted",
+		},
+	)
+}
+
+// rolloutTelemeterClient restarts telemeter-client, so telemetry reports with the token of the current pull secret
+func (t *ownerTransfer) rolloutTelemeterClient() error {
+	return t.restartPods("openshift-monitoring", "app.kubernetes.io/name=telemeter-client")
+}
+
+// noTransferRollback is the rollback of steps which change nothing that needs to be undone
+func noTransferRollback() error {
+	return nil
+}
+
+func (t *ownerTransfer) notifyStart() error {
 	// Send a SL saying we're about to start
 	fmt.Println("Notify the customer before ownership transfer commences. Sending service log.")
-	postCmd := generateServiceLog(slParams, SL_TRANSFER_INITIATED)
+	postCmd := generateServiceLog(t.slParams, SL_TRANSFER_INITIATED)
 	if err := postCmd.Run(); err != nil {
 		fmt.Println("Failed to POST customer service log. Please manually send a service log to notify the customer before ownership transfer commences:")
 		fmt.Printf("osdctl servicelog post %v -t %v -p %v\n",
-			o.clusterID, SL_TRANSFER_INITIATED, strings.Join(postCmd.TemplateParams, " -p "))
+			t.clusterID, SL_TRANSFER_INITIATED, strings.Join(postCmd.TemplateParams, " -p "))
 	}
 
 	// Send internal SL to cluster with additional details in case we
 	// need them later. This prevents leaking PII to customers.
-	postCmd = generateInternalServiceLog(slParams)
+	postCmd = generateInternalServiceLog(t.slParams)
 	fmt.Println("Internal SL Being Sent")
 	if err := postCmd.Run(); err != nil {
 		fmt.Println("Failed to POST internal service log. Please manually send a service log to persist details of the customer transfer before proceeding:")
-		fmt.Println(fmt.Sprintf("osdctl servicelog post -i -p MESSAGE=\"From user '%s' in Red Hat account %s => user '%s' in Red Hat account %s.\" %s", slParams.OldOwnerName, slParams.OldOwnerID, slParams.NewOwnerName, slParams.NewOwnerID, slParams.ClusterID))
+		fmt.Printf("osdctl servicelog post -i -p MESSAGE=\"From user '%s' in Red Hat account %s => user '%s' in Red Hat account %s.\" %s\n", t.slParams.OldOwnerName, t.slParams.OldOwnerID, t.slParams.NewOwnerName, t.slParams.NewOwnerID, t.slParams.ClusterID)
 	}
+	return nil
+}
 
-	masterKubeCli, _, masterKubeClientSet, err := common.GetKubeConfigAndClient(masterCluster.ID(), elevationReasons...)
-	if err != nil {
-		return fmt.Errorf("failed to retrieve Kubernetes configuration and client for Hive cluster ID %s: %w", masterCluster.ID(), err)
+func (t *ownerTransfer) notifyComplete() error {
+	fmt.Println("Notify the customer the ownership transfer is completed. Sending service log.")
+	postCmd := generateServiceLog(t.slParams, SL_TRANSFER_COMPLETE)
+	if err := postCmd.Run(); err != nil {
+		fmt.Println("Failed to POST service log. Please manually send a service log to notify the customer the ownership transfer is completed:")
+		fmt.Printf("osdctl servicelog post %v -t %v -p %v\n",
+			t.clusterID, SL_TRANSFER_COMPLETE, strings.Join(postCmd.TemplateParams, " -p "))
+	}
+	return nil
+}
+
+func (t *ownerTransfer) masterClients() (client.Client, *kubernetes.Clientset, error) {
+	if t.masterKubeCli == nil {
+		kubeCli, _, clientSet, err := common.GetKubeConfigAndClient(t.masterCluster.ID(), t.elevationReasons...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to retrieve Kubernetes configuration and client for Hive cluster ID %s: %w", t.masterCluster.ID(), err)
+		}
+		t.masterKubeCli, t.masterKubeClientSet = kubeCli, clientSet
 	}
+	return t.masterKubeCli, t.masterKubeClientSet, nil
+}
 
-	// Fetch the pull secret with the given new username
-	response, err := ocm.AccountsMgmt().V1().AccessToken().Post().Impersonate(newOwnerUsername).Parameter("body", nil).Send()
+func (t *ownerTransfer) targetClients() (*kubernetes.Clientset, error) {
+	if t.targetClientSet == nil {
+		_, _, clientSet, err := common.GetKubeConfigAndClient(t.clusterID, t.elevationReasons...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve Kubernetes configuration and client for cluster with ID %s: %w", t.clusterID, err)
+		}
+		t.targetClientSet = clientSet
+	}
+	return t.targetClientSet, nil
+}
+
+// pullSecret fetches the pull secret of a user
+func (t *ownerTransfer) pullSecret(username string) ([]byte, error) {
+	if username == t.newOwnerUsername && t.newPullSecret != nil {
+		return t.newPullSecret, nil
+	}
+
+	response, err := t.ocm.AccountsMgmt().V1().AccessToken().Post().Impersonate(username).Parameter("body", nil).Send()
 	if err != nil {
-		return fmt.Errorf("Can't send request: %w", err)
+		return nil, fmt.Errorf("Can't send request: %w", err)
 	}
 
 	auths, ok := response.Body().GetAuths()
 	if !ok {
-		return fmt.Errorf("Error validating pull secret structure. This shouldn't happen, so you might need to contact SDB")
+		return nil, fmt.Errorf("Error validating pull secret structure. This shouldn't happen, so you might need to contact SDB")
 	}
 	authsMap := map[string]map[string]string{}
 	for k, auth := range auths {
@@ -656,11 +1017,28 @@ func (o *transferOwnerOptions) run() error {
 		"auths": authsMap,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to marshal pull secret data: %w", err)
+		return nil, fmt.Errorf("failed to marshal pull secret data: %w", err)
+	}
+	if username == t.newOwnerUsername {
+		t.newPullSecret = pullSecret
+	}
+	return pullSecret, nil
+}
+
+// updatePullSecret replaces the cluster pull secret with the pull secret of a user
+func (t *ownerTransfer) updatePullSecret(username string) error {
+	kubeCli, clientSet, err := t.masterClients()
+	if err != nil {
+		return err
+	}
+
+	pullSecret, err := t.pullSecret(username)
+	if err != nil {
+		return err
 	}
 
 	// Print the pull secret
-	fmt.Println("Pull Secret:")
+	fmt.Printf("Pull Secret of %s:\n", username)
 	fmt.Println(string(pullSecret))
 
 	// Ask the user if they would like to continue
@@ -676,107 +1054,97 @@ func (o *transferOwnerOptions) run() error {
 		return fmt.Errorf("operation aborted by the user")
 	}
 
-	if o.hypershift {
-		err = updateManifestWork(ocm, masterKubeCli, o.clusterID, mgmtCluster.Name(), pullSecret)
-		if err != nil {
-			return fmt.Errorf("failed to update pull secret for service cluster with ID %s: %w", o.clusterID, err)
-		}
-	} else {
-		err = updatePullSecret(ocm, masterKubeCli, masterKubeClientSet, o.clusterID, pullSecret)
+	if t.hypershift {
+		err = updateManifestWork(t.ocm, kubeCli, t.clusterID, t.mgmtCluster.Name(), pullSecret)
 		if err != nil {
-			return fmt.Errorf("failed to update pull secret for Hive cluster with ID %s: %w", o.clusterID, err)
+			return fmt.Errorf("failed to update pull secret for service cluster with ID %s: %w", t.clusterID, err)
 		}
+		return nil
 	}
 
-	_, _, targetClientSet, err := common.GetKubeConfigAndClient(o.clusterID, elevationReasons...)
+	err = updatePullSecret(t.ocm, kubeCli, clientSet, t.clusterID, pullSecret)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve Kubernetes configuration and client for cluster with ID %s: %w", o.clusterID, err)
+		return fmt.Errorf("failed to update pull secret for Hive cluster with ID %s: %w", t.clusterID, err)
 	}
+	return nil
+}
 
-	// Rollout the telemeterClient pod for non HCP clusters
-	if !o.hypershift {
-		err = rolloutPods(targetClientSet, "openshift-monitoring", "app.kubernetes.io/name=telemeter-client")
-		if err != nil {
-			return fmt.Errorf("failed to roll out Telemeter Client pods in namespace 'openshift-monitoring' with label selector 'app.kubernetes.io/name=telemeter-client': %w", err)
-		}
+func (t *ownerTransfer) rolloutPods(namespace, selector string) error {
+	clientSet, err := t.targetClients()
+	if err != nil {
+		return err
 	}
+	if err := rolloutPods(clientSet, namespace, selector); err != nil {
+		return fmt.Errorf("failed to roll out pods in namespace '%s' with label selector '%s': %w", namespace, selector, err)
+	}
+	return nil
+}
 
-	err = verifyClusterPullSecret(targetClientSet, string(pullSecret))
+func (t *ownerTransfer) verifyPullSecret() error {
+	clientSet, err := t.targetClients()
 	if err != nil {
-		return fmt.Errorf("error verifying cluster pull secret: %w", err)
+		return err
 	}
-
-	ok = validateOldOwner(oldOrganizationId, subscription, oldOwnerAccount)
-	if !ok {
-		fmt.Print("can't validate this is old owners cluster, this could be because of a previously failed run\n")
-		if !utils.ConfirmPrompt() {
-			return nil
-		}
+	pullSecret, err := t.pullSecret(t.newOwnerUsername)
+	if err != nil {
+		return err
 	}
+	if err := verifyClusterPullSecret(clientSet, string(pullSecret)); err != nil {
+		return fmt.Errorf("error verifying cluster pull secret: %w", err)
+	}
+	return nil
+}
 
-	subscriptionOrgPatch, err := amv1.NewSubscription().OrganizationID(newOwnerOrganizationId).Build()
-
+func (t *ownerTransfer) patchSubscriptionOrganization(organizationID string) error {
+	subscriptionOrgPatch, err := amv1.NewSubscription().OrganizationID(organizationID).Build()
 	if err != nil {
 		return fmt.Errorf("can't create subscription organization patch: %w", err)
 	}
 
-	subscriptionCreatorPatchRequest, err := createSubscriptionCreatorPatchRequest(ocm, subscriptionID, newOwnerAccountID)
+	subscriptionClient := t.ocm.AccountsMgmt().V1().Subscriptions().Subscription(t.subscriptionID)
+	response, err := subscriptionClient.Update().Body(subscriptionOrgPatch).Send()
+	if err != nil || response.Status() != 200 {
+		return fmt.Errorf("request failed with status: %d, '%w'", response.Status(), err)
+	}
+	fmt.Printf("Patched organization on subscription to %s\n", organizationID)
+	return nil
+}
 
+func (t *ownerTransfer) patchSubscriptionCreator(accountID string) error {
+	subscriptionCreatorPatchRequest, err := createSubscriptionCreatorPatchRequest(t.ocm, t.subscriptionID, accountID)
 	if err != nil {
 		return fmt.Errorf("can't create subscription creator patch: %w", err)
 	}
 
+	patchRes, err := subscriptionCreatorPatchRequest.Send()
+	if err != nil || patchRes.Status() != 200 {
+		return fmt.Errorf("request failed with status: %d, '%w'", patchRes.Status(), err)
+	}
+	fmt.Printf("Patched creator on subscription to %s\n", accountID)
+	return nil
+}
+
+// replaceRoleBinding replaces the ClusterOwner role binding of the subscription with one for accountID
+func (t *ownerTransfer) replaceRoleBinding(accountID string) error {
 	newRoleBinding, err := amv1.
 		NewRoleBinding().
-		AccountID(newOwnerAccountID).
-		SubscriptionID(subscriptionID).
+		AccountID(accountID).
+		SubscriptionID(t.subscriptionID).
 		Type("Subscription").
 		RoleID("ClusterOwner").
 		Build()
-
 	if err != nil {
 		return fmt.Errorf("can't create new owners rolebinding %w", err)
 	}
 
-	if orgChanged {
-		fmt.Printf("with organization change from \t'%v' to '%v'\n", oldOrganizationId, newOwnerOrganizationId)
-	}
-
-	if o.dryrun {
-		fmt.Print("This is a dry run, nothing changed.\n")
-		return nil
-	}
-
-	// Validation done, now update everything
-
-	// org has to be patched before creator
-	if orgChanged {
-		subscriptionClient := ocm.AccountsMgmt().V1().Subscriptions().Subscription(subscriptionID)
-		response, err := subscriptionClient.Update().Body(subscriptionOrgPatch).Send()
-
-		if err != nil || response.Status() != 200 {
-			return fmt.Errorf("request failed with status: %d, '%w'", response.Status(), err)
-		}
-		fmt.Printf("Patched organization on subscription\n")
-	}
-
-	// patch creator on subscription
-	patchRes, err := subscriptionCreatorPatchRequest.Send()
-
-	if err != nil || patchRes.Status() != 200 {
-		return fmt.Errorf("request failed with status: %d, '%w'", patchRes.Status(), err)
-	}
-	fmt.Printf("Patched creator on subscription\n")
-
 	// delete old rolebinding but do not exit on fail could be a rerun
-	err = deleteOldRoleBinding(ocm, subscriptionID)
-
+	err = deleteOldRoleBinding(t.ocm, t.subscriptionID)
 	if err != nil {
 		fmt.Printf("can't delete old rolebinding %v \n", err)
 	}
 
 	// create new rolebinding
-	newRoleBindingClient := ocm.AccountsMgmt().V1().RoleBindings()
+	newRoleBindingClient := t.ocm.AccountsMgmt().V1().RoleBindings()
 	postRes, err := newRoleBindingClient.Add().Body(newRoleBinding).Send()
 
 	// don't fail if the rolebinding already exists, could be rerun
@@ -789,44 +1157,21 @@ func (o *transferOwnerOptions) run() error {
 	} else {
 		return fmt.Errorf("request failed with status: %d, '%w'", postRes.Status(), err)
 	}
+	return nil
+}
 
-	// If the organization id has changed, re-register the cluster with CS with the new organization id
-	if orgChanged {
-
-		request, err := createNewRegisterClusterRequest(ocm, externalClusterID, subscriptionID, newOwnerOrganizationId, clusterURL, displayName)
-		if err != nil {
-			return fmt.Errorf("can't create RegisterClusterRequest with CS, '%w'", err)
-		}
-
-		response, err := request.Send()
-		if err != nil || (response.Status() != 200 && response.Status() != 201) {
-			return fmt.Errorf("request failed with status: %d, '%w'", response.Status(), err)
-		}
-		fmt.Print("Re-registered cluster\n")
-	}
-
-	// Rollout the ocmAgent pods for non HCP clusters
-	if !o.hypershift {
-		err = rolloutPods(targetClientSet, "openshift-ocm-agent-operator", "app=ocm-agent")
-		if err != nil {
-			return fmt.Errorf("failed to roll out OCM Agent pods in namespace 'openshift-ocm-agent-operator' with label selector 'app=ocm-agent': %w", err)
-		}
-	}
-
-	err = validateTransfer(ocm, subscription.ClusterID(), newOwnerOrganizationId)
+// registerCluster re-registers the cluster with CS with the given organization id
+func (t *ownerTransfer) registerCluster(organizationID string) error {
+	request, err := createNewRegisterClusterRequest(t.ocm, t.externalClusterID, t.subscriptionID, organizationID, t.clusterURL, t.displayName)
 	if err != nil {
-		return fmt.Errorf("error while validating transfer %w", err)
+		return fmt.Errorf("can't create RegisterClusterRequest with CS, '%w'", err)
 	}
-	fmt.Print("Transfer complete\n")
 
-	fmt.Println("Notify the customer the ownership transfer is completed. Sending service log.")
-	postCmd = generateServiceLog(slParams, SL_TRANSFER_COMPLETE)
-	if err := postCmd.Run(); err != nil {
-		fmt.Println("Failed to POST service log. Please manually send a service log to notify the customer the ownership transfer is completed:")
-		fmt.Printf("osdctl servicelog post %v -t %v -p %v\n",
-			o.clusterID, SL_TRANSFER_COMPLETE, strings.Join(postCmd.TemplateParams, " -p "))
+	response, err := request.Send()
+	if err != nil || (response.Status() != 200 && response.Status() != 201) {
+		return fmt.Errorf("request failed with status: %d, '%w'", response.Status(), err)
 	}
-
+	fmt.Print("Re-registered cluster\n")
 	return nil
 }
 
@@ -0,0 +1,197 @@
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// transferStep is one mutation of an owner transfer
+type transferStep struct {
+	name string
+	// mutations describe every change made by the step, printed by --plan
+	mutations []string
+	run       func() error
+	// rollback is the compensating action of the step. It is nil for steps which can't be
+	// undone, like sent service logs, in which case manualRollback says what to do instead.
+	rollback       func() error
+	manualRollback string
+}
+
+// transferCheckpoint records the progress of an owner transfer, so a failed transfer can be
+// resumed or rolled back
+type transferCheckpoint struct {
+	ClusterID string `json:"clusterId"`
+	OldOwner  string `json:"oldOwner"`
+	NewOwner  string `json:"newOwner"`
+
+	// Subscription values before the transfer, restored by a rollback
+	OriginalOrganizationID string `json:"originalOrganizationId"`
+	OriginalCreatorID      string `json:"originalCreatorId"`
+
+	Completed  []string  `json:"completed"`
+	FailedStep string    `json:"failedStep,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Finished   bool      `json:"finished"`
+	RolledBack bool      `json:"rolledBack"`
+	StartedAt  time.Time `json:"startedAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// inProgress returns whether the transfer made changes without finishing or being rolled back
+func (c *transferCheckpoint) inProgress() bool {
+	return !c.Finished && !c.RolledBack && len(c.Completed) > 0
+}
+
+func (c *transferCheckpoint) completed(step string) bool {
+	return slices.Contains(c.Completed, step)
+}
+
+// defaultTransferCheckpointPath returns where the checkpoint of a cluster's transfer is kept
+func defaultTransferCheckpointPath(clusterID string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "osdctl-transfer-owner", clusterID+".json"), nil
+}
+
+// loadTransferCheckpoint returns the checkpoint at path, or nil if there is none
+func loadTransferCheckpoint(path string) (*transferCheckpoint, error) {
+	content, err := os.ReadFile(path) //#nosec G304 -- path is the checkpoint of the transferred cluster
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &transferCheckpoint{}
+	if err := json.Unmarshal(content, checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse transfer checkpoint %s: %w", path, err)
+	}
+	return checkpoint, nil
+}
+
+// transferPipeline runs the steps of an owner transfer, saving a checkpoint after each step
+type transferPipeline struct {
+	steps          []transferStep
+	checkpoint     *transferCheckpoint
+	checkpointPath string
+	out            io.Writer
+	now            func() time.Time
+}
+
+func (p *transferPipeline) save() error {
+	p.checkpoint.UpdatedAt = p.now()
+	content, err := json.MarshalIndent(p.checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.checkpointPath), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(p.checkpointPath, content, 0600)
+}
+
+// plan prints the mutations of every step without running them
+func (p *transferPipeline) plan() {
+	fmt.Fprintln(p.out, "The transfer will run the following steps:")
+	for i, step := range p.steps {
+		state := ""
+		if p.checkpoint.completed(step.name) {
+			state = " (completed, skipped on --resume)"
+		}
+		fmt.Fprintf(p.out, "%d. %s%s\n", i+1, step.name, state)
+		for _, mutation := range step.mutations {
+			fmt.Fprintf(p.out, "   - %s\n", mutation)
+		}
+		if step.rollback == nil {
+			fmt.Fprintf(p.out, "   rollback: manual, %s\n", step.manualRollback)
+		}
+	}
+	fmt.Fprintln(p.out, "This is a plan, nothing changed.")
+}
+
+// run runs the steps which did not complete yet
+func (p *transferPipeline) run() error {
+	p.checkpoint.FailedStep, p.checkpoint.Error = "", ""
+	p.checkpoint.Finished, p.checkpoint.RolledBack = false, false
+	if err := p.save(); err != nil {
+		return fmt.Errorf("failed to save transfer checkpoint: %w", err)
+	}
+
+	for _, step := range p.steps {
+		if p.checkpoint.completed(step.name) {
+			fmt.Fprintf(p.out, "Skipping step %s, completed by a previous run\n", step.name)
+			continue
+		}
+
+		fmt.Fprintf(p.out, "Running step %s\n", step.name)
+		if err := step.run(); err != nil {
+			p.checkpoint.FailedStep, p.checkpoint.Error = step.name, err.Error()
+			if saveErr := p.save(); saveErr != nil {
+				fmt.Fprintf(p.out, "Failed to save transfer checkpoint %s: %v\n", p.checkpointPath, saveErr)
+			}
+			return fmt.Errorf("step %s failed: %w\nThe progress is saved in %s, fix the issue and rerun with --resume, or undo the completed steps with --rollback", step.name, err, p.checkpointPath)
+		}
+
+		p.checkpoint.Completed = append(p.checkpoint.Completed, step.name)
+		if err := p.save(); err != nil {
+			return fmt.Errorf("step %s completed but saving the transfer checkpoint failed: %w", step.name, err)
+		}
+	}
+
+	p.checkpoint.Finished = true
+	return p.save()
+}
+
+// rollback runs the compensating actions of the completed steps, in reverse order
+func (p *transferPipeline) rollback() error {
+	var manual []string
+	for i := len(p.steps) - 1; i >= 0; i-- {
+		step := p.steps[i]
+		if !p.checkpoint.completed(step.name) {
+			continue
+		}
+
+		if step.rollback == nil {
+			fmt.Fprintf(p.out, "Step %s can't be rolled back automatically\n", step.name)
+			manual = append(manual, fmt.Sprintf("%s: %s", step.name, step.manualRollback))
+		} else {
+			fmt.Fprintf(p.out, "Rolling back step %s\n", step.name)
+			if err := step.rollback(); err != nil {
+				if saveErr := p.save(); saveErr != nil {
+					fmt.Fprintf(p.out, "Failed to save transfer checkpoint %s: %v\n", p.checkpointPath, saveErr)
+				}
+				return fmt.Errorf("rollback of step %s failed: %w\nRerun --rollback to retry the remaining steps", step.name, err)
+			}
+		}
+
+		p.checkpoint.Completed = slices.DeleteFunc(p.checkpoint.Completed, func(name string) bool { return name == step.name })
+		if err := p.save(); err != nil {
+			return fmt.Errorf("step %s rolled back but saving the transfer checkpoint failed: %w", step.name, err)
+		}
+	}
+
+	p.checkpoint.RolledBack = true
+	p.checkpoint.FailedStep, p.checkpoint.Error = "", ""
+	if err := p.save(); err != nil {
+		return err
+	}
+
+	if len(manual) > 0 {
+		fmt.Fprintln(p.out, "Rollback done, the following steps need manual actions:")
+		for _, m := range manual {
+			fmt.Fprintf(p.out, "  - %s\n", m)
+		}
+		return nil
+	}
+	fmt.Fprintln(p.out, "Rollback done")
+	return nil
+}
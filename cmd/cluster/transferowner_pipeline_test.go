@@ -0,0 +1,328 @@
+package cluster
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSteps returns steps appending their runs and rollbacks to calls, failing the step named failing
+func recordingSteps(calls *[]string, failing string) []transferStep {
+	step := func(name string, rollback bool) transferStep {
+		s := transferStep{
+			name:      name,
+			mutations: []string{"change " + name},
+			run: func() error {
+				*calls = append(*calls, "run "+name)
+				if name == failing {
+					return errors.New("boom")
+				}
+				return nil
+			},
+			manualRollback: "undo " + name + " by hand",
+		}
+		if rollback {
+			s.rollback = func() error {
+				*calls = append(*calls, "rollback "+name)
+				return nil
+			}
+		}
+		return s
+	}
+	return []transferStep{step("notify", false), step("patch", true), step("bind", true)}
+}
+
+func newTestPipeline(t *testing.T, steps []transferStep, checkpoint *transferCheckpoint) (*transferPipeline, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &transferPipeline{
+		steps:          steps,
+		checkpoint:     checkpoint,
+		checkpointPath: filepath.Join(t.TempDir(), "transfer", "cluster.json"),
+		out:            out,
+		now:            func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}, out
+}
+
+func TestTransferPipelineRun(t *testing.T) {
+	var calls []string
+	p, _ := newTestPipeline(t, recordingSteps(&calls, "bind"), &transferCheckpoint{ClusterID: "abc"})
+
+	err := p.run()
+	assert.ErrorContains(t, err, "step bind failed: boom")
+	assert.Equal(t, []string{"run notify", "run patch", "run bind"}, calls)
+
+	saved, err := loadTransferCheckpoint(p.checkpointPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"notify", "patch"}, saved.Completed)
+	assert.Equal(t, "bind", saved.FailedStep)
+	assert.Equal(t, "boom", saved.Error)
+	assert.True(t, saved.inProgress())
+
+	// Resuming skips the completed steps
+	calls = nil
+	resumed := &transferPipeline{steps: recordingSteps(&calls, ""), checkpoint: saved, checkpointPath: p.checkpointPath, out: &bytes.Buffer{}, now: time.Now}
+	require.NoError(t, resumed.run())
+	assert.Equal(t, []string{"run bind"}, calls)
+
+	saved, err = loadTransferCheckpoint(p.checkpointPath)
+	require.NoError(t, err)
+	assert.True(t, saved.Finished)
+	assert.Empty(t, saved.FailedStep)
+	assert.False(t, saved.inProgress())
+}
+
+func TestTransferPipelineRollback(t *testing.T) {
+	var calls []string
+	p, out := newTestPipeline(t, recordingSteps(&calls, ""), &transferCheckpoint{Completed: []string{"notify", "patch"}, FailedStep: "bind"})
+
+	require.NoError(t, p.rollback())
+	assert.Equal(t, []string{"rollback patch"}, calls)
+	assert.Contains(t, out.String(), "notify: undo notify by hand")
+
+	saved, err := loadTransferCheckpoint(p.checkpointPath)
+	require.NoError(t, err)
+	assert.Empty(t, saved.Completed)
+	assert.True(t, saved.RolledBack)
+	assert.False(t, saved.inProgress())
+}
+
+func TestTransferPipelinePlan(t *testing.T) {
+	var calls []string
+	p, out := newTestPipeline(t, recordingSteps(&calls, ""), &transferCheckpoint{Completed: []string{"notify"}})
+
+	p.plan()
+	assert.Empty(t, calls)
+	assert.Equal(t, `The transfer will run the following steps:
+1. notify (completed, skipped on --resume)
+   - change notify
+   rollback: manual, undo notify by hand
+2. patch
+   - change patch
+3. bind
+   - change bind
+This is a plan, nothing changed.
+`, out.String())
+
+	missing, err := loadTransferCheckpoint(p.checkpointPath)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestSelectCheckpoint(t *testing.T) {
+	subscription, err := amv1.NewSubscription().OrganizationID("old-org").Creator(amv1.NewAccount().ID("old-account")).Build()
+	require.NoError(t, err)
+	transfer := &ownerTransfer{clusterID: "abc", subscription: subscription}
+	inProgress := &transferCheckpoint{OldOwner: "old", NewOwner: "new", Completed: []string{"notify-start"}, FailedStep: "update-pull-secret", Error: "timeout"}
+
+	tests := []struct {
+		name     string
+		opts     transferOwnerOptions
+		previous *transferCheckpoint
+		expected *transferCheckpoint
+		errorMsg string
+	}{
+		{
+			name: "new_transfer",
+			opts: transferOwnerOptions{oldOwnerName: "old", newOwnerName: "new"},
+			expected: &transferCheckpoint{
+				ClusterID:              "abc",
+				OldOwner:               "old",
+				NewOwner:               "new",
+				OriginalOrganizationID: "old-org",
+				OriginalCreatorID:      "old-account",
+			},
+		},
+		{
+			name:     "new_transfer_after_finished_one",
+			opts:     transferOwnerOptions{oldOwnerName: "new", newOwnerName: "old"},
+			previous: &transferCheckpoint{OldOwner: "old", NewOwner: "new", Completed: []string{"notify-start"}, Finished: true},
+			expected: &transferCheckpoint{
+				ClusterID:              "abc",
+				OldOwner:               "new",
+				NewOwner:               "old",
+				OriginalOrganizationID: "old-org",
+				OriginalCreatorID:      "old-account",
+			},
+		},
+		{
+			name:     "unfinished_transfer",
+			opts:     transferOwnerOptions{oldOwnerName: "old", newOwnerName: "new"},
+			previous: inProgress,
+			errorMsg: "a previous transfer of this cluster from old to new did not finish, completed steps: notify-start, failed step update-pull-secret: timeout",
+		},
+		{
+			name:     "plan_unfinished_transfer",
+			opts:     transferOwnerOptions{oldOwnerName: "old", newOwnerName: "new", plan: true},
+			previous: inProgress,
+			expected: inProgress,
+		},
+		{
+			name:     "resume",
+			opts:     transferOwnerOptions{oldOwnerName: "old", newOwnerName: "new", resume: true},
+			previous: inProgress,
+			expected: inProgress,
+		},
+		{
+			name:     "resume_without_checkpoint",
+			opts:     transferOwnerOptions{oldOwnerName: "old", newOwnerName: "new", resume: true},
+			errorMsg: "no transfer checkpoint found at /tmp/abc.json",
+		},
+		{
+			name:     "resume_other_transfer",
+			opts:     transferOwnerOptions{oldOwnerName: "old", newOwnerName: "other", resume: true},
+			previous: inProgress,
+			errorMsg: "is a transfer from old to new, not from old to other",
+		},
+		{
+			name:     "resume_finished_transfer",
+			opts:     transferOwnerOptions{oldOwnerName: "old", newOwnerName: "new", resume: true},
+			previous: &transferCheckpoint{OldOwner: "old", NewOwner: "new", Finished: true},
+			errorMsg: "already finished",
+		},
+		{
+			name:     "rollback",
+			opts:     transferOwnerOptions{oldOwnerName: "old", newOwnerName: "new", rollback: true},
+			previous: inProgress,
+			expected: inProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkpoint, err := tt.opts.selectCheckpoint(tt.previous, transfer, "/tmp/abc.json")
+			if tt.errorMsg != "" {
+				assert.ErrorContains(t, err, tt.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			checkpoint.StartedAt = time.Time{}
+			assert.Equal(t, tt.expected, checkpoint)
+		})
+	}
+}
+
+func TestOwnerTransferSteps(t *testing.T) {
+	masterCluster, err := cmv1.NewCluster().ID("master").Name("hivep01").Build()
+	require.NoError(t, err)
+	subscription, err := amv1.NewSubscription().Build()
+	require.NoError(t, err)
+
+	stepNames := func(transfer *ownerTransfer) []string {
+		var names []string
+		for _, step := range transfer.steps() {
+			names = append(names, step.name)
+			assert.NotNil(t, step.run, step.name)
+			assert.NotEmpty(t, step.mutations, step.name)
+			assert.True(t, step.rollback != nil || step.manualRollback != "", "%s has no rollback", step.name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{
+		"notify-start",
+		"update-pull-secret",
+		"rollout-telemeter-client",
+		"verify-pull-secret",
+		"patch-subscription-creator",
+		"replace-role-binding",
+		"rollout-ocm-agent",
+		"validate-transfer",
+		"notify-complete",
+	}, stepNames(&ownerTransfer{masterCluster: masterCluster, subscription: subscription, checkpoint: &transferCheckpoint{}}))
+
+	assert.Equal(t, []string{
+		"notify-start",
+		"update-pull-secret",
+		"verify-pull-secret",
+		"patch-subscription-organization",
+		"patch-subscription-creator",
+		"replace-role-binding",
+		"re-register-cluster",
+		"validate-transfer",
+		"notify-complete",
+	}, stepNames(&ownerTransfer{masterCluster: masterCluster, subscription: subscription, checkpoint: &transferCheckpoint{}, hypershift: true, orgChanged: true}))
+}
+
+func TestOwnerTransferStepsResumed(t *testing.T) {
+	masterCluster, err := cmv1.NewCluster().ID("master").Name("hivep01").Build()
+	require.NoError(t, err)
+	// The subscription was already patched by the first run
+	subscription, err := amv1.NewSubscription().Creator(amv1.NewAccount().ID("new")).OrganizationID("new-org").Build()
+	require.NoError(t, err)
+
+	transfer := &ownerTransfer{
+		masterCluster:     masterCluster,
+		subscription:      subscription,
+		subscriptionID:    "sub",
+		orgChanged:        true,
+		newOrganizationID: "new-org",
+		newOwnerAccountID: "new",
+		checkpoint:        &transferCheckpoint{OriginalCreatorID: "old", OriginalOrganizationID: "old-org"},
+	}
+
+	mutations := map[string][]string{}
+	for _, step := range transfer.steps() {
+		mutations[step.name] = step.mutations
+	}
+	assert.Equal(t, []string{"Patch the creator of subscription sub from old to new"}, mutations["patch-subscription-creator"])
+	assert.Equal(t, []string{"Patch the organization of subscription sub from old-org to new-org"}, mutations["patch-subscription-organization"])
+}
+
+func TestOwnerTransferPullSecretRollback(t *testing.T) {
+	masterCluster, err := cmv1.NewCluster().ID("master").Name("hivep01").Build()
+	require.NoError(t, err)
+	subscription, err := amv1.NewSubscription().Build()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		hypershift bool
+		completed  []string
+		expected   []string
+	}{
+		{
+			name:      "classic",
+			completed: []string{"notify-start", "update-pull-secret", "rollout-telemeter-client"},
+			expected:  []string{"pull secret of old", "restart openshift-monitoring app.kubernetes.io/name=telemeter-client"},
+		},
+		{
+			name:       "hypershift",
+			hypershift: true,
+			completed:  []string{"notify-start", "update-pull-secret"},
+			expected:   []string{"pull secret of old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			transfer := &ownerTransfer{
+				masterCluster:    masterCluster,
+				subscription:     subscription,
+				checkpoint:       &transferCheckpoint{},
+				hypershift:       tt.hypershift,
+				oldOwnerUsername: "old",
+				newOwnerUsername: "new",
+				setPullSecret: func(username string) error {
+					calls = append(calls, "pull secret of "+username)
+					return nil
+				},
+				restartPods: func(namespace, selector string) error {
+					calls = append(calls, "restart "+namespace+" "+selector)
+					return nil
+				},
+			}
+			p, _ := newTestPipeline(t, transfer.steps(), &transferCheckpoint{Completed: tt.completed, FailedStep: "verify-pull-secret"})
+
+			require.NoError(t, p.rollback())
+			assert.Equal(t, tt.expected, calls)
+		})
+	}
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
 	routev1 "github.com/openshift/api/route/v1"
@@ -22,6 +21,7 @@ import (
 	"github.com/openshift/osdctl/cmd/alerts"
 	"github.com/openshift/osdctl/cmd/cloudtrail"
 	"github.com/openshift/osdctl/cmd/cluster"
+	"github.com/openshift/osdctl/cmd/config"
 	"github.com/openshift/osdctl/cmd/cost"
 	"github.com/openshift/osdctl/cmd/dynatrace"
 	"github.com/openshift/osdctl/cmd/env"
@@ -33,6 +33,7 @@ import (
 	"github.com/openshift/osdctl/cmd/mc"
 	"github.com/openshift/osdctl/cmd/network"
 	"github.com/openshift/osdctl/cmd/org"
+	"github.com/openshift/osdctl/cmd/pagerduty"
 	"github.com/openshift/osdctl/cmd/promote"
 	"github.com/openshift/osdctl/cmd/servicelog"
 	"github.com/openshift/osdctl/cmd/setup"
@@ -40,7 +41,6 @@ import (
 	"github.com/openshift/osdctl/internal/utils/globalflags"
 	"github.com/openshift/osdctl/pkg/k8s"
 	"github.com/openshift/osdctl/pkg/provider/aws"
-	"github.com/openshift/osdctl/pkg/utils"
 )
 
 func init() {
@@ -92,6 +92,7 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 	rootCmd.AddCommand(alerts.NewCmdAlerts())
 	rootCmd.AddCommand(cloudtrail.NewCloudtrailCmd())
 	rootCmd.AddCommand(cluster.NewCmdCluster(streams, kubeClient, globalOpts))
+	rootCmd.AddCommand(config.NewCmdConfig())
 	rootCmd.AddCommand(env.NewCmdEnv())
 	rootCmd.AddCommand(hive.NewCmdHive(streams, kubeClient))
 	rootCmd.AddCommand(jira.Cmd)
@@ -100,6 +101,7 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 	rootCmd.AddCommand(hcp.NewCmdHCP())
 	rootCmd.AddCommand(network.NewCmdNetwork(streams, kubeClient))
 	rootCmd.AddCommand(org.NewCmdOrg())
+	rootCmd.AddCommand(pagerduty.NewCmdPagerDuty())
 	rootCmd.AddCommand(promote.NewCmdPromote())
 	rootCmd.AddCommand(servicelog.NewCmdServiceLog())
 	rootCmd.AddCommand(setup.NewCmdSetup())
@@ -114,8 +116,8 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 	// because there is no way to hook a function to the --version flag in cobra.
 	rootCmd.AddCommand(versionCmd)
 
-	// Add upgradeCmd for upgrading the currently running executable in-place.
-	rootCmd.AddCommand(upgradeCmd)
+	// Add upgrade for upgrading the currently running executable in-place.
+	rootCmd.AddCommand(newCmdUpgrade())
 
 	return rootCmd
 }
@@ -136,20 +138,3 @@ func canCommandSkipVersionCheck(commandName string) bool {
 func getSkipVersionCommands() []string {
 	return []string{"upgrade", "version"}
 }
-
-func versionCheck() {
-	latestVersion, err := utils.GetLatestVersion()
-	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, "WARN: Unable to verify that osdctl is running under the latest released version. Error trying to reach GitHub:")
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		_, _ = fmt.Fprintln(os.Stderr, "Please be aware that you are possibly running an outdated or unreleased version.")
-	}
-
-	if utils.Version != strings.TrimPrefix(latestVersion, "v") {
-		_, _ = fmt.Fprintf(os.Stderr, "WARN: The current version (%s) is different than the latest released version (%s). It is recommended that you update to the latest released version to ensure that no known bugs or issues are hit.\n", utils.Version, latestVersion)
-
-		if !utils.ConfirmPrompt() {
-			os.Exit(0)
-		}
-	}
-}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewCmdConfig implements the config command
+func NewCmdConfig() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Get, set and check the osdctl configuration",
+		Long: fmt.Sprintf(`Get, set and check the osdctl configuration file ~/.config/%s.
+
+Use "osdctl setup" to configure osdctl for the first time.`, osdctlConfig.ConfigFileName),
+		Args: cobra.NoArgs,
+	}
+
+	configCmd.AddCommand(
+		newCmdGet(),
+		newCmdSet(),
+		newCmdValidate(),
+		newCmdDoctor(),
+	)
+
+	return configCmd
+}
+
+func newCmdGet() *cobra.Command {
+	var showSecrets bool
+	getCmd := &cobra.Command{
+		Use:   "get [key]",
+		Short: "Print the value of a config key, or of all keys",
+		Example: `
+  # Print all keys
+  osdctl config get
+
+  # Print the PagerDuty token
+  osdctl config get pd_user_token --show-secrets`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := osdctlConfig.ReadConfig()
+			if err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				key, err := lookupKey(args[0])
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), key.Format(v.Get(key.Name), showSecrets))
+				return err
+			}
+			return printConfig(cmd.OutOrStdout(), v, showSecrets)
+		},
+	}
+	getCmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Print tokens instead of masking them")
+	return getCmd
+}
+
+func newCmdSet() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Validate and set the value of a config key",
+		Long: `Validate and set the value of a config key. List values are comma-separated.
+
+Keys holding nested values, like cloudtrail_cmd_lists, have to be edited in the config file.`,
+		Example: `
+  # Set the PagerDuty teams to search for incidents
+  osdctl config set team_ids PXXXXXX,PYYYYYY`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := lookupKey(args[0])
+			if err != nil {
+				return err
+			}
+			value, err := key.Parse(args[1])
+			if err != nil {
+				return err
+			}
+			if key.Name == osdctlConfig.HiveOCMURLKey {
+				if _, err := utils.ValidateAndResolveOcmUrl(args[1]); err != nil {
+					return fmt.Errorf("%s: %w", key.Name, err)
+				}
+			}
+
+			v, err := osdctlConfig.ReadConfig()
+			if err != nil {
+				return err
+			}
+			v.Set(key.Name, value)
+			if err := v.WriteConfig(); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s set to %s\n", key.Name, key.Format(value, false))
+			return err
+		},
+	}
+}
+
+func newCmdValidate() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the config file against the schema of every key osdctl reads",
+		Long: fmt.Sprintf(`Check the config file ~/.config/%s against the schema of every key osdctl reads, and the
+profiles of ~/%s. Fails if a required key is missing or a value is invalid, unknown keys are reported as warnings.`,
+			osdctlConfig.ConfigFileName, osdctlConfig.EnvConfigFileName),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := osdctlConfig.ReadConfig()
+			if err != nil {
+				return err
+			}
+			envCfg, err := osdctlConfig.ReadEnvConfig()
+			if err != nil {
+				return err
+			}
+			problems := validateConfig(v, envCfg)
+			return reportProblems(cmd.OutOrStdout(), problems)
+		},
+	}
+}
+
+// validateConfig checks the osdctl config file and the env profiles, adding the checks the schema can't
+// do on its own without importing utils
+func validateConfig(v *viper.Viper, envCfg osdctlConfig.EnvConfig) []osdctlConfig.Problem {
+	problems := osdctlConfig.Validate(v)
+
+	if hiveOCMURL := v.GetString(osdctlConfig.HiveOCMURLKey); hiveOCMURL != "" {
+		if _, err := utils.ValidateAndResolveOcmUrl(hiveOCMURL); err != nil {
+			problems = append(problems, osdctlConfig.Problem{Key: osdctlConfig.HiveOCMURLKey, Message: err.Error()})
+		}
+	}
+
+	names := make([]string, 0, len(envCfg.Profiles))
+	for name := range envCfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ocmEnv := envCfg.Profiles[name].OcmEnv; ocmEnv != "" {
+			if _, err := utils.ValidateAndResolveOcmUrl(ocmEnv); err != nil {
+				problems = append(problems, osdctlConfig.Problem{Key: fmt.Sprintf("profiles.%s.ocmEnv", name), Message: err.Error()})
+			}
+		}
+	}
+
+	return problems
+}
+
+// reportProblems prints problems and returns an error if the config is invalid
+func reportProblems(w io.Writer, problems []osdctlConfig.Problem) error {
+	errorCount := 0
+	for _, problem := range problems {
+		level := "WARNING"
+		if !problem.Warning {
+			level = "ERROR"
+			errorCount++
+		}
+		fmt.Fprintf(w, "%s: %s\n", level, problem)
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("config is invalid: %d error(s)", errorCount)
+	}
+	fmt.Fprintln(w, "Config is valid")
+	return nil
+}
+
+func printConfig(w io.Writer, v *viper.Viper, showSecrets bool) error {
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"KEY", "VALUE", "DESCRIPTION"})
+	for _, key := range osdctlConfig.Keys {
+		p.AddRow([]string{key.Name, key.Format(v.Get(key.Name), showSecrets), key.Description})
+	}
+	return p.Flush()
+}
+
+func lookupKey(name string) (osdctlConfig.Key, error) {
+	key, ok := osdctlConfig.LookupKey(name)
+	if !ok {
+		return osdctlConfig.Key{}, fmt.Errorf("unknown key %q, run \"osdctl config get\" to list the keys", name)
+	}
+	return key, nil
+}
+
+func completeKeys(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(osdctlConfig.Keys))
+	for _, key := range osdctlConfig.Keys {
+		names = append(names, key.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// secretFromEnv returns the value of an environment variable, falling back to the config file
+func secretFromEnv(v *viper.Viper, envVar, key string) string {
+	if value := os.Getenv(envVar); value != "" {
+		return value
+	}
+	return v.GetString(key)
+}
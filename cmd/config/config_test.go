@@ -0,0 +1,63 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	v := viper.New()
+	v.Set(osdctlConfig.ProdJumproleAccountIDKey, "123456789012")
+	v.Set(osdctlConfig.StageJumproleAccountIDKey, "210987654321")
+	v.Set(osdctlConfig.AWSProxyKey, "http://proxy.example.com:3128")
+	v.Set(osdctlConfig.HiveOCMURLKey, "moon")
+
+	envCfg := osdctlConfig.EnvConfig{Profiles: map[string]osdctlConfig.EnvProfile{
+		"stage": {OcmEnv: "staging"},
+		"bad":   {OcmEnv: "mars"},
+	}}
+
+	problems := validateConfig(v, envCfg)
+	require.Len(t, problems, 2)
+	assert.Equal(t, osdctlConfig.HiveOCMURLKey, problems[0].Key)
+	assert.Contains(t, problems[0].Message, "invalid OCM_URL found: moon")
+	assert.Equal(t, "profiles.bad.ocmEnv", problems[1].Key)
+	assert.Contains(t, problems[1].Message, "invalid OCM_URL found: mars")
+}
+
+func TestReportProblems(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := reportProblems(out, []osdctlConfig.Problem{
+		{Key: "old_key", Message: "unknown key, osdctl does not use it", Warning: true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "WARNING: old_key: unknown key, osdctl does not use it\nConfig is valid\n", out.String())
+
+	out.Reset()
+	err = reportProblems(out, []osdctlConfig.Problem{
+		{Key: osdctlConfig.AWSProxyKey, Message: "invalid AWS proxy URL"},
+	})
+	assert.EqualError(t, err, "config is invalid: 1 error(s)")
+	assert.Equal(t, "ERROR: aws_proxy: invalid AWS proxy URL\n", out.String())
+}
+
+func TestPrintConfig(t *testing.T) {
+	v := viper.New()
+	v.Set(osdctlConfig.PdUserTokenKey, "abcdEFGHijklMNOPqrst")
+	v.Set(osdctlConfig.PdTeamIDsKey, []string{"PAAAAAA", "PBBBBBB"})
+
+	out := &bytes.Buffer{}
+	require.NoError(t, printConfig(out, v, false))
+	assert.Contains(t, out.String(), "ab********st")
+	assert.NotContains(t, out.String(), "abcdEFGHijklMNOPqrst")
+	assert.Contains(t, out.String(), "PAAAAAA,PBBBBBB")
+
+	out.Reset()
+	require.NoError(t, printConfig(out, v, true))
+	assert.Contains(t, out.String(), "abcdEFGHijklMNOPqrst")
+}
@@ -0,0 +1,305 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	checkOK      = "OK"
+	checkFailed  = "FAILED"
+	checkSkipped = "SKIPPED"
+
+	pagerDutyURL = "https://api.pagerduty.com"
+	gitLabURL    = "https://gitlab.cee.redhat.com"
+	awsSTSURL    = "https://sts.amazonaws.com"
+)
+
+type doctorOptions struct {
+	timeout time.Duration
+
+	// Base URLs and lookPath are overridden in tests
+	jiraURL      string
+	pagerDutyURL string
+	gitLabURL    string
+	awsSTSURL    string
+	lookPath     func(string) (string, error)
+}
+
+type checkResult struct {
+	integration string
+	status      string
+	detail      string
+}
+
+func newCmdDoctor() *cobra.Command {
+	ops := &doctorOptions{
+		jiraURL:      utils.JiraBaseURL,
+		pagerDutyURL: pagerDutyURL,
+		gitLabURL:    gitLabURL,
+		awsSTSURL:    awsSTSURL,
+		lookPath:     exec.LookPath,
+	}
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Validate the config and check connectivity of every configured integration",
+		Long: `Validate the config, then check that the configured credentials work for Jira, PagerDuty, Vault and GitLab,
+and that AWS is reachable through the AWS proxy. Integrations which are not configured are skipped.
+
+The Jira token and email can be set with the JIRA_API_TOKEN and JIRA_EMAIL environment variables, like for the jira commands.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := osdctlConfig.ReadConfig()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+
+			envCfg, err := osdctlConfig.ReadEnvConfig()
+			if err != nil {
+				return err
+			}
+			validateErr := reportProblems(out, validateConfig(v, envCfg))
+			fmt.Fprintln(out)
+
+			results := ops.runChecks(cmd.Context(), v)
+			if err := printResults(out, results); err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, result := range results {
+				if result.status == checkFailed {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return errors.Join(validateErr, fmt.Errorf("%d integration check(s) failed", failed))
+			}
+			return validateErr
+		},
+	}
+	doctorCmd.Flags().DurationVar(&ops.timeout, "timeout", 10*time.Second, "Timeout of each connectivity check")
+	return doctorCmd
+}
+
+// runChecks checks every integration concurrently, returning results in a stable order
+func (o *doctorOptions) runChecks(ctx context.Context, v *viper.Viper) []checkResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	checks := []func(context.Context, *viper.Viper) checkResult{
+		o.checkJira,
+		o.checkPagerDuty,
+		o.checkVault,
+		o.checkGitLab,
+		o.checkAWSProxy,
+	}
+
+	results := make([]checkResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check func(context.Context, *viper.Viper) checkResult) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, o.timeout)
+			defer cancel()
+			results[i] = check(checkCtx, v)
+		}(i, check)
+	}
+	wg.Wait()
+	return results
+}
+
+func (o *doctorOptions) checkJira(ctx context.Context, v *viper.Viper) checkResult {
+	result := checkResult{integration: "Jira"}
+	token := secretFromEnv(v, "JIRA_API_TOKEN", osdctlConfig.JiraTokenKey)
+	email := secretFromEnv(v, "JIRA_EMAIL", osdctlConfig.JiraEmailKey)
+	if token == "" {
+		return result.skipped(osdctlConfig.JiraTokenKey)
+	}
+	if email == "" {
+		return result.failed(fmt.Errorf("%s is not set", osdctlConfig.JiraEmailKey))
+	}
+
+	var user struct {
+		EmailAddress string `json:"emailAddress"`
+	}
+	err := o.getJSON(ctx, o.jiraURL+"/rest/api/2/myself", func(req *http.Request) {
+		req.SetBasicAuth(email, token)
+	}, &user)
+	if err != nil {
+		return result.failed(err)
+	}
+	return result.ok("authenticated as " + user.EmailAddress)
+}
+
+func (o *doctorOptions) checkPagerDuty(ctx context.Context, v *viper.Viper) checkResult {
+	result := checkResult{integration: "PagerDuty"}
+	authorization := ""
+	if token := v.GetString(osdctlConfig.PdOauthTokenKey); token != "" {
+		authorization = "Bearer " + token
+	} else if token := v.GetString(osdctlConfig.PdUserTokenKey); token != "" {
+		authorization = "Token token=" + token
+	} else {
+		return result.skipped(osdctlConfig.PdUserTokenKey)
+	}
+
+	var me struct {
+		User struct {
+			Email string `json:"email"`
+		} `json:"user"`
+	}
+	err := o.getJSON(ctx, o.pagerDutyURL+"/users/me", func(req *http.Request) {
+		req.Header.Set("Authorization", authorization)
+		req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	}, &me)
+	if err != nil {
+		return result.failed(err)
+	}
+	return result.ok("authenticated as " + me.User.Email)
+}
+
+func (o *doctorOptions) checkVault(ctx context.Context, v *viper.Viper) checkResult {
+	result := checkResult{integration: "Vault"}
+	address := strings.TrimSuffix(v.GetString(osdctlConfig.VaultAddressKey), "/")
+	if address == "" {
+		return result.skipped(osdctlConfig.VaultAddressKey)
+	}
+	if _, err := o.lookPath("vault"); err != nil {
+		return result.failed(errors.New("vault CLI not found in PATH"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address+"/v1/sys/health", nil)
+	if err != nil {
+		return result.failed(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return result.failed(err)
+	}
+	defer resp.Body.Close()
+
+	// See https://developer.hashicorp.com/vault/api-docs/system/health for the status codes
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusTooManyRequests, 472, 473:
+		return result.ok(address + " is healthy")
+	case http.StatusServiceUnavailable:
+		return result.failed(errors.New(address + " is sealed"))
+	default:
+		return result.failed(fmt.Errorf("%s returned HTTP %d", address, resp.StatusCode))
+	}
+}
+
+func (o *doctorOptions) checkGitLab(ctx context.Context, v *viper.Viper) checkResult {
+	result := checkResult{integration: "GitLab"}
+	token := v.GetString(osdctlConfig.GitLabTokenKey)
+	if token == "" {
+		return result.skipped(osdctlConfig.GitLabTokenKey)
+	}
+
+	var user struct {
+		Username string `json:"username"`
+	}
+	err := o.getJSON(ctx, o.gitLabURL+"/api/v4/user", func(req *http.Request) {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}, &user)
+	if err != nil {
+		return result.failed(err)
+	}
+	return result.ok("authenticated as " + user.Username)
+}
+
+func (o *doctorOptions) checkAWSProxy(ctx context.Context, v *viper.Viper) checkResult {
+	result := checkResult{integration: "AWS proxy"}
+	proxy := v.GetString(osdctlConfig.AWSProxyKey)
+	if proxy == "" {
+		return result.skipped(osdctlConfig.AWSProxyKey)
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return result.failed(err)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)},
+		// Any response from AWS means the proxy works
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.awsSTSURL, nil)
+	if err != nil {
+		return result.failed(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return result.failed(err)
+	}
+	defer resp.Body.Close()
+	return result.ok(fmt.Sprintf("reached %s through %s", o.awsSTSURL, proxy))
+}
+
+// getJSON sends an authenticated GET request, failing on any non 2xx response
+func (o *doctorOptions) getJSON(ctx context.Context, endpoint string, authenticate func(*http.Request), target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	authenticate(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return fmt.Errorf("credentials rejected, HTTP %d", resp.StatusCode)
+		}
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, target)
+}
+
+func (r checkResult) ok(detail string) checkResult {
+	r.status, r.detail = checkOK, detail
+	return r
+}
+
+func (r checkResult) failed(err error) checkResult {
+	r.status, r.detail = checkFailed, err.Error()
+	return r
+}
+
+func (r checkResult) skipped(key string) checkResult {
+	r.status, r.detail = checkSkipped, key+" is not set"
+	return r
+}
+
+func printResults(w io.Writer, results []checkResult) error {
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"INTEGRATION", "STATUS", "DETAIL"})
+	for _, result := range results {
+		p.AddRow([]string{result.integration, result.status, result.detail})
+	}
+	return p.Flush()
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunChecks(t *testing.T) {
+	t.Setenv("JIRA_API_TOKEN", "")
+	t.Setenv("JIRA_EMAIL", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/myself":
+			if user, password, _ := r.BasicAuth(); user != "sre@example.com" || password != "jira-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"emailAddress":"sre@example.com"}`))
+		case "/users/me":
+			if r.Header.Get("Authorization") != "Token token=pd-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"user":{"email":"sre@example.com"}}`))
+		case "/api/v4/user":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/v1/sys/health":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			// Requests proxied to AWS
+			w.WriteHeader(http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	o := &doctorOptions{
+		timeout:      5 * time.Second,
+		jiraURL:      server.URL,
+		pagerDutyURL: server.URL,
+		gitLabURL:    server.URL,
+		awsSTSURL:    "http://sts.amazonaws.example.com",
+		lookPath:     func(string) (string, error) { return "/usr/bin/vault", nil },
+	}
+
+	v := viper.New()
+	v.Set(osdctlConfig.JiraTokenKey, "jira-token")
+	v.Set(osdctlConfig.JiraEmailKey, "sre@example.com")
+	v.Set(osdctlConfig.PdUserTokenKey, "pd-token")
+	v.Set(osdctlConfig.GitLabTokenKey, "bad-token")
+	v.Set(osdctlConfig.VaultAddressKey, server.URL+"/")
+	v.Set(osdctlConfig.AWSProxyKey, server.URL)
+
+	assert.Equal(t, []checkResult{
+		{integration: "Jira", status: checkOK, detail: "authenticated as sre@example.com"},
+		{integration: "PagerDuty", status: checkOK, detail: "authenticated as sre@example.com"},
+		{integration: "Vault", status: checkFailed, detail: server.URL + " is sealed"},
+		{integration: "GitLab", status: checkFailed, detail: "credentials rejected, HTTP 401"},
+		{integration: "AWS proxy", status: checkOK, detail: "reached http://sts.amazonaws.example.com through " + server.URL},
+	}, o.runChecks(context.Background(), v))
+}
+
+func TestRunChecksUnconfigured(t *testing.T) {
+	t.Setenv("JIRA_API_TOKEN", "")
+	t.Setenv("JIRA_EMAIL", "")
+
+	o := &doctorOptions{
+		timeout:  time.Second,
+		lookPath: func(string) (string, error) { return "", errors.New("not found") },
+	}
+
+	v := viper.New()
+	v.Set(osdctlConfig.VaultAddressKey, "https://vault.example.com")
+
+	assert.Equal(t, []checkResult{
+		{integration: "Jira", status: checkSkipped, detail: "jira_token is not set"},
+		{integration: "PagerDuty", status: checkSkipped, detail: "pd_user_token is not set"},
+		{integration: "Vault", status: checkFailed, detail: "vault CLI not found in PATH"},
+		{integration: "GitLab", status: checkSkipped, detail: "gitlab_access is not set"},
+		{integration: "AWS proxy", status: checkSkipped, detail: "aws_proxy is not set"},
+	}, o.runChecks(context.Background(), v))
+}
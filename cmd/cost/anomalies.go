@@ -0,0 +1,248 @@
+package cost
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	outputflag "github.com/openshift/osdctl/cmd/getoutput"
+	"github.com/openshift/osdctl/internal/utils/globalflags"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// anomaliesCmd represents the anomalies command
+func newCmdAnomalies(streams genericclioptions.IOStreams, globalOpts *globalflags.GlobalOptions) *cobra.Command {
+	ops := newAnomaliesOptions(streams, globalOpts)
+	anomaliesCmd := &cobra.Command{
+		Use:   "anomalies",
+		Short: "List the accounts whose daily cost deviates from their baseline",
+		Long: `List the accounts whose average daily cost over the last --days deviates from their average daily
+cost over the --baseline-days before by more than --threshold percent. Today is excluded as its cost isn't final.
+
+Accounts spending less than --min-cost a day, both in the baseline and recently, are ignored. Accounts without
+cost in the baseline are reported as new spend.`,
+		Example: `  # Accounts of an OU whose cost of yesterday is 50% above or below their last two weeks
+  osdctl cost anomalies --ou ou-abcd-12345678
+
+  # Compare the last 3 days to the 4 weeks before, with a 100% threshold, as csv
+  osdctl cost anomalies --ou ou-abcd-12345678 --days 3 --baseline-days 28 --threshold 100 --csv`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.checkArgs(cmd, args))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+	anomaliesCmd.Flags().StringVar(&ops.ou, "ou", "", "set OU ID, all accounts under it are checked")
+	anomaliesCmd.Flags().StringArrayVar(&ops.accounts, "account", nil, "set account ID. Can be repeated")
+	anomaliesCmd.Flags().IntVar(&ops.days, "days", 1, "number of recent days compared to the baseline")
+	anomaliesCmd.Flags().IntVar(&ops.baselineDays, "baseline-days", 14, "number of days of the baseline, before the recent days")
+	anomaliesCmd.Flags().Float64Var(&ops.threshold, "threshold", 50, "deviation from the baseline in percent above which an account is reported")
+	anomaliesCmd.Flags().Float64Var(&ops.minCost, "min-cost", 10, "daily cost below which accounts are ignored")
+	anomaliesCmd.Flags().BoolVar(&ops.csv, "csv", false, "output result as csv")
+
+	return anomaliesCmd
+}
+
+// Store flag options for anomalies command
+type anomaliesOptions struct {
+	ou           string
+	accounts     []string
+	days         int
+	baselineDays int
+	threshold    float64
+	minCost      float64
+	csv          bool
+	output       string
+
+	now func() time.Time
+
+	genericclioptions.IOStreams
+	GlobalOptions *globalflags.GlobalOptions
+}
+
+func newAnomaliesOptions(streams genericclioptions.IOStreams, globalOpts *globalflags.GlobalOptions) *anomaliesOptions {
+	return &anomaliesOptions{
+		now:           time.Now,
+		IOStreams:     streams,
+		GlobalOptions: globalOpts,
+	}
+}
+
+func (o *anomaliesOptions) checkArgs(cmd *cobra.Command, _ []string) error {
+	if o.ou == "" && len(o.accounts) == 0 {
+		return cmdutil.UsageErrorf(cmd, "Please provide OU or accounts")
+	}
+	if o.days < 1 || o.baselineDays < 1 {
+		return cmdutil.UsageErrorf(cmd, "--days and --baseline-days must be at least 1")
+	}
+	if o.threshold <= 0 {
+		return cmdutil.UsageErrorf(cmd, "--threshold must be positive")
+	}
+	if o.minCost < 0 {
+		return cmdutil.UsageErrorf(cmd, "--min-cost can't be negative")
+	}
+
+	o.output = o.GlobalOptions.Output
+
+	return nil
+}
+
+type costAnomaly struct {
+	AccountID     string          `json:"accountId" yaml:"accountId"`
+	BaselineDaily decimal.Decimal `json:"baselineDaily" yaml:"baselineDaily"`
+	CurrentDaily  decimal.Decimal `json:"currentDaily" yaml:"currentDaily"`
+	// ChangePercent is nil for accounts without cost in the baseline
+	ChangePercent *decimal.Decimal `json:"changePercent" yaml:"changePercent"`
+}
+
+func (a costAnomaly) change() string {
+	if a.ChangePercent == nil {
+		return "new"
+	}
+	sign := ""
+	if a.ChangePercent.IsPositive() {
+		sign = "+"
+	}
+	return sign + a.ChangePercent.StringFixed(0) + "%"
+}
+
+type costAnomaliesResponse struct {
+	BaselineStart    string        `json:"baselineStart" yaml:"baselineStart"`
+	Start            string        `json:"start" yaml:"start"`
+	End              string        `json:"end" yaml:"end"`
+	ThresholdPercent float64       `json:"thresholdPercent" yaml:"thresholdPercent"`
+	Unit             string        `json:"unit" yaml:"unit"`
+	Anomalies        []costAnomaly `json:"anomalies" yaml:"anomalies"`
+}
+
+func (f costAnomaliesResponse) String() string {
+	if len(f.Anomalies) == 0 {
+		return fmt.Sprintf("No account deviates by more than %g%% from its baseline", f.ThresholdPercent)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Daily cost in %s from %s to %s compared to %s to %s\n", f.Unit, f.Start, f.End, f.BaselineStart, f.Start)
+	p := printer.NewTablePrinter(&b, 12, 1, 3, ' ')
+	p.AddRow([]string{"ACCOUNT", "BASELINE", "CURRENT", "CHANGE"})
+	for _, anomaly := range f.Anomalies {
+		p.AddRow([]string{anomaly.AccountID, anomaly.BaselineDaily.StringFixed(2), anomaly.CurrentDaily.StringFixed(2), anomaly.change()})
+	}
+	_ = p.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (o *anomaliesOptions) run() error {
+	awsClient, err := opsCost.initAWSClients()
+	if err != nil {
+		return err
+	}
+
+	var OU *organizationTypes.OrganizationalUnit
+	if o.ou != "" {
+		OU = getOU(awsClient, o.ou)
+	}
+	resp, err := o.getAnomalies(awsClient, OU)
+	if err != nil {
+		return err
+	}
+
+	if o.csv {
+		return writeAnomaliesCSV(o.Out, resp)
+	}
+	return outputflag.PrintResponse(o.output, resp)
+}
+
+func (o *anomaliesOptions) getAnomalies(awsClient awsprovider.Client, OU *organizationTypes.OrganizationalUnit) (*costAnomaliesResponse, error) {
+	accounts, err := getScopeAccounts(awsClient, OU, o.accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	now := o.now().UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, 0, -o.days)
+	baselineStart := start.AddDate(0, 0, -o.baselineDays)
+
+	table, err := getCostTable(awsClient, accounts, baselineStart, end, costExplorerTypes.GranularityDaily, costExplorerTypes.DimensionLinkedAccount, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &costAnomaliesResponse{
+		BaselineStart:    baselineStart.Format(dateLayout),
+		Start:            start.Format(dateLayout),
+		End:              end.Format(dateLayout),
+		ThresholdPercent: o.threshold,
+		Unit:             table.Unit,
+		Anomalies:        detectAnomalies(table, o.baselineDays, decimal.NewFromFloat(o.threshold), decimal.NewFromFloat(o.minCost)),
+	}, nil
+}
+
+// detectAnomalies compares the average daily cost after the first baselineDays of the table to the
+// average of the first baselineDays, largest deviations first
+func detectAnomalies(table *costTable, baselineDays int, threshold decimal.Decimal, minCost decimal.Decimal) []costAnomaly {
+	anomalies := []costAnomaly{}
+	hundred := decimal.NewFromInt(100)
+
+	for account, costs := range table.Costs {
+		baseline := average(costs[:baselineDays])
+		current := average(costs[baselineDays:])
+		if baseline.LessThan(minCost) && current.LessThan(minCost) {
+			continue
+		}
+
+		anomaly := costAnomaly{AccountID: account, BaselineDaily: baseline, CurrentDaily: current}
+		if !baseline.IsZero() {
+			change := current.Sub(baseline).Div(baseline).Mul(hundred)
+			if change.Abs().LessThanOrEqual(threshold) {
+				continue
+			}
+			anomaly.ChangePercent = &change
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+
+	slices.SortFunc(anomalies, func(a, b costAnomaly) int {
+		deltaA := a.CurrentDaily.Sub(a.BaselineDaily).Abs()
+		deltaB := b.CurrentDaily.Sub(b.BaselineDaily).Abs()
+		if c := deltaB.Cmp(deltaA); c != 0 {
+			return c
+		}
+		return strings.Compare(a.AccountID, b.AccountID)
+	})
+	return anomalies
+}
+
+func average(costs []decimal.Decimal) decimal.Decimal {
+	if len(costs) == 0 {
+		return decimal.Zero
+	}
+	return decimal.Sum(decimal.Zero, costs...).Div(decimal.NewFromInt(int64(len(costs))))
+}
+
+func writeAnomaliesCSV(w io.Writer, resp *costAnomaliesResponse) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"account", "baseline_daily", "current_daily", "change_percent", "unit"}); err != nil {
+		return err
+	}
+	for _, anomaly := range resp.Anomalies {
+		change := ""
+		if anomaly.ChangePercent != nil {
+			change = anomaly.ChangePercent.StringFixed(2)
+		}
+		if err := writer.Write([]string{anomaly.AccountID, anomaly.BaselineDaily.StringFixed(2), anomaly.CurrentDaily.StringFixed(2), change, resp.Unit}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
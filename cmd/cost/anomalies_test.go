@@ -0,0 +1,83 @@
+package cost
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	table := &costTable{
+		Periods: []string{"2026-01-01", "2026-01-02", "2026-01-03"},
+		Costs: map[string][]decimal.Decimal{
+			"runaway": decimals("100", "100", "400"),
+			"stable":  decimals("100", "120", "130"),
+			"dropped": decimals("50", "50", "10"),
+			"new":     decimals("0", "0", "25"),
+			"tiny":    decimals("1", "1", "9"),
+		},
+	}
+
+	anomalies := detectAnomalies(table, 2, decimal.NewFromInt(50), decimal.NewFromInt(10))
+	require.Len(t, anomalies, 3)
+
+	assert.Equal(t, "runaway", anomalies[0].AccountID)
+	assert.Equal(t, "100.00", anomalies[0].BaselineDaily.StringFixed(2))
+	assert.Equal(t, "400.00", anomalies[0].CurrentDaily.StringFixed(2))
+	assert.Equal(t, "+300%", anomalies[0].change())
+
+	assert.Equal(t, "dropped", anomalies[1].AccountID)
+	assert.Equal(t, "-80%", anomalies[1].change())
+
+	assert.Equal(t, "new", anomalies[2].AccountID)
+	assert.Nil(t, anomalies[2].ChangePercent)
+	assert.Equal(t, "new", anomalies[2].change())
+}
+
+func TestGetAnomalies(t *testing.T) {
+	mockClient := mock.NewMockClient(gomock.NewController(t))
+	mockClient.EXPECT().GetCostAndUsage(gomock.Any()).DoAndReturn(func(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+		assert.Equal(t, []string{"111", "222"}, input.Filter.Dimensions.Values)
+		assert.Equal(t, costExplorerTypes.GranularityDaily, input.Granularity)
+		assert.Equal(t, "2026-01-07", *input.TimePeriod.Start)
+		// Today isn't final and is excluded
+		assert.Equal(t, "2026-01-10", *input.TimePeriod.End)
+		return &costexplorer.GetCostAndUsageOutput{ResultsByTime: []costExplorerTypes.ResultByTime{
+			costResult("2026-01-07", map[string]string{"111": "20", "222": "20"}),
+			costResult("2026-01-08", map[string]string{"111": "20", "222": "20"}),
+			costResult("2026-01-09", map[string]string{"111": "90", "222": "21"}),
+		}}, nil
+	})
+
+	o := &anomaliesOptions{
+		accounts:     []string{"222", "111"},
+		days:         1,
+		baselineDays: 2,
+		threshold:    50,
+		minCost:      10,
+		now:          func() time.Time { return time.Date(2026, 1, 10, 15, 0, 0, 0, time.UTC) },
+	}
+	resp, err := o.getAnomalies(mockClient, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-01-07", resp.BaselineStart)
+	assert.Equal(t, "2026-01-09", resp.Start)
+	assert.Equal(t, "2026-01-10", resp.End)
+	require.Len(t, resp.Anomalies, 1)
+	assert.Equal(t, "111", resp.Anomalies[0].AccountID)
+
+	var out bytes.Buffer
+	require.NoError(t, writeAnomaliesCSV(&out, resp))
+	assert.Equal(t, "account,baseline_daily,current_daily,change_percent,unit\n111,20.00,90.00,350.00,USD\n", out.String())
+
+	resp.Anomalies = nil
+	assert.Equal(t, "No account deviates by more than 50% from its baseline", resp.String())
+}
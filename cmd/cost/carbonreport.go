@@ -8,10 +8,13 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	outputflag "github.com/openshift/osdctl/cmd/getoutput"
 	"github.com/openshift/osdctl/internal/utils/globalflags"
 	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/spf13/cobra"
@@ -21,6 +24,8 @@ import (
 
 const (
 	expectedAccountName = "rh-control"
+	ccftBucketName      = "rhcontrol-ccft-reports"
+	carbonAccountColumn = "usage_account_id"
 )
 
 var (
@@ -35,6 +40,9 @@ var (
 type carbonReportOptions struct {
 	usagePeriod string
 	account     string
+	ou          string
+	markdown    bool
+	output      string
 
 	genericclioptions.IOStreams
 	GlobalOptions *globalflags.GlobalOptions
@@ -47,7 +55,7 @@ func newCarbonReportOptions(streams genericclioptions.IOStreams, globalOpts *glo
 	}
 }
 
-// validateUsagePeriod validates that the usage period is in YYYY or YYYY-MM format
+// validateUsagePeriod validates that the usage periods are in YYYY or YYYY-MM format
 func (o *carbonReportOptions) validateUsagePeriod() error {
 	if o.usagePeriod == "" {
 		return fmt.Errorf("usage period is required")
@@ -58,15 +66,26 @@ func (o *carbonReportOptions) validateUsagePeriod() error {
 	// Regex for YYYY-MM format (year and month)
 	yearMonthRegex := regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])$`)
 
-	if yearRegex.MatchString(o.usagePeriod) {
-		return nil
+	for _, usagePeriod := range o.usagePeriods() {
+		if !yearRegex.MatchString(usagePeriod) && !yearMonthRegex.MatchString(usagePeriod) {
+			return fmt.Errorf("invalid usage period format '%s'. Expected format: YYYY or YYYY-MM", usagePeriod)
+		}
 	}
 
-	if yearMonthRegex.MatchString(o.usagePeriod) {
+	return nil
+}
+
+// usagePeriods returns the comma separated usage periods
+func (o *carbonReportOptions) usagePeriods() []string {
+	return strings.Split(o.usagePeriod, ",")
+}
+
+// validateScope validates the account, which is only optional with an OU
+func (o *carbonReportOptions) validateScope() error {
+	if o.ou != "" && o.account == "" {
 		return nil
 	}
-
-	return fmt.Errorf("invalid usage period format '%s'. Expected format: YYYY or YYYY-MM", o.usagePeriod)
+	return o.validateAccount()
 }
 
 // validateAccount validates that the account is a number with at least 12 digits
@@ -119,12 +138,11 @@ func createAWSClientWithOptions(opts *costOptions) (awsprovider.Client, error) {
 
 // getUsagePeriodDirectories retrieves S3 directories matching the usage period pattern
 func getUsagePeriodDirectories(awsClient awsprovider.Client, usagePeriod string) ([]string, error) {
-	bucketName := "rhcontrol-ccft-reports"
 	basePath := "reports/carbon-emissions/data/carbon_model_version=v3.0.0/"
 	delimiter := "/"
 
 	result, err := awsClient.ListObjectsV2(&s3.ListObjectsV2Input{
-		Bucket:    aws.String(bucketName),
+		Bucket:    aws.String(ccftBucketName),
 		Prefix:    aws.String(basePath),
 		Delimiter: aws.String(delimiter),
 	})
@@ -176,6 +194,17 @@ func getUsagePeriodDirectories(awsClient awsprovider.Client, usagePeriod string)
 
 // processCarbonData downloads and processes carbon emissions data for a specific usage period directory
 func processCarbonData(awsClient awsprovider.Client, bucketName, usagePeriodDir, accountID string) ([][]string, []string, error) {
+	rows, header, err := readCarbonData(awsClient, bucketName, usagePeriodDir, map[string]bool{accountID: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	filteredRows, filteredHeader := excludeCarbonColumns(rows, header, false)
+	return filteredRows, filteredHeader, nil
+}
+
+// readCarbonData downloads the carbon emissions data of a usage period directory and returns the rows of the
+// given accounts with all their columns
+func readCarbonData(awsClient awsprovider.Client, bucketName, usagePeriodDir string, accounts map[string]bool) ([][]string, []string, error) {
 	basePath := "reports/carbon-emissions/data/carbon_model_version=v3.0.0/"
 	prefix := basePath + usagePeriodDir + "/"
 
@@ -227,35 +256,14 @@ func processCarbonData(awsClient awsprovider.Client, bucketName, usagePeriodDir,
 		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
 	}
 
-	// Find the usage_account_id column index and identify columns to exclude
-	accountIDCol := -1
-	var excludeIndices []int
-	var filteredHeader []string
-
-	for i, col := range header {
-		if col == "usage_account_id" {
-			accountIDCol = i
-		}
-		// Check if this column should be excluded
-		if excludedColumns[col] {
-			excludeIndices = append(excludeIndices, i)
-		} else {
-			filteredHeader = append(filteredHeader, col)
-		}
-	}
-
+	// Find the usage_account_id column index
+	accountIDCol := slices.Index(header, carbonAccountColumn)
 	if accountIDCol == -1 {
 		return nil, nil, fmt.Errorf("usage_account_id column not found in CSV")
 	}
 
-	// Create a map for quick lookup of excluded indices
-	excludeMap := make(map[int]bool)
-	for _, idx := range excludeIndices {
-		excludeMap[idx] = true
-	}
-
-	// Read and filter rows
-	var filteredRows [][]string
+	// Read and filter rows by account ID
+	var rows [][]string
 	for {
 		row, err := csvReader.Read()
 		if err == io.EOF {
@@ -265,20 +273,38 @@ func processCarbonData(awsClient awsprovider.Client, bucketName, usagePeriodDir,
 			return nil, nil, fmt.Errorf("failed to read CSV row: %w", err)
 		}
 
-		// Filter by account ID
-		if accountIDCol < len(row) && row[accountIDCol] == accountID {
-			// Filter out excluded columns
-			var filteredRow []string
-			for i, val := range row {
-				if !excludeMap[i] {
-					filteredRow = append(filteredRow, val)
-				}
-			}
-			filteredRows = append(filteredRows, filteredRow)
+		if accountIDCol < len(row) && accounts[row[accountIDCol]] {
+			rows = append(rows, row)
 		}
 	}
 
-	return filteredRows, filteredHeader, nil
+	return rows, header, nil
+}
+
+// excludeCarbonColumns removes the excludedColumns from the rows and the header, but the account column when
+// keepAccount is set, which tells the rows of several accounts apart
+func excludeCarbonColumns(rows [][]string, header []string, keepAccount bool) ([][]string, []string) {
+	var filteredHeader []string
+	excludeMap := make(map[int]bool)
+	for i, col := range header {
+		if excludedColumns[col] && !(keepAccount && col == carbonAccountColumn) {
+			excludeMap[i] = true
+		} else {
+			filteredHeader = append(filteredHeader, col)
+		}
+	}
+
+	var filteredRows [][]string
+	for _, row := range rows {
+		var filteredRow []string
+		for i, val := range row {
+			if !excludeMap[i] {
+				filteredRow = append(filteredRow, val)
+			}
+		}
+		filteredRows = append(filteredRows, filteredRow)
+	}
+	return filteredRows, filteredHeader
 }
 
 // newCmdCarbonReport represents the carbon-report command
@@ -286,69 +312,139 @@ func newCmdCarbonReport(streams genericclioptions.IOStreams, globalOpts *globalf
 	ops := newCarbonReportOptions(streams, globalOpts)
 	carbonReportCmd := &cobra.Command{
 		Use:   "carbon-report",
-		Short: "Generate carbon emissions report csv to stdout for a given AWS Account and Usage Period",
+		Short: "Generate carbon emissions report csv or summary to stdout for AWS Accounts and Usage Periods",
+		Long: `Generate carbon emissions report csv to stdout for a given AWS Account and Usage Period.
+
+With --ou, the report covers all accounts under the OU, recursively, and --account is optional. The csv rows then
+keep their ` + carbonAccountColumn + ` column. Several usage periods can be given separated by commas.
+
+With -o json or -o yaml, or --markdown, a summary of the emissions is printed instead of the csv rows, with the
+market-based and location-based emissions in total and broken down by usage period, account, service and region.`,
+		Example: `  # Emissions of an account in 2024 as csv
+  osdctl cost carbon-report --account 123456789012 --usage-period 2024
+
+  # Summary of the emissions of an OU over two quarters, for sustainability reporting
+  osdctl cost carbon-report --ou ou-abcd-12345678 --usage-period 2024-01,2024-02,2024-03,2024-04,2024-05,2024-06 --markdown`,
 		Run: func(cmd *cobra.Command, args []string) {
 			// Validate usage period
 			cmdutil.CheckErr(ops.validateUsagePeriod())
 
 			// Validate account
-			cmdutil.CheckErr(ops.validateAccount())
+			cmdutil.CheckErr(ops.validateScope())
 
-			awsClient, err := CreateAWSClient()
-			cmdutil.CheckErr(err)
+			ops.output = ops.GlobalOptions.Output
+			cmdutil.CheckErr(ops.run())
+		},
+	}
 
-			// Get usage period directories from S3
-			directories, err := getUsagePeriodDirectories(awsClient, ops.usagePeriod)
-			cmdutil.CheckErr(err)
+	carbonReportCmd.Flags().StringVar(&ops.usagePeriod, "usage-period", "", "Usage period in YYYY or YYYY-MM format, comma separated for several periods")
+	carbonReportCmd.Flags().StringVar(&ops.account, "account", "", "AWS account number")
+	carbonReportCmd.Flags().StringVar(&ops.ou, "ou", "", "OU ID, all accounts under it are included")
+	carbonReportCmd.Flags().BoolVar(&ops.markdown, "markdown", false, "print a markdown summary instead of the csv rows")
 
-			if len(directories) == 0 {
-				log.Printf("No directories found for usage period: %s", ops.usagePeriod)
-				return
-			}
+	return carbonReportCmd
+}
 
-			bucketName := "rhcontrol-ccft-reports"
-			var allRows [][]string
-			var csvHeader []string
+func (o *carbonReportOptions) run() error {
+	awsClient, err := CreateAWSClient()
+	if err != nil {
+		return err
+	}
 
-			// Process each directory
-			for _, dir := range directories {
-				log.Printf("Processing usage period: %s", dir)
+	var OU *organizationTypes.OrganizationalUnit
+	if o.ou != "" {
+		OU = getOU(awsClient, o.ou)
+	}
+	var accounts []string
+	if o.account != "" {
+		accounts = append(accounts, o.account)
+	}
+	accounts, err = getScopeAccounts(awsClient, OU, accounts)
+	if err != nil {
+		return err
+	}
 
-				rows, header, err := processCarbonData(awsClient, bucketName, dir, ops.account)
-				if err != nil {
-					cmdutil.CheckErr(fmt.Errorf("error processing %s: %w", dir, err))
-				}
+	return o.report(awsClient, accounts)
+}
 
-				if len(csvHeader) == 0 {
-					csvHeader = header
-				}
+// report writes the csv rows or the summary of the carbon emissions of the accounts
+func (o *carbonReportOptions) report(awsClient awsprovider.Client, accounts []string) error {
+	// Get usage period directories from S3
+	var directories []string
+	for _, usagePeriod := range o.usagePeriods() {
+		dirs, err := getUsagePeriodDirectories(awsClient, usagePeriod)
+		if err != nil {
+			return err
+		}
+		directories = append(directories, dirs...)
+	}
+	slices.Sort(directories)
+	directories = slices.Compact(directories)
 
-				allRows = append(allRows, rows...)
-				log.Printf("Found %d rows for account %s in %s", len(rows), ops.account, dir)
-			}
+	if len(directories) == 0 {
+		log.Printf("No directories found for usage period: %s", o.usagePeriod)
+		return nil
+	}
 
-			// Write CSV to stdout
-			csvWriter := csv.NewWriter(os.Stdout)
-			defer csvWriter.Flush()
+	accountSet := map[string]bool{}
+	for _, account := range accounts {
+		accountSet[account] = true
+	}
 
-			// Write header
-			if err := csvWriter.Write(csvHeader); err != nil {
-				cmdutil.CheckErr(fmt.Errorf("failed to write CSV header: %w", err))
-			}
+	// The summary needs more columns than the csv rows, only build it when printed
+	summarize := o.markdown || o.output == "json" || o.output == "yaml"
+	summary := newCarbonSummary(accounts)
+	keepAccount := o.ou != "" || len(accounts) > 1
+	var allRows [][]string
+	var csvHeader []string
 
-			// Write all rows
-			for _, row := range allRows {
-				if err := csvWriter.Write(row); err != nil {
-					cmdutil.CheckErr(fmt.Errorf("failed to write CSV row: %w", err))
-				}
+	// Process each directory
+	for _, dir := range directories {
+		log.Printf("Processing usage period: %s", dir)
+
+		rows, header, err := readCarbonData(awsClient, ccftBucketName, dir, accountSet)
+		if err != nil {
+			return fmt.Errorf("error processing %s: %w", dir, err)
+		}
+		if summarize {
+			if err := summary.add(strings.TrimPrefix(dir, "usage_period="), header, rows); err != nil {
+				return fmt.Errorf("error summarizing %s: %w", dir, err)
 			}
+		}
 
-			log.Printf("Total rows exported: %d", len(allRows))
-		},
+		rows, header = excludeCarbonColumns(rows, header, keepAccount)
+		if len(csvHeader) == 0 {
+			csvHeader = header
+		}
+
+		allRows = append(allRows, rows...)
+		log.Printf("Found %d rows for %d account(s) in %s", len(rows), len(accounts), dir)
 	}
 
-	carbonReportCmd.Flags().StringVar(&ops.usagePeriod, "usage-period", "", "Usage period in YYYY or YYYY-MM format")
-	carbonReportCmd.Flags().StringVar(&ops.account, "account", "", "AWS account number")
+	if o.markdown {
+		_, err := fmt.Fprintln(o.Out, summary)
+		return err
+	}
+	if summarize {
+		return outputflag.PrintResponse(o.output, summary)
+	}
 
-	return carbonReportCmd
+	// Write CSV to stdout
+	csvWriter := csv.NewWriter(o.Out)
+
+	// Write header
+	if err := csvWriter.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write all rows
+	for _, row := range allRows {
+		if err := csvWriter.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+	csvWriter.Flush()
+
+	log.Printf("Total rows exported: %d", len(allRows))
+	return csvWriter.Error()
 }
@@ -651,3 +651,111 @@ func TestCreateAWSClientWithMock(t *testing.T) {
 		})
 	}
 }
+
+func TestCarbonReportAcrossAccountsAndPeriods(t *testing.T) {
+	basePath := "reports/carbon-emissions/data/carbon_model_version=v3.0.0/"
+	csvData := map[string]string{
+		"usage_period=2024-01": "usage_account_id,payer_account_id,product_code,region_code,total_mbm_emissions_value,total_lbm_emissions_value,total_mbm_emissions_unit\n" +
+			"111111111111,999999999999,AmazonEC2,us-east-1,1,2,MTCO2e\n" +
+			"333333333333,999999999999,AmazonEC2,us-east-1,100,100,MTCO2e\n",
+		"usage_period=2024-02": "usage_account_id,payer_account_id,product_code,region_code,total_mbm_emissions_value,total_lbm_emissions_value,total_mbm_emissions_unit\n" +
+			"222222222222,999999999999,AmazonS3,eu-west-1,0.5,0.25,MTCO2e\n",
+	}
+
+	mockCtrl := gomock.NewController(t)
+	mockClient := mock.NewMockClient(mockCtrl)
+	mockClient.EXPECT().ListObjectsV2(gomock.Any()).DoAndReturn(func(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+		if *input.Prefix == basePath {
+			return &s3.ListObjectsV2Output{CommonPrefixes: []types.CommonPrefix{
+				{Prefix: awsSdk.String(basePath + "usage_period=2024-01/")},
+				{Prefix: awsSdk.String(basePath + "usage_period=2024-02/")},
+			}}, nil
+		}
+		return &s3.ListObjectsV2Output{Contents: []types.Object{{Key: awsSdk.String(*input.Prefix + "data.csv.gz")}}}, nil
+	}).Times(8)
+	mockClient.EXPECT().GetObject(gomock.Any()).DoAndReturn(func(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+		dir := strings.TrimSuffix(strings.TrimPrefix(*input.Key, basePath), "/data.csv.gz")
+		var buf strings.Builder
+		gzWriter := gzip.NewWriter(&buf)
+		_, _ = gzWriter.Write([]byte(csvData[dir]))
+		gzWriter.Close()
+		return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(buf.String()))}, nil
+	}).Times(4)
+
+	var out strings.Builder
+	ops := newCarbonReportOptions(genericclioptions.IOStreams{Out: &out}, &globalflags.GlobalOptions{})
+	ops.usagePeriod = "2024-01,2024-02"
+	ops.markdown = true
+
+	if err := ops.report(mockClient, []string{"111111111111", "222222222222"}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	for _, expected := range []string{
+		"- Usage periods: 2024-01, 2024-02\n- Accounts: 2\n- Market-based emissions: 1.500 MTCO2e\n- Location-based emissions: 2.250 MTCO2e\n",
+		"| 111111111111 | 1.000 | 2.000 |\n| 222222222222 | 0.500 | 0.250 |",
+		"| us-east-1 | 1.000 | 2.000 |\n| eu-west-1 | 0.500 | 0.250 |",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected report to contain %q but got:\n%s", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "333333333333") {
+		t.Errorf("Expected report not to contain accounts out of scope but got:\n%s", out.String())
+	}
+
+	// The csv rows of several accounts keep their account
+	out.Reset()
+	ops.markdown = false
+	if err := ops.report(mockClient, []string{"111111111111", "222222222222"}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	expected := "usage_account_id,product_code,region_code,total_mbm_emissions_value,total_lbm_emissions_value,total_mbm_emissions_unit\n" +
+		"111111111111,AmazonEC2,us-east-1,1,2,MTCO2e\n" +
+		"222222222222,AmazonS3,eu-west-1,0.5,0.25,MTCO2e\n"
+	if out.String() != expected {
+		t.Errorf("Expected csv:\n%s\nbut got:\n%s", expected, out.String())
+	}
+}
+
+func TestCarbonReportCSVWithoutSummaryColumns(t *testing.T) {
+	basePath := "reports/carbon-emissions/data/carbon_model_version=v3.0.0/"
+	// Older exports have no location-based emissions, which only the summary needs
+	csvData := "usage_account_id,product_code,region_code,total_mbm_emissions_value,total_mbm_emissions_unit\n" +
+		"111111111111,AmazonEC2,us-east-1,1,MTCO2e\n"
+
+	mockCtrl := gomock.NewController(t)
+	mockClient := mock.NewMockClient(mockCtrl)
+	mockClient.EXPECT().ListObjectsV2(gomock.Any()).DoAndReturn(func(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+		if *input.Prefix == basePath {
+			return &s3.ListObjectsV2Output{CommonPrefixes: []types.CommonPrefix{
+				{Prefix: awsSdk.String(basePath + "usage_period=2024-01/")},
+			}}, nil
+		}
+		return &s3.ListObjectsV2Output{Contents: []types.Object{{Key: awsSdk.String(*input.Prefix + "data.csv.gz")}}}, nil
+	}).AnyTimes()
+	mockClient.EXPECT().GetObject(gomock.Any()).DoAndReturn(func(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+		var buf strings.Builder
+		gzWriter := gzip.NewWriter(&buf)
+		_, _ = gzWriter.Write([]byte(csvData))
+		gzWriter.Close()
+		return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(buf.String()))}, nil
+	}).AnyTimes()
+
+	var out strings.Builder
+	ops := newCarbonReportOptions(genericclioptions.IOStreams{Out: &out}, &globalflags.GlobalOptions{})
+	ops.usagePeriod = "2024-01"
+
+	if err := ops.report(mockClient, []string{"111111111111"}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "product_code,region_code,total_mbm_emissions_value,total_mbm_emissions_unit\nAmazonEC2,us-east-1,1,MTCO2e") {
+		t.Errorf("Expected csv rows of the account but got:\n%s", out.String())
+	}
+
+	out.Reset()
+	ops.markdown = true
+	if err := ops.report(mockClient, []string{"111111111111"}); err == nil || !strings.Contains(err.Error(), carbonLocationBasedColumn) {
+		t.Errorf("Expected summary error about the missing %s column but got: %v", carbonLocationBasedColumn, err)
+	}
+}
@@ -0,0 +1,169 @@
+package cost
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Columns of the carbon emissions data the summary is made of, the emissions are in metric tons of CO2e
+const (
+	carbonServiceColumn       = "product_code"
+	carbonRegionColumn        = "region_code"
+	carbonMarketBasedColumn   = "total_mbm_emissions_value"
+	carbonLocationBasedColumn = "total_lbm_emissions_value"
+	carbonUnitColumn          = "total_mbm_emissions_unit"
+
+	// carbonGlobalRegion groups the emissions without region
+	carbonGlobalRegion = "global"
+)
+
+// carbonEmissions are the market-based and location-based emissions of a usage period, account, service or region
+type carbonEmissions struct {
+	Key           string          `json:"key" yaml:"key"`
+	MarketBased   decimal.Decimal `json:"marketBased" yaml:"marketBased"`
+	LocationBased decimal.Decimal `json:"locationBased" yaml:"locationBased"`
+}
+
+func (e *carbonEmissions) add(marketBased decimal.Decimal, locationBased decimal.Decimal) {
+	e.MarketBased = e.MarketBased.Add(marketBased)
+	e.LocationBased = e.LocationBased.Add(locationBased)
+}
+
+// carbonSummary sums up the carbon emissions of accounts over usage periods
+type carbonSummary struct {
+	UsagePeriods []string          `json:"usagePeriods" yaml:"usagePeriods"`
+	Accounts     []string          `json:"accounts" yaml:"accounts"`
+	Unit         string            `json:"unit" yaml:"unit"`
+	Total        carbonEmissions   `json:"total" yaml:"total"`
+	ByPeriod     []carbonEmissions `json:"byPeriod" yaml:"byPeriod"`
+	ByAccount    []carbonEmissions `json:"byAccount" yaml:"byAccount"`
+	ByService    []carbonEmissions `json:"byService" yaml:"byService"`
+	ByRegion     []carbonEmissions `json:"byRegion" yaml:"byRegion"`
+}
+
+func newCarbonSummary(accounts []string) *carbonSummary {
+	return &carbonSummary{
+		UsagePeriods: []string{},
+		Accounts:     accounts,
+		Total:        carbonEmissions{Key: "total"},
+		ByPeriod:     []carbonEmissions{},
+		ByAccount:    []carbonEmissions{},
+		ByService:    []carbonEmissions{},
+		ByRegion:     []carbonEmissions{},
+	}
+}
+
+// add sums up the rows of a usage period, read with their header
+func (s *carbonSummary) add(usagePeriod string, header []string, rows [][]string) error {
+	columns := map[string]int{}
+	for _, column := range []string{carbonAccountColumn, carbonServiceColumn, carbonRegionColumn, carbonMarketBasedColumn, carbonLocationBasedColumn, carbonUnitColumn} {
+		i := slices.Index(header, column)
+		if i == -1 {
+			return fmt.Errorf("%s column not found in CSV", column)
+		}
+		columns[column] = i
+	}
+
+	if !slices.Contains(s.UsagePeriods, usagePeriod) {
+		s.UsagePeriods = append(s.UsagePeriods, usagePeriod)
+	}
+	for _, row := range rows {
+		if len(row) != len(header) {
+			return fmt.Errorf("row has %d columns but header has %d", len(row), len(header))
+		}
+		marketBased, err := parseEmissions(row[columns[carbonMarketBasedColumn]])
+		if err != nil {
+			return err
+		}
+		locationBased, err := parseEmissions(row[columns[carbonLocationBasedColumn]])
+		if err != nil {
+			return err
+		}
+		if unit := row[columns[carbonUnitColumn]]; unit != "" {
+			if s.Unit != "" && s.Unit != unit {
+				return fmt.Errorf("can't sum up different units: %s and %s", s.Unit, unit)
+			}
+			s.Unit = unit
+		}
+		region := row[columns[carbonRegionColumn]]
+		if region == "" {
+			region = carbonGlobalRegion
+		}
+
+		s.Total.add(marketBased, locationBased)
+		s.ByPeriod = addEmissions(s.ByPeriod, usagePeriod, marketBased, locationBased)
+		s.ByAccount = addEmissions(s.ByAccount, row[columns[carbonAccountColumn]], marketBased, locationBased)
+		s.ByService = addEmissions(s.ByService, row[columns[carbonServiceColumn]], marketBased, locationBased)
+		s.ByRegion = addEmissions(s.ByRegion, region, marketBased, locationBased)
+	}
+
+	// Periods stay in chronological order, the other breakdowns largest emissions first
+	slices.SortFunc(s.ByPeriod, func(a, b carbonEmissions) int { return strings.Compare(a.Key, b.Key) })
+	for _, emissions := range [][]carbonEmissions{s.ByAccount, s.ByService, s.ByRegion} {
+		slices.SortFunc(emissions, func(a, b carbonEmissions) int {
+			if c := b.MarketBased.Cmp(a.MarketBased); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Key, b.Key)
+		})
+	}
+	return nil
+}
+
+func addEmissions(emissions []carbonEmissions, key string, marketBased decimal.Decimal, locationBased decimal.Decimal) []carbonEmissions {
+	i := slices.IndexFunc(emissions, func(e carbonEmissions) bool { return e.Key == key })
+	if i == -1 {
+		emissions = append(emissions, carbonEmissions{Key: key})
+		i = len(emissions) - 1
+	}
+	emissions[i].add(marketBased, locationBased)
+	return emissions
+}
+
+func parseEmissions(value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	emissions, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid emissions value %q: %w", value, err)
+	}
+	return emissions, nil
+}
+
+// String returns the summary as markdown tables, for sustainability reports
+func (s *carbonSummary) String() string {
+	unit := s.Unit
+	if unit == "" {
+		unit = "MTCO2e"
+	}
+
+	var b strings.Builder
+	b.WriteString("# Carbon emissions report\n\n")
+	fmt.Fprintf(&b, "- Usage periods: %s\n", strings.Join(s.UsagePeriods, ", "))
+	fmt.Fprintf(&b, "- Accounts: %d\n", len(s.Accounts))
+	fmt.Fprintf(&b, "- Market-based emissions: %s %s\n", s.Total.MarketBased.StringFixed(3), unit)
+	fmt.Fprintf(&b, "- Location-based emissions: %s %s\n", s.Total.LocationBased.StringFixed(3), unit)
+
+	for _, table := range []struct {
+		title     string
+		key       string
+		emissions []carbonEmissions
+	}{
+		{"By usage period", "Usage period", s.ByPeriod},
+		{"By account", "Account", s.ByAccount},
+		{"By service", "Service", s.ByService},
+		{"By region", "Region", s.ByRegion},
+	} {
+		fmt.Fprintf(&b, "\n## %s\n\n", table.title)
+		fmt.Fprintf(&b, "| %s | Market-based (%s) | Location-based (%s) |\n", table.key, unit, unit)
+		b.WriteString("| --- | ---: | ---: |\n")
+		for _, emissions := range table.emissions {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", emissions.Key, emissions.MarketBased.StringFixed(3), emissions.LocationBased.StringFixed(3))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,57 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCarbonHeader = []string{"usage_account_id", "product_code", "region_code", "total_mbm_emissions_value", "total_lbm_emissions_value", "total_mbm_emissions_unit"}
+
+func TestCarbonSummaryAdd(t *testing.T) {
+	summary := newCarbonSummary([]string{"111111111111", "222222222222"})
+	require.NoError(t, summary.add("2024-02", testCarbonHeader, [][]string{
+		{"111111111111", "AmazonEC2", "us-east-1", "1.5", "2", "MTCO2e"},
+		{"222222222222", "AmazonS3", "", "0.25", "0.5", "MTCO2e"},
+	}))
+	require.NoError(t, summary.add("2024-01", testCarbonHeader, [][]string{
+		{"222222222222", "AmazonEC2", "eu-west-1", "3", "", "MTCO2e"},
+	}))
+
+	assert.Equal(t, []string{"2024-02", "2024-01"}, summary.UsagePeriods)
+	assert.Equal(t, "MTCO2e", summary.Unit)
+	assert.Equal(t, "4.750", summary.Total.MarketBased.StringFixed(3))
+	assert.Equal(t, "2.500", summary.Total.LocationBased.StringFixed(3))
+
+	keys := func(emissions []carbonEmissions) []string {
+		var result []string
+		for _, e := range emissions {
+			result = append(result, e.Key+"="+e.MarketBased.String())
+		}
+		return result
+	}
+	assert.Equal(t, []string{"2024-01=3", "2024-02=1.75"}, keys(summary.ByPeriod))
+	assert.Equal(t, []string{"222222222222=3.25", "111111111111=1.5"}, keys(summary.ByAccount))
+	assert.Equal(t, []string{"AmazonEC2=4.5", "AmazonS3=0.25"}, keys(summary.ByService))
+	assert.Equal(t, []string{"eu-west-1=3", "us-east-1=1.5", "global=0.25"}, keys(summary.ByRegion))
+}
+
+func TestCarbonSummaryAddErrors(t *testing.T) {
+	summary := newCarbonSummary(nil)
+	assert.EqualError(t, summary.add("2024-01", []string{"usage_account_id", "region_code"}, nil), "product_code column not found in CSV")
+	assert.ErrorContains(t, summary.add("2024-01", testCarbonHeader, [][]string{{"111111111111", "AmazonEC2", "us-east-1", "n/a", "1", "MTCO2e"}}), `invalid emissions value "n/a"`)
+	assert.EqualError(t, summary.add("2024-01", testCarbonHeader, [][]string{
+		{"111111111111", "AmazonEC2", "us-east-1", "1", "1", "MTCO2e"},
+		{"111111111111", "AmazonEC2", "us-east-1", "1", "1", "kgCO2e"},
+	}), "can't sum up different units: MTCO2e and kgCO2e")
+}
+
+func TestCarbonSummaryString(t *testing.T) {
+	summary := newCarbonSummary([]string{"111111111111"})
+	require.NoError(t, summary.add("2024-01", testCarbonHeader, [][]string{{"111111111111", "AmazonEC2", "us-east-1", "1.5", "2", "MTCO2e"}}))
+
+	markdown := summary.String()
+	assert.Contains(t, markdown, "- Usage periods: 2024-01\n- Accounts: 1\n- Market-based emissions: 1.500 MTCO2e\n")
+	assert.Contains(t, markdown, "## By service\n\n| Service | Market-based (MTCO2e) | Location-based (MTCO2e) |\n| --- | ---: | ---: |\n| AmazonEC2 | 1.500 | 2.000 |")
+}
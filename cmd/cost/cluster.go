@@ -0,0 +1,445 @@
+package cost
+
+//go:generate mockgen -source=cluster.go -package=mock -destination=mock/cluster.go
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	outputflag "github.com/openshift/osdctl/cmd/getoutput"
+	"github.com/openshift/osdctl/internal/utils/globalflags"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	clusterTagPrefix = "kubernetes.io/cluster/"
+	clusterTagOwned  = "owned"
+)
+
+// clusterCmd represents the cluster command
+func newCmdCluster(streams genericclioptions.IOStreams, globalOpts *globalflags.GlobalOptions) *cobra.Command {
+	ops := newClusterCostOptions(streams, globalOpts)
+	clusterCmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Get the cost of a cluster or of the clusters of an OCM search",
+		Long: `Get the cost of the AWS account of a cluster by service, next to the cost of the resources tagged
+` + clusterTagPrefix + `<infra-id>=` + clusterTagOwned + `, which are the resources of the cluster itself.
+
+The cost of non-CCS accounts, found through their AccountClaim, is read from the payer account of the given AWS
+credentials. The cost of CCS accounts is read in the account through the support role of the cluster.
+
+The tagged cost is only known when the cluster tag is activated as a cost allocation tag of the account. The account
+cost includes everything else in the account, like other clusters of a CCS account. The control plane of hosted
+control plane clusters doesn't run in their account and isn't included.`,
+		Example: `  # Cost of a cluster last month
+  osdctl cost cluster -C ${CLUSTER_ID} -t LM
+
+  # Cost of the ROSA clusters of an organization as csv
+  osdctl cost cluster --search "product.id = 'rosa' and organization.id = '${ORG_ID}'" --start 2026-01-01 --end 2026-02-01 --csv`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.checkArgs(cmd, args))
+			defer ops.clients.Close()
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+	clusterCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "internal ID, external ID or name of the cluster")
+	clusterCmd.Flags().StringVar(&ops.search, "search", "", "OCM search of the clusters, e.g. \"organization.id = 'abc'\"")
+	clusterCmd.Flags().StringVarP(&ops.time, "time", "t", "", "set time. One of 'LM', 'MTD', 'YTD', '3M', '6M', '1Y'")
+	clusterCmd.Flags().StringVar(&ops.start, "start", "", "set start date range")
+	clusterCmd.Flags().StringVar(&ops.end, "end", "", "set end date range, excluded")
+	clusterCmd.Flags().BoolVar(&ops.csv, "csv", false, "output result as csv")
+	clusterCmd.MarkFlagsMutuallyExclusive("cluster-id", "search")
+	clusterCmd.MarkFlagsOneRequired("cluster-id", "search")
+
+	return clusterCmd
+}
+
+// ClusterClients looks up clusters in OCM and creates the AWS clients reading their cost
+type ClusterClients interface {
+	// Clusters returns the cluster of clusterID or the clusters of an OCM search
+	Clusters(clusterID string, search string) ([]*cmv1.Cluster, error)
+	// AccountID returns the AWS account of a cluster
+	AccountID(clusterID string) (string, error)
+	// NewAWSClient returns the client reading the cost of a cluster: the payer client of the cost command for
+	// non-CCS clusters, the support role of the cluster for CCS clusters
+	NewAWSClient(clusterID string, ccs bool) (awsprovider.Client, error)
+	Close() error
+}
+
+// costCluster is a cluster and the AWS account its cost is read from
+type costCluster struct {
+	ID        string
+	Name      string
+	InfraID   string
+	AccountID string
+	CCS       bool
+	// Error is set when the account of the cluster can't be found
+	Error string
+}
+
+// Store flag options for cluster command
+type clusterCostOptions struct {
+	clusterID string
+	search    string
+	time      string
+	start     string
+	end       string
+	csv       bool
+	output    string
+
+	clients ClusterClients
+
+	genericclioptions.IOStreams
+	GlobalOptions *globalflags.GlobalOptions
+}
+
+func newClusterCostOptions(streams genericclioptions.IOStreams, globalOpts *globalflags.GlobalOptions) *clusterCostOptions {
+	return &clusterCostOptions{
+		clients:       &ocmClusterClients{},
+		IOStreams:     streams,
+		GlobalOptions: globalOpts,
+	}
+}
+
+func (o *clusterCostOptions) checkArgs(cmd *cobra.Command, _ []string) error {
+	if o.start == "" && o.end == "" && o.time == "" {
+		return cmdutil.UsageErrorf(cmd, "Please provide a date range or a predefined time")
+	}
+	if (o.start != "" || o.end != "") && o.time != "" {
+		return cmdutil.UsageErrorf(cmd, "Please provide either a date range or a predefined time")
+	}
+	if o.time == "" && (o.start == "" || o.end == "") {
+		return cmdutil.UsageErrorf(cmd, "Please provide both start and end of date range")
+	}
+
+	o.output = o.GlobalOptions.Output
+
+	return nil
+}
+
+type serviceCost struct {
+	Service string          `json:"service" yaml:"service"`
+	Cost    decimal.Decimal `json:"cost" yaml:"cost"`
+	Tagged  decimal.Decimal `json:"tagged" yaml:"tagged"`
+}
+
+type clusterCostResponse struct {
+	ClusterID string `json:"clusterId" yaml:"clusterId"`
+	Name      string `json:"name" yaml:"name"`
+	AccountID string `json:"accountId" yaml:"accountId"`
+	CCS       bool   `json:"ccs" yaml:"ccs"`
+	InfraID   string `json:"infraId" yaml:"infraId"`
+	Start     string `json:"start" yaml:"start"`
+	End       string `json:"end" yaml:"end"`
+	Unit      string `json:"unit" yaml:"unit"`
+	// Cost is the cost of the whole account, Tagged the cost of the resources tagged with the cluster tag
+	Cost     decimal.Decimal `json:"cost" yaml:"cost"`
+	Tagged   decimal.Decimal `json:"tagged" yaml:"tagged"`
+	Services []serviceCost   `json:"services" yaml:"services"`
+	Error    string          `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func (f clusterCostResponse) String() string {
+	if f.Error != "" {
+		return fmt.Sprintf("Cluster %s (%s): %s", f.Name, f.ClusterID, f.Error)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Cluster %s (%s), %s account %s, cost in %s from %s to %s\n", f.Name, f.ClusterID, accountType(f.CCS), f.AccountID, f.Unit, f.Start, f.End)
+	p := printer.NewTablePrinter(&b, 12, 1, 3, ' ')
+	p.AddRow([]string{"SERVICE", "COST", "TAGGED"})
+	for _, service := range f.Services {
+		p.AddRow([]string{service.Service, service.Cost.StringFixed(2), service.Tagged.StringFixed(2)})
+	}
+	p.AddRow([]string{"TOTAL", f.Cost.StringFixed(2), f.Tagged.StringFixed(2)})
+	_ = p.Flush()
+	fmt.Fprintf(&b, "TAGGED is the cost of the resources tagged %s%s=%s", clusterTagPrefix, f.InfraID, clusterTagOwned)
+	return b.String()
+}
+
+type clusterCostsResponse struct {
+	Clusters []clusterCostResponse `json:"clusters" yaml:"clusters"`
+}
+
+func (f clusterCostsResponse) String() string {
+	var b strings.Builder
+	p := printer.NewTablePrinter(&b, 12, 1, 3, ' ')
+	p.AddRow([]string{"CLUSTER", "NAME", "ACCOUNT", "TYPE", "COST", "TAGGED", "UNIT", "ERROR"})
+	for _, cluster := range f.Clusters {
+		row := []string{cluster.ClusterID, cluster.Name, cluster.AccountID, accountType(cluster.CCS), "-", "-", "-", "-"}
+		if cluster.AccountID == "" {
+			row[2] = "-"
+		}
+		if cluster.Error != "" {
+			row[7] = cluster.Error
+		} else {
+			row[4], row[5], row[6] = cluster.Cost.StringFixed(2), cluster.Tagged.StringFixed(2), cluster.Unit
+		}
+		p.AddRow(row)
+	}
+	_ = p.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func accountType(ccs bool) string {
+	if ccs {
+		return "CCS"
+	}
+	return "non-CCS"
+}
+
+func (o *clusterCostOptions) run() error {
+	start, end, err := parseDateRange(o.time, o.start, o.end)
+	if err != nil {
+		return err
+	}
+	clusters, err := o.getClusters()
+	if err != nil {
+		return err
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no cluster matches the search %q", o.search)
+	}
+
+	var costs []clusterCostResponse
+	for _, cluster := range clusters {
+		costs = append(costs, o.getClusterCost(cluster, start, end))
+	}
+
+	if o.clusterID != "" {
+		if costs[0].Error != "" {
+			return fmt.Errorf("failed to get the cost of cluster %s: %s", o.clusterID, costs[0].Error)
+		}
+		if o.csv {
+			return writeClusterServicesCSV(o.Out, costs[0])
+		}
+		return outputflag.PrintResponse(o.output, costs[0])
+	}
+
+	if o.csv {
+		return writeClustersCSV(o.Out, costs)
+	}
+	return outputflag.PrintResponse(o.output, clusterCostsResponse{Clusters: costs})
+}
+
+// getClusterCost returns the cost of a cluster by service, errors are reported in the response
+func (o *clusterCostOptions) getClusterCost(cluster costCluster, start time.Time, end time.Time) clusterCostResponse {
+	resp := clusterCostResponse{
+		ClusterID: cluster.ID,
+		Name:      cluster.Name,
+		AccountID: cluster.AccountID,
+		CCS:       cluster.CCS,
+		InfraID:   cluster.InfraID,
+		Start:     start.Format(dateLayout),
+		End:       end.Format(dateLayout),
+		Services:  []serviceCost{},
+		Error:     cluster.Error,
+	}
+	if resp.Error != "" {
+		return resp
+	}
+
+	awsClient, err := o.clients.NewAWSClient(cluster.ID, cluster.CCS)
+	if err != nil {
+		resp.Error = fmt.Sprintf("failed to create AWS client: %v", err)
+		return resp
+	}
+	accounts := []string{cluster.AccountID}
+	services, err := getCostTable(awsClient, accounts, start, end, costExplorerTypes.GranularityMonthly, costExplorerTypes.DimensionService, nil)
+	if err != nil {
+		resp.Error = fmt.Sprintf("failed to get the cost of account %s: %v", cluster.AccountID, err)
+		return resp
+	}
+	tagged, err := getCostTable(awsClient, accounts, start, end, costExplorerTypes.GranularityMonthly, costExplorerTypes.DimensionService, &costExplorerTypes.TagValues{
+		Key:    aws.String(clusterTagPrefix + cluster.InfraID),
+		Values: []string{clusterTagOwned},
+	})
+	if err != nil {
+		resp.Error = fmt.Sprintf("failed to get the cost of the resources tagged for cluster %s: %v", cluster.InfraID, err)
+		return resp
+	}
+
+	resp.Unit = services.Unit
+	for service, costs := range services.Costs {
+		cost := serviceCost{
+			Service: service,
+			Cost:    decimal.Sum(decimal.Zero, costs...),
+			Tagged:  decimal.Sum(decimal.Zero, tagged.Costs[service]...),
+		}
+		resp.Cost = resp.Cost.Add(cost.Cost)
+		resp.Tagged = resp.Tagged.Add(cost.Tagged)
+		resp.Services = append(resp.Services, cost)
+	}
+	slices.SortFunc(resp.Services, func(a, b serviceCost) int {
+		if c := b.Cost.Cmp(a.Cost); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Service, b.Service)
+	})
+	return resp
+}
+
+// getClusters returns the cluster of clusterID or the clusters of the OCM search, with their AWS account
+func (o *clusterCostOptions) getClusters() ([]costCluster, error) {
+	clusters, err := o.clients.Clusters(o.clusterID, o.search)
+	if err != nil {
+		return nil, err
+	}
+
+	costClusters := make([]costCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		c := costCluster{
+			ID:      cluster.ID(),
+			Name:    cluster.Name(),
+			InfraID: cluster.InfraID(),
+			CCS:     cluster.CCS().Enabled(),
+		}
+		if cluster.CloudProvider().ID() != "aws" {
+			c.Error = fmt.Sprintf("not an AWS cluster but a %s one", cluster.CloudProvider().ID())
+		} else if c.AccountID, err = o.clients.AccountID(cluster.ID()); err != nil {
+			c.Error = fmt.Sprintf("failed to find the AWS account: %v", err)
+		}
+		costClusters = append(costClusters, c)
+	}
+	return costClusters, nil
+}
+
+// ocmClusterClients implements ClusterClients with a single OCM connection and payer client, created on
+// their first use
+type ocmClusterClients struct {
+	mu          sync.Mutex
+	ocmClient   *sdk.Connection
+	payerClient awsprovider.Client
+}
+
+func (c *ocmClusterClients) connection() (*sdk.Connection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ocmClient == nil {
+		ocmClient, err := utils.CreateConnection()
+		if err != nil {
+			return nil, err
+		}
+		c.ocmClient = ocmClient
+	}
+	return c.ocmClient, nil
+}
+
+func (c *ocmClusterClients) payer() (awsprovider.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.payerClient == nil {
+		payerClient, err := opsCost.initAWSClients()
+		if err != nil {
+			return nil, err
+		}
+		c.payerClient = payerClient
+	}
+	return c.payerClient, nil
+}
+
+func (c *ocmClusterClients) Clusters(clusterID string, search string) ([]*cmv1.Cluster, error) {
+	ocmClient, err := c.connection()
+	if err != nil {
+		return nil, err
+	}
+	if clusterID == "" {
+		return utils.ApplyFilters(ocmClient, []string{search})
+	}
+	cluster, err := utils.GetClusterAnyStatus(ocmClient, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return []*cmv1.Cluster{cluster}, nil
+}
+
+func (c *ocmClusterClients) AccountID(clusterID string) (string, error) {
+	ocmClient, err := c.connection()
+	if err != nil {
+		return "", err
+	}
+	return utils.GetAWSAccountIdForCluster(ocmClient, clusterID)
+}
+
+func (c *ocmClusterClients) NewAWSClient(clusterID string, ccs bool) (awsprovider.Client, error) {
+	payerClient, err := c.payer()
+	if err != nil {
+		return nil, err
+	}
+	if !ccs {
+		return payerClient, nil
+	}
+
+	ocmClient, err := c.connection()
+	if err != nil {
+		return nil, err
+	}
+	partition, err := awsprovider.GetAwsPartition(payerClient)
+	if err != nil {
+		return nil, err
+	}
+	sessionName, err := osdCloud.GenerateRoleSessionName(payerClient)
+	if err != nil {
+		return nil, err
+	}
+	// Cost Explorer has a single endpoint, the region of the cost command is used instead of the region of the cluster
+	return osdCloud.GenerateCCSClusterAWSClient(ocmClient, payerClient, clusterID, opsCost.region, partition, sessionName)
+}
+
+func (c *ocmClusterClients) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ocmClient == nil {
+		return nil
+	}
+	return c.ocmClient.Close()
+}
+
+func writeClusterServicesCSV(w io.Writer, resp clusterCostResponse) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"service", "cost", "tagged", "unit"}); err != nil {
+		return err
+	}
+	for _, service := range resp.Services {
+		if err := writer.Write([]string{service.Service, service.Cost.StringFixed(2), service.Tagged.StringFixed(2), resp.Unit}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeClustersCSV(w io.Writer, costs []clusterCostResponse) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"cluster_id", "name", "account_id", "ccs", "cost", "tagged", "unit", "error"}); err != nil {
+		return err
+	}
+	for _, cost := range costs {
+		row := []string{cost.ClusterID, cost.Name, cost.AccountID, strconv.FormatBool(cost.CCS), "", "", cost.Unit, cost.Error}
+		if cost.Error == "" {
+			row[4], row[5] = cost.Cost.StringFixed(2), cost.Tagged.StringFixed(2)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,150 @@
+package cost
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	costMock "github.com/openshift/osdctl/cmd/cost/mock"
+	"github.com/openshift/osdctl/internal/utils/globalflags"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// expectClusterCost expects the cost of an account by service, then the cost of the resources of a cluster
+func expectClusterCost(t *testing.T, mockClient *mock.MockClient, account string, infraID string, services map[string]string, tagged map[string]string) {
+	gomock.InOrder(
+		mockClient.EXPECT().GetCostAndUsage(gomock.Any()).DoAndReturn(func(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+			assert.Equal(t, []string{account}, input.Filter.Dimensions.Values)
+			return &costexplorer.GetCostAndUsageOutput{
+				ResultsByTime: []costExplorerTypes.ResultByTime{costResult("2026-01-01", services)},
+			}, nil
+		}),
+		mockClient.EXPECT().GetCostAndUsage(gomock.Any()).DoAndReturn(func(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+			require.Len(t, input.Filter.And, 2)
+			assert.Equal(t, []string{account}, input.Filter.And[0].Dimensions.Values)
+			assert.Equal(t, "kubernetes.io/cluster/"+infraID, *input.Filter.And[1].Tags.Key)
+			assert.Equal(t, []string{"owned"}, input.Filter.And[1].Tags.Values)
+			return &costexplorer.GetCostAndUsageOutput{
+				ResultsByTime: []costExplorerTypes.ResultByTime{costResult("2026-01-01", tagged)},
+			}, nil
+		}),
+	)
+}
+
+// testCluster returns an OCM cluster on the cloud provider
+func testCluster(id string, name string, provider string, ccs bool) *cmv1.Cluster {
+	cluster, err := cmv1.NewCluster().ID(id).Name(name).InfraID("infra-" + strings.TrimPrefix(id, "id-")).
+		CloudProvider(cmv1.NewCloudProvider().ID(provider)).
+		CCS(cmv1.NewCCS().Enabled(ccs)).
+		Build()
+	if err != nil {
+		panic(err)
+	}
+	return cluster
+}
+
+// newTestClusterCostOptions returns cluster cost options finding the clusters, the accounts of the clusters
+// by id and their AWS clients by id, other clients are denied
+func newTestClusterCostOptions(t *testing.T, clusters []*cmv1.Cluster, accounts map[string]string, clients map[string]awsprovider.Client, out *bytes.Buffer) *clusterCostOptions {
+	clusterClients := costMock.NewMockClusterClients(gomock.NewController(t))
+	clusterClients.EXPECT().Clusters(gomock.Any(), gomock.Any()).Return(clusters, nil).AnyTimes()
+	clusterClients.EXPECT().AccountID(gomock.Any()).DoAndReturn(func(clusterID string) (string, error) {
+		account, ok := accounts[clusterID]
+		if !ok {
+			return "", errors.New("no claim")
+		}
+		return account, nil
+	}).AnyTimes()
+	clusterClients.EXPECT().NewAWSClient(gomock.Any(), gomock.Any()).DoAndReturn(func(clusterID string, _ bool) (awsprovider.Client, error) {
+		client, ok := clients[clusterID]
+		if !ok {
+			return nil, errors.New("access denied")
+		}
+		return client, nil
+	}).AnyTimes()
+	return &clusterCostOptions{
+		start:         "2026-01-01",
+		end:           "2026-02-01",
+		output:        "text",
+		clients:       clusterClients,
+		IOStreams:     genericclioptions.IOStreams{Out: out},
+		GlobalOptions: &globalflags.GlobalOptions{},
+	}
+}
+
+func TestGetClusterCost(t *testing.T) {
+	mockClient := mock.NewMockClient(gomock.NewController(t))
+	expectClusterCost(t, mockClient, "111", "infra-1",
+		map[string]string{"EC2": "100", "S3": "5", "Route53": "1"},
+		map[string]string{"EC2": "80", "S3": "2"},
+	)
+
+	cluster := costCluster{ID: "id-1", Name: "one", InfraID: "infra-1", AccountID: "111"}
+	o := newTestClusterCostOptions(t, nil, nil, map[string]awsprovider.Client{"id-1": mockClient}, &bytes.Buffer{})
+	start, end, err := parseDateRange("", o.start, o.end)
+	require.NoError(t, err)
+
+	resp := o.getClusterCost(cluster, start, end)
+	require.Empty(t, resp.Error)
+	assert.Equal(t, "USD", resp.Unit)
+	assert.Equal(t, "106.00", resp.Cost.StringFixed(2))
+	assert.Equal(t, "82.00", resp.Tagged.StringFixed(2))
+	require.Len(t, resp.Services, 3)
+	assert.Equal(t, "EC2", resp.Services[0].Service)
+	assert.Equal(t, "80.00", resp.Services[0].Tagged.StringFixed(2))
+	assert.Equal(t, "Route53", resp.Services[2].Service)
+	assert.True(t, resp.Services[2].Tagged.IsZero())
+}
+
+func TestClusterCostRun(t *testing.T) {
+	t.Run("single cluster as csv", func(t *testing.T) {
+		mockClient := mock.NewMockClient(gomock.NewController(t))
+		expectClusterCost(t, mockClient, "111", "infra-1", map[string]string{"EC2": "10"}, map[string]string{"EC2": "7.5"})
+
+		var out bytes.Buffer
+		o := newTestClusterCostOptions(t, []*cmv1.Cluster{testCluster("id-1", "one", "aws", false)}, map[string]string{"id-1": "111"}, map[string]awsprovider.Client{"id-1": mockClient}, &out)
+		o.clusterID, o.csv = "id-1", true
+		require.NoError(t, o.run())
+		assert.Equal(t, "service,cost,tagged,unit\nEC2,10.00,7.50,USD\n", out.String())
+	})
+
+	t.Run("single cluster without account", func(t *testing.T) {
+		o := newTestClusterCostOptions(t, []*cmv1.Cluster{testCluster("id-1", "one", "gcp", true)}, nil, nil, &bytes.Buffer{})
+		o.clusterID = "id-1"
+		assert.EqualError(t, o.run(), "failed to get the cost of cluster id-1: not an AWS cluster but a gcp one")
+	})
+
+	t.Run("fleet keeps going on errors", func(t *testing.T) {
+		mockClient := mock.NewMockClient(gomock.NewController(t))
+		expectClusterCost(t, mockClient, "111", "infra-1", map[string]string{"EC2": "10"}, map[string]string{"EC2": "7.5"})
+
+		var out bytes.Buffer
+		o := newTestClusterCostOptions(t, []*cmv1.Cluster{
+			testCluster("id-1", "one", "aws", false),
+			testCluster("id-2", "two", "aws", true),
+			testCluster("id-3", "three", "aws", false),
+		}, map[string]string{"id-1": "111", "id-2": "222"}, map[string]awsprovider.Client{"id-1": mockClient}, &out)
+		o.search, o.csv = "product.id = 'rosa'", true
+		require.NoError(t, o.run())
+		assert.Equal(t, `cluster_id,name,account_id,ccs,cost,tagged,unit,error
+id-1,one,111,false,10.00,7.50,USD,
+id-2,two,222,true,,,,failed to create AWS client: access denied
+id-3,three,,false,,,,failed to find the AWS account: no claim
+`, out.String())
+	})
+
+	t.Run("empty search", func(t *testing.T) {
+		o := newTestClusterCostOptions(t, nil, nil, nil, &bytes.Buffer{})
+		o.search = "name = 'none'"
+		assert.EqualError(t, o.run(), `no cluster matches the search "name = 'none'"`)
+	})
+}
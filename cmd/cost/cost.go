@@ -35,6 +35,9 @@ platforms may be added in the future)`,
 	costCmd.AddCommand(newCmdCreate(streams))
 	costCmd.AddCommand(newCmdList(streams, globalOpts))
 	costCmd.AddCommand(newCmdCarbonReport(streams, globalOpts))
+	costCmd.AddCommand(newCmdTrend(streams, globalOpts))
+	costCmd.AddCommand(newCmdAnomalies(streams, globalOpts))
+	costCmd.AddCommand(newCmdCluster(streams, globalOpts))
 
 	return costCmd
 }
@@ -5,7 +5,6 @@ import (
 	"log"
 
 	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
-	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
 	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
 	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/spf13/cobra"
@@ -32,7 +31,9 @@ func newCmdCreate(streams genericclioptions.IOStreams) *cobra.Command {
 			//Get information regarding Organizational Unit
 			OU := getOU(awsClient, OUid)
 
-			if err := createCostCategory(&OUid, OU, awsClient); err != nil {
+			// The root OU isn't known here, so the cost category is tagged with its own OU, which reconcile --prune
+			// deletes once the OU is deleted
+			if err := createCostCategory(&OUid, OU, OUid, awsClient); err != nil {
 				log.Fatalf("Error creating cost category for %s: %v", OUid, err)
 			}
 		},
@@ -45,33 +46,24 @@ func newCmdCreate(streams genericclioptions.IOStreams) *cobra.Command {
 	return createCmd
 }
 
-// Create Cost Category for OU given as argument for -ou flag
-func createCostCategory(OUid *string, OU *organizationTypes.OrganizationalUnit, awsClient awsprovider.Client) error {
+// Create Cost Category for OU given as argument for -ou flag, tagged with the root OU it is created under
+func createCostCategory(OUid *string, OU *organizationTypes.OrganizationalUnit, root string, awsClient awsprovider.Client) error {
 	//Gets all (not only immediate) accounts under the given OU
 	accountsRecursiveResults, err := getAccountsRecursive(OU, awsClient)
 	if err != nil {
 		return err
 	}
 
-	accounts := make([]string, len(accountsRecursiveResults))
+	accounts := make([]string, 0, len(accountsRecursiveResults))
 	for _, account := range accountsRecursiveResults {
 		accounts = append(accounts, *account)
 	}
 
 	_, err = awsClient.CreateCostCategoryDefinition(&costexplorer.CreateCostCategoryDefinitionInput{
-		Name:        OUid,
-		RuleVersion: "CostCategoryExpression.v1",
-		Rules: []costExplorerTypes.CostCategoryRule{
-			{
-				Rule: &costExplorerTypes.Expression{
-					Dimensions: &costExplorerTypes.DimensionValues{
-						Key:    "LINKED_ACCOUNT",
-						Values: accounts,
-					},
-				},
-				Value: OUid,
-			},
-		},
+		Name:         OUid,
+		RuleVersion:  costCategoryRuleVersion,
+		Rules:        costCategoryRules(*OUid, accounts),
+		ResourceTags: costCategoryTags(root),
 	})
 	if err != nil {
 		return err
@@ -130,7 +130,7 @@ func TestCreateCostCategory(t *testing.T) {
 
 			OU := &organizationTypes.OrganizationalUnit{Id: tc.OUid, Name: tc.name}
 
-			err := createCostCategory(tc.OUid, OU, mocks.mockAWSClient)
+			err := createCostCategory(tc.OUid, OU, *tc.OUid, mocks.mockAWSClient)
 
 			if tc.errExpected {
 				g.Expect(err).Should(gomega.HaveOccurred())
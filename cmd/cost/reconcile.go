@@ -3,6 +3,7 @@ package cost
 import (
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
 	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
@@ -18,6 +19,24 @@ func newCmdReconcile(streams genericclioptions.IOStreams) *cobra.Command {
 	reconcileCmd := &cobra.Command{
 		Use:   "reconcile",
 		Short: "Checks if there's a cost category for every OU. If an OU is missing a cost category, creates the cost category",
+		Long: `Checks if there's a cost category for every OU. If an OU is missing a cost category, creates the cost category.
+
+With --plan, compares the cost category of every OU under --ou to the accounts of the OU and prints the cost
+categories to add, update and delete, without changing them. The plan can be written to a JSON file with --plan-file,
+reviewed, then applied with --apply. Applying fails when the cost categories changed since the plan was made.
+
+Cost categories are tagged with the OU under which they are created. The cost categories of OUs without accounts are
+deleted, as a cost category can't be empty. Of the cost categories named after an OU which doesn't exist anymore,
+only the ones tagged with --ou are deleted. The untagged ones, created by hand or by older versions, and
+the ones tagged with their own OU by osdctl cost create are only deleted with --prune.
+
+Updating a cost category whose rules were edited by hand replaces its rules by a single rule matching the accounts of
+its OU, keeping its default value and split charges. The plan marks these updates.`,
+		Example: `  # Review the changes to the cost categories of the OUs under an OU
+  osdctl cost reconcile --ou ou-abcd-12345678 --plan --plan-file plan.json
+
+  # Apply the reviewed plan
+  osdctl cost reconcile --ou ou-abcd-12345678 --apply plan.json`,
 		Run: func(cmd *cobra.Command, args []string) {
 
 			awsClient, err := opsCost.initAWSClients()
@@ -29,15 +48,60 @@ func newCmdReconcile(streams genericclioptions.IOStreams) *cobra.Command {
 				log.Fatalln("OU flag:", err)
 			}
 
+			plan, _ := cmd.Flags().GetBool("plan")
+			prune, _ := cmd.Flags().GetBool("prune")
+			planFile, _ := cmd.Flags().GetString("plan-file")
+			applyFile, _ := cmd.Flags().GetString("apply")
+			if prune && !plan && planFile == "" {
+				log.Fatalln("--prune can only be used with --plan or --plan-file")
+			}
+
+			if applyFile != "" {
+				costCategoryPlan, err := readCostCategoryPlan(applyFile)
+				if err != nil {
+					log.Fatalln("Error reading plan:", err)
+				}
+				if costCategoryPlan.Root != OUid {
+					log.Fatalf("The plan %s was made for OU %s, not %s", applyFile, costCategoryPlan.Root, OUid)
+				}
+				costCategoryPlan.printDiff(os.Stdout)
+				if err := applyCostCategoryPlan(costCategoryPlan, awsClient, os.Stdout); err != nil {
+					log.Fatalln("Error applying plan:", err)
+				}
+				return
+			}
+
 			//Get information regarding Organizational Unit
 			OU := getOU(awsClient, OUid)
 
+			if plan || planFile != "" {
+				costCategoryPlan, err := planCostCategories(OU, awsClient, prune)
+				if err != nil {
+					log.Fatalln("Error planning cost categories:", err)
+				}
+				costCategoryPlan.printDiff(os.Stdout)
+				if planFile != "" {
+					if err := writeCostCategoryPlan(planFile, costCategoryPlan); err != nil {
+						log.Fatalln("Error writing plan:", err)
+					}
+					fmt.Printf("Plan written to %s, apply it with: osdctl cost reconcile --ou %s --apply %s\n", planFile, OUid, planFile)
+				}
+				return
+			}
+
 			if err := reconcileCostCategories(OU, awsClient); err != nil {
 				log.Fatalln("Error reconciling cost categories:", err)
 			}
 		},
 	}
 	reconcileCmd.Flags().String("ou", "", "get OU ID")
+	reconcileCmd.Flags().Bool("plan", false, "print the cost categories to add, update and delete without changing them")
+	reconcileCmd.Flags().String("plan-file", "", "write the plan as JSON to this file, implies --plan")
+	reconcileCmd.Flags().String("apply", "", "apply the plan of this JSON file, written with --plan-file")
+	reconcileCmd.Flags().Bool("prune", false, "also plan the deletion of the cost categories of deleted OUs which aren't tagged with their root OU, requires --plan or --plan-file")
+	reconcileCmd.MarkFlagsMutuallyExclusive("plan", "apply")
+	reconcileCmd.MarkFlagsMutuallyExclusive("plan-file", "apply")
+	reconcileCmd.MarkFlagsMutuallyExclusive("prune", "apply")
 	if err := reconcileCmd.MarkFlagRequired("ou"); err != nil {
 		log.Fatalln("OU flag:", err)
 	}
@@ -73,6 +137,7 @@ func reconcileCostCategories(OU *organizationTypes.OrganizationalUnit, awsClient
 		nextToken = existingCostCategories.NextToken //If NextToken != nil, keep looping
 	}
 
+	root := *OU.Id
 	OUs, err := getOUsRecursive(OU, awsClient)
 	if err != nil {
 		return err
@@ -80,7 +145,7 @@ func reconcileCostCategories(OU *organizationTypes.OrganizationalUnit, awsClient
 	//Loop through every OU under OpenShift and create cost category if missing
 	for _, OU := range OUs {
 		if !costCategoriesSet.Contains(*OU.Id) {
-			if err := createCostCategory(OU.Id, OU, awsClient); err != nil {
+			if err := createCostCategory(OU.Id, OU, root, awsClient); err != nil {
 				return err
 			}
 			costCategoryCreated = true
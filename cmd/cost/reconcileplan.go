@@ -0,0 +1,380 @@
+package cost
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+)
+
+const (
+	costCategoryRuleVersion = "CostCategoryExpression.v1"
+	// costCategoryRootTag is the tag of the cost categories created by osdctl, whose value is the root OU they were
+	// created under
+	costCategoryRootTag = "osdctl-root-ou"
+
+	planActionAdd    = "add"
+	planActionUpdate = "update"
+	planActionDelete = "delete"
+)
+
+// costCategoryPlan holds the changes reconciling the cost categories of the OUs under a root OU
+type costCategoryPlan struct {
+	Root    string               `json:"root"`
+	Changes []costCategoryChange `json:"changes"`
+}
+
+// costCategoryChange is the addition, update or deletion of the cost category of an OU
+type costCategoryChange struct {
+	Action string `json:"action"`
+	Name   string `json:"name"`
+	// OUName is unknown for the cost categories of deleted OUs
+	OUName string `json:"ouName,omitempty"`
+	Arn    string `json:"arn,omitempty"`
+	// Before and After are the linked accounts of the cost category
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+	// RewritesRules is set on updates of cost categories whose rules were edited by hand, which the update replaces
+	// by the single rule of costCategoryRules. Their default value and split charge rules are kept.
+	RewritesRules bool `json:"rewritesRules,omitempty"`
+}
+
+// costCategoryRules returns the rules of the cost category of an OU, which matches the accounts of the OU
+func costCategoryRules(name string, accounts []string) []costExplorerTypes.CostCategoryRule {
+	return []costExplorerTypes.CostCategoryRule{
+		{
+			Rule: &costExplorerTypes.Expression{
+				Dimensions: &costExplorerTypes.DimensionValues{
+					Key:    costExplorerTypes.DimensionLinkedAccount,
+					Values: accounts,
+				},
+			},
+			Value: &name,
+		},
+	}
+}
+
+// costCategoryTags returns the tags of the cost categories created under a root OU
+func costCategoryTags(root string) []costExplorerTypes.ResourceTag {
+	return []costExplorerTypes.ResourceTag{{Key: aws.String(costCategoryRootTag), Value: aws.String(root)}}
+}
+
+// costCategoryRoot returns the root OU a cost category was created under, empty when it has no root OU tag
+func costCategoryRoot(awsClient awsprovider.Client, name string, arn string) (string, error) {
+	output, err := awsClient.ListCostCategoryTags(&costexplorer.ListTagsForResourceInput{ResourceArn: &arn})
+	if err != nil {
+		return "", fmt.Errorf("failed to list the tags of cost category %s: %w", name, err)
+	}
+	for _, tag := range output.ResourceTags {
+		if tag.Key != nil && *tag.Key == costCategoryRootTag && tag.Value != nil {
+			return *tag.Value, nil
+		}
+	}
+	return "", nil
+}
+
+// listCostCategories returns the cost categories by name
+func listCostCategories(awsClient awsprovider.Client) (map[string]costExplorerTypes.CostCategoryReference, error) {
+	costCategories := map[string]costExplorerTypes.CostCategoryReference{}
+	var nextToken *string
+	for {
+		output, err := awsClient.ListCostCategoryDefinitions(&costexplorer.ListCostCategoryDefinitionsInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, costCategory := range output.CostCategoryReferences {
+			if costCategory.Name != nil {
+				costCategories[*costCategory.Name] = costCategory
+			}
+		}
+		if output.NextToken == nil {
+			return costCategories, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// describeCostCategory returns the definition of a cost category
+func describeCostCategory(awsClient awsprovider.Client, name string, arn string) (*costExplorerTypes.CostCategory, error) {
+	output, err := awsClient.DescribeCostCategoryDefinition(&costexplorer.DescribeCostCategoryDefinitionInput{
+		CostCategoryArn: &arn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cost category %s: %w", name, err)
+	}
+	if output.CostCategory == nil {
+		return nil, fmt.Errorf("cost category %s not found", name)
+	}
+	return output.CostCategory, nil
+}
+
+// describeCostCategoryAccounts describes a cost category and returns its costCategoryAccounts
+func describeCostCategoryAccounts(awsClient awsprovider.Client, name string, arn string) ([]string, bool, error) {
+	costCategory, err := describeCostCategory(awsClient, name, arn)
+	if err != nil {
+		return nil, false, err
+	}
+	accounts, managed := costCategoryAccounts(name, costCategory)
+	return accounts, managed, nil
+}
+
+// costCategoryAccounts returns the sorted linked accounts of a cost category, and whether its rules are the ones
+// of costCategoryRules
+func costCategoryAccounts(name string, costCategory *costExplorerTypes.CostCategory) ([]string, bool) {
+	var accounts []string
+	rules := costCategory.Rules
+	managed := len(rules) == 1
+	for _, rule := range rules {
+		if rule.Value == nil || *rule.Value != name || rule.Rule == nil || rule.Rule.Dimensions == nil ||
+			rule.Rule.Dimensions.Key != costExplorerTypes.DimensionLinkedAccount {
+			managed = false
+			continue
+		}
+		accounts = append(accounts, rule.Rule.Dimensions.Values...)
+	}
+	return sortedAccounts(accounts), managed
+}
+
+// sortedAccounts sorts the accounts and drops duplicates and empty ones
+func sortedAccounts(accounts []string) []string {
+	accounts = slices.DeleteFunc(slices.Clone(accounts), func(account string) bool { return account == "" })
+	slices.Sort(accounts)
+	return slices.Compact(accounts)
+}
+
+// ouExists returns whether an OU exists anywhere in the organization
+func ouExists(awsClient awsprovider.Client, OUid string) (bool, error) {
+	_, err := awsClient.DescribeOrganizationalUnit(&organizations.DescribeOrganizationalUnitInput{
+		OrganizationalUnitId: &OUid,
+	})
+	var notFound *organizationTypes.OrganizationalUnitNotFoundException
+	if errors.As(err, &notFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// planCostCategories compares the cost category of every OU under the root OU to the accounts of the OU. OUs
+// without accounts get no cost category, as a cost category can't be empty, and the one they had is deleted. Cost
+// categories named after an OU which was
+// deleted are deleted when tagged with the root OU. The untagged ones, created before the tag or by hand, and the
+// ones tagged with their own OU by osdctl cost create are only deleted with prune, as they may belong to another
+// root OU.
+func planCostCategories(root *organizationTypes.OrganizationalUnit, awsClient awsprovider.Client, prune bool) (*costCategoryPlan, error) {
+	existing, err := listCostCategories(awsClient)
+	if err != nil {
+		return nil, err
+	}
+	OUs, err := getOUsRecursive(root, awsClient)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &costCategoryPlan{Root: *root.Id, Changes: []costCategoryChange{}}
+	desired := map[string]bool{}
+	for _, OU := range OUs {
+		desired[*OU.Id] = true
+
+		accountIDs, err := getAccountsRecursive(OU, awsClient)
+		if err != nil {
+			return nil, err
+		}
+		var accounts []string
+		for _, account := range accountIDs {
+			accounts = append(accounts, *account)
+		}
+		accounts = sortedAccounts(accounts)
+
+		costCategory, ok := existing[*OU.Id]
+		if len(accounts) == 0 {
+			if ok {
+				before, _, err := describeCostCategoryAccounts(awsClient, *OU.Id, *costCategory.CostCategoryArn)
+				if err != nil {
+					return nil, err
+				}
+				plan.Changes = append(plan.Changes, costCategoryChange{Action: planActionDelete, Name: *OU.Id, OUName: *OU.Name, Arn: *costCategory.CostCategoryArn, Before: before})
+			}
+			continue
+		}
+		if !ok {
+			plan.Changes = append(plan.Changes, costCategoryChange{Action: planActionAdd, Name: *OU.Id, OUName: *OU.Name, After: accounts})
+			continue
+		}
+		before, managed, err := describeCostCategoryAccounts(awsClient, *OU.Id, *costCategory.CostCategoryArn)
+		if err != nil {
+			return nil, err
+		}
+		if !managed || !slices.Equal(before, accounts) {
+			plan.Changes = append(plan.Changes, costCategoryChange{Action: planActionUpdate, Name: *OU.Id, OUName: *OU.Name, Arn: *costCategory.CostCategoryArn, Before: before, After: accounts, RewritesRules: !managed})
+		}
+	}
+
+	var names []string
+	for name := range existing {
+		if !desired[name] && strings.HasPrefix(name, "ou-") {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		exists, err := ouExists(awsClient, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check OU %s: %w", name, err)
+		}
+		if exists {
+			continue
+		}
+		arn := *existing[name].CostCategoryArn
+		costCategoryRoot, err := costCategoryRoot(awsClient, name, arn)
+		if err != nil {
+			return nil, err
+		}
+		if costCategoryRoot != *root.Id && ((costCategoryRoot != "" && costCategoryRoot != name) || !prune) {
+			continue
+		}
+		before, _, err := describeCostCategoryAccounts(awsClient, name, arn)
+		if err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, costCategoryChange{Action: planActionDelete, Name: name, Arn: arn, Before: before})
+	}
+
+	return plan, nil
+}
+
+// printDiff prints the changes of the plan, with the accounts added to and removed from each cost category
+func (p *costCategoryPlan) printDiff(w io.Writer) {
+	if len(p.Changes) == 0 {
+		fmt.Fprintln(w, "Cost categories are up-to-date. No changes.")
+		return
+	}
+
+	counts := map[string]int{}
+	for _, change := range p.Changes {
+		counts[change.Action]++
+
+		name := change.Name
+		if change.OUName != "" {
+			name = fmt.Sprintf("%s (%s)", change.Name, change.OUName)
+		}
+		switch change.Action {
+		case planActionAdd:
+			fmt.Fprintf(w, "+ %s: %d account(s)\n", name, len(change.After))
+		case planActionUpdate:
+			fmt.Fprintf(w, "~ %s: %d -> %d account(s)\n", name, len(change.Before), len(change.After))
+			if change.RewritesRules {
+				fmt.Fprintln(w, "~     rules edited by hand are replaced, the default value and split charges are kept")
+			}
+		case planActionDelete:
+			fmt.Fprintf(w, "- %s: %d account(s)\n", name, len(change.Before))
+		}
+		for _, account := range change.After {
+			if !slices.Contains(change.Before, account) {
+				fmt.Fprintf(w, "+     %s\n", account)
+			}
+		}
+		for _, account := range change.Before {
+			if !slices.Contains(change.After, account) {
+				fmt.Fprintf(w, "-     %s\n", account)
+			}
+		}
+	}
+	fmt.Fprintf(w, "Plan: %d to add, %d to update, %d to delete.\n", counts[planActionAdd], counts[planActionUpdate], counts[planActionDelete])
+}
+
+func writeCostCategoryPlan(path string, plan *costCategoryPlan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+func readCostCategoryPlan(path string) (*costCategoryPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	plan := &costCategoryPlan{}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	for _, change := range plan.Changes {
+		if !slices.Contains([]string{planActionAdd, planActionUpdate, planActionDelete}, change.Action) {
+			return nil, fmt.Errorf("invalid action %q of cost category %s in plan %s", change.Action, change.Name, path)
+		}
+	}
+	return plan, nil
+}
+
+// applyCostCategoryPlan applies the changes of a plan, once checked that the cost categories didn't change since
+// the plan was made
+func applyCostCategoryPlan(plan *costCategoryPlan, awsClient awsprovider.Client, w io.Writer) error {
+	existing, err := listCostCategories(awsClient)
+	if err != nil {
+		return err
+	}
+	definitions := map[string]*costExplorerTypes.CostCategory{}
+	for _, change := range plan.Changes {
+		costCategory, ok := existing[change.Name]
+		if change.Action == planActionAdd {
+			if ok {
+				return fmt.Errorf("cost category %s was created since the plan was made, make a new plan", change.Name)
+			}
+			continue
+		}
+		if !ok || *costCategory.CostCategoryArn != change.Arn {
+			return fmt.Errorf("cost category %s was deleted since the plan was made, make a new plan", change.Name)
+		}
+		definition, err := describeCostCategory(awsClient, change.Name, change.Arn)
+		if err != nil {
+			return err
+		}
+		accounts, managed := costCategoryAccounts(change.Name, definition)
+		if !slices.Equal(accounts, change.Before) || (change.Action == planActionUpdate && managed == change.RewritesRules) {
+			return fmt.Errorf("cost category %s was changed since the plan was made, make a new plan", change.Name)
+		}
+		definitions[change.Name] = definition
+	}
+
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case planActionAdd:
+			_, err = awsClient.CreateCostCategoryDefinition(&costexplorer.CreateCostCategoryDefinitionInput{
+				Name:         &change.Name,
+				RuleVersion:  costCategoryRuleVersion,
+				Rules:        costCategoryRules(change.Name, change.After),
+				ResourceTags: costCategoryTags(plan.Root),
+			})
+		case planActionUpdate:
+			_, err = awsClient.UpdateCostCategoryDefinition(&costexplorer.UpdateCostCategoryDefinitionInput{
+				CostCategoryArn:  &change.Arn,
+				RuleVersion:      costCategoryRuleVersion,
+				Rules:            costCategoryRules(change.Name, change.After),
+				DefaultValue:     definitions[change.Name].DefaultValue,
+				SplitChargeRules: definitions[change.Name].SplitChargeRules,
+			})
+		case planActionDelete:
+			_, err = awsClient.DeleteCostCategoryDefinition(&costexplorer.DeleteCostCategoryDefinitionInput{
+				CostCategoryArn: &change.Arn,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s cost category %s: %w", change.Action, change.Name, err)
+		}
+		fmt.Fprintf(w, "Applied %s of cost category %s\n", change.Action, change.Name)
+	}
+	return nil
+}
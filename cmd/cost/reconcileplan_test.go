@@ -0,0 +1,232 @@
+package cost
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// mockCostCategories mocks the listing and description of cost categories, named arn-<name>, with their accounts
+func mockCostCategories(mockClient *mock.MockClient, costCategories map[string][]string) {
+	mockEditedCostCategories(mockClient, costCategories, nil)
+}
+
+// mockEditedCostCategories mocks cost categories like mockCostCategories, the edited ones being described as given
+func mockEditedCostCategories(mockClient *mock.MockClient, costCategories map[string][]string, edited map[string]*costExplorerTypes.CostCategory) {
+	mockClient.EXPECT().ListCostCategoryDefinitions(gomock.Any()).DoAndReturn(func(*costexplorer.ListCostCategoryDefinitionsInput) (*costexplorer.ListCostCategoryDefinitionsOutput, error) {
+		output := &costexplorer.ListCostCategoryDefinitionsOutput{}
+		for name := range costCategories {
+			output.CostCategoryReferences = append(output.CostCategoryReferences, costExplorerTypes.CostCategoryReference{
+				Name:            aws.String(name),
+				CostCategoryArn: aws.String("arn-" + name),
+			})
+		}
+		return output, nil
+	}).AnyTimes()
+	mockClient.EXPECT().DescribeCostCategoryDefinition(gomock.Any()).DoAndReturn(func(input *costexplorer.DescribeCostCategoryDefinitionInput) (*costexplorer.DescribeCostCategoryDefinitionOutput, error) {
+		name := (*input.CostCategoryArn)[len("arn-"):]
+		if costCategory, ok := edited[name]; ok {
+			return &costexplorer.DescribeCostCategoryDefinitionOutput{CostCategory: costCategory}, nil
+		}
+		return &costexplorer.DescribeCostCategoryDefinitionOutput{CostCategory: &costExplorerTypes.CostCategory{
+			Name:  aws.String(name),
+			Rules: costCategoryRules(name, costCategories[name]),
+		}}, nil
+	}).AnyTimes()
+}
+
+// mockCostCategoryRoots mocks the root OU tags of the cost categories, named arn-<name>
+func mockCostCategoryRoots(mockClient *mock.MockClient, roots map[string]string) {
+	mockClient.EXPECT().ListCostCategoryTags(gomock.Any()).DoAndReturn(func(input *costexplorer.ListTagsForResourceInput) (*costexplorer.ListTagsForResourceOutput, error) {
+		output := &costexplorer.ListTagsForResourceOutput{ResourceTags: []costExplorerTypes.ResourceTag{{Key: aws.String("team"), Value: aws.String("sre")}}}
+		if root, ok := roots[(*input.ResourceArn)[len("arn-"):]]; ok {
+			output.ResourceTags = append(output.ResourceTags, costExplorerTypes.ResourceTag{Key: aws.String(costCategoryRootTag), Value: aws.String(root)})
+		}
+		return output, nil
+	}).AnyTimes()
+}
+
+func mockOrganization(mockClient *mock.MockClient) *organizationTypes.OrganizationalUnit {
+	accounts := map[string][]organizationTypes.Account{
+		"ou-a": {{Id: aws.String("222")}, {Id: aws.String("111")}},
+		"ou-b": {{Id: aws.String("333")}},
+		"ou-d": {{Id: aws.String("444")}},
+	}
+	mockClient.EXPECT().ListAccountsForParent(gomock.Any()).DoAndReturn(func(input *organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error) {
+		return &organizations.ListAccountsForParentOutput{Accounts: accounts[*input.ParentId]}, nil
+	}).AnyTimes()
+	mockClient.EXPECT().ListOrganizationalUnitsForParent(gomock.Any()).DoAndReturn(func(input *organizations.ListOrganizationalUnitsForParentInput) (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+		if *input.ParentId != "ou-root" {
+			return &organizations.ListOrganizationalUnitsForParentOutput{}, nil
+		}
+		var OUs []organizationTypes.OrganizationalUnit
+		for _, id := range []string{"ou-a", "ou-b", "ou-c", "ou-d"} {
+			OUs = append(OUs, organizationTypes.OrganizationalUnit{Id: aws.String(id), Name: aws.String("Team " + id[3:])})
+		}
+		return &organizations.ListOrganizationalUnitsForParentOutput{OrganizationalUnits: OUs}, nil
+	}).AnyTimes()
+	mockClient.EXPECT().DescribeOrganizationalUnit(gomock.Any()).DoAndReturn(func(input *organizations.DescribeOrganizationalUnitInput) (*organizations.DescribeOrganizationalUnitOutput, error) {
+		if *input.OrganizationalUnitId == "ou-other" {
+			return &organizations.DescribeOrganizationalUnitOutput{}, nil
+		}
+		return nil, &organizationTypes.OrganizationalUnitNotFoundException{}
+	}).AnyTimes()
+	return &organizationTypes.OrganizationalUnit{Id: aws.String("ou-root"), Name: aws.String("Root")}
+}
+
+// editedCostCategory returns a cost category with the rules of costCategoryRules, an extra rule and a default value
+func editedCostCategory(name string, accounts []string) *costExplorerTypes.CostCategory {
+	return &costExplorerTypes.CostCategory{
+		Name: aws.String(name),
+		Rules: append(costCategoryRules(name, accounts), costExplorerTypes.CostCategoryRule{
+			Rule:  &costExplorerTypes.Expression{Tags: &costExplorerTypes.TagValues{Key: aws.String("team"), Values: []string{"sre"}}},
+			Value: aws.String("sre"),
+		}),
+		DefaultValue: aws.String("other"),
+	}
+}
+
+func TestPlanCostCategories(t *testing.T) {
+	mockClient := mock.NewMockClient(gomock.NewController(t))
+	root := mockOrganization(mockClient)
+	mockEditedCostCategories(mockClient, map[string][]string{
+		"ou-b":          {"333", "999"},
+		"ou-c":          {"321"},
+		"ou-d":          {"444"},
+		"ou-gone":       {"555"},
+		"ou-other":      {"666"},
+		"Team":          {"777"},
+		"ou-untagged":   {"888"},
+		"ou-other-root": {"999"},
+		"ou-created":    {"123"},
+	}, map[string]*costExplorerTypes.CostCategory{"ou-d": editedCostCategory("ou-d", []string{"444"})})
+	mockCostCategoryRoots(mockClient, map[string]string{"ou-gone": "ou-root", "ou-other-root": "ou-elsewhere", "ou-created": "ou-created"})
+
+	plan, err := planCostCategories(root, mockClient, false)
+	require.NoError(t, err)
+	assert.Equal(t, &costCategoryPlan{Root: "ou-root", Changes: []costCategoryChange{
+		{Action: planActionAdd, Name: "ou-a", OUName: "Team a", After: []string{"111", "222"}},
+		{Action: planActionUpdate, Name: "ou-b", OUName: "Team b", Arn: "arn-ou-b", Before: []string{"333", "999"}, After: []string{"333"}},
+		{Action: planActionDelete, Name: "ou-c", OUName: "Team c", Arn: "arn-ou-c", Before: []string{"321"}},
+		{Action: planActionUpdate, Name: "ou-d", OUName: "Team d", Arn: "arn-ou-d", Before: []string{"444"}, After: []string{"444"}, RewritesRules: true},
+		{Action: planActionDelete, Name: "ou-gone", Arn: "arn-ou-gone", Before: []string{"555"}},
+	}}, plan)
+
+	var out bytes.Buffer
+	plan.printDiff(&out)
+	assert.Equal(t, `+ ou-a (Team a): 2 account(s)
++     111
++     222
+~ ou-b (Team b): 2 -> 1 account(s)
+-     999
+- ou-c (Team c): 1 account(s)
+-     321
+~ ou-d (Team d): 1 -> 1 account(s)
+~     rules edited by hand are replaced, the default value and split charges are kept
+- ou-gone: 1 account(s)
+-     555
+Plan: 1 to add, 2 to update, 2 to delete.
+`, out.String())
+
+	// Pruning deletes the untagged cost categories of deleted OUs and the ones tagged with their own OU, never the
+	// ones of another root OU
+	plan, err = planCostCategories(root, mockClient, true)
+	require.NoError(t, err)
+	var deleted []string
+	for _, change := range plan.Changes {
+		if change.Action == planActionDelete {
+			deleted = append(deleted, change.Name)
+		}
+	}
+	assert.Equal(t, []string{"ou-c", "ou-created", "ou-gone", "ou-untagged"}, deleted)
+}
+
+func TestCostCategoryPlanFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	plan := &costCategoryPlan{Root: "ou-root", Changes: []costCategoryChange{{Action: planActionAdd, Name: "ou-a", After: []string{"111"}}}}
+	require.NoError(t, writeCostCategoryPlan(path, plan))
+
+	read, err := readCostCategoryPlan(path)
+	require.NoError(t, err)
+	assert.Equal(t, plan, read)
+
+	plan.Changes[0].Action = "replace"
+	require.NoError(t, writeCostCategoryPlan(path, plan))
+	_, err = readCostCategoryPlan(path)
+	assert.ErrorContains(t, err, `invalid action "replace" of cost category ou-a`)
+}
+
+func TestApplyCostCategoryPlan(t *testing.T) {
+	plan := &costCategoryPlan{Root: "ou-root", Changes: []costCategoryChange{
+		{Action: planActionAdd, Name: "ou-a", After: []string{"111"}},
+		{Action: planActionUpdate, Name: "ou-b", Arn: "arn-ou-b", Before: []string{"333", "999"}, After: []string{"333"}},
+		{Action: planActionDelete, Name: "ou-gone", Arn: "arn-ou-gone", Before: []string{"555"}},
+	}}
+
+	t.Run("applies the changes", func(t *testing.T) {
+		mockClient := mock.NewMockClient(gomock.NewController(t))
+		mockCostCategories(mockClient, map[string][]string{"ou-b": {"999", "333"}, "ou-gone": {"555"}})
+		gomock.InOrder(
+			mockClient.EXPECT().CreateCostCategoryDefinition(gomock.Any()).DoAndReturn(func(input *costexplorer.CreateCostCategoryDefinitionInput) (*costexplorer.CreateCostCategoryDefinitionOutput, error) {
+				assert.Equal(t, "ou-a", *input.Name)
+				assert.Equal(t, []string{"111"}, input.Rules[0].Rule.Dimensions.Values)
+				assert.Equal(t, costCategoryTags("ou-root"), input.ResourceTags)
+				return &costexplorer.CreateCostCategoryDefinitionOutput{}, nil
+			}),
+			mockClient.EXPECT().UpdateCostCategoryDefinition(gomock.Any()).DoAndReturn(func(input *costexplorer.UpdateCostCategoryDefinitionInput) (*costexplorer.UpdateCostCategoryDefinitionOutput, error) {
+				assert.Equal(t, "arn-ou-b", *input.CostCategoryArn)
+				assert.Equal(t, []string{"333"}, input.Rules[0].Rule.Dimensions.Values)
+				return &costexplorer.UpdateCostCategoryDefinitionOutput{}, nil
+			}),
+			mockClient.EXPECT().DeleteCostCategoryDefinition(&costexplorer.DeleteCostCategoryDefinitionInput{CostCategoryArn: aws.String("arn-ou-gone")}).Return(&costexplorer.DeleteCostCategoryDefinitionOutput{}, nil),
+		)
+
+		var out bytes.Buffer
+		require.NoError(t, applyCostCategoryPlan(plan, mockClient, &out))
+		assert.Equal(t, "Applied add of cost category ou-a\nApplied update of cost category ou-b\nApplied delete of cost category ou-gone\n", out.String())
+	})
+
+	t.Run("keeps the default value of rewritten rules", func(t *testing.T) {
+		plan := &costCategoryPlan{Root: "ou-root", Changes: []costCategoryChange{
+			{Action: planActionUpdate, Name: "ou-d", Arn: "arn-ou-d", Before: []string{"444"}, After: []string{"444"}, RewritesRules: true},
+		}}
+		mockClient := mock.NewMockClient(gomock.NewController(t))
+		mockEditedCostCategories(mockClient, map[string][]string{"ou-d": nil}, map[string]*costExplorerTypes.CostCategory{"ou-d": editedCostCategory("ou-d", []string{"444"})})
+		mockClient.EXPECT().UpdateCostCategoryDefinition(gomock.Any()).DoAndReturn(func(input *costexplorer.UpdateCostCategoryDefinitionInput) (*costexplorer.UpdateCostCategoryDefinitionOutput, error) {
+			assert.Equal(t, costCategoryRules("ou-d", []string{"444"}), input.Rules)
+			assert.Equal(t, "other", *input.DefaultValue)
+			return &costexplorer.UpdateCostCategoryDefinitionOutput{}, nil
+		})
+		require.NoError(t, applyCostCategoryPlan(plan, mockClient, &bytes.Buffer{}))
+	})
+
+	t.Run("rules edited since the plan was made", func(t *testing.T) {
+		mockClient := mock.NewMockClient(gomock.NewController(t))
+		mockEditedCostCategories(mockClient, map[string][]string{"ou-b": {"333", "999"}, "ou-gone": {"555"}}, map[string]*costExplorerTypes.CostCategory{"ou-b": editedCostCategory("ou-b", []string{"333", "999"})})
+		assert.EqualError(t, applyCostCategoryPlan(plan, mockClient, &bytes.Buffer{}), "cost category ou-b was changed since the plan was made, make a new plan")
+	})
+
+	t.Run("stale plan", func(t *testing.T) {
+		mockClient := mock.NewMockClient(gomock.NewController(t))
+		mockCostCategories(mockClient, map[string][]string{"ou-b": {"333", "888"}, "ou-gone": {"555"}})
+		assert.EqualError(t, applyCostCategoryPlan(plan, mockClient, &bytes.Buffer{}), "cost category ou-b was changed since the plan was made, make a new plan")
+	})
+
+	t.Run("failed change", func(t *testing.T) {
+		mockClient := mock.NewMockClient(gomock.NewController(t))
+		mockCostCategories(mockClient, map[string][]string{"ou-b": {"333", "999"}, "ou-gone": {"555"}})
+		mockClient.EXPECT().CreateCostCategoryDefinition(gomock.Any()).Return(nil, errors.New("limit exceeded"))
+		assert.EqualError(t, applyCostCategoryPlan(plan, mockClient, &bytes.Buffer{}), "failed to add cost category ou-a: limit exceeded")
+	})
+}
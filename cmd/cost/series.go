@@ -0,0 +1,215 @@
+package cost
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	costMetric = "NetUnblendedCost"
+	dateLayout = "2006-01-02"
+
+	// maxFilterAccounts is the number of accounts filtered on per Cost Explorer request
+	maxFilterAccounts = 100
+)
+
+// costTable is the cost per period of accounts, OUs or services
+type costTable struct {
+	// Periods are the start dates of the periods
+	Periods []string
+	Unit    string
+	// Costs holds a cost per period of every key
+	Costs map[string][]decimal.Decimal
+}
+
+// costPeriods returns the start dates of the periods between start and end, end excluded. Like Cost
+// Explorer, the first monthly period starts at start even when it isn't the first day of a month.
+func costPeriods(start time.Time, end time.Time, granularity costExplorerTypes.Granularity) []string {
+	var periods []string
+	for t := start; t.Before(end); {
+		periods = append(periods, t.Format(dateLayout))
+		if granularity == costExplorerTypes.GranularityMonthly {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		} else {
+			t = t.AddDate(0, 0, 1)
+		}
+	}
+	return periods
+}
+
+// getCostTable returns the cost of the accounts between start and end, grouped by a dimension like
+// LINKED_ACCOUNT or SERVICE. With tags, only the cost of the resources with one of the tag values is returned.
+func getCostTable(awsClient awsprovider.Client, accounts []string, start time.Time, end time.Time, granularity costExplorerTypes.Granularity, dimension costExplorerTypes.Dimension, tags *costExplorerTypes.TagValues) (*costTable, error) {
+	table := &costTable{
+		Periods: costPeriods(start, end, granularity),
+		Costs:   map[string][]decimal.Decimal{},
+	}
+	periodIndex := map[string]int{}
+	for i, period := range table.Periods {
+		periodIndex[period] = i
+	}
+	startDate, endDate := start.Format(dateLayout), end.Format(dateLayout)
+
+	for chunk := range slices.Chunk(accounts, maxFilterAccounts) {
+		filter := &costExplorerTypes.Expression{
+			Dimensions: &costExplorerTypes.DimensionValues{
+				Key:    costExplorerTypes.DimensionLinkedAccount,
+				Values: chunk,
+			},
+		}
+		if tags != nil {
+			filter = &costExplorerTypes.Expression{And: []costExplorerTypes.Expression{*filter, {Tags: tags}}}
+		}
+
+		var nextToken *string
+		for {
+			costs, err := awsClient.GetCostAndUsage(&costexplorer.GetCostAndUsageInput{
+				Filter: filter,
+				TimePeriod: &costExplorerTypes.DateInterval{
+					Start: &startDate,
+					End:   &endDate,
+				},
+				Granularity: granularity,
+				Metrics:     []string{costMetric},
+				GroupBy: []costExplorerTypes.GroupDefinition{
+					{Type: costExplorerTypes.GroupDefinitionTypeDimension, Key: (*string)(&dimension)},
+				},
+				NextPageToken: nextToken,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, result := range costs.ResultsByTime {
+				if result.TimePeriod == nil || result.TimePeriod.Start == nil {
+					continue
+				}
+				i, ok := periodIndex[*result.TimePeriod.Start]
+				if !ok {
+					return nil, fmt.Errorf("unexpected cost period starting on %s", *result.TimePeriod.Start)
+				}
+				for _, group := range result.Groups {
+					metric, ok := group.Metrics[costMetric]
+					if !ok || metric.Amount == nil || len(group.Keys) == 0 {
+						continue
+					}
+					amount, err := decimal.NewFromString(*metric.Amount)
+					if err != nil {
+						return nil, err
+					}
+					if metric.Unit != nil {
+						if table.Unit != "" && table.Unit != *metric.Unit {
+							return nil, fmt.Errorf("can't sum up different currencies: %s and %s", table.Unit, *metric.Unit)
+						}
+						table.Unit = *metric.Unit
+					}
+					table.add(group.Keys[0], i, amount)
+				}
+			}
+
+			if costs.NextPageToken == nil {
+				break
+			}
+			nextToken = costs.NextPageToken
+		}
+	}
+
+	return table, nil
+}
+
+func (t *costTable) add(key string, period int, amount decimal.Decimal) {
+	costs, ok := t.Costs[key]
+	if !ok {
+		costs = make([]decimal.Decimal, len(t.Periods))
+		t.Costs[key] = costs
+	}
+	costs[period] = costs[period].Add(amount)
+}
+
+// regroup returns the table with the costs of the keys summed up under the key group returns, keys
+// without a group are dropped
+func (t *costTable) regroup(group func(key string) (string, bool)) *costTable {
+	regrouped := &costTable{Periods: t.Periods, Unit: t.Unit, Costs: map[string][]decimal.Decimal{}}
+	for key, costs := range t.Costs {
+		newKey, ok := group(key)
+		if !ok {
+			continue
+		}
+		for i, cost := range costs {
+			regrouped.add(newKey, i, cost)
+		}
+	}
+	return regrouped
+}
+
+// getScopeAccounts returns the accounts under an OU, recursively, and the given accounts
+func getScopeAccounts(awsClient awsprovider.Client, OU *organizationTypes.OrganizationalUnit, accounts []string) ([]string, error) {
+	scope := slices.Clone(accounts)
+	if OU != nil {
+		ouAccounts, err := getAccountsRecursive(OU, awsClient)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range ouAccounts {
+			scope = append(scope, *account)
+		}
+	}
+	slices.Sort(scope)
+	return slices.Compact(scope), nil
+}
+
+// getAccountOUs maps the accounts under an OU to the child OU they are in, or to the OU itself for its
+// immediate accounts
+func getAccountOUs(awsClient awsprovider.Client, OU *organizationTypes.OrganizationalUnit) (map[string]*organizationTypes.OrganizationalUnit, error) {
+	accountOUs := map[string]*organizationTypes.OrganizationalUnit{}
+
+	accounts, err := getAccounts(OU, awsClient)
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		accountOUs[*account] = OU
+	}
+
+	childOUs, err := getOUs(OU, awsClient)
+	if err != nil {
+		return nil, err
+	}
+	for _, childOU := range childOUs {
+		accounts, err := getAccountsRecursive(childOU, awsClient)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range accounts {
+			accountOUs[*account] = childOU
+		}
+	}
+
+	return accountOUs, nil
+}
+
+// parseDateRange returns the dates of the time flag or the start and end flags
+func parseDateRange(timeFlag string, startFlag string, endFlag string) (time.Time, time.Time, error) {
+	if timeFlag != "" {
+		startFlag, endFlag = getTimePeriod(&timeFlag)
+	}
+	start, err := time.Parse(dateLayout, startFlag)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date %q, expected YYYY-MM-DD", startFlag)
+	}
+	end, err := time.Parse(dateLayout, endFlag)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date %q, expected YYYY-MM-DD", endFlag)
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the start date %s must be before the end date %s", startFlag, endFlag)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,245 @@
+package cost
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	outputflag "github.com/openshift/osdctl/cmd/getoutput"
+	"github.com/openshift/osdctl/internal/utils/globalflags"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	groupByAccount = "account"
+	groupByOU      = "ou"
+	groupByService = "service"
+)
+
+// trendCmd represents the trend command
+func newCmdTrend(streams genericclioptions.IOStreams, globalOpts *globalflags.GlobalOptions) *cobra.Command {
+	ops := newTrendOptions(streams, globalOpts)
+	trendCmd := &cobra.Command{
+		Use:   "trend",
+		Short: "Get the cost per day or month of each account, OU or service",
+		Long: `Get the cost per day or month of each account, child OU or service of the given OU and accounts.
+
+With --group-by ou, the accounts are grouped by the immediate child OU of --ou they belong to, the immediate
+accounts of --ou are grouped under --ou itself.`,
+		Example: `  # Monthly cost of each child OU over the last 6 months
+  osdctl cost trend --ou ou-abcd-12345678 --group-by ou -t 6M
+
+  # Daily cost of each service of an account as csv
+  osdctl cost trend --account 123456789012 --group-by service --granularity daily --start 2026-01-01 --end 2026-02-01 --csv`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.checkArgs(cmd, args))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+	trendCmd.Flags().StringVar(&ops.ou, "ou", "", "set OU ID, all accounts under it are included")
+	trendCmd.Flags().StringArrayVar(&ops.accounts, "account", nil, "set account ID. Can be repeated")
+	trendCmd.Flags().StringVar(&ops.groupBy, "group-by", groupByAccount, "group the cost by 'account', 'ou' or 'service'")
+	trendCmd.Flags().StringVar(&ops.granularity, "granularity", "monthly", "one of 'daily' or 'monthly'")
+	trendCmd.Flags().StringVarP(&ops.time, "time", "t", "", "set time. One of 'LM', 'MTD', 'YTD', '3M', '6M', '1Y'")
+	trendCmd.Flags().StringVar(&ops.start, "start", "", "set start date range")
+	trendCmd.Flags().StringVar(&ops.end, "end", "", "set end date range, excluded")
+	trendCmd.Flags().BoolVar(&ops.csv, "csv", false, "output result as csv")
+
+	return trendCmd
+}
+
+// Store flag options for trend command
+type trendOptions struct {
+	ou          string
+	accounts    []string
+	groupBy     string
+	granularity string
+	time        string
+	start       string
+	end         string
+	csv         bool
+	output      string
+
+	genericclioptions.IOStreams
+	GlobalOptions *globalflags.GlobalOptions
+}
+
+func newTrendOptions(streams genericclioptions.IOStreams, globalOpts *globalflags.GlobalOptions) *trendOptions {
+	return &trendOptions{
+		IOStreams:     streams,
+		GlobalOptions: globalOpts,
+	}
+}
+
+func (o *trendOptions) checkArgs(cmd *cobra.Command, _ []string) error {
+	if o.start == "" && o.end == "" && o.time == "" {
+		return cmdutil.UsageErrorf(cmd, "Please provide a date range or a predefined time")
+	}
+	if (o.start != "" || o.end != "") && o.time != "" {
+		return cmdutil.UsageErrorf(cmd, "Please provide either a date range or a predefined time")
+	}
+	if o.time == "" && (o.start == "" || o.end == "") {
+		return cmdutil.UsageErrorf(cmd, "Please provide both start and end of date range")
+	}
+	if o.ou == "" && len(o.accounts) == 0 {
+		return cmdutil.UsageErrorf(cmd, "Please provide OU or accounts")
+	}
+	if !slices.Contains([]string{groupByAccount, groupByOU, groupByService}, o.groupBy) {
+		return cmdutil.UsageErrorf(cmd, "Invalid --group-by %q, expected 'account', 'ou' or 'service'", o.groupBy)
+	}
+	if o.groupBy == groupByOU && o.ou == "" {
+		return cmdutil.UsageErrorf(cmd, "Please provide OU to group by OU")
+	}
+	if o.granularity != "daily" && o.granularity != "monthly" {
+		return cmdutil.UsageErrorf(cmd, "Invalid --granularity %q, expected 'daily' or 'monthly'", o.granularity)
+	}
+
+	o.output = o.GlobalOptions.Output
+
+	return nil
+}
+
+type costSeries struct {
+	Key   string            `json:"key" yaml:"key"`
+	Name  string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Costs []decimal.Decimal `json:"costs" yaml:"costs"`
+	Total decimal.Decimal   `json:"total" yaml:"total"`
+}
+
+type costTrendResponse struct {
+	GroupBy     string       `json:"groupBy" yaml:"groupBy"`
+	Granularity string       `json:"granularity" yaml:"granularity"`
+	Unit        string       `json:"unit" yaml:"unit"`
+	Periods     []string     `json:"periods" yaml:"periods"`
+	Series      []costSeries `json:"series" yaml:"series"`
+}
+
+func (f costTrendResponse) String() string {
+	var b strings.Builder
+	p := printer.NewTablePrinter(&b, 12, 1, 3, ' ')
+	p.AddRow(append(append([]string{"KEY", "NAME"}, f.Periods...), "TOTAL"))
+	for _, series := range f.Series {
+		row := []string{series.Key, series.Name}
+		if row[1] == "" {
+			row[1] = "-"
+		}
+		for _, cost := range series.Costs {
+			row = append(row, cost.StringFixed(2))
+		}
+		p.AddRow(append(row, series.Total.StringFixed(2)))
+	}
+	_ = p.Flush()
+	return fmt.Sprintf("Cost in %s\n%s", f.Unit, b.String())
+}
+
+func (o *trendOptions) run() error {
+	awsClient, err := opsCost.initAWSClients()
+	if err != nil {
+		return err
+	}
+
+	var OU *organizationTypes.OrganizationalUnit
+	if o.ou != "" {
+		OU = getOU(awsClient, o.ou)
+	}
+	resp, err := o.getTrend(awsClient, OU)
+	if err != nil {
+		return err
+	}
+
+	if o.csv {
+		return writeTrendCSV(o.Out, resp)
+	}
+	return outputflag.PrintResponse(o.output, resp)
+}
+
+func (o *trendOptions) getTrend(awsClient awsprovider.Client, OU *organizationTypes.OrganizationalUnit) (*costTrendResponse, error) {
+	start, end, err := parseDateRange(o.time, o.start, o.end)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := getScopeAccounts(awsClient, OU, o.accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity := costExplorerTypes.GranularityMonthly
+	if o.granularity == "daily" {
+		granularity = costExplorerTypes.GranularityDaily
+	}
+	dimension := costExplorerTypes.DimensionLinkedAccount
+	if o.groupBy == groupByService {
+		dimension = costExplorerTypes.DimensionService
+	}
+
+	table, err := getCostTable(awsClient, accounts, start, end, granularity, dimension, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	if o.groupBy == groupByOU {
+		accountOUs, err := getAccountOUs(awsClient, OU)
+		if err != nil {
+			return nil, err
+		}
+		table = table.regroup(func(account string) (string, bool) {
+			ou, ok := accountOUs[account]
+			if ok {
+				names[*ou.Id] = *ou.Name
+				return *ou.Id, true
+			}
+			// Accounts given with --account outside of --ou
+			return account, true
+		})
+	}
+
+	resp := &costTrendResponse{
+		GroupBy:     o.groupBy,
+		Granularity: o.granularity,
+		Unit:        table.Unit,
+		Periods:     table.Periods,
+		Series:      []costSeries{},
+	}
+	for key, costs := range table.Costs {
+		series := costSeries{Key: key, Name: names[key], Costs: costs}
+		for _, cost := range costs {
+			series.Total = series.Total.Add(cost)
+		}
+		resp.Series = append(resp.Series, series)
+	}
+	slices.SortFunc(resp.Series, func(a, b costSeries) int {
+		if c := b.Total.Cmp(a.Total); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return resp, nil
+}
+
+// writeTrendCSV writes one row per key and period, which spreadsheets can pivot
+func writeTrendCSV(w io.Writer, resp *costTrendResponse) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"key", "name", "period", "cost", "unit"}); err != nil {
+		return err
+	}
+	for _, series := range resp.Series {
+		for i, cost := range series.Costs {
+			if err := writer.Write([]string{series.Key, series.Name, resp.Periods[i], cost.StringFixed(2), resp.Unit}); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
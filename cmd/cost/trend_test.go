@@ -0,0 +1,160 @@
+package cost
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costExplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// costResult returns the Cost Explorer result of a period with the cost of each group key
+func costResult(start string, costs map[string]string) costExplorerTypes.ResultByTime {
+	result := costExplorerTypes.ResultByTime{TimePeriod: &costExplorerTypes.DateInterval{Start: aws.String(start)}}
+	for key, amount := range costs {
+		result.Groups = append(result.Groups, costExplorerTypes.Group{
+			Keys:    []string{key},
+			Metrics: map[string]costExplorerTypes.MetricValue{costMetric: {Amount: aws.String(amount), Unit: aws.String("USD")}},
+		})
+	}
+	return result
+}
+
+// fixed formats costs, decimal.Decimal values can't be compared with assert.Equal
+func fixed(costs []decimal.Decimal) []string {
+	result := make([]string, len(costs))
+	for i, cost := range costs {
+		result[i] = cost.StringFixed(2)
+	}
+	return result
+}
+
+func decimals(values ...string) []decimal.Decimal {
+	result := make([]decimal.Decimal, len(values))
+	for i, value := range values {
+		result[i] = decimal.RequireFromString(value)
+	}
+	return result
+}
+
+func TestCostPeriods(t *testing.T) {
+	start := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"2026-01-15", "2026-02-01", "2026-03-01"}, costPeriods(start, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), costExplorerTypes.GranularityMonthly))
+	assert.Equal(t, []string{"2026-01-15", "2026-01-16"}, costPeriods(start, start.AddDate(0, 0, 2), costExplorerTypes.GranularityDaily))
+}
+
+func TestGetCostTable(t *testing.T) {
+	mockClient := mock.NewMockClient(gomock.NewController(t))
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	gomock.InOrder(
+		mockClient.EXPECT().GetCostAndUsage(gomock.Any()).DoAndReturn(func(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+			assert.Equal(t, []string{"111", "222"}, input.Filter.Dimensions.Values)
+			assert.Equal(t, "2026-01-01", *input.TimePeriod.Start)
+			assert.Equal(t, "2026-01-03", *input.TimePeriod.End)
+			assert.Equal(t, "SERVICE", *input.GroupBy[0].Key)
+			assert.Nil(t, input.NextPageToken)
+			return &costexplorer.GetCostAndUsageOutput{
+				ResultsByTime: []costExplorerTypes.ResultByTime{costResult("2026-01-01", map[string]string{"EC2": "10.5", "S3": "1"})},
+				NextPageToken: aws.String("next"),
+			}, nil
+		}),
+		mockClient.EXPECT().GetCostAndUsage(gomock.Any()).DoAndReturn(func(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+			assert.Equal(t, "next", *input.NextPageToken)
+			return &costexplorer.GetCostAndUsageOutput{
+				ResultsByTime: []costExplorerTypes.ResultByTime{costResult("2026-01-02", map[string]string{"EC2": "20"})},
+			}, nil
+		}),
+	)
+
+	table, err := getCostTable(mockClient, []string{"111", "222"}, start, start.AddDate(0, 0, 2), costExplorerTypes.GranularityDaily, costExplorerTypes.DimensionService, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026-01-01", "2026-01-02"}, table.Periods)
+	assert.Equal(t, "USD", table.Unit)
+	require.Len(t, table.Costs, 2)
+	assert.Equal(t, []string{"10.50", "20.00"}, fixed(table.Costs["EC2"]))
+	assert.Equal(t, []string{"1.00", "0.00"}, fixed(table.Costs["S3"]))
+}
+
+func TestGetCostTableUnexpectedPeriod(t *testing.T) {
+	mockClient := mock.NewMockClient(gomock.NewController(t))
+	mockClient.EXPECT().GetCostAndUsage(gomock.Any()).Return(&costexplorer.GetCostAndUsageOutput{
+		ResultsByTime: []costExplorerTypes.ResultByTime{costResult("2025-12-31", map[string]string{"EC2": "1"})},
+	}, nil)
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := getCostTable(mockClient, []string{"111"}, start, start.AddDate(0, 0, 1), costExplorerTypes.GranularityDaily, costExplorerTypes.DimensionService, nil)
+	assert.EqualError(t, err, "unexpected cost period starting on 2025-12-31")
+}
+
+func TestGetTrendByOU(t *testing.T) {
+	mockClient := mock.NewMockClient(gomock.NewController(t))
+	root := &organizationTypes.OrganizationalUnit{Id: aws.String("ou-root"), Name: aws.String("Root")}
+	child := organizationTypes.OrganizationalUnit{Id: aws.String("ou-child"), Name: aws.String("Child")}
+
+	mockClient.EXPECT().ListAccountsForParent(gomock.Any()).DoAndReturn(func(input *organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error) {
+		accounts := map[string][]organizationTypes.Account{
+			"ou-root":  {{Id: aws.String("111")}},
+			"ou-child": {{Id: aws.String("222")}, {Id: aws.String("333")}},
+		}
+		return &organizations.ListAccountsForParentOutput{Accounts: accounts[*input.ParentId]}, nil
+	}).AnyTimes()
+	mockClient.EXPECT().ListOrganizationalUnitsForParent(gomock.Any()).DoAndReturn(func(input *organizations.ListOrganizationalUnitsForParentInput) (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+		if *input.ParentId == "ou-root" {
+			return &organizations.ListOrganizationalUnitsForParentOutput{OrganizationalUnits: []organizationTypes.OrganizationalUnit{child}}, nil
+		}
+		return &organizations.ListOrganizationalUnitsForParentOutput{}, nil
+	}).AnyTimes()
+	mockClient.EXPECT().GetCostAndUsage(gomock.Any()).DoAndReturn(func(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error) {
+		assert.Equal(t, []string{"111", "222", "333"}, input.Filter.Dimensions.Values)
+		assert.Equal(t, costExplorerTypes.GranularityMonthly, input.Granularity)
+		return &costexplorer.GetCostAndUsageOutput{ResultsByTime: []costExplorerTypes.ResultByTime{
+			costResult("2026-01-01", map[string]string{"111": "5", "222": "10", "333": "20"}),
+			costResult("2026-02-01", map[string]string{"111": "50", "222": "1"}),
+		}}, nil
+	})
+
+	o := &trendOptions{groupBy: groupByOU, granularity: "monthly", start: "2026-01-01", end: "2026-03-01"}
+	resp, err := o.getTrend(mockClient, root)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2026-01-01", "2026-02-01"}, resp.Periods)
+	require.Len(t, resp.Series, 2)
+	assert.Equal(t, "ou-root", resp.Series[0].Key)
+	assert.Equal(t, "Root", resp.Series[0].Name)
+	assert.Equal(t, []string{"5.00", "50.00"}, fixed(resp.Series[0].Costs))
+	assert.Equal(t, "55.00", resp.Series[0].Total.StringFixed(2))
+	assert.Equal(t, "ou-child", resp.Series[1].Key)
+	assert.Equal(t, []string{"30.00", "1.00"}, fixed(resp.Series[1].Costs))
+
+	var out bytes.Buffer
+	require.NoError(t, writeTrendCSV(&out, resp))
+	assert.Equal(t, "key,name,period,cost,unit\n"+
+		"ou-root,Root,2026-01-01,5.00,USD\n"+
+		"ou-root,Root,2026-02-01,50.00,USD\n"+
+		"ou-child,Child,2026-01-01,30.00,USD\n"+
+		"ou-child,Child,2026-02-01,1.00,USD\n", out.String())
+
+	assert.Contains(t, resp.String(), "Cost in USD")
+}
+
+func TestParseDateRange(t *testing.T) {
+	start, end, err := parseDateRange("", "2026-01-01", "2026-02-01")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), end)
+
+	_, _, err = parseDateRange("", "2026-02-01", "2026-01-01")
+	assert.ErrorContains(t, err, "must be before")
+	_, _, err = parseDateRange("", "01/01/2026", "2026-01-01")
+	assert.ErrorContains(t, err, "invalid start date")
+}
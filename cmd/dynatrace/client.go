@@ -0,0 +1,440 @@
+package dynatrace
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Note: Dynatrace caps the number of records returned by a single query. Queries returning more
+	// records than this have to be run through QueryPages.
+	defaultMaxResultRecords = 20000
+
+	defaultRequestTimeout = 2 * time.Minute
+	defaultMaxRetries     = 5
+	defaultRetryBackoff   = time.Second
+	maxRetryBackoff       = 30 * time.Second
+	defaultPollInterval   = 500 * time.Millisecond
+
+	// tokenExpiryMargin is how long before its expiry an access token is refreshed, so that it doesn't
+	// expire during a request
+	tokenExpiryMargin = time.Minute
+)
+
+// Client is a typed client for the Dynatrace platform APIs used by osdctl
+type Client interface {
+	// Query runs a DQL query to completion and returns its records
+	Query(ctx context.Context, query string) ([]json.RawMessage, error)
+
+	// QueryPages runs a DQL query sorted by ascending timestamp in pages of at most pageSize records,
+	// moving a timestamp cursor between pages. fn is called with the records of every page in order.
+	// The query must not limit its own results.
+	QueryPages(ctx context.Context, query string, pageSize int, fn func(records []json.RawMessage) error) error
+
+	// FindDocumentID returns the id of the only document matching both name and type
+	FindDocumentID(ctx context.Context, name string, docType string) (string, error)
+}
+
+// accessToken is an OAuth access token, with the time it expires at. A zero expiry never expires.
+type accessToken struct {
+	value  string
+	expiry time.Time
+}
+
+// tokenSource caches an access token and fetches a new one when it is about to expire or is rejected.
+// Without a fetch function the token is static and can't be refreshed.
+type tokenSource struct {
+	fetch func() (accessToken, error)
+
+	mu    sync.Mutex
+	token accessToken
+}
+
+func staticTokenSource(token string) *tokenSource {
+	return &tokenSource{token: accessToken{value: token}}
+}
+
+func refreshingTokenSource(fetch func() (accessToken, error)) *tokenSource {
+	return &tokenSource{fetch: fetch}
+}
+
+// get returns the cached token, fetching a new one if there is none or it expires within tokenExpiryMargin
+func (s *tokenSource) get() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expired := !s.token.expiry.IsZero() && time.Now().Add(tokenExpiryMargin).After(s.token.expiry)
+	if s.fetch != nil && (s.token.value == "" || expired) {
+		token, err := s.fetch()
+		if err != nil {
+			return "", fmt.Errorf("failed to refresh access token: %w", err)
+		}
+		s.token = token
+	}
+	return s.token.value, nil
+}
+
+// invalidate drops the cached token so that the next get fetches a new one. It returns false for
+// static tokens, which can't be refreshed.
+func (s *tokenSource) invalidate() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fetch == nil {
+		return false
+	}
+	s.token = accessToken{}
+	return true
+}
+
+type restClient struct {
+	baseURL          string
+	tokens           *tokenSource
+	httpClient       *http.Client
+	maxRetries       int
+	retryBackoff     time.Duration
+	pollInterval     time.Duration
+	maxResultRecords int
+}
+
+// NewClient returns a Client for the Dynatrace tenant at baseURL, e.g. https://<tenant>.apps.dynatrace.com/
+func NewClient(baseURL string, accessToken string) Client {
+	return newRestClient(baseURL, staticTokenSource(accessToken))
+}
+
+func newRestClient(baseURL string, tokens *tokenSource) *restClient {
+	return &restClient{
+		baseURL:          baseURL,
+		tokens:           tokens,
+		httpClient:       &http.Client{Timeout: defaultRequestTimeout},
+		maxRetries:       defaultMaxRetries,
+		retryBackoff:     defaultRetryBackoff,
+		pollInterval:     defaultPollInterval,
+		maxResultRecords: defaultMaxResultRecords,
+	}
+}
+
+// newStorageClient authenticates against the Grail storage APIs of the tenant
+func newStorageClient(dtURL string) (Client, error) {
+	return newScopedClient(dtURL, DTStorageVaultPath, DTStorageScopes)
+}
+
+// newScopedClient authenticates with the credentials in the vault path of configKey, requesting scopes.
+// The access token is refreshed before it expires, so long running commands like log following keep working.
+func newScopedClient(dtURL string, configKey string, scopes string) (Client, error) {
+	tokens := refreshingTokenSource(func() (accessToken, error) {
+		return getScopedToken(configKey, scopes)
+	})
+	// Fail early rather than on the first request
+	if _, err := tokens.get(); err != nil {
+		return nil, fmt.Errorf("failed to acquire access token %v", err)
+	}
+
+	return newRestClient(dtURL, tokens), nil
+}
+
+// unauthorizedError is returned for 401 responses, which are retried once with a new access token
+type unauthorizedError struct {
+	err error
+}
+
+func (e *unauthorizedError) Error() string {
+	return e.err.Error()
+}
+
+// retryableError is returned for responses worth retrying, i.e. rate limiting and server errors
+type retryableError struct {
+	status     string
+	retryAfter time.Duration
+	err        error
+}
+
+func (e *retryableError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("request failed: %s", e.status)
+}
+
+// do sends a request, retrying with exponential backoff on connection errors, 429 and 5xx responses
+func (c *restClient) do(ctx context.Context, method string, path string, body []byte, successCode int) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			wait := c.retryBackoff << (attempt - 1)
+			if wait > maxRetryBackoff {
+				wait = maxRetryBackoff
+			}
+			var retryErr *retryableError
+			if errors.As(lastErr, &retryErr) && retryErr.retryAfter > 0 {
+				wait = retryErr.retryAfter
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		resp, err := c.send(ctx, method, path, body, successCode)
+		var authErr *unauthorizedError
+		if errors.As(err, &authErr) && c.tokens.invalidate() {
+			// The token may have expired or been revoked early, a fresh one is only worth a single try
+			resp, err = c.send(ctx, method, path, body, successCode)
+		}
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var retryErr *retryableError
+		if !errors.As(err, &retryErr) {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("giving up after %d retries: %w", c.maxRetries, lastErr)
+}
+
+func (c *restClient) send(ctx context.Context, method string, path string, body []byte, successCode int) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request %v", err)
+	}
+	token, err := c.tokens.get()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &retryableError{err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &retryableError{err: err}
+	}
+
+	if resp.StatusCode == successCode {
+		return respBody, nil
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		retryAfter := time.Duration(0)
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, &retryableError{status: resp.Status, retryAfter: retryAfter}
+	}
+
+	var dtError DTRequestError
+	err = fmt.Errorf("request failed: %v %s", resp.Status, string(respBody))
+	if json.Unmarshal(respBody, &dtError) == nil && len(dtError.Records) > 0 {
+		err = fmt.Errorf("request failed: %v %s", resp.Status, dtError.Records)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, &unauthorizedError{err: err}
+	}
+	return nil, err
+}
+
+type dtQueryResponse struct {
+	State        string        `json:"state"`
+	RequestToken string        `json:"requestToken"`
+	Result       DTEventResult `json:"result"`
+}
+
+func (c *restClient) Query(ctx context.Context, query string) ([]json.RawMessage, error) {
+	payload, err := json.Marshal(DTQueryPayload{
+		Query:            query,
+		MaxResultRecords: c.maxResultRecords,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, "platform/storage/query/v1/query:execute", payload, http.StatusAccepted)
+	if err != nil {
+		return nil, err
+	}
+
+	var state dtQueryResponse
+	if err := json.Unmarshal(resp, &state); err != nil {
+		return nil, err
+	}
+
+	pollPath := "platform/storage/query/v1/query:poll?" + url.Values{"request-token": {state.RequestToken}}.Encode()
+	for {
+		switch state.State {
+		case "SUCCEEDED":
+			return state.Result.Records, nil
+		case "RUNNING", "NOT_STARTED":
+		default:
+			return nil, fmt.Errorf("query failed with state %s", state.State)
+		}
+
+		if state.RequestToken == "" {
+			return nil, fmt.Errorf("query is %s but no request token was returned", state.State)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.pollInterval):
+		}
+
+		resp, err := c.do(ctx, http.MethodGet, pollPath, nil, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+
+		state.State = ""
+		if err := json.Unmarshal(resp, &state); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *restClient) QueryPages(ctx context.Context, query string, pageSize int, fn func(records []json.RawMessage) error) error {
+	if pageSize <= 0 || pageSize > c.maxResultRecords {
+		pageSize = c.maxResultRecords
+	}
+
+	cursorFilter := ""
+	// Records at the cursor timestamp that were already passed to fn
+	seen := map[[sha256.Size]byte]bool{}
+	emit := func(records []json.RawMessage) error {
+		var fresh []json.RawMessage
+		for _, r := range records {
+			key := sha256.Sum256(r)
+			if !seen[key] {
+				seen[key] = true
+				fresh = append(fresh, r)
+			}
+		}
+		if len(fresh) == 0 {
+			return nil
+		}
+		return fn(fresh)
+	}
+
+	for {
+		records, err := c.Query(ctx, fmt.Sprintf("%s%s\n| limit %d", query, cursorFilter, pageSize))
+		if err != nil {
+			return err
+		}
+
+		if err := emit(records); err != nil {
+			return err
+		}
+
+		if len(records) < pageSize {
+			return nil
+		}
+
+		first, err := recordTimestamp(records[0])
+		if err != nil {
+			return fmt.Errorf("failed to page query results: %v", err)
+		}
+		last, err := recordTimestamp(records[len(records)-1])
+		if err != nil {
+			return fmt.Errorf("failed to page query results: %v", err)
+		}
+
+		if first == last {
+			// The whole page shares one timestamp, so a cursor on it cannot move forward. Fetch every
+			// record at that timestamp at once, then continue strictly after it.
+			sameTimestamp, err := c.Query(ctx, fmt.Sprintf("%s\n| filter timestamp == toTimestamp(\"%s\")", query, last))
+			if err != nil {
+				return err
+			}
+			// Records sharing a timestamp can't be paged, a full result may have been cut
+			if len(sameTimestamp) >= c.maxResultRecords {
+				return fmt.Errorf("more than %d records share the timestamp %s, narrow down the query", c.maxResultRecords, last)
+			}
+			if err := emit(sameTimestamp); err != nil {
+				return err
+			}
+			cursorFilter = fmt.Sprintf("\n| filter timestamp > toTimestamp(\"%s\")", last)
+			seen = map[[sha256.Size]byte]bool{}
+			continue
+		}
+
+		// The next page starts at the last timestamp, only keep the records at it to skip them again
+		cursorFilter = fmt.Sprintf("\n| filter timestamp >= toTimestamp(\"%s\")", last)
+		seen = map[[sha256.Size]byte]bool{}
+		for _, r := range records {
+			if ts, err := recordTimestamp(r); err == nil && ts == last {
+				seen[sha256.Sum256(r)] = true
+			}
+		}
+	}
+}
+
+func recordTimestamp(record json.RawMessage) (string, error) {
+	var r struct {
+		Timestamp string `json:"timestamp"`
+	}
+	if err := json.Unmarshal(record, &r); err != nil {
+		return "", err
+	}
+	if r.Timestamp == "" {
+		return "", fmt.Errorf("record has no timestamp")
+	}
+
+	return r.Timestamp, nil
+}
+
+// FindDocumentID searches using the dynatrace document API using a filter that
+// checks for an exact match of both name and type. It will return the id of the document
+// found, unless it find zero or multiple in which case it will return an error
+func (c *restClient) FindDocumentID(ctx context.Context, name string, docType string) (string, error) {
+	dtDashFilter := "name == '" + name + "' and type == '" + docType + "'"
+	parameters := url.Values{
+		"filter": {dtDashFilter},
+	}.Encode()
+
+	result, err := c.do(ctx, http.MethodGet, "platform/document/v1/documents?"+parameters, nil, http.StatusOK)
+	if err != nil {
+		return "", fmt.Errorf("could not search for dashboard: %w", err)
+	}
+
+	var dtDocResult DTDocumentResult
+	err = json.Unmarshal(result, &dtDocResult)
+	if err != nil {
+		return "", fmt.Errorf("response in incorrect format")
+	}
+
+	docCount := len(dtDocResult.Documents)
+	if docCount == 0 {
+		return "", fmt.Errorf("dashboard not found")
+	}
+	if docCount > 1 {
+		return "", fmt.Errorf("dashboard name was ambiguous, %d dashboards found", docCount)
+	}
+
+	return dtDocResult.Documents[0].Id, nil
+}
@@ -0,0 +1,288 @@
+package dynatrace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/dynatrace/fakegrail"
+)
+
+func newTestClient(server *fakegrail.Server) *restClient {
+	c := newRestClient(server.URL(), staticTokenSource("token"))
+	c.retryBackoff = time.Millisecond
+	c.pollInterval = time.Millisecond
+	return c
+}
+
+func TestClientQuery(t *testing.T) {
+	server := fakegrail.NewServer()
+	defer server.Close()
+	server.HandleQuery(fakegrail.Records(map[string]interface{}{"timestamp": "2025-06-15T04:00:00Z", "content": "a"}))
+	server.SetRunningPolls(2)
+	// Rate limiting and server errors are retried
+	server.FailNext(http.StatusTooManyRequests, http.StatusServiceUnavailable)
+
+	records, err := newTestClient(server).Query(context.Background(), "fetch logs")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 || !strings.Contains(string(records[0]), `"content":"a"`) {
+		t.Errorf("unexpected records: %s", records)
+	}
+}
+
+func TestClientQueryErrors(t *testing.T) {
+	t.Run("client errors are not retried", func(t *testing.T) {
+		server := fakegrail.NewServer()
+		defer server.Close()
+		server.HandleQuery(func(string) ([]map[string]interface{}, error) {
+			return nil, fmt.Errorf("PARSE_ERROR")
+		})
+
+		_, err := newTestClient(server).Query(context.Background(), "fetch nothing")
+		if err == nil || !strings.Contains(err.Error(), "PARSE_ERROR") {
+			t.Fatalf("expected parse error, got %v", err)
+		}
+		if len(server.Queries()) != 1 {
+			t.Errorf("expected a single attempt, got %d", len(server.Queries()))
+		}
+	})
+
+	t.Run("retries are bounded", func(t *testing.T) {
+		server := fakegrail.NewServer()
+		defer server.Close()
+		server.FailNext(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+
+		c := newTestClient(server)
+		c.maxRetries = 2
+		_, err := c.Query(context.Background(), "fetch logs")
+		if err == nil || !strings.Contains(err.Error(), "giving up after 2 retries") {
+			t.Fatalf("expected retries to be exhausted, got %v", err)
+		}
+	})
+
+	t.Run("polling stops when the context is cancelled", func(t *testing.T) {
+		server := fakegrail.NewServer()
+		defer server.Close()
+		server.SetRunningPolls(1000)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := newTestClient(server).Query(ctx, "fetch logs")
+		if err != context.DeadlineExceeded {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+	})
+}
+
+func TestClientQueryPages(t *testing.T) {
+	server := fakegrail.NewServer()
+	defer server.Close()
+
+	var records []map[string]interface{}
+	for i, ts := range []string{"01", "02", "02", "02", "03", "04", "05"} {
+		records = append(records, map[string]interface{}{"timestamp": "2025-06-15T04:00:" + ts + "Z", "content": fmt.Sprint(i)})
+	}
+	server.HandleQuery(fakegrail.Records(records...))
+
+	var got []string
+	pages := 0
+	err := newTestClient(server).QueryPages(context.Background(), "fetch logs\n| sort timestamp asc", 3, func(page []json.RawMessage) error {
+		pages++
+		for _, raw := range page {
+			var r logRecord
+			if err := json.Unmarshal(raw, &r); err != nil {
+				return err
+			}
+			got = append(got, r.Content)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(got, ",") != "0,1,2,3,4,5,6" {
+		t.Errorf("expected every record exactly once in order, got %v", got)
+	}
+
+	queries := server.Queries()
+	if !strings.HasSuffix(queries[0], "\n| limit 3") || !strings.Contains(queries[1], `timestamp >= toTimestamp("2025-06-15T04:00:02Z")`) {
+		t.Errorf("unexpected page queries: %q", queries)
+	}
+	if pages == 0 || pages > len(queries) {
+		t.Errorf("expected at most one callback per query, got %d callbacks for %d queries", pages, len(queries))
+	}
+}
+
+func TestClientQueryPagesSameTimestamp(t *testing.T) {
+	server := fakegrail.NewServer()
+	defer server.Close()
+
+	var records []map[string]interface{}
+	for i := 0; i < 5; i++ {
+		records = append(records, map[string]interface{}{"timestamp": "2025-06-15T04:00:00Z", "content": fmt.Sprint(i)})
+	}
+	records = append(records, map[string]interface{}{"timestamp": "2025-06-15T04:00:01Z", "content": "5"})
+	server.HandleQuery(fakegrail.Records(records...))
+
+	count := 0
+	err := newTestClient(server).QueryPages(context.Background(), "fetch logs", 2, func(page []json.RawMessage) error {
+		count += len(page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 6 {
+		t.Errorf("expected 6 records, got %d", count)
+	}
+
+	queries := server.Queries()
+	if !strings.Contains(queries[1], `timestamp == toTimestamp("2025-06-15T04:00:00Z")`) || !strings.Contains(queries[2], `timestamp > toTimestamp("2025-06-15T04:00:00Z")`) {
+		t.Errorf("unexpected page queries: %q", queries)
+	}
+}
+
+func TestClientQueryPagesSameTimestampTruncated(t *testing.T) {
+	server := fakegrail.NewServer()
+	defer server.Close()
+
+	var records []map[string]interface{}
+	for i := 0; i < 5; i++ {
+		records = append(records, map[string]interface{}{"timestamp": "2025-06-15T04:00:00Z", "content": fmt.Sprint(i)})
+	}
+	server.HandleQuery(fakegrail.Records(records...))
+
+	c := newTestClient(server)
+	c.maxResultRecords = 3
+	err := c.QueryPages(context.Background(), "fetch logs", 2, func([]json.RawMessage) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "more than 3 records share the timestamp 2025-06-15T04:00:00Z") {
+		t.Errorf("expected the truncated result to be reported, got %v", err)
+	}
+}
+
+func TestClientFindDocumentID(t *testing.T) {
+	server := fakegrail.NewServer()
+	defer server.Close()
+	server.AddDocument(fakegrail.Document{ID: "dash-1", Name: "Central ROSA HCP Dashboard", Type: DTDashboardType})
+	server.AddDocument(fakegrail.Document{ID: "dash-2", Name: "Duplicate", Type: DTDashboardType})
+	server.AddDocument(fakegrail.Document{ID: "dash-3", Name: "Duplicate", Type: DTDashboardType})
+
+	c := newTestClient(server)
+
+	id, err := c.FindDocumentID(context.Background(), "Central ROSA HCP Dashboard", DTDashboardType)
+	if err != nil || id != "dash-1" {
+		t.Errorf("expected dash-1, got %q (%v)", id, err)
+	}
+
+	if _, err := c.FindDocumentID(context.Background(), "Missing", DTDashboardType); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	if _, err := c.FindDocumentID(context.Background(), "Duplicate", DTDashboardType); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected ambiguous error, got %v", err)
+	}
+}
+
+func TestClientRefreshesAccessToken(t *testing.T) {
+	t.Run("unauthorized requests are retried once with a new token", func(t *testing.T) {
+		server := fakegrail.NewServer()
+		defer server.Close()
+		server.HandleQuery(fakegrail.Records(map[string]interface{}{"timestamp": "2025-06-15T04:00:00Z", "content": "a"}))
+		server.FailNext(http.StatusUnauthorized)
+
+		fetches := 0
+		c := newTestClient(server)
+		c.tokens = refreshingTokenSource(func() (accessToken, error) {
+			fetches++
+			return accessToken{value: fmt.Sprintf("token-%d", fetches)}, nil
+		})
+
+		if _, err := c.Query(context.Background(), "fetch logs"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fetches != 2 {
+			t.Errorf("expected the token to be fetched twice, got %d", fetches)
+		}
+	})
+
+	t.Run("a second unauthorized response fails", func(t *testing.T) {
+		server := fakegrail.NewServer()
+		defer server.Close()
+		server.FailNext(http.StatusUnauthorized, http.StatusUnauthorized)
+
+		c := newTestClient(server)
+		c.tokens = refreshingTokenSource(func() (accessToken, error) {
+			return accessToken{value: "token"}, nil
+		})
+
+		_, err := c.Query(context.Background(), "fetch logs")
+		if err == nil || !strings.Contains(err.Error(), "401") {
+			t.Fatalf("expected unauthorized error, got %v", err)
+		}
+	})
+
+	t.Run("static tokens are not refreshed", func(t *testing.T) {
+		server := fakegrail.NewServer()
+		defer server.Close()
+		server.FailNext(http.StatusUnauthorized)
+
+		_, err := newTestClient(server).Query(context.Background(), "fetch logs")
+		if err == nil || !strings.Contains(err.Error(), "401") {
+			t.Fatalf("expected unauthorized error, got %v", err)
+		}
+	})
+}
+
+func TestTokenSource(t *testing.T) {
+	fetches := 0
+	expiry := time.Now().Add(time.Hour)
+	tokens := refreshingTokenSource(func() (accessToken, error) {
+		fetches++
+		return accessToken{value: fmt.Sprintf("token-%d", fetches), expiry: expiry}, nil
+	})
+
+	for range 2 {
+		if token, err := tokens.get(); err != nil || token != "token-1" {
+			t.Fatalf("expected cached token-1, got %q (%v)", token, err)
+		}
+	}
+
+	// Tokens about to expire are refreshed ahead of time
+	expiry = time.Now().Add(tokenExpiryMargin / 2)
+	tokens.invalidate()
+	if token, _ := tokens.get(); token != "token-2" {
+		t.Fatalf("expected token-2, got %q", token)
+	}
+	if token, _ := tokens.get(); token != "token-3" {
+		t.Errorf("expected the expiring token to be refreshed, got %q", token)
+	}
+
+	if staticTokenSource("static").invalidate() {
+		t.Errorf("static tokens can't be invalidated")
+	}
+}
+
+func TestParseAccessToken(t *testing.T) {
+	now := time.Date(2025, 6, 15, 4, 0, 0, 0, time.UTC)
+
+	token, err := parseAccessToken([]byte(`{"access_token":"abc","expires_in":300}`), now)
+	if err != nil || token.value != "abc" || !token.expiry.Equal(now.Add(5*time.Minute)) {
+		t.Errorf("unexpected token %+v (%v)", token, err)
+	}
+
+	token, err = parseAccessToken([]byte(`{"access_token":"abc"}`), now)
+	if err != nil || !token.expiry.IsZero() {
+		t.Errorf("expected a token without expiry, got %+v (%v)", token, err)
+	}
+
+	if _, err := parseAccessToken([]byte(`{}`), now); err == nil {
+		t.Errorf("expected missing token error")
+	}
+}
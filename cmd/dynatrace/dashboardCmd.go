@@ -1,6 +1,7 @@
 package dynatrace
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"runtime"
@@ -58,14 +59,13 @@ func newCmdDashboard() *cobra.Command {
 			}
 
 			// Search for the dashboard
-			id, err := getDocumentIDByNameAndType(hcpCluster.DynatraceURL, accessToken, dashboardName, DTDashboardType)
+			dashUrl, err := getDashboardURL(context.Background(), NewClient(hcpCluster.DynatraceURL, accessToken), hcpCluster, dashboardName)
 			if err != nil {
 				fmt.Printf("Could not find dashboard named '%s': %s\n", dashboardName, err)
 				return
 			}
 
 			// Tell the user
-			dashUrl := hcpCluster.DynatraceURL + "ui/apps/dynatrace.dashboards/dashboard/" + id + "#vfilter__id=" + hcpCluster.externalID
 			fmt.Printf("\n\nDashboard URL:\n  %s\n", dashUrl)
 
 			// Only try to open browser if not in a container environment
@@ -87,3 +87,13 @@ func newCmdDashboard() *cobra.Command {
 
 	return urlCmd
 }
+
+// getDashboardURL returns the URL of the named dashboard, filtered on the given cluster
+func getDashboardURL(ctx context.Context, client Client, hcpCluster HCPCluster, name string) (string, error) {
+	id, err := client.FindDocumentID(ctx, name, DTDashboardType)
+	if err != nil {
+		return "", err
+	}
+
+	return hcpCluster.DynatraceURL + "ui/apps/dynatrace.dashboards/dashboard/" + id + "#vfilter__id=" + hcpCluster.externalID, nil
+}
@@ -0,0 +1,25 @@
+package dynatrace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/dynatrace/fakegrail"
+)
+
+func TestGetDashboardURL(t *testing.T) {
+	server := fakegrail.NewServer()
+	defer server.Close()
+	server.AddDocument(fakegrail.Document{ID: "dash-1", Name: "Central ROSA HCP Dashboard", Type: DTDashboardType})
+
+	hcpCluster := HCPCluster{externalID: "ext-123", DynatraceURL: server.URL()}
+	url, err := getDashboardURL(context.Background(), newTestClient(server), hcpCluster, "Central ROSA HCP Dashboard")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := server.URL() + "ui/apps/dynatrace.dashboards/dashboard/dash-1#vfilter__id=ext-123"
+	if url != expected {
+		t.Errorf("expected %s, got %s", expected, url)
+	}
+}
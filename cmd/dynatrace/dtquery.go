@@ -40,6 +40,29 @@ func (q *DTQuery) InitEvents(hours int) *DTQuery {
 	return q
 }
 
+func (q *DTQuery) InitSpans(hours int) *DTQuery {
+	q.fragments = []string{}
+
+	q.fragments = append(q.fragments, fmt.Sprintf("fetch spans, from:now()-%dh \n| filter ", hours))
+
+	return q
+}
+
+// InitMetrics starts a timeseries query of the given metric, e.g. InitMetrics("avg", "dt.kubernetes.container.cpu_usage", ...).
+// Any field filtered on afterwards (including the cluster name) has to be listed in by.
+func (q *DTQuery) InitMetrics(aggregation string, metric string, by []string, hours int) *DTQuery {
+	q.fragments = []string{}
+
+	byClause := ""
+	if len(by) > 0 {
+		byClause = fmt.Sprintf(", by:{%s}", strings.Join(by, ", "))
+	}
+
+	q.fragments = append(q.fragments, fmt.Sprintf("timeseries %s(%s)%s, from:now()-%dh \n| filter ", aggregation, metric, byClause, hours))
+
+	return q
+}
+
 func (q *DTQuery) Cluster(mgmtClusterName string) *DTQuery {
 	q.fragments = append(q.fragments, fmt.Sprintf("matchesPhrase(dt.kubernetes.cluster.name, \"%s\")", mgmtClusterName))
 
@@ -167,6 +190,40 @@ func (q *DTQuery) Deployments(workloads []string) *DTQuery {
 	return q
 }
 
+// AnyOf adds a condition matching if any of the given conditions match, e.g.
+// q.AnyOf(MatchesValue("status", "ERROR"), MatchesPhrase("content", "timeout"))
+func (q *DTQuery) AnyOf(conditions ...string) *DTQuery {
+	if len(conditions) == 0 {
+		return q
+	}
+
+	q.fragments = append(q.fragments, " and ("+strings.Join(conditions, " or ")+")")
+
+	return q
+}
+
+// Not adds a condition excluding records matching any of the given conditions
+func (q *DTQuery) Not(conditions ...string) *DTQuery {
+	if len(conditions) == 0 {
+		return q
+	}
+
+	q.fragments = append(q.fragments, " and not("+strings.Join(conditions, " or ")+")")
+
+	return q
+}
+
+// Fields projects the records onto the given fields, in order
+func (q *DTQuery) Fields(fields []string) *DTQuery {
+	if len(fields) == 0 {
+		return q
+	}
+
+	q.fragments = append(q.fragments, "\n| fields "+strings.Join(fields, ", "))
+
+	return q
+}
+
 func (q *DTQuery) Limit(limit int) *DTQuery {
 	q.fragments = append(q.fragments, "\n| limit "+fmt.Sprint(limit))
 
@@ -178,3 +235,13 @@ func (q *DTQuery) Build() string {
 
 	return q.finalQuery
 }
+
+// MatchesValue returns a condition matching records where field equals value, for use with AnyOf and Not
+func MatchesValue(field string, value string) string {
+	return fmt.Sprintf("matchesValue(%s, \"%s\")", field, value)
+}
+
+// MatchesPhrase returns a condition matching records where field contains the phrase, for use with AnyOf and Not
+func MatchesPhrase(field string, phrase string) string {
+	return fmt.Sprintf("matchesPhrase(%s, \"%s\")", field, phrase)
+}
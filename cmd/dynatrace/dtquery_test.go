@@ -219,3 +219,48 @@ func TestDTQuery_InitLogsWithTimeRange(t *testing.T) {
 		})
 	}
 }
+
+func TestDTQuery_InitSpans(t *testing.T) {
+	q := new(DTQuery).InitSpans(3)
+	expected := `fetch spans, from:now()-3h 
+| filter `
+	if q.fragments[0] != expected {
+		t.Errorf("expected: %s\ngot: %s", expected, q.fragments[0])
+	}
+}
+
+func TestDTQuery_InitMetrics(t *testing.T) {
+	q := new(DTQuery).InitMetrics("avg", "dt.kubernetes.container.cpu_usage", []string{"dt.kubernetes.cluster.name", "k8s.namespace.name"}, 6)
+	expected := `timeseries avg(dt.kubernetes.container.cpu_usage), by:{dt.kubernetes.cluster.name, k8s.namespace.name}, from:now()-6h 
+| filter `
+	if q.fragments[0] != expected {
+		t.Errorf("expected: %s\ngot: %s", expected, q.fragments[0])
+	}
+}
+
+func TestDTQuery_AnyOfNot(t *testing.T) {
+	q := new(DTQuery).InitLogs(1).Cluster("mc").
+		AnyOf(MatchesValue("status", "ERROR"), MatchesPhrase("content", "timeout")).
+		Not(MatchesValue("k8s.container.name", "sidecar")).
+		AnyOf()
+	expected := []string{
+		` and (matchesValue(status, "ERROR") or matchesPhrase(content, "timeout"))`,
+		` and not(matchesValue(k8s.container.name, "sidecar"))`,
+	}
+	if len(q.fragments) != 4 {
+		t.Fatalf("expected 4 fragments, got %d", len(q.fragments))
+	}
+	for i, e := range expected {
+		if q.fragments[i+2] != e {
+			t.Errorf("expected: %s\ngot: %s", e, q.fragments[i+2])
+		}
+	}
+}
+
+func TestDTQuery_Fields(t *testing.T) {
+	q := new(DTQuery).InitSpans(1).Cluster("mc").Fields([]string{"timestamp", "span.name"})
+	expected := "\n| fields timestamp, span.name"
+	if q.fragments[2] != expected {
+		t.Errorf("expected: %s\ngot: %s", expected, q.fragments[2])
+	}
+}
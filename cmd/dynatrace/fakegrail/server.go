@@ -0,0 +1,249 @@
+// Package fakegrail provides an in-process fake of the Dynatrace Grail query and document APIs,
+// so commands talking to Dynatrace can be tested without a tenant.
+package fakegrail
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// QueryHandler returns the records of a DQL query, or an error rejecting the query
+type QueryHandler func(query string) ([]map[string]interface{}, error)
+
+// Document is a document served by the document API
+type Document struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Server serves the query:execute, query:poll and documents endpoints of a Dynatrace tenant
+type Server struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	handler      QueryHandler
+	documents    []Document
+	queries      []string
+	failures     []int
+	runningPolls int
+	pending      map[string]*pendingQuery
+	nextToken    int
+}
+
+type pendingQuery struct {
+	records []map[string]interface{}
+	polls   int
+}
+
+var (
+	documentFilterRegex = regexp.MustCompile(`^name == '(.*)' and type == '(.*)'$`)
+	cursorRegex         = regexp.MustCompile(`\| filter timestamp (>=|>|==) toTimestamp\("([^"]+)"\)`)
+	limitRegex          = regexp.MustCompile(`\| limit (\d+)\s*$`)
+)
+
+// NewServer starts a fake tenant returning no records for any query. It has to be closed with Close.
+func NewServer() *Server {
+	s := &Server{
+		handler: func(string) ([]map[string]interface{}, error) { return nil, nil },
+		pending: map[string]*pendingQuery{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /platform/storage/query/v1/query:execute", s.execute)
+	mux.HandleFunc("GET /platform/storage/query/v1/query:poll", s.poll)
+	mux.HandleFunc("GET /platform/document/v1/documents", s.listDocuments)
+	s.server = httptest.NewServer(s.injectFailures(mux))
+
+	return s
+}
+
+// URL returns the tenant URL, with the trailing slash the Dynatrace commands expect
+func (s *Server) URL() string {
+	return s.server.URL + "/"
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// HandleQuery sets the handler answering queries
+func (s *Server) HandleQuery(handler QueryHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = handler
+}
+
+// AddDocument adds a document to the document API
+func (s *Server) AddDocument(doc Document) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.documents = append(s.documents, doc)
+}
+
+// FailNext makes the next requests fail with the given status codes, in order
+func (s *Server) FailNext(statusCodes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statusCodes...)
+}
+
+// SetRunningPolls sets how many times a query is polled in the RUNNING state before it succeeds
+func (s *Server) SetRunningPolls(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runningPolls = n
+}
+
+// Queries returns the queries executed so far
+func (s *Server) Queries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.queries...)
+}
+
+// Records returns a handler serving the given records sorted by timestamp. Like Grail, it honours a trailing
+// `| limit n` and the `| filter timestamp >= toTimestamp("...")` cursors (>=, > or ==) used to page through results.
+func Records(records ...map[string]interface{}) QueryHandler {
+	sorted := append([]map[string]interface{}{}, records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return fmt.Sprint(sorted[i]["timestamp"]) < fmt.Sprint(sorted[j]["timestamp"])
+	})
+
+	return func(query string) ([]map[string]interface{}, error) {
+		result := sorted
+		if m := cursorRegex.FindStringSubmatch(query); m != nil {
+			result = nil
+			for _, r := range sorted {
+				ts := fmt.Sprint(r["timestamp"])
+				if (m[1] == ">=" && ts >= m[2]) || (m[1] == ">" && ts > m[2]) || (m[1] == "==" && ts == m[2]) {
+					result = append(result, r)
+				}
+			}
+		}
+
+		if m := limitRegex.FindStringSubmatch(query); m != nil {
+			limit, _ := strconv.Atoi(m[1])
+			if len(result) > limit {
+				result = result[:limit]
+			}
+		}
+
+		return result, nil
+	}
+}
+
+func (s *Server) injectFailures(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		status := 0
+		if len(s.failures) > 0 {
+			status = s.failures[0]
+			s.failures = s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if status != 0 {
+			writeError(w, status, "injected failure")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) execute(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Query            string `json:"query"`
+		MaxResultRecords int    `json:"maxResultRecords"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queries = append(s.queries, payload.Query)
+	records, err := s.handler(payload.Query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if payload.MaxResultRecords > 0 && len(records) > payload.MaxResultRecords {
+		records = records[:payload.MaxResultRecords]
+	}
+
+	s.nextToken++
+	token := fmt.Sprintf("request-%d", s.nextToken)
+	s.pending[token] = &pendingQuery{records: records, polls: s.runningPolls}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{"state": "RUNNING", "requestToken": token})
+}
+
+func (s *Server) poll(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("request-token")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending, ok := s.pending[token]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "unknown request token")
+		return
+	}
+
+	if pending.polls > 0 {
+		pending.polls--
+		writeJSON(w, http.StatusOK, map[string]interface{}{"state": "RUNNING", "progress": 50})
+		return
+	}
+
+	delete(s.pending, token)
+	records := pending.records
+	if records == nil {
+		records = []map[string]interface{}{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"state":    "SUCCEEDED",
+		"progress": 100,
+		"result":   map[string]interface{}{"records": records},
+	})
+}
+
+func (s *Server) listDocuments(w http.ResponseWriter, r *http.Request) {
+	m := documentFilterRegex.FindStringSubmatch(strings.TrimSpace(r.URL.Query().Get("filter")))
+	if m == nil {
+		writeError(w, http.StatusBadRequest, "unsupported filter")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	documents := []Document{}
+	for _, doc := range s.documents {
+		if doc.Name == m[1] && doc.Type == m[2] {
+			documents = append(documents, doc)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"documents": documents})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"error": map[string]interface{}{"code": status, "message": message}})
+}
@@ -0,0 +1,215 @@
+package dynatrace
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	gatherIndexFileName = "index.json"
+
+	indexKindPodLogs          = "pod-logs"
+	indexKindDeploymentEvents = "deployment-events"
+	indexKindRestartedPodLogs = "restarted-pod-logs"
+)
+
+// gatherIndex describes the content of a gather-logs directory in index.json
+type gatherIndex struct {
+	ClusterID             string             `json:"clusterID"`
+	ManagementClusterName string             `json:"managementClusterName"`
+	HCPNamespace          string             `json:"hcpNamespace"`
+	From                  time.Time          `json:"from"`
+	To                    time.Time          `json:"to"`
+	Files                 []gatherIndexEntry `json:"files"`
+
+	dir string
+	mu  sync.Mutex
+}
+
+type gatherIndexEntry struct {
+	// Path is relative to the gather directory
+	Path       string                `json:"path"`
+	Kind       string                `json:"kind"`
+	Namespace  string                `json:"namespace"`
+	Pod        string                `json:"pod,omitempty"`
+	Deployment string                `json:"deployment,omitempty"`
+	Containers []gatherContainerInfo `json:"containers,omitempty"`
+	// From and To are the oldest and newest timestamps of the records in the file
+	From    *time.Time `json:"from,omitempty"`
+	To      *time.Time `json:"to,omitempty"`
+	Records int        `json:"records"`
+	Error   string     `json:"error,omitempty"`
+}
+
+type gatherContainerInfo struct {
+	Name                  string     `json:"name"`
+	RestartCount          int32      `json:"restartCount"`
+	LastTerminationReason string     `json:"lastTerminationReason,omitempty"`
+	LastTerminatedAt      *time.Time `json:"lastTerminatedAt,omitempty"`
+}
+
+func newGatherIndex(dir string, hcpCluster HCPCluster, from time.Time, to time.Time) *gatherIndex {
+	return &gatherIndex{
+		ClusterID:             hcpCluster.internalID,
+		ManagementClusterName: hcpCluster.managementClusterName,
+		HCPNamespace:          hcpCluster.hcpNamespace,
+		From:                  from,
+		To:                    to,
+		dir:                   dir,
+	}
+}
+
+// add records a gathered file, it is safe to call from concurrent gathering routines
+func (i *gatherIndex) add(path string, entry gatherIndexEntry) {
+	if rel, err := filepath.Rel(i.dir, path); err == nil {
+		path = rel
+	}
+	entry.Path = filepath.ToSlash(path)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.Files = append(i.Files, entry)
+}
+
+// recordRange counts the records written to a gathered file and the range of their timestamps
+type recordRange struct {
+	count int
+	from  *time.Time
+	to    *time.Time
+}
+
+func (r *recordRange) add(record json.RawMessage) {
+	r.count++
+
+	timestamp, err := recordTimestamp(record)
+	if err != nil {
+		return
+	}
+	ts, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return
+	}
+	if r.from == nil || ts.Before(*r.from) {
+		r.from = &ts
+	}
+	if r.to == nil || ts.After(*r.to) {
+		r.to = &ts
+	}
+}
+
+// record sets the record count and time range of an entry
+func (r recordRange) record(entry *gatherIndexEntry) {
+	entry.Records, entry.From, entry.To = r.count, r.from, r.to
+}
+
+func (i *gatherIndex) write() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	sort.Slice(i.Files, func(a, b int) bool {
+		return i.Files[a].Path < i.Files[b].Path
+	})
+
+	data, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %v", err)
+	}
+
+	indexPath := filepath.Join(i.dir, gatherIndexFileName)
+	if err := os.WriteFile(indexPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write index %s: %v", indexPath, err)
+	}
+
+	return nil
+}
+
+// podContainerRestarts returns the restart information of the init and regular containers of a pod
+func podContainerRestarts(pod *corev1.Pod) []gatherContainerInfo {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+
+	containers := make([]gatherContainerInfo, 0, len(statuses))
+	for _, cs := range statuses {
+		info := gatherContainerInfo{Name: cs.Name, RestartCount: cs.RestartCount}
+		if terminated := cs.LastTerminationState.Terminated; terminated != nil {
+			info.LastTerminationReason = terminated.Reason
+			if !terminated.FinishedAt.IsZero() {
+				finishedAt := terminated.FinishedAt.UTC()
+				info.LastTerminatedAt = &finishedAt
+			}
+		}
+		containers = append(containers, info)
+	}
+
+	return containers
+}
+
+// archiveGatherDir packages the gather directory into a tar.gz archive. Entries are prefixed with the
+// name of the directory so the archive extracts into a single folder.
+func archiveGatherDir(sourceDir string, archivePath string) (err error) {
+	archiveFile, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive %s: %v", archivePath, err)
+	}
+	defer func() {
+		if cerr := archiveFile.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
+	gzipWriter := gzip.NewWriter(archiveFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	baseDir := filepath.Dir(sourceDir)
+	err = filepath.Walk(sourceDir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return fmt.Errorf("failed to create tar header for %s: %v", file, err)
+		}
+
+		relPath, err := filepath.Rel(baseDir, file)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relPath)
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write tar header for %s: %v", file, err)
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tarWriter, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %v", sourceDir, err)
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %v", err)
+	}
+
+	return gzipWriter.Close()
+}
@@ -0,0 +1,141 @@
+package dynatrace
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGatherNamespaceList(t *testing.T) {
+	got := gatherNamespaceList([]string{"ocm-hcp", "", "hypershift"}, []string{"hypershift", " openshift-monitoring ", ""})
+	expected := []string{"ocm-hcp", "hypershift", "openshift-monitoring"}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestPodContainerRestarts(t *testing.T) {
+	finishedAt := time.Date(2025, 6, 15, 4, 0, 0, 0, time.UTC)
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{Name: "init"}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "kube-apiserver",
+				RestartCount: 3,
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", FinishedAt: v1.NewTime(finishedAt)},
+				},
+			}},
+		},
+	}
+
+	got := podContainerRestarts(pod)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 containers, got %d", len(got))
+	}
+	if got[0].Name != "init" || got[0].RestartCount != 0 || got[0].LastTerminatedAt != nil {
+		t.Errorf("unexpected init container info: %+v", got[0])
+	}
+	if got[1].Name != "kube-apiserver" || got[1].RestartCount != 3 || got[1].LastTerminationReason != "OOMKilled" || !got[1].LastTerminatedAt.Equal(finishedAt) {
+		t.Errorf("unexpected container info: %+v", got[1])
+	}
+}
+
+func TestGatherIndexAndArchive(t *testing.T) {
+	gatherDir, err := setupGatherDir(t.TempDir(), "ocm-hcp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	from := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Hour)
+	index := newGatherIndex(gatherDir, HCPCluster{internalID: "abc", managementClusterName: "mc01", hcpNamespace: "ocm-hcp"}, from, to)
+
+	podDir, err := addDir([]string{gatherDir, "ocm-hcp", "pods", "etcd-0"}, []string{"pod.log"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	podRecords := recordRange{}
+	podRecords.add(json.RawMessage(`{"timestamp":"2025-06-15T04:00:05Z","content":"b"}`))
+	podRecords.add(json.RawMessage(`{"timestamp":"2025-06-15T04:00:01.5Z","content":"a"}`))
+	podRecords.add(json.RawMessage(`{"content":"no timestamp"}`))
+	podEntry := gatherIndexEntry{Kind: indexKindPodLogs, Namespace: "ocm-hcp", Pod: "etcd-0"}
+	podRecords.record(&podEntry)
+	index.add(filepath.Join(podDir, "pod.log"), podEntry)
+	index.add(filepath.Join(gatherDir, "ocm-hcp", "events", "etcd", "events.log"), gatherIndexEntry{Kind: indexKindDeploymentEvents, Namespace: "ocm-hcp", Deployment: "etcd", Error: "query failed"})
+
+	if err := index.write(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(gatherDir, gatherIndexFileName))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var written gatherIndex
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if written.ClusterID != "abc" || len(written.Files) != 2 {
+		t.Fatalf("unexpected index: %s", data)
+	}
+	if written.Files[0].Path != "ocm-hcp/events/etcd/events.log" || written.Files[1].Path != "ocm-hcp/pods/etcd-0/pod.log" {
+		t.Errorf("expected files sorted by relative path, got %s and %s", written.Files[0].Path, written.Files[1].Path)
+	}
+	pod := written.Files[1]
+	if pod.Records != 3 || pod.From == nil || !pod.From.Equal(time.Date(2025, 6, 15, 4, 0, 1, 5e8, time.UTC)) ||
+		pod.To == nil || !pod.To.Equal(time.Date(2025, 6, 15, 4, 0, 5, 0, time.UTC)) {
+		t.Errorf("expected the time range of the records, got %+v", pod)
+	}
+	if events := written.Files[0]; events.From != nil || events.To != nil {
+		t.Errorf("expected no time range without records, got %+v", events)
+	}
+
+	archivePath := gatherDir + ".tar.gz"
+	if err := archiveGatherDir(gatherDir, archivePath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			names = append(names, hdr.Name)
+		}
+	}
+	sort.Strings(names)
+	expected := []string{"hcp-logs-dump-ocm-hcp/index.json", "hcp-logs-dump-ocm-hcp/ocm-hcp/pods/etcd-0/pod.log"}
+	if len(names) != len(expected) || names[0] != expected[0] || names[1] != expected[1] {
+		t.Errorf("expected archive entries %v, got %v", expected, names)
+	}
+}
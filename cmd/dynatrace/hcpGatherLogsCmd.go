@@ -7,9 +7,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/openshift/osdctl/cmd/common"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -18,12 +20,18 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
+// defaultGatherConcurrency bounds the Dynatrace queries running at once to stay within the tenant rate limits
+const defaultGatherConcurrency = 4
+
 type GatherLogsOpts struct {
-	Since     int
-	Tail      int
-	SortOrder string
-	DestDir   string
-	ClusterID string
+	Since       int
+	Tail        int
+	SortOrder   string
+	DestDir     string
+	ClusterID   string
+	Namespaces  []string
+	Archive     bool
+	Concurrency int
 }
 
 func NewCmdHCPMustGather() *cobra.Command {
@@ -37,10 +45,16 @@ func NewCmdHCPMustGather() *cobra.Command {
 
   This command fetches the logs from the HCP namespace, the hypershift namespace and cert-manager related namespaces.
   Logs will be dumped to a directory with prefix hcp-must-gather.
+
+  An index.json file at the root of the directory lists every gathered log and event file together with its
+  namespace, pod, containers, time range, record count and container restarts.
 		`,
 		Example: `
   # Gather logs for a HCP cluster with cluster id hcp-cluster-id-123
-  osdctl dt gather-logs --cluster-id hcp-cluster-id-123`,
+  osdctl dt gather-logs --cluster-id hcp-cluster-id-123
+
+  # Also gather the logs of additional namespaces on the management cluster and package them for a Jira attachment
+  osdctl dt gather-logs --cluster-id hcp-cluster-id-123 --namespaces openshift-monitoring,openshift-ingress --archive`,
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
 
@@ -56,6 +70,9 @@ func NewCmdHCPMustGather() *cobra.Command {
 	hcpMgCmd.Flags().StringVar(&g.SortOrder, "sort", "asc", "Sort the results by timestamp in either ascending or descending order. Accepted values are 'asc' and 'desc'")
 	hcpMgCmd.Flags().StringVar(&g.DestDir, "dest-dir", "", "Destination directory for the logs dump, defaults to the local directory.")
 	hcpMgCmd.Flags().StringVarP(&g.ClusterID, "cluster-id", "C", "", "Internal ID of the HCP cluster to gather logs from (required)")
+	hcpMgCmd.Flags().StringSliceVar(&g.Namespaces, "namespaces", []string{}, "Additional management cluster namespace(s) to gather logs and events from (comma-separated)")
+	hcpMgCmd.Flags().BoolVar(&g.Archive, "archive", false, "Package the gathered logs into a tar.gz archive next to the logs directory")
+	hcpMgCmd.Flags().IntVar(&g.Concurrency, "concurrency", defaultGatherConcurrency, "Maximum number of Dynatrace queries to run in parallel")
 
 	_ = hcpMgCmd.MarkFlagRequired("cluster-id")
 
@@ -63,12 +80,12 @@ func NewCmdHCPMustGather() *cobra.Command {
 }
 
 func (g *GatherLogsOpts) GatherLogs(clusterID string, elevationReasons ...string) (error error) {
-	accessToken, err := getStorageAccessToken()
+	hcpCluster, err := FetchClusterDetails(clusterID)
 	if err != nil {
-		return fmt.Errorf("failed to acquire access token %v", err)
+		return err
 	}
 
-	hcpCluster, err := FetchClusterDetails(clusterID)
+	client, err := newStorageClient(hcpCluster.DynatraceURL)
 	if err != nil {
 		return err
 	}
@@ -78,19 +95,34 @@ func (g *GatherLogsOpts) GatherLogs(clusterID string, elevationReasons ...string
 		return fmt.Errorf("failed to retrieve Kubernetes configuration and client for cluster with ID %s: %w", hcpCluster.managementClusterID, err)
 	}
 
+	return g.gather(context.Background(), client, clientset, hcpCluster)
+}
+
+// gather dumps the logs and events of the HCP namespaces on the management cluster into a new gather directory
+func (g *GatherLogsOpts) gather(ctx context.Context, client Client, clientset kubernetes.Interface, hcpCluster HCPCluster) error {
 	fmt.Printf("Using HCP Namespace %v\n", hcpCluster.hcpNamespace)
 
-	gatherNamespaces := []string{hcpCluster.hcpNamespace, hcpCluster.klusterletNS, hcpCluster.hostedNS, "hypershift", "cert-manager", "redhat-cert-manager-operator", "open-cluster-management-agent", "open-cluster-management-agent-addon"}
+	gatherNamespaces := gatherNamespaceList([]string{hcpCluster.hcpNamespace, hcpCluster.klusterletNS, hcpCluster.hostedNS, "hypershift", "cert-manager", "redhat-cert-manager-operator", "open-cluster-management-agent", "open-cluster-management-agent-addon"}, g.Namespaces)
 
 	gatherDir, err := setupGatherDir(g.DestDir, hcpCluster.hcpNamespace)
 	if err != nil {
 		return err
 	}
 
+	to := time.Now().UTC()
+	index := newGatherIndex(gatherDir, hcpCluster, to.Add(-time.Duration(g.Since)*time.Hour), to)
+
+	concurrency := g.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultGatherConcurrency
+	}
+	eg := &errgroup.Group{}
+	eg.SetLimit(concurrency)
+
 	for _, gatherNS := range gatherNamespaces {
 		fmt.Printf("Gathering for %s\n", gatherNS)
 
-		pods, err := getPodsForNamespace(clientset, gatherNS)
+		pods, err := getPodsForNamespace(ctx, clientset, gatherNS)
 		if err != nil {
 			return err
 		}
@@ -100,181 +132,218 @@ func (g *GatherLogsOpts) GatherLogs(clusterID string, elevationReasons ...string
 			return err
 		}
 
-		err = g.dumpPodLogs(pods, nsDir, gatherNS, hcpCluster.managementClusterName, hcpCluster.DynatraceURL, accessToken, g.Since, g.Tail, g.SortOrder)
-		if err != nil {
-			return err
-		}
+		g.dumpPodLogs(ctx, eg, index, pods, nsDir, gatherNS, hcpCluster.managementClusterName, client)
 
-		deployments, err := getDeploymentsForNamespace(clientset, gatherNS)
+		deployments, err := getDeploymentsForNamespace(ctx, clientset, gatherNS)
 		if err != nil {
 			return err
 		}
 
-		err = g.dumpEvents(deployments, nsDir, gatherNS, hcpCluster.managementClusterName, hcpCluster.DynatraceURL, accessToken, g.Since, g.Tail, g.SortOrder)
-		if err != nil {
-			return err
-		}
+		g.dumpEvents(ctx, eg, index, deployments, nsDir, gatherNS, hcpCluster.managementClusterName, client)
 
-		err = g.dumpRestartedPodLogs(pods, nsDir, gatherNS, hcpCluster.managementClusterName, hcpCluster.DynatraceURL, accessToken)
-		if err != nil {
+		g.dumpRestartedPodLogs(ctx, eg, index, pods, nsDir, gatherNS, hcpCluster.managementClusterName, client)
+	}
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	if err := index.write(); err != nil {
+		return err
+	}
+	fmt.Printf("Logs written to %s\n", gatherDir)
+
+	if g.Archive {
+		archivePath := gatherDir + ".tar.gz"
+		if err := archiveGatherDir(gatherDir, archivePath); err != nil {
 			return err
 		}
-
+		fmt.Printf("Archive written to %s\n", archivePath)
 	}
 
 	return nil
 }
 
-func (g *GatherLogsOpts) dumpEvents(deploys *appsv1.DeploymentList, parentDir string, targetNS string, managementClusterName string, DTURL string, accessToken string, since int, tail int, sortOrder string) error {
+// pageSize returns the page size used to fetch every record of a query beyond the maximum result size of
+// Dynatrace, or 0 when the query is limited with --tail or not sorted by ascending timestamp
+func (g *GatherLogsOpts) pageSize() int {
+	if g.Tail > 0 || g.SortOrder != "asc" {
+		return 0
+	}
+	return defaultMaxResultRecords
+}
+
+// gatherNamespaceList appends the extra namespaces to the default ones, dropping empty and duplicate entries
+func gatherNamespaceList(defaults []string, extra []string) []string {
+	seen := map[string]bool{}
+	var namespaces []string
+	for _, ns := range append(append([]string{}, defaults...), extra...) {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+
+	return namespaces
+}
+
+func (g *GatherLogsOpts) dumpEvents(ctx context.Context, eg *errgroup.Group, index *gatherIndex, deploys *appsv1.DeploymentList, parentDir string, targetNS string, managementClusterName string, client Client) {
 	totalDeployments := len(deploys.Items)
 	for k, d := range deploys.Items {
-		fmt.Printf("[%d/%d] Deployment events for %s\n", k+1, totalDeployments, d.Name)
+		eg.Go(func() error {
+			fmt.Printf("[%d/%d] Deployment events for %s\n", k+1, totalDeployments, d.Name)
 
-		eventQuery, err := getEventQuery(d.Name, targetNS, g.Since, g.Tail, g.SortOrder, managementClusterName)
-		if err != nil {
-			return err
-		}
-		eventQuery.Build()
+			eventQuery, err := getEventQuery(d.Name, targetNS, g.Since, g.Tail, g.SortOrder, managementClusterName)
+			if err != nil {
+				return err
+			}
+			eventQuery.Build()
 
-		deploymentYamlFileName := "deployment.yaml"
-		eventsFileName := "events.log"
-		eventsDirPath, err := addDir([]string{parentDir, "events", d.Name}, []string{deploymentYamlFileName, eventsFileName})
-		if err != nil {
-			return err
-		}
+			deploymentYamlFileName := "deployment.yaml"
+			eventsFileName := "events.log"
+			eventsDirPath, err := addDir([]string{parentDir, "events", d.Name}, []string{deploymentYamlFileName, eventsFileName})
+			if err != nil {
+				return err
+			}
 
-		deploymentYamlPath := filepath.Join(eventsDirPath, deploymentYamlFileName)
-		deploymentYaml, err := yaml.Marshal(d)
-		if err != nil {
-			return fmt.Errorf("failed to marshal YAML: %v", err)
-		}
-		f, err := os.OpenFile(deploymentYamlPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-		if err != nil {
-			return err
-		}
-		_, err = f.Write(deploymentYaml)
-		if err != nil {
-			return err
-		}
-		err = f.Close()
-		if err != nil {
-			return err
-		}
+			deploymentYamlPath := filepath.Join(eventsDirPath, deploymentYamlFileName)
+			deploymentYaml, err := yaml.Marshal(d)
+			if err != nil {
+				return fmt.Errorf("failed to marshal YAML: %v", err)
+			}
+			f, err := os.OpenFile(deploymentYamlPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			if err != nil {
+				return err
+			}
+			_, err = f.Write(deploymentYaml)
+			if err != nil {
+				return err
+			}
+			err = f.Close()
+			if err != nil {
+				return err
+			}
 
-		eventsFilePath := filepath.Join(eventsDirPath, eventsFileName)
-		f, err = os.OpenFile(eventsFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-		if err != nil {
-			return err
-		}
+			eventsFilePath := filepath.Join(eventsDirPath, eventsFileName)
+			entry := gatherIndexEntry{Kind: indexKindDeploymentEvents, Namespace: targetNS, Deployment: d.Name}
+			defer func() { index.add(eventsFilePath, entry) }()
 
-		eventsRequestToken, err := getDTQueryExecution(DTURL, accessToken, eventQuery.finalQuery)
-		if err != nil {
-			log.Print("failed to get request token", err)
-			continue
-		}
-		err = getEvents(DTURL, accessToken, eventsRequestToken, f)
-		_ = f.Close()
-		if err != nil {
-			log.Printf("failed to get logs, continuing: %v. Query: %v", err, eventQuery.finalQuery)
-			continue
-		}
+			f, err = os.OpenFile(eventsFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			if err != nil {
+				return err
+			}
+
+			written, err := getEvents(ctx, client, eventQuery.finalQuery, g.pageSize(), f)
+			written.record(&entry)
+			_ = f.Close()
+			if err != nil {
+				log.Printf("failed to get logs, continuing: %v. Query: %v", err, eventQuery.finalQuery)
+				entry.Error = err.Error()
+			}
 
+			return nil
+		})
 	}
-	return nil
 }
 
-func (g *GatherLogsOpts) dumpPodLogs(pods *corev1.PodList, parentDir string, targetNS string, managementClusterName string, DTURL string, accessToken string, since int, tail int, sortOrder string) error {
+func (g *GatherLogsOpts) dumpPodLogs(ctx context.Context, eg *errgroup.Group, index *gatherIndex, pods *corev1.PodList, parentDir string, targetNS string, managementClusterName string, client Client) {
 	totalPods := len(pods.Items)
 	for k, p := range pods.Items {
-		fmt.Printf("[%d/%d] Pod logs for %s\n", k+1, totalPods, p.Name)
+		eg.Go(func() error {
+			fmt.Printf("[%d/%d] Pod logs for %s\n", k+1, totalPods, p.Name)
 
-		podLogsQuery, err := getPodQuery(p.Name, targetNS, g.Since, g.Tail, g.SortOrder, managementClusterName)
-		if err != nil {
-			return err
-		}
-		podLogsQuery.Build()
+			podLogsQuery, err := getPodQuery(p.Name, targetNS, g.Since, g.Tail, g.SortOrder, managementClusterName)
+			if err != nil {
+				return err
+			}
+			podLogsQuery.Build()
 
-		podYamlFileName := "pod.yaml"
-		podLogFileName := "pod.log"
-		podDirPath, err := addDir([]string{parentDir, "pods", p.Name}, []string{podLogFileName, podYamlFileName})
-		if err != nil {
-			return err
-		}
+			podYamlFileName := "pod.yaml"
+			podLogFileName := "pod.log"
+			podDirPath, err := addDir([]string{parentDir, "pods", p.Name}, []string{podLogFileName, podYamlFileName})
+			if err != nil {
+				return err
+			}
 
-		podYamlFilePath := filepath.Join(podDirPath, podYamlFileName)
-		podYaml, err := yaml.Marshal(p)
-		if err != nil {
-			return fmt.Errorf("failed to marshal YAML: %v", err)
-		}
-		f, err := os.OpenFile(podYamlFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-		if err != nil {
-			return err
-		}
-		_, err = f.Write(podYaml)
-		if err != nil {
-			return err
-		}
-		_ = f.Close()
+			podYamlFilePath := filepath.Join(podDirPath, podYamlFileName)
+			podYaml, err := yaml.Marshal(p)
+			if err != nil {
+				return fmt.Errorf("failed to marshal YAML: %v", err)
+			}
+			f, err := os.OpenFile(podYamlFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			if err != nil {
+				return err
+			}
+			_, err = f.Write(podYaml)
+			if err != nil {
+				return err
+			}
+			_ = f.Close()
 
-		podLogsFilePath := filepath.Join(podDirPath, podLogFileName)
-		f, err = os.OpenFile(podLogsFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-		if err != nil {
-			return err
-		}
+			podLogsFilePath := filepath.Join(podDirPath, podLogFileName)
+			entry := gatherIndexEntry{Kind: indexKindPodLogs, Namespace: targetNS, Pod: p.Name, Containers: podContainerRestarts(&p)}
+			defer func() { index.add(podLogsFilePath, entry) }()
 
-		podLogsRequestToken, err := getDTQueryExecution(DTURL, accessToken, podLogsQuery.finalQuery)
-		if err != nil {
-			log.Print("failed to get request token", err)
-			continue
-		}
-		err = getLogs(DTURL, accessToken, podLogsRequestToken, f)
-		_ = f.Close()
-		if err != nil {
-			log.Printf("failed to get logs, continuing: %v. Query: %v", err, podLogsQuery.finalQuery)
-			continue
-		}
-	}
+			f, err = os.OpenFile(podLogsFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			if err != nil {
+				return err
+			}
 
-	return nil
+			written, err := getLogs(ctx, client, podLogsQuery.finalQuery, g.pageSize(), f)
+			written.record(&entry)
+			_ = f.Close()
+			if err != nil {
+				log.Printf("failed to get logs, continuing: %v. Query: %v", err, podLogsQuery.finalQuery)
+				entry.Error = err.Error()
+			}
+
+			return nil
+		})
+	}
 }
 
-func (g *GatherLogsOpts) dumpRestartedPodLogs(pods *corev1.PodList, parentDir string, targetNS string, managementClusterName string, DTURL string, accessToken string) error {
+func (g *GatherLogsOpts) dumpRestartedPodLogs(ctx context.Context, eg *errgroup.Group, index *gatherIndex, pods *corev1.PodList, parentDir string, targetNS string, managementClusterName string, client Client) {
 	var podList []string
 	for _, p := range pods.Items {
 		podList = append(podList, p.Name)
 	}
-	fmt.Printf("Collecting Restarted Pod logs for %s\n", targetNS)
 
-	restartedPodLogsQuery, err := getRestartedPodQuery(podList, targetNS, g.Since, g.Tail, g.SortOrder, managementClusterName)
-	if err != nil {
-		return err
-	}
-	restartedPodLogsQuery.Build()
+	eg.Go(func() error {
+		fmt.Printf("Collecting Restarted Pod logs for %s\n", targetNS)
 
-	restartedPodLogFileName := "pods.log"
-	podDirPath, err := addDir([]string{parentDir, "restarted-pods"}, []string{restartedPodLogFileName})
-	if err != nil {
-		return err
-	}
+		restartedPodLogsQuery, err := getRestartedPodQuery(podList, targetNS, g.Since, g.Tail, g.SortOrder, managementClusterName)
+		if err != nil {
+			return err
+		}
+		restartedPodLogsQuery.Build()
 
-	restartedPodLogsFilePath := filepath.Join(podDirPath, restartedPodLogFileName)
-	f, err := os.OpenFile(restartedPodLogsFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0655)
-	if err != nil {
-		return err
-	}
+		restartedPodLogFileName := "pods.log"
+		podDirPath, err := addDir([]string{parentDir, "restarted-pods"}, []string{restartedPodLogFileName})
+		if err != nil {
+			return err
+		}
 
-	podLogsRequestToken, err := getDTQueryExecution(DTURL, accessToken, restartedPodLogsQuery.finalQuery)
-	if err != nil {
-		log.Print("failed to get request token", err)
+		restartedPodLogsFilePath := filepath.Join(podDirPath, restartedPodLogFileName)
+		entry := gatherIndexEntry{Kind: indexKindRestartedPodLogs, Namespace: targetNS}
+		defer func() { index.add(restartedPodLogsFilePath, entry) }()
 
-	}
-	err = getLogs(DTURL, accessToken, podLogsRequestToken, f)
-	f.Close()
-	if err != nil {
-		log.Printf("failed to get restarted pod logs: %v. Query: %v", err, restartedPodLogsQuery.finalQuery)
-	}
+		f, err := os.OpenFile(restartedPodLogsFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0655)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		written, err := getLogs(ctx, client, restartedPodLogsQuery.finalQuery, g.pageSize(), f)
+		written.record(&entry)
+		f.Close()
+		if err != nil {
+			log.Printf("failed to get restarted pod logs: %v. Query: %v", err, restartedPodLogsQuery.finalQuery)
+			entry.Error = err.Error()
+		}
+
+		return nil
+	})
 }
 
 func setupGatherDir(destBaseDir string, dirName string) (logsDir string, error error) {
@@ -388,9 +457,9 @@ func getEventQuery(deploy string, namespace string, since int, tail int, sortOrd
 	return q, nil
 }
 
-func getPodsForNamespace(clientset *kubernetes.Clientset, namespace string) (pl *corev1.PodList, error error) {
+func getPodsForNamespace(ctx context.Context, clientset kubernetes.Interface, namespace string) (pl *corev1.PodList, error error) {
 	// Getting pod objects for non-running state pod
-	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), v1.ListOptions{})
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, v1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods in namespace '%s'", namespace)
 	}
@@ -398,9 +467,9 @@ func getPodsForNamespace(clientset *kubernetes.Clientset, namespace string) (pl
 	return pods, nil
 }
 
-func getDeploymentsForNamespace(clientset *kubernetes.Clientset, namespace string) (pl *appsv1.DeploymentList, error error) {
+func getDeploymentsForNamespace(ctx context.Context, clientset kubernetes.Interface, namespace string) (pl *appsv1.DeploymentList, error error) {
 	// Getting pod objects for non-running state pod
-	deploys, err := clientset.AppsV1().Deployments(namespace).List(context.TODO(), v1.ListOptions{})
+	deploys, err := clientset.AppsV1().Deployments(namespace).List(ctx, v1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods in namespace '%s'", namespace)
 	}
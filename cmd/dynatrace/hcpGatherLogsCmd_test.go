@@ -1,11 +1,21 @@
 package dynatrace
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/dynatrace/fakegrail"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestSetupGatherDir(t *testing.T) {
@@ -277,3 +287,83 @@ func TestGetEventQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestGather(t *testing.T) {
+	server := fakegrail.NewServer()
+	defer server.Close()
+
+	podLogs := fakegrail.Records(
+		map[string]interface{}{"timestamp": "2025-06-15T04:00:00Z", "content": "etcd started"},
+		map[string]interface{}{"timestamp": "2025-06-15T04:00:01Z", "content": "etcd ready"},
+	)
+	events := fakegrail.Records(
+		map[string]interface{}{"timestamp": "2025-06-15T04:00:00Z", "event.name": "ScalingReplicaSet"},
+	)
+	server.HandleQuery(func(query string) ([]map[string]interface{}, error) {
+		switch {
+		case strings.HasPrefix(query, "fetch events"):
+			return events(query)
+		case strings.Contains(query, `and (matchesValue(k8s.pod.name, "etcd-0"))`):
+			return podLogs(query)
+		}
+		return nil, nil
+	})
+	// A flaky tenant must not abort the gathering
+	server.FailNext(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: "etcd-0", Namespace: "ocm-hcp"},
+			Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "etcd", RestartCount: 2}}},
+		},
+		&appsv1.Deployment{ObjectMeta: v1.ObjectMeta{Name: "etcd-operator", Namespace: "ocm-hcp"}},
+	)
+
+	destDir := t.TempDir()
+	g := &GatherLogsOpts{Since: 1, SortOrder: "asc", DestDir: destDir, Archive: true, Concurrency: 2}
+	hcpCluster := HCPCluster{internalID: "abc", hcpNamespace: "ocm-hcp", managementClusterName: "mc01", DynatraceURL: server.URL()}
+
+	if err := g.gather(context.Background(), newTestClient(server), clientset, hcpCluster); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gatherDir := filepath.Join(destDir, "hcp-logs-dump-ocm-hcp")
+	podLog, err := os.ReadFile(filepath.Join(gatherDir, "ocm-hcp", "pods", "etcd-0", "pod.log"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(podLog) != "etcd started\netcd ready\n" {
+		t.Errorf("unexpected pod log: %q", podLog)
+	}
+
+	data, err := os.ReadFile(filepath.Join(gatherDir, gatherIndexFileName))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var index gatherIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := map[string]gatherIndexEntry{}
+	for _, entry := range index.Files {
+		entries[entry.Path] = entry
+	}
+	pod := entries["ocm-hcp/pods/etcd-0/pod.log"]
+	if pod.Records != 2 || len(pod.Containers) != 1 || pod.Containers[0].RestartCount != 2 || pod.Error != "" {
+		t.Errorf("unexpected pod index entry: %+v", pod)
+	}
+	if pod.From == nil || pod.From.Format(time.RFC3339) != "2025-06-15T04:00:00Z" || pod.To == nil || pod.To.Format(time.RFC3339) != "2025-06-15T04:00:01Z" {
+		t.Errorf("expected the time range of the pod logs, got %v - %v", pod.From, pod.To)
+	}
+	if deploy := entries["ocm-hcp/events/etcd-operator/events.log"]; deploy.Records != 1 || deploy.Kind != indexKindDeploymentEvents {
+		t.Errorf("unexpected deployment index entry: %+v", deploy)
+	}
+	if restarted, ok := entries["hypershift/restarted-pods/pods.log"]; !ok || restarted.Records != 0 {
+		t.Errorf("expected an empty restarted pods entry for hypershift, got %+v", restarted)
+	}
+
+	if _, err := os.Stat(gatherDir + ".tar.gz"); err != nil {
+		t.Errorf("expected archive to be written: %v", err)
+	}
+}
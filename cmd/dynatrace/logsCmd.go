@@ -1,11 +1,17 @@
 package dynatrace
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
 	"time"
 
+	"github.com/fatih/color"
 	k8s "github.com/openshift/osdctl/pkg/k8s"
 	"github.com/spf13/cobra"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
@@ -26,6 +32,10 @@ var (
 	containerList []string
 	statusList    []string
 	console       bool
+	follow        bool
+	interval      time.Duration
+	logsOutput    string
+	noColor       bool
 )
 
 const (
@@ -34,6 +44,8 @@ const (
 
   This command also prints the Dynatrace URL and the corresponding DQL in the output.
 
+  Logs are printed as text by default, or as JSON lines with -o jsonl.
+
 `
 
 	logsCmdExample = `
@@ -60,6 +72,12 @@ const (
 
   # Restrict return of logs to those that contain a specific phrase
   $ osdctl dt logs alertmanager-main-0 -n openshift-monitoring --contains <phrase>
+
+  # Stream new logs of the kube-apiserver of an HCP cluster as they arrive, like oc logs -f
+  $ osdctl dt logs --cluster-id <cluster-id> --container kube-apiserver --follow
+
+  # Stream error logs as JSON lines for further processing
+  $ osdctl dt logs --cluster-id <cluster-id> --status error --follow -o jsonl | jq .content
 `
 )
 
@@ -84,6 +102,13 @@ func NewCmdLogs() *cobra.Command {
 				pod = args[0]
 			}
 
+			// The format is read from the global --output flag, text is its empty default
+			logsOutput, err = cmd.Flags().GetString("output")
+			cmdutil.CheckErr(err)
+			if logsOutput == "" {
+				logsOutput = "text"
+			}
+
 			err = main(clusterID)
 			if err != nil {
 				cmdutil.CheckErr(err)
@@ -95,9 +120,8 @@ func NewCmdLogs() *cobra.Command {
 	logsCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only builds the query without fetching any logs from the tenant")
 	logsCmd.Flags().IntVar(&tail, "tail", 1000, "Last 'n' logs to fetch (defaults to 100)")
 	logsCmd.Flags().IntVar(&since, "since", 1, "Number of hours (integer) since which to search (defaults to 1 hour)")
-	logsCmd.Flags().TimeVar(&fromVar, "from", time.Time{}, []string{time.RFC3339, "2006-01-02 15:04"}, "Datetime from which to filter logs, in the format \"YYYY-MM-DD HH:MM\"")
+	logsCmd.Flags().TimeVar(&fromVar, "from", time.Time{}, []string{time.RFC3339, "2006-01-02 15:04"}, "Datetime from which to filter logs, in the format \"YYYY-MM-DD HH:MM\". With --follow, logs are streamed from it")
 	logsCmd.Flags().TimeVar(&toVar, "to", time.Time{}, []string{time.RFC3339, "2006-01-02 15:04"}, "Datetime until which to filter logs to, in the format \"YYYY-MM-DD HH:MM\"")
-	logsCmd.MarkFlagsRequiredTogether("from", "to")
 	logsCmd.MarkFlagsMutuallyExclusive("since", "from")
 	logsCmd.MarkFlagsMutuallyExclusive("since", "to")
 	logsCmd.Flags().StringVar(&contains, "contains", "", "Include logs which contain a phrase")
@@ -107,6 +131,11 @@ func NewCmdLogs() *cobra.Command {
 	logsCmd.Flags().StringSliceVar(&containerList, "container", []string{}, "Container name(s) (comma-separated)")
 	logsCmd.Flags().StringSliceVarP(&namespaceList, "namespace", "n", []string{}, "Namespace(s) (comma-separated)")
 	logsCmd.Flags().BoolVar(&console, "console", false, "Print the url to the dynatrace web console instead of outputting the logs")
+	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep polling Dynatrace and stream new logs as they arrive")
+	logsCmd.Flags().DurationVar(&interval, "interval", 10*time.Second, "Interval between queries when following logs")
+	logsCmd.Flags().BoolVar(&noColor, "no-color", false, "Do not colorize logs by severity")
+	logsCmd.MarkFlagsMutuallyExclusive("follow", "to")
+	logsCmd.MarkFlagsMutuallyExclusive("follow", "console")
 
 	return logsCmd
 }
@@ -148,6 +177,11 @@ func main(clusterID string) error {
 		return fmt.Errorf("--to cannot be set to a datetime before --from")
 	}
 
+	// --to is implied when following, the logs are streamed from --from onwards
+	if fromVar.IsZero() != toVar.IsZero() && !(follow && !fromVar.IsZero()) {
+		return fmt.Errorf("--from and --to must be set together, unless following logs with --from")
+	}
+
 	hcpCluster, err := FetchClusterDetails(clusterID)
 	if err != nil {
 		return fmt.Errorf("failed to acquire cluster details %v", err)
@@ -157,12 +191,34 @@ func main(clusterID string) error {
 		return fmt.Errorf("invalid sort order, expecting 'asc' or 'desc'")
 	}
 
-	query, err := GetQuery(hcpCluster, fromVar, toVar, since)
+	if logsOutput != "text" && logsOutput != "jsonl" {
+		return fmt.Errorf("invalid output format '%s', expecting 'text' or 'jsonl'", logsOutput)
+	}
+
+	if follow && sortOrder != "asc" {
+		return fmt.Errorf("--follow requires ascending sort order")
+	}
+
+	if follow && interval <= 0 {
+		return fmt.Errorf("invalid interval %v", interval)
+	}
+
+	to := toVar
+	if follow && !fromVar.IsZero() {
+		to = time.Now().UTC()
+	}
+
+	query, err := GetQuery(hcpCluster, fromVar, to, since)
 	if err != nil {
 		return fmt.Errorf("failed to build query for Dynatrace %v", err)
 	}
 
-	fmt.Println(query.Build())
+	// The query is printed to stdout like the logs, except for JSON lines which must stay parseable
+	queryOut := os.Stdout
+	if logsOutput == "jsonl" && !dryRun && !console {
+		queryOut = os.Stderr
+	}
+	fmt.Fprintln(queryOut, query.Build())
 
 	if console {
 		var url string
@@ -186,24 +242,148 @@ func main(clusterID string) error {
 		return nil
 	}
 
-	accessToken, err := getStorageAccessToken()
+	client, err := newStorageClient(hcpCluster.DynatraceURL)
 	if err != nil {
-		return fmt.Errorf("failed to acquire access token %v", err)
+		return err
 	}
 
-	requestToken, err := getDTQueryExecution(hcpCluster.DynatraceURL, accessToken, query.finalQuery)
-	if err != nil {
-		return fmt.Errorf("failed to get  vault token %v", err)
-	}
-	err = getLogs(hcpCluster.DynatraceURL, accessToken, requestToken, nil)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// fatih/color already disables colors when stdout is not a terminal or NO_COLOR is set
+	printer := newLogPrinter(os.Stdout, logsOutput, !noColor && !color.NoColor)
+
+	return printLogs(ctx, client, hcpCluster, query.finalQuery, printer)
+}
+
+// followIngestLag is how late Dynatrace may ingest a record, the queries of --follow overlap by it
+const followIngestLag = time.Minute
+
+// printLogs prints the records of the logs query and, when following, keeps streaming new records until ctx is done
+func printLogs(ctx context.Context, client Client, hcpCluster HCPCluster, query string, printer *logPrinter) error {
+	start := time.Now().UTC()
+	records, err := getLogRecords(ctx, client, query)
 	if err != nil {
 		return fmt.Errorf("failed to get logs %v", err)
 	}
 
-	return nil
+	if follow {
+		// The query fetched the newest records first to get the last --tail ones, print them oldest first
+		slices.Reverse(records)
+	}
+
+	for _, r := range records {
+		if err := printer.print(r); err != nil {
+			return err
+		}
+	}
+
+	if !follow {
+		return nil
+	}
+
+	follower := newLogFollower(start, followFloor(start, records), interval+followIngestLag)
+	follower.next(records)
+
+	return followLogs(ctx, client, hcpCluster, follower, printer)
+}
+
+// followStart returns where following logs starts: --from when set, otherwise the start of the --since window
+func followStart(now time.Time) time.Time {
+	if !fromVar.IsZero() {
+		return fromVar
+	}
+	return now.Add(-time.Duration(since) * time.Hour)
+}
+
+// followFloor returns the oldest timestamp to print while following. When the initial records were
+// cut to --tail, the older records of the window must not show up later through the query overlap.
+func followFloor(now time.Time, records []logRecord) time.Time {
+	if tail <= 0 || len(records) < tail {
+		return followStart(now)
+	}
+	for _, r := range records {
+		if ts, err := r.time(); err == nil {
+			return ts
+		}
+	}
+	return followStart(now)
+}
+
+// followLogs re-runs the logs query from the follower's cursor every interval until ctx is done,
+// paging through the results so that bursts of more than --tail records are not cut
+func followLogs(ctx context.Context, client Client, hcpCluster HCPCluster, follower *logFollower, printer *logPrinter) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		to := time.Now().UTC()
+		query, err := getFollowQuery(hcpCluster, follower.from(), to)
+		if err != nil {
+			return fmt.Errorf("failed to build query for Dynatrace %v", err)
+		}
+
+		err = client.QueryPages(ctx, query.Build(), 0, func(raw []json.RawMessage) error {
+			records, err := decodeLogRecords(raw)
+			if err != nil {
+				return err
+			}
+			for _, r := range follower.next(records) {
+				if err := printer.print(r); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to get logs %v", err)
+		}
+
+		follower.advance(to)
+	}
 }
 
 func GetQuery(hcpCluster HCPCluster, fromVar time.Time, toVar time.Time, since int) (query DTQuery, error error) {
+	q := getLogsFilter(hcpCluster, fromVar, toVar, since)
+
+	order := sortOrder
+	if follow {
+		// The last --tail records are printed before following, printLogs puts them back in ascending order
+		order = "desc"
+	}
+	if order != "" {
+		q, err := q.Sort(order)
+		if err != nil {
+			return *q, err
+		}
+	}
+
+	if tail > 0 {
+		q.Limit(tail)
+	}
+
+	return q, nil
+}
+
+// getFollowQuery returns the unlimited query of the records between from and to, in ascending order
+func getFollowQuery(hcpCluster HCPCluster, from time.Time, to time.Time) (DTQuery, error) {
+	q := getLogsFilter(hcpCluster, from, to, since)
+	_, err := q.Sort("asc")
+
+	return q, err
+}
+
+// getLogsFilter returns the logs query filtered by the flags, without sorting or limit
+func getLogsFilter(hcpCluster HCPCluster, fromVar time.Time, toVar time.Time, since int) DTQuery {
 	q := DTQuery{}
 
 	if !fromVar.IsZero() && !toVar.IsZero() {
@@ -212,12 +392,14 @@ func GetQuery(hcpCluster HCPCluster, fromVar time.Time, toVar time.Time, since i
 		q.InitLogs(since).Cluster(hcpCluster.managementClusterName)
 	}
 
+	// Copy the flag value, GetQuery is called repeatedly when following logs
+	namespaces := append([]string{}, namespaceList...)
 	if hcpCluster.hcpNamespace != "" {
-		namespaceList = append(namespaceList, hcpCluster.hcpNamespace)
+		namespaces = append(namespaces, hcpCluster.hcpNamespace)
 	}
 
-	if len(namespaceList) > 0 {
-		q.Namespaces(namespaceList)
+	if len(namespaces) > 0 {
+		q.Namespaces(namespaces)
 	}
 
 	if len(nodeList) > 0 {
@@ -240,16 +422,5 @@ func GetQuery(hcpCluster HCPCluster, fromVar time.Time, toVar time.Time, since i
 		q.ContainsPhrase(contains)
 	}
 
-	if sortOrder != "" {
-		q, err := q.Sort(sortOrder)
-		if err != nil {
-			return *q, err
-		}
-	}
-
-	if tail > 0 {
-		q.Limit(tail)
-	}
-
-	return q, nil
+	return q
 }
@@ -0,0 +1,156 @@
+package dynatrace
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// logRecord is the subset of a Dynatrace log record printed by `dt logs`
+type logRecord struct {
+	Timestamp string `json:"timestamp"`
+	Status    string `json:"status,omitempty"`
+	Namespace string `json:"k8s.namespace.name,omitempty"`
+	Pod       string `json:"k8s.pod.name,omitempty"`
+	Container string `json:"k8s.container.name,omitempty"`
+	Content   string `json:"content"`
+}
+
+// key identifies a record across overlapping follow queries
+func (r logRecord) key() string {
+	sum := sha256.Sum256([]byte(r.Content))
+	return r.Timestamp + "/" + hex.EncodeToString(sum[:8])
+}
+
+func (r logRecord) time() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, r.Timestamp)
+}
+
+func getLogRecords(ctx context.Context, client Client, query string) ([]logRecord, error) {
+	records, err := client.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeLogRecords(records)
+}
+
+func decodeLogRecords(records []json.RawMessage) ([]logRecord, error) {
+	logRecords := make([]logRecord, 0, len(records))
+	for _, raw := range records {
+		var r logRecord
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse log record: %v", err)
+		}
+		logRecords = append(logRecords, r)
+	}
+
+	return logRecords, nil
+}
+
+// logPrinter writes log records either as plain content, colorized by severity, or as JSON lines
+type logPrinter struct {
+	out    io.Writer
+	output string
+	colors map[string]*color.Color
+}
+
+func newLogPrinter(out io.Writer, output string, colorize bool) *logPrinter {
+	colors := map[string]*color.Color{
+		"ERROR": color.New(color.FgRed),
+		"WARN":  color.New(color.FgYellow),
+		"DEBUG": color.New(color.Faint),
+	}
+	for _, c := range colors {
+		if colorize {
+			c.EnableColor()
+		} else {
+			c.DisableColor()
+		}
+	}
+
+	return &logPrinter{out: out, output: output, colors: colors}
+}
+
+func (p *logPrinter) print(r logRecord) error {
+	if p.output == "jsonl" {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.out, string(line))
+		return err
+	}
+
+	status := strings.ToUpper(r.Status)
+	if strings.HasPrefix(status, "WARN") {
+		status = "WARN"
+	}
+	if c, ok := p.colors[status]; ok {
+		_, err := fmt.Fprintln(p.out, c.Sprint(r.Content))
+		return err
+	}
+	_, err := fmt.Fprintln(p.out, r.Content)
+	return err
+}
+
+// logFollower tracks the records already printed while `dt logs --follow` re-queries Dynatrace.
+// Every query starts an overlap before the end of the previous one, so records ingested late are
+// still fetched. Records of the overlap are returned again, so they are deduplicated by timestamp
+// and content hash.
+type logFollower struct {
+	// cursor is the end of the last query
+	cursor  time.Time
+	overlap time.Duration
+	// floor is the oldest timestamp printed, older records were left out of the initial --tail
+	floor time.Time
+	seen  map[string]time.Time
+}
+
+func newLogFollower(cursor time.Time, floor time.Time, overlap time.Duration) *logFollower {
+	return &logFollower{cursor: cursor, floor: floor, overlap: overlap, seen: map[string]time.Time{}}
+}
+
+// next returns the records not seen before, in the order received. Records without a parseable
+// timestamp cannot be deduplicated and are dropped.
+func (f *logFollower) next(records []logRecord) []logRecord {
+	var fresh []logRecord
+	for _, r := range records {
+		ts, err := r.time()
+		if err != nil || ts.Before(f.floor) {
+			continue
+		}
+		key := r.key()
+		if _, ok := f.seen[key]; ok {
+			continue
+		}
+		f.seen[key] = ts
+		fresh = append(fresh, r)
+	}
+
+	return fresh
+}
+
+// advance moves the cursor to the end of the query that just completed and forgets the records
+// the next query can no longer return
+func (f *logFollower) advance(to time.Time) {
+	f.cursor = to
+	from := f.from()
+	for key, ts := range f.seen {
+		if ts.Before(from) {
+			delete(f.seen, key)
+		}
+	}
+}
+
+// from is the start of the next query; the query format has a resolution of one second
+func (f *logFollower) from() time.Time {
+	return f.cursor.Add(-f.overlap).UTC().Truncate(time.Second)
+}
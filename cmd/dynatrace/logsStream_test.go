@@ -0,0 +1,223 @@
+package dynatrace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/dynatrace/fakegrail"
+)
+
+func TestDecodeLogRecords(t *testing.T) {
+	raw := []json.RawMessage{
+		json.RawMessage(`{"timestamp":"2025-06-15T04:00:00.123000000Z","status":"ERROR","k8s.pod.name":"kube-apiserver-0","content":"boom"}`),
+	}
+
+	records, err := decodeLogRecords(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	expected := logRecord{Timestamp: "2025-06-15T04:00:00.123000000Z", Status: "ERROR", Pod: "kube-apiserver-0", Content: "boom"}
+	if records[0] != expected {
+		t.Errorf("expected %+v, got %+v", expected, records[0])
+	}
+}
+
+func TestLogFollowerNext(t *testing.T) {
+	start := time.Date(2025, 6, 15, 4, 0, 10, 0, time.UTC)
+	floor := time.Date(2025, 6, 15, 4, 0, 1, 0, time.UTC)
+	f := newLogFollower(start, floor, 5*time.Second)
+
+	first := []logRecord{
+		{Timestamp: "2025-06-15T04:00:00.5Z", Content: "cut by --tail"},
+		{Timestamp: "2025-06-15T04:00:01.5Z", Content: "a"},
+		{Timestamp: "2025-06-15T04:00:08.5Z", Content: "b"},
+		{Timestamp: "not-a-time", Content: "dropped"},
+	}
+	fresh := f.next(first)
+	if len(fresh) != 2 || fresh[0].Content != "a" || fresh[1].Content != "b" {
+		t.Fatalf("unexpected first batch: %+v", fresh)
+	}
+
+	if expected := time.Date(2025, 6, 15, 4, 0, 5, 0, time.UTC); !f.from().Equal(expected) {
+		t.Errorf("expected next query from %v, got %v", expected, f.from())
+	}
+
+	// The next query overlaps the previous one, records ingested late are returned while those
+	// already printed are skipped
+	second := []logRecord{
+		{Timestamp: "2025-06-15T04:00:06Z", Content: "late"},
+		{Timestamp: "2025-06-15T04:00:08.5Z", Content: "b"},
+		{Timestamp: "2025-06-15T04:00:12Z", Content: "c"},
+	}
+	fresh = f.next(second)
+	if len(fresh) != 2 || fresh[0].Content != "late" || fresh[1].Content != "c" {
+		t.Fatalf("unexpected second batch: %+v", fresh)
+	}
+
+	// Records before the next query can no longer be returned and are forgotten
+	f.advance(time.Date(2025, 6, 15, 4, 0, 12, 0, time.UTC))
+	if _, ok := f.seen[first[1].key()]; ok {
+		t.Errorf("expected record before the next query to be pruned")
+	}
+	if _, ok := f.seen[second[1].key()]; !ok {
+		t.Errorf("expected record of the overlap to be kept")
+	}
+}
+
+func TestFollowLogs(t *testing.T) {
+	defer func(i time.Duration, n int) { interval, tail = i, n }(interval, tail)
+	interval, tail = time.Millisecond, 2
+
+	now := time.Now().UTC()
+	ts := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339Nano) }
+	// More records share a second than --tail, and one is ingested after a newer one was printed
+	records := []map[string]interface{}{
+		{"timestamp": ts(-3 * time.Second), "content": "printed"},
+		{"timestamp": ts(-2 * time.Second), "content": "burst 1"},
+		{"timestamp": ts(-2 * time.Second), "content": "burst 2"},
+		{"timestamp": ts(-2 * time.Second), "content": "burst 3"},
+		{"timestamp": ts(-4 * time.Second), "content": "late"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := fakegrail.NewServer()
+	defer server.Close()
+	polls := 0
+	server.HandleQuery(func(query string) ([]map[string]interface{}, error) {
+		if polls++; polls > 1 {
+			cancel()
+		}
+		return fakegrail.Records(records...)(query)
+	})
+
+	var out bytes.Buffer
+	follower := newLogFollower(now.Add(-time.Second), now.Add(-time.Minute), time.Minute)
+	follower.next([]logRecord{{Timestamp: ts(-3 * time.Second), Content: "printed"}})
+	if err := followLogs(ctx, newTestClient(server), HCPCluster{managementClusterName: "mc01"}, follower, newLogPrinter(&out, "text", false)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.String() != "late\nburst 1\nburst 2\nburst 3\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+	for _, query := range server.Queries() {
+		if strings.HasSuffix(query, "| limit 2") {
+			t.Errorf("expected follow queries not to be cut to --tail: %s", query)
+		}
+	}
+}
+
+func TestLogPrinter(t *testing.T) {
+	r := logRecord{Timestamp: "2025-06-15T04:00:00Z", Status: "ERROR", Content: "boom"}
+
+	tests := []struct {
+		name     string
+		output   string
+		colorize bool
+		expected string
+	}{
+		{"plain text", "text", false, "boom\n"},
+		{"colorized text", "text", true, "\x1b[31mboom\x1b[0m\n"},
+		{"json lines", "jsonl", true, `{"timestamp":"2025-06-15T04:00:00Z","status":"ERROR","content":"boom"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := newLogPrinter(&out, tt.output, tt.colorize).print(r); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestPrintLogs(t *testing.T) {
+	server := fakegrail.NewServer()
+	defer server.Close()
+	server.HandleQuery(fakegrail.Records(
+		map[string]interface{}{"timestamp": "2025-06-15T04:00:00Z", "status": "INFO", "content": "started"},
+		map[string]interface{}{"timestamp": "2025-06-15T04:00:01Z", "status": "ERROR", "content": "boom"},
+	))
+	server.SetRunningPolls(1)
+
+	var out bytes.Buffer
+	query, err := GetQuery(HCPCluster{managementClusterName: "mc01"}, time.Time{}, time.Time{}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = printLogs(context.Background(), newTestClient(server), HCPCluster{}, query.Build(), newLogPrinter(&out, "text", false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.String() != "started\nboom\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+	if queries := server.Queries(); len(queries) != 1 || !strings.Contains(queries[0], `matchesPhrase(dt.kubernetes.cluster.name, "mc01")`) {
+		t.Errorf("unexpected queries: %q", queries)
+	}
+}
+
+func TestPrintLogsFollowTail(t *testing.T) {
+	defer func(f bool, n int, i time.Duration) { follow, tail, interval = f, n, i }(follow, tail, interval)
+	follow, tail, interval = true, 2, time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := fakegrail.NewServer()
+	defer server.Close()
+	queries := 0
+	server.HandleQuery(func(string) ([]map[string]interface{}, error) {
+		if queries++; queries > 1 {
+			cancel()
+			return nil, nil
+		}
+		// Newest first, as returned by the descending tail query
+		return []map[string]interface{}{
+			{"timestamp": "2025-06-15T04:00:02Z", "content": "c"},
+			{"timestamp": "2025-06-15T04:00:01Z", "content": "b"},
+		}, nil
+	})
+
+	query, err := GetQuery(HCPCluster{managementClusterName: "mc01"}, time.Time{}, time.Time{}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(query.Build(), "sort timestamp desc\n| limit 2") {
+		t.Errorf("expected the initial query to fetch the newest records: %s", query.Build())
+	}
+
+	var out bytes.Buffer
+	if err := printLogs(ctx, newTestClient(server), HCPCluster{}, query.Build(), newLogPrinter(&out, "text", false)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "b\nc\n" {
+		t.Errorf("expected the last records oldest first, got %q", out.String())
+	}
+}
+
+func TestFollowStart(t *testing.T) {
+	defer func(from time.Time, hours int) { fromVar, since = from, hours }(fromVar, since)
+	now := time.Date(2025, 6, 15, 4, 0, 0, 0, time.UTC)
+
+	fromVar, since = time.Time{}, 2
+	if got := followStart(now); !got.Equal(now.Add(-2 * time.Hour)) {
+		t.Errorf("expected the start of the --since window, got %v", got)
+	}
+
+	fromVar = time.Date(2025, 6, 14, 0, 0, 0, 0, time.UTC)
+	if got := followStart(now); !got.Equal(fromVar) {
+		t.Errorf("expected --from, got %v", got)
+	}
+}
@@ -0,0 +1,417 @@
+package dynatrace
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	k8s "github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	// DTQueryScopes extends the storage scopes with the spans and metrics tables the query command can fetch
+	DTQueryScopes string = DTStorageScopes + " storage:spans:read storage:metrics:read"
+
+	queryCmdDescription = `
+  Run an arbitrary DQL query against the Dynatrace tenant of a cluster and render the resulting records.
+
+  The query is either passed with --query or read from a saved query file with --file. Saved queries
+  may reference parameters as ${name}, which are substituted from --param. The following parameters are
+  always available, derived from the cluster:
+
+    ${clusterID}, ${clusterName}, ${mgmtClusterName}, ${hcpNamespace}, ${klusterletNamespace}
+
+  Alternatively, --fetch builds the query from flags, scoped to the management cluster (and the HCP
+  namespace) of the cluster. Records matching any of --match and --match-phrase are kept, records
+  matching any of --exclude are dropped. For metrics, every field filtered on must be listed in --by.
+
+  The records are rendered as a table by default, or as JSON or CSV with -o json and -o csv.
+`
+
+	queryCmdExample = `
+  # Count error logs per namespace on the management cluster of an HCP cluster
+  $ osdctl dt query -C <cluster-id> --query 'fetch logs | filter matchesPhrase(dt.kubernetes.cluster.name, "${mgmtClusterName}") and status == "ERROR" | summarize count(), by:{k8s.namespace.name}'
+
+  # Run a saved query with parameters and write the results as CSV
+  $ osdctl dt query -C <cluster-id> --file restarts.dql --param container=kube-apiserver -o csv > restarts.csv
+
+  # Only print the columns of interest
+  $ osdctl dt query -C <cluster-id> --file spans.dql --columns timestamp,span.name,duration
+
+  # Build a query for the slowest failing spans of the HCP namespace without writing DQL
+  $ osdctl dt query -C <cluster-id> --fetch spans --match span.status_code=error --exclude k8s.container.name=istio-proxy --fields timestamp,span.name,duration --limit 20
+
+  # Average CPU usage of the kube-apiserver containers over the last 6 hours
+  $ osdctl dt query -C <cluster-id> --fetch metrics --metric dt.kubernetes.container.cpu_usage --by k8s.container.name --match k8s.container.name=kube-apiserver --since 6
+`
+)
+
+var queryParamRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+type queryOptions struct {
+	clusterID string
+	query     string
+	file      string
+	params    map[string]string
+	output    string
+	columns   []string
+	dryRun    bool
+
+	// Options of the query built with --fetch
+	fetch       string
+	metric      string
+	aggregation string
+	by          []string
+	since       int
+	namespaces  []string
+	match       []string
+	matchPhrase []string
+	exclude     []string
+	fields      []string
+	limit       int
+}
+
+func newCmdQuery() *cobra.Command {
+	opts := &queryOptions{output: "table"}
+
+	queryCmd := &cobra.Command{
+		Use:               "query --cluster-id <cluster-identifier> (--query <dql> | --file <path> | --fetch <type>)",
+		Short:             "Run a raw or saved DQL query against Dynatrace",
+		Long:              queryCmdDescription,
+		Example:           queryCmdExample,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			// The format is read from the global --output flag, table is its empty default
+			output, err := cmd.Flags().GetString("output")
+			cmdutil.CheckErr(err)
+			if output != "" {
+				opts.output = output
+			}
+			cmdutil.CheckErr(opts.run())
+		},
+	}
+
+	queryCmd.Flags().StringVarP(&opts.clusterID, "cluster-id", "C", "", "Name or Internal ID of the cluster (defaults to current cluster context)")
+	queryCmd.Flags().StringVarP(&opts.query, "query", "q", "", "DQL query to run")
+	queryCmd.Flags().StringVarP(&opts.file, "file", "f", "", "Path to a file containing the DQL query to run")
+	queryCmd.Flags().StringToStringVarP(&opts.params, "param", "p", map[string]string{}, "Parameters substituted for ${name} in the query (e.g. --param container=kube-apiserver)")
+	queryCmd.Flags().StringSliceVar(&opts.columns, "columns", []string{}, "Columns to render, in order (defaults to all fields of the records)")
+	queryCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Only print the rendered query without running it")
+	queryCmd.Flags().StringVar(&opts.fetch, "fetch", "", "Build the query from flags instead, fetching one of: logs, events, spans, metrics")
+	queryCmd.Flags().StringVar(&opts.metric, "metric", "", "Metric key of the timeseries to fetch with --fetch metrics (e.g. dt.kubernetes.container.cpu_usage)")
+	queryCmd.Flags().StringVar(&opts.aggregation, "aggregation", "avg", "Aggregation of the metric with --fetch metrics: avg, sum, min, max, count")
+	queryCmd.Flags().StringSliceVar(&opts.by, "by", []string{}, "Fields to split the metric by with --fetch metrics (comma-separated)")
+	queryCmd.Flags().IntVar(&opts.since, "since", 1, "Number of hours (integer) since which to search with --fetch")
+	queryCmd.Flags().StringSliceVarP(&opts.namespaces, "namespace", "n", []string{}, "Namespace(s) to restrict --fetch to (comma-separated)")
+	queryCmd.Flags().StringArrayVar(&opts.match, "match", []string{}, "Keep records where field equals value, as field=value (repeatable, any may match)")
+	queryCmd.Flags().StringArrayVar(&opts.matchPhrase, "match-phrase", []string{}, "Keep records where field contains the phrase, as field=phrase (repeatable, any may match)")
+	queryCmd.Flags().StringArrayVar(&opts.exclude, "exclude", []string{}, "Drop records where field equals value, as field=value (repeatable)")
+	queryCmd.Flags().StringSliceVar(&opts.fields, "fields", []string{}, "Fields to project the records onto with --fetch, in order (comma-separated)")
+	queryCmd.Flags().IntVar(&opts.limit, "limit", 1000, "Maximum number of records to fetch with --fetch")
+	queryCmd.MarkFlagsOneRequired("query", "file", "fetch")
+	queryCmd.MarkFlagsMutuallyExclusive("query", "file", "fetch")
+
+	return queryCmd
+}
+
+func (o *queryOptions) run() error {
+	if o.output != "table" && o.output != "json" && o.output != "csv" {
+		return fmt.Errorf("invalid output format '%s', expecting 'table', 'json' or 'csv'", o.output)
+	}
+
+	var err error
+	if o.clusterID == "" {
+		o.clusterID, err = k8s.GetCurrentCluster()
+		if err != nil {
+			return err
+		}
+	}
+
+	hcpCluster, err := FetchClusterDetails(o.clusterID)
+	if err != nil {
+		return fmt.Errorf("failed to acquire cluster details %v", err)
+	}
+
+	query, err := o.buildQuery(hcpCluster)
+	if err != nil {
+		return err
+	}
+
+	if o.dryRun {
+		fmt.Println(query)
+		return nil
+	}
+	// The records are printed to stdout, so the query goes to stderr to keep them parseable
+	fmt.Fprintln(os.Stderr, query)
+
+	client, err := newScopedClient(hcpCluster.DynatraceURL, DTStorageVaultPath, DTQueryScopes)
+	if err != nil {
+		return err
+	}
+
+	records, err := runQuery(context.Background(), client, query)
+	if err != nil {
+		return err
+	}
+
+	columns := o.columns
+	if len(columns) == 0 {
+		columns = o.fields
+	}
+
+	return renderRecords(os.Stdout, records, columns, o.output)
+}
+
+// buildQuery returns the query to run, either built from the --fetch flags or the raw query of
+// --query or --file with its parameters substituted
+func (o *queryOptions) buildQuery(hcpCluster HCPCluster) (string, error) {
+	if o.fetch != "" {
+		return o.fetchQuery(hcpCluster)
+	}
+
+	rawQuery := o.query
+	if o.file != "" {
+		data, err := os.ReadFile(o.file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read query file %s: %v", o.file, err)
+		}
+		rawQuery = string(data)
+	}
+
+	return renderQuery(rawQuery, clusterQueryParams(hcpCluster), o.params)
+}
+
+// fetchQuery builds the query of --fetch, scoped to the management cluster and HCP namespace of the cluster
+func (o *queryOptions) fetchQuery(hcpCluster HCPCluster) (string, error) {
+	if o.since <= 0 {
+		return "", fmt.Errorf("invalid time duration")
+	}
+
+	namespaces := append([]string{}, o.namespaces...)
+	if hcpCluster.hcpNamespace != "" {
+		namespaces = append(namespaces, hcpCluster.hcpNamespace)
+	}
+
+	q := DTQuery{}
+	switch o.fetch {
+	case "logs":
+		q.InitLogs(o.since)
+	case "events":
+		q.InitEvents(o.since)
+	case "spans":
+		q.InitSpans(o.since)
+	case "metrics":
+		if o.metric == "" {
+			return "", fmt.Errorf("--metric is required with --fetch metrics")
+		}
+		// The timeseries only keeps the fields it is split by, so the ones filtered on below are added
+		by := []string{"dt.kubernetes.cluster.name"}
+		if len(namespaces) > 0 {
+			by = append(by, "k8s.namespace.name")
+		}
+		q.InitMetrics(o.aggregation, o.metric, append(by, o.by...), o.since)
+	default:
+		return "", fmt.Errorf("invalid fetch '%s', expecting 'logs', 'events', 'spans' or 'metrics'", o.fetch)
+	}
+
+	q.Cluster(hcpCluster.managementClusterName)
+
+	if len(namespaces) > 0 {
+		q.Namespaces(namespaces)
+	}
+
+	var anyOf []string
+	for _, m := range o.match {
+		field, value, err := splitFieldValue(m, "--match")
+		if err != nil {
+			return "", err
+		}
+		anyOf = append(anyOf, MatchesValue(field, value))
+	}
+	for _, m := range o.matchPhrase {
+		field, phrase, err := splitFieldValue(m, "--match-phrase")
+		if err != nil {
+			return "", err
+		}
+		anyOf = append(anyOf, MatchesPhrase(field, phrase))
+	}
+	q.AnyOf(anyOf...)
+
+	var exclude []string
+	for _, e := range o.exclude {
+		field, value, err := splitFieldValue(e, "--exclude")
+		if err != nil {
+			return "", err
+		}
+		exclude = append(exclude, MatchesValue(field, value))
+	}
+	q.Not(exclude...)
+
+	q.Fields(o.fields)
+
+	if o.limit > 0 {
+		q.Limit(o.limit)
+	}
+
+	return q.Build(), nil
+}
+
+func splitFieldValue(condition string, flag string) (string, string, error) {
+	field, value, ok := strings.Cut(condition, "=")
+	if !ok || field == "" {
+		return "", "", fmt.Errorf("invalid %s '%s', expecting field=value", flag, condition)
+	}
+	return field, value, nil
+}
+
+// clusterQueryParams returns the parameters derived from the cluster that saved queries may reference
+func clusterQueryParams(hcpCluster HCPCluster) map[string]string {
+	return map[string]string{
+		"clusterID":           hcpCluster.internalID,
+		"clusterName":         hcpCluster.name,
+		"mgmtClusterName":     hcpCluster.managementClusterName,
+		"hcpNamespace":        hcpCluster.hcpNamespace,
+		"klusterletNamespace": hcpCluster.klusterletNS,
+	}
+}
+
+// renderQuery substitutes ${name} references in the query, user parameters taking precedence over
+// those derived from the cluster. Referencing an unknown parameter is an error.
+func renderQuery(rawQuery string, clusterParams map[string]string, userParams map[string]string) (string, error) {
+	var missing []string
+	// Only ${name} is a parameter, DQL itself may contain $ (e.g. in regular expressions)
+	rendered := queryParamRegex.ReplaceAllStringFunc(rawQuery, func(placeholder string) string {
+		name := queryParamRegex.FindStringSubmatch(placeholder)[1]
+		if value, ok := userParams[name]; ok {
+			return value
+		}
+		if value, ok := clusterParams[name]; ok {
+			return value
+		}
+		missing = append(missing, name)
+		return placeholder
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("query references undefined parameters: %s", strings.Join(missing, ", "))
+	}
+
+	rendered = strings.TrimSpace(rendered)
+	if rendered == "" {
+		return "", fmt.Errorf("query is empty")
+	}
+
+	return rendered, nil
+}
+
+func runQuery(ctx context.Context, client Client, query string) ([]map[string]interface{}, error) {
+	rawRecords, err := client.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get query results %v", err)
+	}
+
+	records := make([]map[string]interface{}, 0, len(rawRecords))
+	for _, raw := range rawRecords {
+		record := map[string]interface{}{}
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return nil, fmt.Errorf("failed to parse record: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// recordColumns returns the union of the fields of all records, timestamp first and the rest sorted
+func recordColumns(records []map[string]interface{}) []string {
+	seen := map[string]bool{}
+	var columns []string
+	for _, record := range records {
+		for field := range record {
+			if !seen[field] {
+				seen[field] = true
+				columns = append(columns, field)
+			}
+		}
+	}
+
+	sort.Slice(columns, func(i, j int) bool {
+		if columns[i] == "timestamp" || columns[j] == "timestamp" {
+			return columns[i] == "timestamp"
+		}
+		return columns[i] < columns[j]
+	})
+
+	return columns
+}
+
+func formatRecordValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+func renderRecords(w io.Writer, records []map[string]interface{}, columns []string, output string) error {
+	if output == "json" {
+		out, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %v", err)
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	}
+
+	if len(columns) == 0 {
+		columns = recordColumns(records)
+	}
+
+	rows := make([][]string, 0, len(records))
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = formatRecordValue(record[column])
+		}
+		rows = append(rows, row)
+	}
+
+	if output == "csv" {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return fmt.Errorf("failed to write CSV header: %v", err)
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return fmt.Errorf("failed to write CSV rows: %v", err)
+		}
+		return nil
+	}
+
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column)
+	}
+	p.AddRow(header)
+	for _, row := range rows {
+		p.AddRow(row)
+	}
+	return p.Flush()
+}
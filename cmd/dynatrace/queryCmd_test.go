@@ -0,0 +1,184 @@
+package dynatrace
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/dynatrace/fakegrail"
+)
+
+func TestRenderQuery(t *testing.T) {
+	clusterParams := map[string]string{"mgmtClusterName": "mc01", "hcpNamespace": "ocm-production-abc-test"}
+
+	tests := []struct {
+		name       string
+		query      string
+		userParams map[string]string
+		expected   string
+		expectErr  string
+	}{
+		{
+			name:     "cluster params",
+			query:    `fetch logs | filter matchesPhrase(dt.kubernetes.cluster.name, "${mgmtClusterName}") and k8s.namespace.name == "${hcpNamespace}"`,
+			expected: `fetch logs | filter matchesPhrase(dt.kubernetes.cluster.name, "mc01") and k8s.namespace.name == "ocm-production-abc-test"`,
+		},
+		{
+			name:       "user params take precedence",
+			query:      "${mgmtClusterName} ${container}\n",
+			userParams: map[string]string{"mgmtClusterName": "mc02", "container": "kube-apiserver"},
+			expected:   "mc02 kube-apiserver",
+		},
+		{
+			name:     "only braced names are params",
+			query:    `fetch logs | filter matchesValue(content, "$HOME") and matches(content, "^a$1") | fields ${mgmtClusterName}`,
+			expected: `fetch logs | filter matchesValue(content, "$HOME") and matches(content, "^a$1") | fields mc01`,
+		},
+		{
+			name:      "undefined param",
+			query:     "fetch logs | filter k8s.container.name == \"${container}\"",
+			expectErr: "undefined parameters: container",
+		},
+		{
+			name:      "empty query",
+			query:     "  \n",
+			expectErr: "query is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderQuery(tt.query, clusterParams, tt.userParams)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected: %s\ngot: %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderRecords(t *testing.T) {
+	server := fakegrail.NewServer()
+	defer server.Close()
+	server.HandleQuery(fakegrail.Records(
+		map[string]interface{}{"timestamp": "2024-01-01T00:00:00Z", "status": "ERROR", "count": 3},
+		map[string]interface{}{"timestamp": "2024-01-01T00:01:00Z", "content": "a, b", "labels": map[string]interface{}{"app": "x"}},
+	))
+
+	records, err := runQuery(context.Background(), newTestClient(server), "fetch logs")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	columns := recordColumns(records)
+	expectedColumns := []string{"timestamp", "content", "count", "labels", "status"}
+	if strings.Join(columns, ",") != strings.Join(expectedColumns, ",") {
+		t.Errorf("expected columns %v, got %v", expectedColumns, columns)
+	}
+
+	var csvOut bytes.Buffer
+	if err := renderRecords(&csvOut, records, []string{"timestamp", "content", "labels"}, "csv"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedCSV := "timestamp,content,labels\n" +
+		"2024-01-01T00:00:00Z,,\n" +
+		"2024-01-01T00:01:00Z,\"a, b\",\"{\"\"app\"\":\"\"x\"\"}\"\n"
+	if csvOut.String() != expectedCSV {
+		t.Errorf("expected:\n%s\ngot:\n%s", expectedCSV, csvOut.String())
+	}
+
+	var tableOut bytes.Buffer
+	if err := renderRecords(&tableOut, records, nil, "table"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(tableOut.String(), "TIMESTAMP") || !strings.Contains(tableOut.String(), "ERROR") {
+		t.Errorf("unexpected table output:\n%s", tableOut.String())
+	}
+}
+
+func TestFetchQuery(t *testing.T) {
+	hcpCluster := HCPCluster{managementClusterName: "mc01", hcpNamespace: "ocm-production-abc-test"}
+
+	tests := []struct {
+		name      string
+		opts      queryOptions
+		expected  string
+		expectErr string
+	}{
+		{
+			name: "spans with composition and projection",
+			opts: queryOptions{
+				fetch:       "spans",
+				since:       2,
+				match:       []string{"span.status_code=error"},
+				matchPhrase: []string{"span.name=etcd"},
+				exclude:     []string{"k8s.container.name=istio-proxy"},
+				fields:      []string{"timestamp", "span.name"},
+				limit:       20,
+			},
+			expected: "fetch spans, from:now()-2h \n| filter matchesPhrase(dt.kubernetes.cluster.name, \"mc01\")" +
+				" and (matchesValue(k8s.namespace.name, \"ocm-production-abc-test\"))" +
+				" and (matchesValue(span.status_code, \"error\") or matchesPhrase(span.name, \"etcd\"))" +
+				" and not(matchesValue(k8s.container.name, \"istio-proxy\"))" +
+				"\n| fields timestamp, span.name\n| limit 20",
+		},
+		{
+			name: "metrics split by the filtered fields",
+			opts: queryOptions{
+				fetch:       "metrics",
+				metric:      "dt.kubernetes.container.cpu_usage",
+				aggregation: "max",
+				by:          []string{"k8s.container.name"},
+				since:       6,
+			},
+			expected: "timeseries max(dt.kubernetes.container.cpu_usage), by:{dt.kubernetes.cluster.name, k8s.namespace.name, k8s.container.name}, from:now()-6h \n" +
+				"| filter matchesPhrase(dt.kubernetes.cluster.name, \"mc01\")" +
+				" and (matchesValue(k8s.namespace.name, \"ocm-production-abc-test\"))",
+		},
+		{
+			name:      "metrics without metric",
+			opts:      queryOptions{fetch: "metrics", since: 1},
+			expectErr: "--metric is required",
+		},
+		{
+			name:      "invalid condition",
+			opts:      queryOptions{fetch: "logs", since: 1, exclude: []string{"status"}},
+			expectErr: "invalid --exclude 'status'",
+		},
+		{
+			name:      "invalid fetch",
+			opts:      queryOptions{fetch: "traces", since: 1},
+			expectErr: "invalid fetch 'traces'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.opts.buildQuery(hcpCluster)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected: %s\ngot: %s", tt.expected, got)
+			}
+		})
+	}
+}
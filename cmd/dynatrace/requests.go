@@ -2,11 +2,13 @@ package dynatrace
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"time"
 
 	"github.com/spf13/viper"
@@ -102,19 +104,28 @@ func getVaultPath(vaultPathKey string) (addr, path string, error error) {
 // getScopedAccessToken gets an access token using the vault path in the configuration key specified
 // It will request any scopes listed in the scopes string
 func getScopedAccessToken(configKey string, scopes string) (string, error) {
-	vaultAddr, vaultPath, err := getVaultPath(configKey)
+	token, err := getScopedToken(configKey, scopes)
 	if err != nil {
 		return "", err
 	}
+	return token.value, nil
+}
+
+// getScopedToken is getScopedAccessToken, also returning when the token expires
+func getScopedToken(configKey string, scopes string) (accessToken, error) {
+	vaultAddr, vaultPath, err := getVaultPath(configKey)
+	if err != nil {
+		return accessToken{}, err
+	}
 
 	err = setupVaultToken(vaultAddr)
 	if err != nil {
-		return "", nil
+		return accessToken{}, err
 	}
 
 	clientId, clientSecret, err := getSecretFromVault(vaultAddr, vaultPath)
 	if err != nil {
-		return "", nil
+		return accessToken{}, err
 	}
 
 	reqData := url.Values{
@@ -136,22 +147,36 @@ func getScopedAccessToken(configKey string, scopes string) (string, error) {
 
 	resp, err := requester.send()
 	if err != nil {
-		return "", err
+		return accessToken{}, err
+	}
+
+	token, err := parseAccessToken([]byte(resp), time.Now())
+	if err != nil {
+		return accessToken{}, err
 	}
 
+	fmt.Fprintln(os.Stderr, "Successfully authenticated with DynaTrace")
+
+	return token, nil
+}
+
+// parseAccessToken parses the response of the SSO token endpoint, received at now
+func parseAccessToken(resp []byte, now time.Time) (accessToken, error) {
 	var respObj map[string]interface{}
-	err = json.Unmarshal([]byte(resp), &respObj)
+	err := json.Unmarshal(resp, &respObj)
 	if err != nil {
-		return "", err
+		return accessToken{}, err
 	}
 
-	token, ok := respObj["access_token"].(string)
+	value, ok := respObj["access_token"].(string)
 	if !ok {
-		return "", fmt.Errorf("access token not present in response")
+		return accessToken{}, fmt.Errorf("access token not present in response")
 	}
 
-	fmt.Println("Successfully authenticated with DynaTrace")
-
+	token := accessToken{value: value}
+	if expiresIn, ok := respObj["expires_in"].(float64); ok && expiresIn > 0 {
+		token.expiry = now.Add(time.Duration(expiresIn) * time.Second)
+	}
 	return token, nil
 }
 
@@ -168,20 +193,6 @@ type DTQueryPayload struct {
 	MaxResultRecords int    `json:"maxResultRecords"`
 }
 
-type DTPollResult struct {
-	State string `json:"state"`
-}
-
-type DTLogsPollResult struct {
-	State    string    `json:"state"`
-	Progress int       `json:"progress"`
-	Result   LogResult `json:"result"`
-}
-
-type LogResult struct {
-	Records []LogContent `json:"records"`
-}
-
 type LogContent struct {
 	Content string `json:"content"`
 }
@@ -196,19 +207,6 @@ type DTEventResult struct {
 	Records []json.RawMessage `json:"records"`
 }
 
-type DTExecuteState struct {
-	State      string `json:"state"`
-	TTLSeconds int    `json:"ttlSeconds"`
-}
-
-type DTExecuteToken struct {
-	RequestToken string `json:"requestToken"`
-}
-
-type DTExecuteResults struct {
-	Result []json.RawMessage `json:"records"`
-}
-
 type DTDocumentResult struct {
 	Documents []DTDocument `json:"documents"`
 }
@@ -219,198 +217,59 @@ type DTDocument struct {
 	Type string `json:"type"`
 }
 
-func getDTQueryExecution(dtURL string, accessToken string, query string) (reqToken string, error error) {
-	// Note: Currently we are setting a limit of 20,000 lines to pull from Dynatrace
-	// due to a limitation in dynatrace to pull all logs. This limitation can be revoked
-	// once https://community.dynatrace.com/t5/Product-ideas/Pagination-in-DQL-results/idi-p/248282#M45818
-	// is addressed. Then we can implement https://issues.redhat.com/browse/OSD-24349 to get rid of this limitation.
-	payload := DTQueryPayload{
-		Query:            query,
-		MaxResultRecords: 20000,
+// queryRecords runs the query and calls fn with its records. When pageSize is set the query is run in
+// pages of that size, which requires it to be sorted by ascending timestamp without a limit.
+func queryRecords(ctx context.Context, client Client, query string, pageSize int, fn func(records []json.RawMessage) error) error {
+	if pageSize > 0 {
+		return client.QueryPages(ctx, query, pageSize, fn)
 	}
 
-	payloadJSON, err := json.Marshal(payload)
-	if err != nil {
-		return "", err
-	}
-
-	requester := Requester{
-		method: http.MethodPost,
-		url:    dtURL + "platform/storage/query/v1/query:execute",
-		data:   string(payloadJSON),
-		headers: map[string]string{
-			"Content-Type":  "application/json",
-			"Authorization": "Bearer " + accessToken,
-		},
-		successCode: http.StatusAccepted,
-	}
-
-	var resp string
-	for {
-		resp, err = requester.send()
-		if err != nil {
-			return "", err
-		}
-		var execState DTExecuteState
-		err = json.Unmarshal([]byte(resp), &execState)
-		if err != nil {
-			return "", err
-		}
-
-		if execState.State != "RUNNING" && execState.State != "SUCCEEDED" {
-			return "", fmt.Errorf("query failed")
-		}
-
-		break
-	}
-
-	var state DTExecuteState
-	err = json.Unmarshal([]byte(resp), &state)
-	if err != nil {
-		return "", err
-	}
-
-	if state.State != "RUNNING" && state.State != "SUCCEEDED" {
-		return "", fmt.Errorf("query failed")
-	}
-
-	// acquire the request token for the execution
-	var token DTExecuteToken
-	err = json.Unmarshal([]byte(resp), &token)
-	if err != nil {
-		return "", err
-	}
-
-	return token.RequestToken, err
-}
-
-func getDTPollResults(dtURL string, requestToken string, accessToken string) (respBody string, error error) {
-	var dtPollRes DTLogsPollResult
-	reqData := url.Values{
-		"request-token": {requestToken},
-	}.Encode()
-
-	requester := Requester{
-		method: http.MethodGet,
-		url:    dtURL + "platform/storage/query/v1/query:poll?" + reqData,
-		headers: map[string]string{
-			"Content-Type":  "application/json",
-			"Authorization": "Bearer " + accessToken,
-		},
-		successCode: http.StatusOK,
-	}
-
-	for {
-		resp, err := requester.send()
-		if err != nil {
-			return "", err
-		}
-
-		err = json.Unmarshal([]byte(resp), &dtPollRes)
-		if err != nil {
-			return "", err
-		}
-
-		if dtPollRes.State == "RUNNING" {
-			continue
-		}
-
-		if dtPollRes.State == "SUCCEEDED" {
-			return resp, nil
-		}
-
-		if dtPollRes.State != "RUNNING" && dtPollRes.State != "SUCCEEDED" {
-			return "", fmt.Errorf("query failed")
-		}
-	}
-}
-
-// getDocumentIDByNameAndType searches using the dynatrace document API using a filter that
-// checks for an exact match of both name and type. It will return the id of the document
-// found, unless it find zero or multiple in which case it will return an error
-func getDocumentIDByNameAndType(dtURL string, accessToken string, docName string, docType string) (string, error) {
-	dtDashFilter := "name == '" + docName + "' and type == '" + docType + "'"
-	parameters := url.Values{
-		"filter": {dtDashFilter},
-	}.Encode()
-
-	requester := Requester{
-		method: http.MethodGet,
-		url:    dtURL + "platform/document/v1/documents?" + parameters,
-		headers: map[string]string{
-			"Content-Type":  "application/json",
-			"Authorization": "Bearer " + accessToken,
-		},
-		successCode: http.StatusOK,
-	}
-
-	result, err := requester.send()
-	if err != nil {
-		return "", fmt.Errorf("could not search for dashboard: %w", err)
-	}
-
-	var dtDocResult DTDocumentResult
-	err = json.Unmarshal([]byte(result), &dtDocResult)
-	if err != nil {
-		return "", fmt.Errorf("response in incorrect format")
-	}
-
-	docCount := len(dtDocResult.Documents)
-	if docCount == 0 {
-		return "", fmt.Errorf("dashboard not found")
-	}
-	if docCount > 1 {
-		return "", fmt.Errorf("dashboard name was ambiguous, %d dashboards found", docCount)
-	}
-
-	dtDashboard := dtDocResult.Documents[0]
-
-	return dtDashboard.Id, nil
-}
-
-func getLogs(dtURL string, accessToken string, requestToken string, dumpWriter io.Writer) error {
-	resp, err := getDTPollResults(dtURL, requestToken, accessToken)
+	records, err := client.Query(ctx, query)
 	if err != nil {
 		return err
 	}
 
-	var dtPollRes DTLogsPollResult
-	err = json.Unmarshal([]byte(resp), &dtPollRes)
-	if err != nil {
-		return err
-	}
+	return fn(records)
+}
 
-	for _, result := range dtPollRes.Result.Records {
-		content := result.Content
-		if dumpWriter != nil {
-			dumpWriter.Write([]byte(fmt.Sprintf("%s\n", content)))
-		} else {
-			fmt.Println(content)
+// getLogs writes the content of the log records to dumpWriter, or stdout if nil, and returns the number and time range of the records
+func getLogs(ctx context.Context, client Client, query string, pageSize int, dumpWriter io.Writer) (recordRange, error) {
+	written := recordRange{}
+	err := queryRecords(ctx, client, query, pageSize, func(records []json.RawMessage) error {
+		for _, record := range records {
+			var result LogContent
+			if err := json.Unmarshal(record, &result); err != nil {
+				return err
+			}
+
+			content := result.Content
+			if dumpWriter != nil {
+				dumpWriter.Write([]byte(fmt.Sprintf("%s\n", content)))
+			} else {
+				fmt.Println(content)
+			}
+			written.add(record)
 		}
-	}
+		return nil
+	})
 
-	return nil
+	return written, err
 }
 
-func getEvents(dtURL string, accessToken string, requestToken string, dumpWriter io.Writer) error {
-	resp, err := getDTPollResults(dtURL, requestToken, accessToken)
-	if err != nil {
-		return err
-	}
-
-	var dtPollRes DTEventsPollResult
-	err = json.Unmarshal([]byte(resp), &dtPollRes)
-	if err != nil {
-		return err
-	}
-
-	for _, result := range dtPollRes.Result.Records {
-		if dumpWriter != nil {
-			dumpWriter.Write([]byte(fmt.Sprintf("%s\n", result)))
-		} else {
-			fmt.Println(result)
+// getEvents writes the event records to dumpWriter, or stdout if nil, and returns the number and time range of the records
+func getEvents(ctx context.Context, client Client, query string, pageSize int, dumpWriter io.Writer) (recordRange, error) {
+	written := recordRange{}
+	err := queryRecords(ctx, client, query, pageSize, func(records []json.RawMessage) error {
+		for _, result := range records {
+			if dumpWriter != nil {
+				dumpWriter.Write([]byte(fmt.Sprintf("%s\n", result)))
+			} else {
+				fmt.Println(result)
+			}
+			written.add(result)
 		}
-	}
+		return nil
+	})
 
-	return nil
+	return written, err
 }
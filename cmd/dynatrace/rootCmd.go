@@ -17,6 +17,7 @@ func NewCmdDynatrace() *cobra.Command {
 	dtCmd.AddCommand(newCmdURL())
 	dtCmd.AddCommand(newCmdDashboard())
 	dtCmd.AddCommand(NewCmdHCPMustGather())
+	dtCmd.AddCommand(newCmdQuery())
 
 	return dtCmd
 }
@@ -14,7 +14,7 @@ import (
 	"syscall"
 
 	ocmconfig "github.com/openshift-online/ocm-cli/pkg/config"
-	config "github.com/openshift/osdctl/pkg/envConfig"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
 	"github.com/spf13/cobra"
 )
 
@@ -24,7 +24,8 @@ type Options struct {
 	ResetEnv         bool
 	ExportKubeConfig bool
 
-	Alias string
+	Alias   string
+	Profile string
 
 	// Options for OCM login
 	ClusterId   string
@@ -41,14 +42,14 @@ type OcEnv struct {
 	Path    string
 	Exists  bool
 	Options *Options
-	Config  config.Config
+	Config  osdctlConfig.EnvConfig
+	// Profile is the profile selected with --profile, if any
+	Profile osdctlConfig.EnvProfile
 }
 
-var Config_Filepath = "/.osdctl.yaml"
-
 var commandHelp = `
 Creates an isolated environment where you can interact with a cluster.
-The environment is set up in a dedicated folder in $HOME/.ocenv.
+The environment is set up in a dedicated folder in $HOME/ocenv.
 The $CLUSTERID variable will be populated with the external ID of the cluster you're logged in to.
 
 *PS1*
@@ -68,11 +69,39 @@ When initialized, osdctl env will copy the ocm config you're currently using.
 
 To log in to a cluster within the environment using backplane, osdctl creates the ocb command.
 The ocb command is created in the bin directory in the environment folder and added to the PATH when inside the environment.
+
+*Profiles*
+
+Profiles in ~/.osdctl.yaml set defaults for new environments, selected with --profile:
+
+profiles:
+  stage:
+    ocmEnv: staging
+    backplaneUrl: https://backplane.stage.example.com
+    awsProfile: osd-staging
+    elevationReason: OHSS-1234
+    hooks:
+    - export HISTFILE=$OCENV_PATH/.history
+    fishHooks:
+    - set -gx HISTFILE $OCENV_PATH/.history
+
+With an elevation reason, the oce command runs commands with "ocm backplane elevate".
+Profiles are applied when an environment is created or reset.
+
+*Shell integration*
+
+Run "osdctl env shell-init bash|zsh|fish" for a snippet to add to your shell's rc file, which sets up
+the prompt and runs the profile hooks inside an environment.
+
+Use "osdctl env list" to show existing environments and "osdctl env gc" to clean up stale ones.
 `
 
 func NewCmdEnv() *cobra.Command {
 	options := Options{}
-	config := config.LoadYaml(Config_Filepath)
+	config, err := osdctlConfig.ReadEnvConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	env := OcEnv{
 		Options: &options,
@@ -88,7 +117,7 @@ func NewCmdEnv() *cobra.Command {
 		Run:               env.RunCommand,
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			validEnvs := []string{}
-			files, err := os.ReadDir(os.Getenv("HOME") + "/ocenv/")
+			files, err := os.ReadDir(envsDir())
 			if err != nil {
 				return validEnvs, cobra.ShellCompDirectiveNoFileComp
 			}
@@ -108,12 +137,22 @@ func NewCmdEnv() *cobra.Command {
 
 	envCmd.Flags().StringVarP(&options.ClusterId, "cluster-id", "C", "", "Cluster ID")
 	envCmd.Flags().StringVarP(&options.LoginScript, "login-script", "l", "", "OCM login script to execute in a loop in ocb every 30 seconds")
+	envCmd.Flags().StringVar(&options.Profile, "profile", "", "Profile from ~/.osdctl.yaml to create the environment with")
+	_ = envCmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return env.profileNames(), cobra.ShellCompDirectiveNoFileComp
+	})
 
 	envCmd.Flags().StringVarP(&options.LoginScript, "username", "u", "", "Username for individual cluster login")
 	envCmd.Flags().StringVarP(&options.LoginScript, "password", "p", "", "Password for individual cluster login")
 	envCmd.Flags().StringVarP(&options.LoginScript, "api", "a", "", "OpenShift API URL for individual cluster login")
 	envCmd.Flags().StringVarP(&options.LoginScript, "kubeconfig", "K", "", "KUBECONFIG file to use in this env (will be copied to the environment dir)")
 
+	envCmd.AddCommand(
+		newCmdListEnvs(),
+		newCmdGcEnvs(),
+		newCmdShellInit(),
+	)
+
 	return envCmd
 }
 
@@ -135,7 +174,11 @@ func (e *OcEnv) RunCommand(cmd *cobra.Command, args []string) {
 		e.Options.Alias = e.Options.ClusterId
 	}
 
-	e.Path = os.Getenv("HOME") + "/ocenv/" + e.Options.Alias
+	if err := e.loadProfile(); err != nil {
+		log.Fatal(err)
+	}
+
+	e.Path = filepath.Join(envsDir(), e.Options.Alias)
 	e.Setup()
 
 	if e.Options.DeleteEnv {
@@ -162,10 +205,13 @@ func (e *OcEnv) Setup() {
 		e.createBins()
 		e.ensureEnvVariables()
 		e.createKubeconfig()
+		e.createProfileFiles()
+		e.writeMetadata()
 	}
 }
 
 func (e *OcEnv) PrintKubeConfigExport() {
+	e.touchMetadata()
 	fmt.Printf("export KUBECONFIG=%s\n", e.Path+"/kubeconfig.json")
 }
 
@@ -173,6 +219,7 @@ func (e *OcEnv) Start() {
 	shell := os.Getenv("SHELL")
 
 	fmt.Print("Switching to OpenShift environment " + e.Options.Alias + "\n")
+	e.touchMetadata()
 	// ignore the following line in linter, only way to fix this is via setting a
 	// constant string for the exec.Command
 	cmd := exec.Command(shell) //#nosec G204 -- shell cannot be constant
@@ -280,10 +327,13 @@ KUBECONFIG=` + e.Path + `/kubeconfig.json
 OCM_CONFIG=` + e.Path + `/ocm.json
 PS1=[\u@\h \W $(kube_ps1)]\$ 
 PATH=` + e.Path + `/bin:` + os.Getenv("PATH") + `
+OCENV_ALIAS=` + e.Options.Alias + `
+OCENV_PATH=` + e.Path + `
 `
 	if e.Options.ClusterId != "" {
 		envContent = envContent + "CLUSTERID=" + e.Options.ClusterId + "\n"
 	}
+	envContent += e.profileEnvVariables()
 	direnvfile := e.ensureFile(e.Path + "/.ocenv")
 	_, err := direnvfile.WriteString(envContent)
 	if err != nil {
@@ -384,6 +434,10 @@ func (e *OcEnv) getLoginScript() string {
 		fmt.Printf("Using login script from -l argument: %s\n", e.Options.LoginScript)
 		return e.Options.LoginScript
 	}
+	if e.Profile.LoginScript != "" {
+		fmt.Printf("Using login script from profile %s: %s\n", e.Options.Profile, e.Profile.LoginScript)
+		return e.Profile.LoginScript
+	}
 	cfg, err := ocmconfig.Load()
 	if err != nil || cfg == nil {
 		fmt.Println("Can't read ocm config. Ignoring.")
@@ -447,3 +501,8 @@ func (e *OcEnv) ensureFile(filename string) (file *os.File) {
 func (e *OcEnv) binPath() string {
 	return e.Path + "/bin"
 }
+
+// envsDir returns the directory containing all environments
+func envsDir() string {
+	return filepath.Join(os.Getenv("HOME"), "ocenv")
+}
@@ -0,0 +1,119 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type gcOptions struct {
+	olderThan       time.Duration
+	deletedClusters bool
+	dryRun          bool
+	yes             bool
+}
+
+func newCmdGcEnvs() *cobra.Command {
+	opts := gcOptions{}
+
+	gc := &cobra.Command{
+		Use:   "gc",
+		Short: "Delete stale environments created by osdctl env",
+		Long: `Delete environments created by "osdctl env" which have not been used for --older-than,
+or whose cluster no longer exists in OCM.
+
+The environment of the current shell is never deleted, but environments open in other shells
+can't be detected, so the deletion has to be confirmed unless --yes is set. Clusters are only
+considered gone when they could be looked up in the OCM environment the environment was created for.`,
+		Example: `
+  # Show which environments would be deleted
+  osdctl env gc --dry-run
+
+  # Delete environments unused for a week, keeping the ones for deleted clusters
+  osdctl env gc --older-than 168h --deleted-clusters=false
+
+  # Delete stale environments without confirmation, e.g. from a cron job
+  osdctl env gc --yes`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.olderThan <= 0 {
+				return fmt.Errorf("--older-than must be positive")
+			}
+
+			envs, err := loadEnvs(envsDir())
+			if err != nil {
+				return err
+			}
+
+			var states map[string]string
+			if opts.deletedClusters {
+				states = clusterStates(envs, ocmClusterStates)
+			}
+
+			stale := staleEnvs(envs, states, opts, os.Getenv("OCENV_PATH"), time.Now())
+			if len(stale) == 0 {
+				fmt.Println("No stale environments found")
+				return nil
+			}
+
+			for _, env := range stale {
+				fmt.Printf("Stale environment %s: %s\n", env.Alias, env.reason)
+			}
+			if opts.dryRun {
+				return nil
+			}
+			if !opts.yes {
+				fmt.Println("Environments still open in other shells will stop working.")
+				if !utils.ConfirmPrompt() {
+					return nil
+				}
+			}
+
+			for _, env := range stale {
+				fmt.Printf("Deleting environment %s\n", env.Alias)
+				if err := os.RemoveAll(env.path); err != nil {
+					return fmt.Errorf("failed to delete environment %s: %w", env.Alias, err)
+				}
+			}
+			return nil
+		},
+	}
+
+	gc.Flags().DurationVar(&opts.olderThan, "older-than", 30*24*time.Hour, "Delete environments not used for this long")
+	gc.Flags().BoolVar(&opts.deletedClusters, "deleted-clusters", true, "Delete environments whose cluster no longer exists in OCM")
+	gc.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Only print the environments which would be deleted")
+	gc.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Don't ask for confirmation before deleting the environments")
+	gc.MarkFlagsMutuallyExclusive("dry-run", "yes")
+
+	return gc
+}
+
+type staleEnv struct {
+	*envMetadata
+	reason string
+}
+
+// staleEnvs returns the environments to delete, never including the environment at activePath
+func staleEnvs(envs []*envMetadata, states map[string]string, opts gcOptions, activePath string, now time.Time) []staleEnv {
+	var stale []staleEnv
+	for _, env := range envs {
+		if activePath != "" && filepath.Clean(activePath) == filepath.Clean(env.path) {
+			continue
+		}
+
+		if unused := now.Sub(env.LastUsedAt); unused > opts.olderThan {
+			stale = append(stale, staleEnv{envMetadata: env, reason: fmt.Sprintf("last used %s ago", formatAge(unused))})
+			continue
+		}
+
+		if opts.deletedClusters && states[env.Alias] == clusterStateNotFound {
+			stale = append(stale, staleEnv{envMetadata: env, reason: fmt.Sprintf("cluster %s not found in OCM", env.ClusterID)})
+		}
+	}
+	return stale
+}
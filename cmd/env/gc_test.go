@@ -0,0 +1,49 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaleEnvs(t *testing.T) {
+	now := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	envs := []*envMetadata{
+		{Alias: "recent", ClusterID: "a", LastUsedAt: now.Add(-time.Hour), path: "/home/sre/ocenv/recent"},
+		{Alias: "old", LastUsedAt: now.Add(-40 * 24 * time.Hour), path: "/home/sre/ocenv/old"},
+		{Alias: "deleted", ClusterID: "b", LastUsedAt: now.Add(-time.Hour), path: "/home/sre/ocenv/deleted"},
+		{Alias: "active", LastUsedAt: now.Add(-40 * 24 * time.Hour), path: "/home/sre/ocenv/active"},
+	}
+	states := map[string]string{"recent": "ready", "deleted": clusterStateNotFound}
+
+	tests := []struct {
+		name     string
+		opts     gcOptions
+		expected map[string]string
+	}{
+		{
+			name: "unused_and_deleted_clusters",
+			opts: gcOptions{olderThan: 30 * 24 * time.Hour, deletedClusters: true},
+			expected: map[string]string{
+				"old":     "last used 40d ago",
+				"deleted": "cluster b not found in OCM",
+			},
+		},
+		{
+			name:     "unused_only",
+			opts:     gcOptions{olderThan: 30 * 24 * time.Hour},
+			expected: map[string]string{"old": "last used 40d ago"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stale := map[string]string{}
+			for _, env := range staleEnvs(envs, states, tt.opts, "/home/sre/ocenv/active/", now) {
+				stale[env.Alias] = env.reason
+			}
+			assert.Equal(t, tt.expected, stale)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package env
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	clusterStateNotFound = "not found"
+	clusterStateUnknown  = "unknown"
+)
+
+// clusterStateLookup returns the state of each cluster known to the OCM environment ocmEnv,
+// keyed by every identifier the cluster was requested with
+type clusterStateLookup func(ocmEnv string, clusterIds []string) (map[string]string, error)
+
+func newCmdListEnvs() *cobra.Command {
+	var skipClusterState bool
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List environments created by osdctl env",
+		Long: `List environments created by "osdctl env" with their profile, last used time and the state of their cluster in OCM.
+
+Environments created before osdctl recorded when they were last used show the modification time of their directory instead.
+The cluster state of environments whose OCM environment is unknown is not looked up.`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			envs, err := loadEnvs(envsDir())
+			if err != nil {
+				return err
+			}
+
+			lookup := ocmClusterStates
+			if skipClusterState {
+				lookup = nil
+			}
+
+			printEnvs(os.Stdout, envs, clusterStates(envs, lookup), time.Now())
+			return nil
+		},
+	}
+
+	list.Flags().BoolVar(&skipClusterState, "skip-cluster-state", false, "Do not look up the state of the clusters in OCM")
+
+	return list
+}
+
+// clusterStates returns the cluster state of each environment keyed by its alias, looking up clusters
+// in the OCM environment each environment was created for. Environments without a cluster are left out.
+// Clusters of environments whose OCM environment is unknown are never looked up, as not finding them in
+// another OCM environment doesn't mean they are gone.
+func clusterStates(envs []*envMetadata, lookup clusterStateLookup) map[string]string {
+	states := map[string]string{}
+
+	byOcmEnv := map[string][]string{}
+	for _, env := range envs {
+		if env.ClusterID == "" {
+			continue
+		}
+		states[env.Alias] = clusterStateUnknown
+		if env.OcmEnv != "" {
+			byOcmEnv[env.OcmEnv] = append(byOcmEnv[env.OcmEnv], env.ClusterID)
+		}
+	}
+	if lookup == nil {
+		return states
+	}
+
+	for ocmEnv, clusterIds := range byOcmEnv {
+		found, err := lookup(ocmEnv, clusterIds)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Can't look up clusters in OCM %s: %v\n", ocmEnvName(ocmEnv), err)
+			continue
+		}
+
+		for _, env := range envs {
+			if env.ClusterID == "" || env.OcmEnv != ocmEnv {
+				continue
+			}
+			if state, ok := found[env.ClusterID]; ok {
+				states[env.Alias] = state
+			} else {
+				states[env.Alias] = clusterStateNotFound
+			}
+		}
+	}
+
+	return states
+}
+
+// ocmClusterStates looks up clusters by internal ID, external ID or name in OCM
+func ocmClusterStates(ocmEnv string, clusterIds []string) (map[string]string, error) {
+	conn, err := utils.CreateConnectionWithUrl(ocmEnv)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	queries := make([]string, 0, len(clusterIds))
+	for _, id := range clusterIds {
+		queries = append(queries, utils.GenerateQuery(id))
+	}
+
+	clusters, err := utils.ApplyFilters(conn, []string{strings.Join(queries, " or ")})
+	if err != nil {
+		return nil, err
+	}
+
+	states := map[string]string{}
+	for _, cluster := range clusters {
+		for _, id := range clusterIds {
+			if id == cluster.ID() || id == cluster.ExternalID() || id == cluster.Name() {
+				states[id] = string(cluster.State())
+			}
+		}
+	}
+
+	return states, nil
+}
+
+func ocmEnvName(ocmEnv string) string {
+	if ocmEnv == "" {
+		return clusterStateUnknown
+	}
+	return ocmEnv
+}
+
+func printEnvs(w io.Writer, envs []*envMetadata, states map[string]string, now time.Time) {
+	if len(envs) == 0 {
+		fmt.Fprintf(w, "No environments found in %s\n", envsDir())
+		return
+	}
+
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"ALIAS", "CLUSTER", "PROFILE", "OCM", "LAST USED", "CLUSTER STATE"})
+	for _, env := range envs {
+		state, ok := states[env.Alias]
+		if !ok {
+			state = "-"
+		}
+
+		p.AddRow([]string{
+			env.Alias,
+			valueOrDash(env.ClusterID),
+			valueOrDash(env.Profile),
+			ocmEnvName(env.OcmEnv),
+			formatAge(now.Sub(env.LastUsedAt)) + " ago",
+			state,
+		})
+	}
+	_ = p.Flush()
+}
+
+// formatAge formats a duration with the largest fitting unit, e.g. 3d, 5h or 10m
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
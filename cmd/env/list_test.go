@@ -0,0 +1,62 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterStates(t *testing.T) {
+	envs := []*envMetadata{
+		{Alias: "running", ClusterID: "a", OcmEnv: "production"},
+		{Alias: "deleted", ClusterID: "b", OcmEnv: "production"},
+		{Alias: "stage", ClusterID: "c", OcmEnv: "staging"},
+		{Alias: "legacy", ClusterID: "d"},
+		{Alias: "scratch"},
+	}
+
+	var lookedUp []string
+	lookup := func(ocmEnv string, clusterIds []string) (map[string]string, error) {
+		lookedUp = append(lookedUp, ocmEnv)
+		if ocmEnv == "staging" {
+			return nil, errors.New("not logged in")
+		}
+		return map[string]string{"a": "ready"}, nil
+	}
+
+	assert.Equal(t, map[string]string{
+		"running": "ready",
+		"deleted": clusterStateNotFound,
+		"stage":   clusterStateUnknown,
+		"legacy":  clusterStateUnknown,
+	}, clusterStates(envs, lookup))
+	// Clusters of an unknown OCM environment are never looked up
+	assert.ElementsMatch(t, []string{"production", "staging"}, lookedUp)
+
+	assert.Equal(t, map[string]string{
+		"running": clusterStateUnknown,
+		"deleted": clusterStateUnknown,
+		"stage":   clusterStateUnknown,
+		"legacy":  clusterStateUnknown,
+	}, clusterStates(envs, nil))
+}
+
+func TestPrintEnvs(t *testing.T) {
+	now := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	printEnvs(&buf, []*envMetadata{
+		{Alias: "recent", ClusterID: "a", Profile: "stage", OcmEnv: "staging", LastUsedAt: now.Add(-3 * time.Hour)},
+		{Alias: "scratch", LastUsedAt: now.Add(-50 * 24 * time.Hour)},
+	}, map[string]string{"recent": "ready"}, now)
+
+	out := buf.String()
+	assert.Contains(t, out, "CLUSTER STATE")
+	assert.Contains(t, out, "3h ago")
+	assert.Contains(t, out, "50d ago")
+	assert.Contains(t, out, "ready")
+	assert.Contains(t, out, "unknown")
+}
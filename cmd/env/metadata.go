@@ -0,0 +1,180 @@
+package env
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	ocmconfig "github.com/openshift-online/ocm-cli/pkg/config"
+	"github.com/openshift/osdctl/pkg/utils"
+)
+
+const metadataFile = ".ocenv.json"
+
+// envMetadata describes an environment for "osdctl env list" and "osdctl env gc"
+type envMetadata struct {
+	Alias     string `json:"alias"`
+	ClusterID string `json:"clusterId,omitempty"`
+	Profile   string `json:"profile,omitempty"`
+	// OcmEnv is the OCM environment alias or URL the cluster belongs to, empty when unknown
+	OcmEnv     string    `json:"ocmEnv,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+
+	path string
+}
+
+func (e *OcEnv) writeMetadata() {
+	now := time.Now().UTC()
+	m := &envMetadata{
+		Alias:      e.Options.Alias,
+		ClusterID:  e.Options.ClusterId,
+		Profile:    e.Options.Profile,
+		OcmEnv:     e.ocmEnv(),
+		CreatedAt:  now,
+		LastUsedAt: now,
+		path:       e.Path,
+	}
+	if err := m.save(); err != nil {
+		log.Printf("failed to write environment metadata: %v", err)
+	}
+}
+
+// ocmEnv returns the OCM environment of the profile, or else the one osdctl is currently using,
+// which is also the one the environment's ocm config is copied from
+func (e *OcEnv) ocmEnv() string {
+	if e.Profile.OcmEnv != "" {
+		return e.Profile.OcmEnv
+	}
+	if url := os.Getenv("OCM_URL"); url != "" {
+		return knownOcmEnv(url)
+	}
+	cfg, err := ocmconfig.Load()
+	if err != nil || cfg == nil {
+		return ""
+	}
+	return knownOcmEnv(cfg.URL)
+}
+
+// knownOcmEnv returns ocmEnv if clusters can be looked up in it, otherwise an empty string
+func knownOcmEnv(ocmEnv string) string {
+	if _, err := utils.ValidateAndResolveOcmUrl(ocmEnv); err != nil {
+		return ""
+	}
+	return ocmEnv
+}
+
+// touchMetadata records the environment as used now
+func (e *OcEnv) touchMetadata() {
+	m, err := loadEnvMetadata(e.Path)
+	if err != nil {
+		log.Printf("failed to read environment metadata: %v", err)
+		return
+	}
+
+	m.LastUsedAt = time.Now().UTC()
+	if err := m.save(); err != nil {
+		log.Printf("failed to write environment metadata: %v", err)
+	}
+}
+
+func (m *envMetadata) save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(m.path, metadataFile), data, 0600)
+}
+
+// loadEnvMetadata reads the metadata of the environment at path. Environments created before metadata was
+// recorded fall back to their .ocenv file and the modification time of the directory.
+func loadEnvMetadata(path string) (*envMetadata, error) {
+	data, err := os.ReadFile(filepath.Join(path, metadataFile)) //#nosec G304 -- path is an environment directory
+	if err == nil {
+		m := &envMetadata{}
+		if err := json.Unmarshal(data, m); err != nil {
+			return nil, err
+		}
+		m.path = path
+		return m, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &envMetadata{
+		Alias:      filepath.Base(path),
+		CreatedAt:  info.ModTime().UTC(),
+		LastUsedAt: info.ModTime().UTC(),
+		path:       path,
+	}
+
+	if vars, err := readEnvVariables(filepath.Join(path, ".ocenv")); err == nil {
+		m.ClusterID = vars["CLUSTERID"]
+		m.Profile = vars["OCENV_PROFILE"]
+		m.OcmEnv = vars["OCM_URL"]
+	}
+	if m.OcmEnv == "" {
+		// Without a profile the environment uses the OCM environment it was logged in to
+		if cfg, err := utils.GetOcmConfigFromFilePath(filepath.Join(path, "ocm.json")); err == nil && cfg != nil {
+			m.OcmEnv = knownOcmEnv(cfg.URL)
+		}
+	}
+
+	return m, nil
+}
+
+// readEnvVariables parses the KEY=VALUE lines of an .ocenv file
+func readEnvVariables(path string) (map[string]string, error) {
+	file, err := os.Open(path) //#nosec G304 -- path is an environment file
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "="); ok {
+			vars[key] = value
+		}
+	}
+	return vars, scanner.Err()
+}
+
+// loadEnvs returns the metadata of all environments in dir, sorted by alias
+func loadEnvs(dir string) ([]*envMetadata, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var envs []*envMetadata
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		m, err := loadEnvMetadata(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			log.Printf("skipping environment %s: %v", entry.Name(), err)
+			continue
+		}
+		envs = append(envs, m)
+	}
+
+	return envs, nil
+}
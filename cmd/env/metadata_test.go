@@ -0,0 +1,83 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvMetadata(t *testing.T) {
+	dir := t.TempDir()
+
+	// An environment with metadata
+	current := filepath.Join(dir, "current")
+	require.NoError(t, os.Mkdir(current, 0750))
+	e := &OcEnv{Path: current, Options: &Options{Alias: "current", ClusterId: "abc", Profile: "stage"}}
+	e.Profile.OcmEnv = "staging"
+	e.writeMetadata()
+
+	// An environment created before metadata was recorded
+	legacy := filepath.Join(dir, "legacy")
+	require.NoError(t, os.Mkdir(legacy, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, ".ocenv"), []byte("\nKUBECONFIG=/tmp/kubeconfig.json\nCLUSTERID=def\n"), 0600))
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(legacy, modTime, modTime))
+
+	// A legacy environment without a profile, logged in to an OCM environment
+	loggedIn := filepath.Join(dir, "logged-in")
+	require.NoError(t, os.Mkdir(loggedIn, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(loggedIn, "ocm.json"), []byte(`{"url":"https://api.integration.openshift.com"}`), 0600))
+	require.NoError(t, os.Chtimes(loggedIn, modTime, modTime))
+
+	// Files are not environments
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0600))
+
+	envs, err := loadEnvs(dir)
+	require.NoError(t, err)
+	require.Len(t, envs, 3)
+
+	assert.Equal(t, "current", envs[0].Alias)
+	assert.Equal(t, "abc", envs[0].ClusterID)
+	assert.Equal(t, "stage", envs[0].Profile)
+	assert.Equal(t, "staging", envs[0].OcmEnv)
+	assert.WithinDuration(t, time.Now(), envs[0].LastUsedAt, time.Minute)
+
+	assert.Equal(t, &envMetadata{
+		Alias:      "legacy",
+		ClusterID:  "def",
+		CreatedAt:  modTime,
+		LastUsedAt: modTime,
+		path:       legacy,
+	}, envs[1])
+	assert.Equal(t, "https://api.integration.openshift.com", envs[2].OcmEnv)
+
+	// Using the legacy environment records metadata
+	(&OcEnv{Path: legacy, Options: &Options{}}).touchMetadata()
+	m, err := loadEnvMetadata(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, "def", m.ClusterID)
+	assert.Equal(t, modTime, m.CreatedAt)
+	assert.WithinDuration(t, time.Now(), m.LastUsedAt, time.Minute)
+
+	envs, err = loadEnvs(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, envs)
+}
+
+func TestOcEnvOcmEnv(t *testing.T) {
+	e := &OcEnv{}
+	e.Profile.OcmEnv = "staging"
+	assert.Equal(t, "staging", e.ocmEnv())
+
+	e.Profile.OcmEnv = ""
+	t.Setenv("OCM_URL", "integration")
+	assert.Equal(t, "integration", e.ocmEnv())
+
+	// Clusters can't be looked up in unknown OCM environments
+	t.Setenv("OCM_URL", "https://ocm.example.com")
+	assert.Equal(t, "", e.ocmEnv())
+}
@@ -0,0 +1,94 @@
+package env
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/utils"
+)
+
+const (
+	hooksFile     = "hooks.sh"
+	fishHooksFile = "hooks.fish"
+)
+
+// loadProfile resolves the profile selected with --profile
+func (e *OcEnv) loadProfile() error {
+	if e.Options.Profile == "" {
+		return nil
+	}
+
+	profile, ok := e.Config.Profiles[e.Options.Profile]
+	if !ok {
+		return fmt.Errorf("profile %q not found in ~/%s, available profiles: %s", e.Options.Profile, osdctlConfig.EnvConfigFileName, strings.Join(e.profileNames(), ", "))
+	}
+
+	if profile.OcmEnv != "" {
+		if _, err := utils.ValidateAndResolveOcmUrl(profile.OcmEnv); err != nil {
+			return fmt.Errorf("profile %q: %w", e.Options.Profile, err)
+		}
+	}
+
+	e.Profile = profile
+	return nil
+}
+
+func (e *OcEnv) profileNames() []string {
+	names := make([]string, 0, len(e.Config.Profiles))
+	for name := range e.Config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profileEnvVariables returns the .ocenv lines setting the profile's environment variables
+func (e *OcEnv) profileEnvVariables() string {
+	vars := ""
+	if e.Options.Profile != "" {
+		vars += "OCENV_PROFILE=" + e.Options.Profile + "\n"
+	}
+	if e.Profile.OcmEnv != "" {
+		vars += "OCM_URL=" + e.Profile.OcmEnv + "\n"
+	}
+	if e.Profile.BackplaneURL != "" {
+		vars += "BACKPLANE_URL=" + e.Profile.BackplaneURL + "\n"
+	}
+	if e.Profile.AWSProfile != "" {
+		vars += "AWS_PROFILE=" + e.Profile.AWSProfile + "\n"
+	}
+	return vars
+}
+
+// createProfileFiles creates the oce command and the hook files sourced by the shell integration
+func (e *OcEnv) createProfileFiles() {
+	if e.Profile.ElevationReason != "" {
+		e.createBin("oce", `#!/bin/bash
+exec ocm backplane elevate `+shellQuote(e.Profile.ElevationReason)+` -- "$@"
+`)
+	}
+
+	e.writeHooks(hooksFile, e.Profile.Hooks)
+	e.writeHooks(fishHooksFile, e.Profile.FishHooks)
+}
+
+func (e *OcEnv) writeHooks(name string, hooks []string) {
+	if len(hooks) == 0 {
+		return
+	}
+
+	path := filepath.Join(e.Path, name)
+	if err := os.WriteFile(path, []byte(strings.Join(hooks, "\n")+"\n"), 0600); err != nil {
+		log.Fatalf("Can't write hooks to %s: %v", path, err)
+	}
+}
+
+// shellQuote quotes s as a single argument for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,96 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProfile(t *testing.T) {
+	cfg := osdctlConfig.EnvConfig{Profiles: map[string]osdctlConfig.EnvProfile{
+		"stage":   {OcmEnv: "staging", AWSProfile: "osd-staging"},
+		"invalid": {OcmEnv: "moon"},
+	}}
+
+	tests := []struct {
+		name     string
+		profile  string
+		expected osdctlConfig.EnvProfile
+		errorMsg string
+	}{
+		{
+			name: "no_profile",
+		},
+		{
+			name:     "valid_profile",
+			profile:  "stage",
+			expected: osdctlConfig.EnvProfile{OcmEnv: "staging", AWSProfile: "osd-staging"},
+		},
+		{
+			name:     "missing_profile",
+			profile:  "prod",
+			errorMsg: `profile "prod" not found in ~/.osdctl.yaml, available profiles: invalid, stage`,
+		},
+		{
+			name:     "invalid_ocm_env",
+			profile:  "invalid",
+			errorMsg: "invalid OCM_URL found: moon",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &OcEnv{Options: &Options{Profile: tt.profile}, Config: cfg}
+			err := e.loadProfile()
+			if tt.errorMsg != "" {
+				assert.ErrorContains(t, err, tt.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, e.Profile)
+		})
+	}
+}
+
+func TestProfileEnvVariables(t *testing.T) {
+	e := &OcEnv{
+		Options: &Options{Profile: "stage"},
+		Profile: osdctlConfig.EnvProfile{
+			OcmEnv:       "staging",
+			BackplaneURL: "https://backplane.example.com",
+			AWSProfile:   "osd-staging",
+		},
+	}
+
+	assert.Equal(t, "OCENV_PROFILE=stage\nOCM_URL=staging\nBACKPLANE_URL=https://backplane.example.com\nAWS_PROFILE=osd-staging\n", e.profileEnvVariables())
+	assert.Equal(t, "", (&OcEnv{Options: &Options{}}).profileEnvVariables())
+}
+
+func TestCreateProfileFiles(t *testing.T) {
+	e := &OcEnv{
+		Path:    t.TempDir(),
+		Options: &Options{},
+		Profile: osdctlConfig.EnvProfile{
+			ElevationReason: "OHSS-1234 it's broken",
+			Hooks:           []string{"export FOO=bar", "alias k=oc"},
+		},
+	}
+	require.NoError(t, os.Mkdir(e.binPath(), 0750))
+
+	e.createProfileFiles()
+
+	oce, err := os.ReadFile(filepath.Join(e.binPath(), "oce"))
+	require.NoError(t, err)
+	assert.Contains(t, string(oce), `exec ocm backplane elevate 'OHSS-1234 it'\''s broken' -- "$@"`)
+
+	hooks, err := os.ReadFile(filepath.Join(e.Path, hooksFile))
+	require.NoError(t, err)
+	assert.Equal(t, "export FOO=bar\nalias k=oc\n", string(hooks))
+
+	_, err = os.Stat(filepath.Join(e.Path, fishHooksFile))
+	assert.True(t, os.IsNotExist(err))
+}
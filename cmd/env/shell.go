@@ -0,0 +1,93 @@
+package env
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var shellSnippets = map[string]string{
+	"bash": `# osdctl env integration, add to ~/.bashrc:
+#   eval "$(osdctl env shell-init bash)"
+if [ -n "${OCENV_PATH:-}" ]; then
+  source "${OCENV_PATH}/bin/kube-ps1.sh"
+  if [ -f "${OCENV_PATH}/hooks.sh" ]; then
+    source "${OCENV_PATH}/hooks.sh"
+  fi
+  PS1="[\u@\h \W \$(kube_ps1)]\\$ "
+fi
+`,
+	"zsh": `# osdctl env integration, add to ~/.zshrc:
+#   eval "$(osdctl env shell-init zsh)"
+if [[ -n "${OCENV_PATH:-}" ]]; then
+  source "${OCENV_PATH}/bin/kube-ps1.sh"
+  if [[ -f "${OCENV_PATH}/hooks.sh" ]]; then
+    source "${OCENV_PATH}/hooks.sh"
+  fi
+  setopt PROMPT_SUBST
+  PROMPT='$(kube_ps1) '"${PROMPT}"
+fi
+`,
+	"fish": `# osdctl env integration, add to ~/.config/fish/config.fish:
+#   osdctl env shell-init fish | source
+if set -q OCENV_PATH
+    if test -f "$OCENV_PATH/hooks.fish"
+        source "$OCENV_PATH/hooks.fish"
+    end
+    if functions -q fish_prompt; and not functions -q __osdctl_env_fish_prompt
+        functions -c fish_prompt __osdctl_env_fish_prompt
+    end
+    function fish_prompt
+        set -l namespace (oc project -q 2>/dev/null)
+        if test -n "$namespace"
+            printf '(%s:%s) ' $OCENV_ALIAS $namespace
+        else
+            printf '(%s) ' $OCENV_ALIAS
+        end
+        if functions -q __osdctl_env_fish_prompt
+            __osdctl_env_fish_prompt
+        else
+            printf '> '
+        end
+    end
+end
+`,
+}
+
+func newCmdShellInit() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell-init bash|zsh|fish",
+		Short: "Print the shell integration for environments created by osdctl env",
+		Long: `Print a snippet to add to your shell's rc file. Inside an environment, it adds the cluster to the
+prompt and runs the hooks of the environment's profile. Outside of environments it does nothing.`,
+		Example: `
+  # bash
+  echo 'eval "$(osdctl env shell-init bash)"' >> ~/.bashrc
+
+  # zsh
+  echo 'eval "$(osdctl env shell-init zsh)"' >> ~/.zshrc
+
+  # fish
+  echo 'osdctl env shell-init fish | source' >> ~/.config/fish/config.fish`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgs:         []string{"bash", "zsh", "fish"},
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			snippet, err := shellSnippet(args[0])
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprint(os.Stdout, snippet)
+			return err
+		},
+	}
+}
+
+func shellSnippet(shell string) (string, error) {
+	snippet, ok := shellSnippets[shell]
+	if !ok {
+		return "", fmt.Errorf("unsupported shell %q, supported shells are bash, zsh and fish", shell)
+	}
+	return snippet, nil
+}
@@ -0,0 +1,18 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShellSnippet(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		snippet, err := shellSnippet(shell)
+		assert.NoError(t, err)
+		assert.Contains(t, snippet, "OCENV_PATH")
+	}
+
+	_, err := shellSnippet("tcsh")
+	assert.EqualError(t, err, `unsupported shell "tcsh", supported shells are bash, zsh and fish`)
+}
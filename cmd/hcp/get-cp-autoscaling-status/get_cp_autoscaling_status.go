@@ -8,6 +8,9 @@ import (
 	"os"
 	"regexp"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	sdk "github.com/openshift-online/ocm-sdk-go"
@@ -16,43 +19,60 @@ import (
 	"github.com/openshift/osdctl/pkg/printer"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
+	// Not part of the vendored hypershift API yet
 	annotationResourceBasedAutoscaling = "hypershift.openshift.io/resource-based-cp-auto-scaling"
-	annotationClusterSizeOverride      = "hypershift.openshift.io/cluster-size-override"
 	annotationRecommendedClusterSize   = "hypershift.openshift.io/recommended-cluster-size"
 
-	labelHostedClusterSize = "hypershift.openshift.io/hosted-cluster-size"
-	labelClusterID         = "api.openshift.com/id"
+	labelClusterID = "api.openshift.com/id"
+
+	fleetManagementClusterName = "all"
+	notAvailable               = "N/A"
 )
 
 type options struct {
-	mgmtClusterID string
-	output        string
-	showOnly      string
-	noHeaders     bool
+	mgmtClusterID     string
+	allMgmtClusters   bool
+	concurrency       int
+	staleOverrideDays int
+	output            string
+	showOnly          string
+	noHeaders         bool
 }
 
 type clusterInfo struct {
-	ClusterID             string `json:"cluster_id" yaml:"cluster_id"`
-	ClusterName           string `json:"cluster_name" yaml:"cluster_name"`
-	Namespace             string `json:"namespace" yaml:"namespace"`
-	AutoscalingEnabled    bool   `json:"autoscaling_enabled" yaml:"autoscaling_enabled"`
-	HasOverrideAnnotation bool   `json:"has_override" yaml:"has_override"`
-	CurrentSize           string `json:"current_size" yaml:"current_size"`
-	RecommendedSize       string `json:"recommended_size" yaml:"recommended_size"`
+	ClusterID             string     `json:"cluster_id" yaml:"cluster_id"`
+	ClusterName           string     `json:"cluster_name" yaml:"cluster_name"`
+	Namespace             string     `json:"namespace" yaml:"namespace"`
+	ManagementCluster     string     `json:"management_cluster,omitempty" yaml:"management_cluster,omitempty"`
+	AutoscalingEnabled    bool       `json:"autoscaling_enabled" yaml:"autoscaling_enabled"`
+	HasOverrideAnnotation bool       `json:"has_override" yaml:"has_override"`
+	OverrideSize          string     `json:"override_size,omitempty" yaml:"override_size,omitempty"`
+	OverrideAppliedAt     *time.Time `json:"override_applied_at,omitempty" yaml:"override_applied_at,omitempty"`
+	CurrentSize           string     `json:"current_size" yaml:"current_size"`
+	RecommendedSize       string     `json:"recommended_size" yaml:"recommended_size"`
+	DesiredSize           string     `json:"desired_size" yaml:"desired_size"`
+	NodeSize              string     `json:"node_size" yaml:"node_size"`
+	Drift                 bool       `json:"drift" yaml:"drift"`
+	StaleOverride         bool       `json:"stale_override" yaml:"stale_override"`
+	Oversized             bool       `json:"oversized" yaml:"oversized"`
 }
 
 type auditResults struct {
-	Timestamp         time.Time     `json:"timestamp" yaml:"timestamp"`
-	ManagementCluster string        `json:"management_cluster" yaml:"management_cluster"`
-	TotalClusters     int           `json:"total_clusters" yaml:"total_clusters"`
-	Clusters          []clusterInfo `json:"clusters" yaml:"clusters"`
+	Timestamp                time.Time     `json:"timestamp" yaml:"timestamp"`
+	ManagementCluster        string        `json:"management_cluster" yaml:"management_cluster"`
+	TotalClusters            int           `json:"total_clusters" yaml:"total_clusters"`
+	Clusters                 []clusterInfo `json:"clusters" yaml:"clusters"`
+	FailedManagementClusters []string      `json:"failed_management_clusters,omitempty" yaml:"failed_management_clusters,omitempty"`
 }
 
 func newCmdAutoscalingAudit() *cobra.Command {
@@ -60,11 +80,22 @@ func newCmdAutoscalingAudit() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "get-cp-autoscaling-status",
-		Short: "Get control plane autoscaling status for hosted clusters on a management cluster",
-		Long: `Query a single HCP management cluster to retrieve autoscaling status for all hosted clusters.
+		Short: "Get control plane autoscaling status for hosted clusters on one or all management clusters",
+		Long: `Query HCP management clusters to retrieve autoscaling status for all hosted clusters.
 
 This command is useful for checking the autoscaling configuration status of hosted clusters
-on a specific management cluster during day-to-day operations.`,
+on a specific management cluster during day-to-day operations. With --all, every management
+cluster known to OSD Fleet Manager is audited concurrently.
+
+For each hosted cluster the desired size (the cluster-size-override annotation, or the
+hosted-cluster-size label when no override is set) is compared with the size of the
+request-serving nodes the control plane is actually running on. The audit reports:
+  - drift: the request-serving nodes do not match the desired size
+  - stale overrides: a cluster-size-override applied more than --stale-override-days ago
+  - oversized clusters: an override pinning the cluster above its recommended size
+
+Use --output remediation to print the 'osdctl cluster resize request-serving-nodes'
+commands that clean up stale and oversized overrides.`,
 		Example: `
   # Get autoscaling status for all hosted clusters on a management cluster
   osdctl hcp get-cp-autoscaling-status --mgmt-cluster-id <cluster-id>
@@ -79,7 +110,13 @@ on a specific management cluster during day-to-day operations.`,
   osdctl hcp get-cp-autoscaling-status --mgmt-cluster-id <cluster-id> --show-only needs-removal
 
   # Show only clusters safe to remove override
-  osdctl hcp get-cp-autoscaling-status --mgmt-cluster-id <cluster-id> --show-only safe-to-remove-override`,
+  osdctl hcp get-cp-autoscaling-status --mgmt-cluster-id <cluster-id> --show-only safe-to-remove-override
+
+  # Audit every management cluster and show clusters whose nodes drifted from the desired size
+  osdctl hcp get-cp-autoscaling-status --all --show-only drift
+
+  # Generate resize commands for overrides older than 14 days or above the recommended size
+  osdctl hcp get-cp-autoscaling-status --all --stale-override-days 14 --output remediation`,
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -88,40 +125,60 @@ on a specific management cluster during day-to-day operations.`,
 	}
 
 	cmd.Flags().StringVar(&opts.mgmtClusterID, "mgmt-cluster-id", "",
-		"Management cluster ID or name (required)")
+		"Management cluster ID or name")
+	cmd.Flags().BoolVar(&opts.allMgmtClusters, "all", false,
+		"Audit all management clusters returned by OSD Fleet Manager")
+	cmd.Flags().IntVar(&opts.concurrency, "concurrency", 5,
+		"Number of management clusters to audit in parallel when using --all")
+	cmd.Flags().IntVar(&opts.staleOverrideDays, "stale-override-days", 7,
+		"Report cluster-size-override annotations applied more than this many days ago as stale")
 	cmd.Flags().StringVar(&opts.output, "output", "text",
-		"Output format: text, json, yaml, csv")
+		"Output format: text, json, yaml, csv, remediation")
 	cmd.Flags().StringVar(&opts.showOnly, "show-only", "",
-		"Filter output: needs-removal, ready-for-migration, safe-to-remove-override")
+		"Filter output: needs-removal, ready-for-migration, safe-to-remove-override, drift, stale-override, oversized")
 	cmd.Flags().BoolVar(&opts.noHeaders, "no-headers", false,
 		"Skip table headers in output")
 
-	if err := cmd.MarkFlagRequired("mgmt-cluster-id"); err != nil {
-		panic(fmt.Sprintf("failed to mark flag as required: %v", err))
-	}
+	cmd.MarkFlagsOneRequired("mgmt-cluster-id", "all")
+	cmd.MarkFlagsMutuallyExclusive("mgmt-cluster-id", "all")
 
 	return cmd
 }
 
 func (o *options) run(ctx context.Context) error {
-	validOutputs := map[string]bool{"text": true, "json": true, "yaml": true, "csv": true}
+	validOutputs := map[string]bool{"text": true, "json": true, "yaml": true, "csv": true, "remediation": true}
 	if !validOutputs[o.output] {
-		return fmt.Errorf("invalid output format '%s'. Valid options: text, json, yaml, csv", o.output)
+		return fmt.Errorf("invalid output format '%s'. Valid options: text, json, yaml, csv, remediation", o.output)
 	}
 
 	if o.showOnly != "" {
-		validFilters := map[string]bool{"needs-removal": true, "ready-for-migration": true, "safe-to-remove-override": true}
+		validFilters := map[string]bool{
+			"needs-removal":           true,
+			"ready-for-migration":     true,
+			"safe-to-remove-override": true,
+			"drift":                   true,
+			"stale-override":          true,
+			"oversized":               true,
+		}
 		if !validFilters[o.showOnly] {
-			return fmt.Errorf("invalid show-only filter '%s'. Valid options: needs-removal, ready-for-migration, safe-to-remove-override", o.showOnly)
+			return fmt.Errorf("invalid show-only filter '%s'. Valid options: needs-removal, ready-for-migration, safe-to-remove-override, drift, stale-override, oversized", o.showOnly)
 		}
 	}
 
+	if o.concurrency < 1 {
+		return fmt.Errorf("invalid concurrency %d: must be at least 1", o.concurrency)
+	}
+
 	connection, err := utils.CreateConnection()
 	if err != nil {
 		return fmt.Errorf("failed to create OCM connection: %v", err)
 	}
 	defer connection.Close()
 
+	if o.allMgmtClusters {
+		return o.auditFleet(ctx, connection)
+	}
+
 	return o.auditManagementCluster(ctx, connection)
 }
 
@@ -143,20 +200,79 @@ func (o *options) auditManagementCluster(ctx context.Context, conn *sdk.Connecti
 		return fmt.Errorf("cluster %s is not a management cluster", cluster.ID())
 	}
 
-	resolvedMgmtClusterID := cluster.ID()
-	resolvedMgmtClusterName := cluster.Name()
+	results, err := o.auditMC(ctx, conn, cluster.ID(), cluster.Name())
+	if err != nil {
+		return err
+	}
+
+	if o.showOnly != "" {
+		results = o.applyFilter(results)
+	}
+
+	return o.outputResults(results)
+}
+
+// auditFleet audits every management cluster known to OSD Fleet Manager, at most
+// o.concurrency at a time, and merges the results into a single report.
+// Management clusters that cannot be audited are recorded rather than aborting the run.
+func (o *options) auditFleet(ctx context.Context, conn *sdk.Connection) error {
+	mcResp, err := conn.OSDFleetMgmt().V1().ManagementClusters().List().Send()
+	if err != nil {
+		return fmt.Errorf("failed to list management clusters: %v", err)
+	}
+
+	results := &auditResults{
+		Timestamp:         time.Now(),
+		ManagementCluster: fleetManagementClusterName,
+		Clusters:          []clusterInfo{},
+	}
+
+	var mutex sync.Mutex
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(o.concurrency)
+
+	for _, mc := range mcResp.Items().Slice() {
+		mcID := mc.ClusterManagementReference().ClusterId()
+		mcName := mc.Name()
+		eg.Go(func() error {
+			mcResults, err := o.auditMC(egCtx, conn, mcID, mcName)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to audit management cluster %s: %v\n", mcName, err)
+				results.FailedManagementClusters = append(results.FailedManagementClusters, mcName)
+				return nil
+			}
+			results.Clusters = append(results.Clusters, mcResults.Clusters...)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	sort.Strings(results.FailedManagementClusters)
+	results.TotalClusters = len(results.Clusters)
+
+	if o.showOnly != "" {
+		results = o.applyFilter(results)
+	}
+
+	return o.outputResults(results)
+}
 
+// auditMC audits all hosted clusters on a single management cluster
+func (o *options) auditMC(ctx context.Context, conn *sdk.Connection, mgmtClusterID, mgmtClusterName string) (*auditResults, error) {
 	scheme := runtime.NewScheme()
 	if err := hypershiftv1beta1.AddToScheme(scheme); err != nil {
-		return fmt.Errorf("failed to add hypershift scheme: %v", err)
+		return nil, fmt.Errorf("failed to add hypershift scheme: %v", err)
 	}
 	if err := corev1.AddToScheme(scheme); err != nil {
-		return fmt.Errorf("failed to add core v1 scheme: %v", err)
+		return nil, fmt.Errorf("failed to add core v1 scheme: %v", err)
 	}
 
-	mgmtClient, err := k8s.NewWithConn(resolvedMgmtClusterID, client.Options{Scheme: scheme}, conn)
+	mgmtClient, err := k8s.NewWithConn(mgmtClusterID, client.Options{Scheme: scheme}, conn)
 	if err != nil {
-		return fmt.Errorf("failed to create management cluster client: %v", err)
+		return nil, fmt.Errorf("failed to create management cluster client: %v", err)
 	}
 
 	var namespaces []corev1.Namespace
@@ -170,35 +286,50 @@ func (o *options) auditManagementCluster(ctx context.Context, conn *sdk.Connecti
 		}
 
 		if attempt == maxRetries {
-			return fmt.Errorf("failed to list namespaces after %d attempts (cluster may be unreachable): %v", maxRetries, err)
+			return nil, fmt.Errorf("failed to list namespaces after %d attempts (cluster may be unreachable): %v", maxRetries, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryDelay):
 		}
+	}
 
-		time.Sleep(retryDelay)
+	nodeSizes, err := listRequestServingNodeSizes(ctx, mgmtClient)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to list request-serving nodes on %s: %v\n", mgmtClusterName, err)
+	}
+
+	sizeOrder, err := getSizeOrder(ctx, mgmtClient)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to get cluster sizing configuration on %s, oversized clusters will not be reported: %v\n", mgmtClusterName, err)
 	}
 
 	results := &auditResults{
 		Timestamp:         time.Now(),
-		ManagementCluster: resolvedMgmtClusterName,
+		ManagementCluster: mgmtClusterName,
 		Clusters:          []clusterInfo{},
 	}
 
+	staleAfter := time.Duration(o.staleOverrideDays) * 24 * time.Hour
+
 	for _, ns := range namespaces {
-		info, err := auditNamespace(ctx, mgmtClient, ns.Name)
+		info, hc, err := auditNamespace(ctx, mgmtClient, ns.Name)
 		if err != nil {
-			fmt.Printf("Warning: failed to audit namespace %s: %v\n", ns.Name, err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to audit namespace %s: %v\n", ns.Name, err)
 			continue
 		}
 
+		info.ManagementCluster = mgmtClusterName
+		evaluateSizing(info, nodeSizes[fmt.Sprintf("%s-%s", hc.Namespace, hc.Name)], sizeOrder, staleAfter, results.Timestamp)
+
 		results.Clusters = append(results.Clusters, *info)
 	}
 
 	results.TotalClusters = len(results.Clusters)
 
-	if o.showOnly != "" {
-		results = o.applyFilter(results)
-	}
-
-	return o.outputResults(results)
+	return results, nil
 }
 
 func listOcmNamespaces(ctx context.Context, kubeClient client.Client) ([]corev1.Namespace, error) {
@@ -219,14 +350,14 @@ func listOcmNamespaces(ctx context.Context, kubeClient client.Client) ([]corev1.
 	return filtered, nil
 }
 
-func auditNamespace(ctx context.Context, kubeClient client.Client, namespace string) (*clusterInfo, error) {
+func auditNamespace(ctx context.Context, kubeClient client.Client, namespace string) (*clusterInfo, *hypershiftv1beta1.HostedCluster, error) {
 	hc, err := getHostedClusterInNamespace(ctx, kubeClient, namespace)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	clusterID := hc.Labels[labelClusterID]
-	currentSize := hc.Labels[labelHostedClusterSize]
+	currentSize := hc.Labels[hypershiftv1beta1.HostedClusterSizeLabel]
 	if currentSize == "" {
 		currentSize = "N/A"
 	}
@@ -234,7 +365,7 @@ func auditNamespace(ctx context.Context, kubeClient client.Client, namespace str
 	autoScaling, hasAutoScaling := hc.Annotations[annotationResourceBasedAutoscaling]
 	autoscalingEnabled := hasAutoScaling && autoScaling == "true"
 
-	_, hasOverride := hc.Annotations[annotationClusterSizeOverride]
+	overrideSize, hasOverride := hc.Annotations[hypershiftv1beta1.ClusterSizeOverrideAnnotation]
 
 	recommendedSize := hc.Annotations[annotationRecommendedClusterSize]
 	if recommendedSize == "" {
@@ -247,9 +378,125 @@ func auditNamespace(ctx context.Context, kubeClient client.Client, namespace str
 		Namespace:             namespace,
 		AutoscalingEnabled:    autoscalingEnabled,
 		HasOverrideAnnotation: hasOverride,
+		OverrideSize:          overrideSize,
+		OverrideAppliedAt:     overrideAppliedAt(hc),
 		CurrentSize:           currentSize,
 		RecommendedSize:       recommendedSize,
-	}, nil
+	}, hc, nil
+}
+
+// overrideAppliedAt returns the last time the cluster-size-override annotation was written,
+// based on the HostedCluster's managed fields. Returns nil if it cannot be determined.
+func overrideAppliedAt(hc *hypershiftv1beta1.HostedCluster) *time.Time {
+	if _, ok := hc.Annotations[hypershiftv1beta1.ClusterSizeOverrideAnnotation]; !ok {
+		return nil
+	}
+
+	var latest *time.Time
+	fieldKey := fmt.Sprintf("%q", "f:"+hypershiftv1beta1.ClusterSizeOverrideAnnotation)
+	for _, mf := range hc.ManagedFields {
+		if mf.Time == nil || mf.FieldsV1 == nil {
+			continue
+		}
+		if !strings.Contains(string(mf.FieldsV1.Raw), fieldKey) {
+			continue
+		}
+		t := mf.Time.Time
+		if latest == nil || t.After(*latest) {
+			latest = &t
+		}
+	}
+
+	return latest
+}
+
+// listRequestServingNodeSizes maps each hosted control plane namespace to the size of
+// the dedicated request-serving nodes it is scheduled on
+func listRequestServingNodeSizes(ctx context.Context, kubeClient client.Client) (map[string]string, error) {
+	nodeList := &corev1.NodeList{}
+	if err := kubeClient.List(ctx, nodeList, client.MatchingLabels{hypershiftv1beta1.RequestServingComponentLabel: "true"}); err != nil {
+		return nil, err
+	}
+
+	sizes := map[string]string{}
+	for _, node := range nodeList.Items {
+		hcpNamespace := node.Labels[hypershiftv1beta1.HostedClusterLabel]
+		size := node.Labels[hypershiftv1beta1.NodeSizeLabel]
+		if hcpNamespace == "" || size == "" {
+			continue
+		}
+		// Nodes of different sizes only coexist for a cluster while it is being resized,
+		// which is reported as drift regardless of which size is recorded here
+		if existing, ok := sizes[hcpNamespace]; ok && existing != size {
+			continue
+		}
+		sizes[hcpNamespace] = size
+	}
+
+	return sizes, nil
+}
+
+// getSizeOrder returns the position of each size in the management cluster's
+// ClusterSizingConfiguration, smallest first
+func getSizeOrder(ctx context.Context, kubeClient client.Client) (map[string]int, error) {
+	clusterSizingConfig := &unstructured.Unstructured{}
+	clusterSizingConfig.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "scheduling.hypershift.openshift.io",
+		Version: "v1alpha1",
+		Kind:    "ClusterSizingConfiguration",
+	})
+
+	if err := kubeClient.Get(ctx, client.ObjectKey{Name: "cluster"}, clusterSizingConfig); err != nil {
+		return nil, err
+	}
+
+	sizesRaw, found, err := unstructured.NestedSlice(clusterSizingConfig.Object, "spec", "sizes")
+	if err != nil || !found {
+		return nil, fmt.Errorf("failed to get sizes from cluster sizing configuration: %v", err)
+	}
+
+	order := map[string]int{}
+	for i, sizeRaw := range sizesRaw {
+		sizeMap, ok := sizeRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(sizeMap, "name")
+		if name != "" {
+			order[name] = i
+		}
+	}
+
+	return order, nil
+}
+
+// evaluateSizing compares the desired size of a hosted cluster with the size of its
+// request-serving nodes and flags drift, stale overrides and oversized overrides
+func evaluateSizing(info *clusterInfo, nodeSize string, sizeOrder map[string]int, staleAfter time.Duration, now time.Time) {
+	info.DesiredSize = info.CurrentSize
+	if info.HasOverrideAnnotation && info.OverrideSize != "" {
+		info.DesiredSize = info.OverrideSize
+	}
+
+	info.NodeSize = nodeSize
+	if info.NodeSize == "" {
+		info.NodeSize = notAvailable
+	}
+
+	info.Drift = info.DesiredSize != notAvailable &&
+		info.NodeSize != notAvailable &&
+		info.DesiredSize != info.NodeSize
+
+	info.StaleOverride = info.HasOverrideAnnotation &&
+		info.OverrideAppliedAt != nil &&
+		now.Sub(*info.OverrideAppliedAt) > staleAfter
+
+	overrideIdx, overrideKnown := sizeOrder[info.OverrideSize]
+	recommendedIdx, recommendedKnown := sizeOrder[info.RecommendedSize]
+	info.Oversized = info.HasOverrideAnnotation &&
+		overrideKnown &&
+		recommendedKnown &&
+		overrideIdx > recommendedIdx
 }
 
 func getHostedClusterInNamespace(ctx context.Context, kubeClient client.Client, namespace string) (*hypershiftv1beta1.HostedCluster, error) {
@@ -298,9 +545,22 @@ func (o *options) applyFilter(results *auditResults) *auditResults {
 			if isSafeToRemoveOverride {
 				filtered.Clusters = append(filtered.Clusters, cluster)
 			}
+		case "drift":
+			if cluster.Drift {
+				filtered.Clusters = append(filtered.Clusters, cluster)
+			}
+		case "stale-override":
+			if cluster.StaleOverride {
+				filtered.Clusters = append(filtered.Clusters, cluster)
+			}
+		case "oversized":
+			if cluster.Oversized {
+				filtered.Clusters = append(filtered.Clusters, cluster)
+			}
 		}
 	}
 
+	filtered.FailedManagementClusters = results.FailedManagementClusters
 	filtered.TotalClusters = len(filtered.Clusters)
 	return filtered
 }
@@ -315,6 +575,8 @@ func (o *options) outputResults(results *auditResults) error {
 		return o.printYAML(results)
 	case "csv":
 		return o.printCSV(results)
+	case "remediation":
+		return o.printRemediation(results)
 	default:
 		return fmt.Errorf("unsupported output format: %s", o.output)
 	}
@@ -323,28 +585,34 @@ func (o *options) outputResults(results *auditResults) error {
 func (o *options) printTable(results *auditResults) error {
 	fmt.Printf("\n=== Management Cluster: %s ===\n", results.ManagementCluster)
 	fmt.Printf("Timestamp: %s\n", results.Timestamp.Format(time.RFC3339))
-	fmt.Printf("Total Hosted Clusters: %d\n\n", results.TotalClusters)
+	fmt.Printf("Total Hosted Clusters: %d\n", results.TotalClusters)
+	if len(results.FailedManagementClusters) > 0 {
+		fmt.Printf("Failed Management Clusters: %s\n", strings.Join(results.FailedManagementClusters, ", "))
+	}
+	fmt.Println()
 
 	if len(results.Clusters) == 0 {
 		fmt.Println("No hosted clusters found")
 		return nil
 	}
 
-	sort.Slice(results.Clusters, func(i, j int) bool {
-		return results.Clusters[i].ClusterName < results.Clusters[j].ClusterName
-	})
+	sortClusters(results.Clusters)
 
 	p := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
 
 	if !o.noHeaders {
 		p.AddRow([]string{
+			"MGMT CLUSTER",
 			"CLUSTER ID",
 			"CLUSTER NAME",
 			"NAMESPACE",
 			"AUTOSCALING",
 			"HAS OVERRIDE",
+			"OVERRIDE AGE",
 			"CURRENT SIZE",
 			"RECOMMENDED SIZE",
+			"NODE SIZE",
+			"FINDINGS",
 		})
 	}
 
@@ -360,13 +628,17 @@ func (o *options) printTable(results *auditResults) error {
 		}
 
 		p.AddRow([]string{
+			c.ManagementCluster,
 			c.ClusterID,
 			c.ClusterName,
 			c.Namespace,
 			autoscalingStr,
 			overrideStr,
+			formatOverrideAge(c.OverrideAppliedAt, results.Timestamp),
 			c.CurrentSize,
 			c.RecommendedSize,
+			c.NodeSize,
+			strings.Join(findings(c), ","),
 		})
 	}
 
@@ -404,6 +676,14 @@ func (o *options) printCSV(results *auditResults) error {
 			"has_override",
 			"current_size",
 			"recommended_size",
+			"management_cluster",
+			"override_size",
+			"override_applied_at",
+			"desired_size",
+			"node_size",
+			"drift",
+			"stale_override",
+			"oversized",
 		}); err != nil {
 			return fmt.Errorf("failed to write CSV header: %v", err)
 		}
@@ -420,6 +700,11 @@ func (o *options) printCSV(results *auditResults) error {
 			overrideStr = "true"
 		}
 
+		overrideAppliedAt := ""
+		if c.OverrideAppliedAt != nil {
+			overrideAppliedAt = c.OverrideAppliedAt.Format(time.RFC3339)
+		}
+
 		if err := w.Write([]string{
 			c.ClusterID,
 			c.ClusterName,
@@ -428,6 +713,14 @@ func (o *options) printCSV(results *auditResults) error {
 			overrideStr,
 			c.CurrentSize,
 			c.RecommendedSize,
+			c.ManagementCluster,
+			c.OverrideSize,
+			overrideAppliedAt,
+			c.DesiredSize,
+			c.NodeSize,
+			strconv.FormatBool(c.Drift),
+			strconv.FormatBool(c.StaleOverride),
+			strconv.FormatBool(c.Oversized),
 		}); err != nil {
 			return fmt.Errorf("failed to write CSV row: %v", err)
 		}
@@ -435,3 +728,75 @@ func (o *options) printCSV(results *auditResults) error {
 
 	return nil
 }
+
+// printRemediation prints the resize commands that clean up stale and oversized overrides.
+// Overrides on clusters with autoscaling enabled are removed so the recommended size applies again,
+// otherwise the cluster is resized down to its recommended size.
+func (o *options) printRemediation(results *auditResults) error {
+	sortClusters(results.Clusters)
+
+	fmt.Println("#!/usr/bin/env bash")
+	fmt.Printf("# Generated by osdctl hcp get-cp-autoscaling-status at %s\n", results.Timestamp.Format(time.RFC3339))
+	fmt.Println("# Set REASON to the OHSS or PD ticket justifying the change before running")
+	fmt.Println(`REASON="${REASON:?REASON must be set}"`)
+
+	count := 0
+	for _, c := range results.Clusters {
+		cmd := remediationCommand(c)
+		if cmd == "" {
+			continue
+		}
+		fmt.Printf("\n# %s (%s) on %s: %s\n", c.ClusterName, c.ClusterID, c.ManagementCluster, strings.Join(findings(c), ", "))
+		fmt.Println(cmd)
+		count++
+	}
+
+	if count == 0 {
+		fmt.Println("\n# No stale or oversized overrides found")
+	}
+
+	return nil
+}
+
+func remediationCommand(c clusterInfo) string {
+	if !c.StaleOverride && !c.Oversized {
+		return ""
+	}
+
+	if c.AutoscalingEnabled || c.RecommendedSize == notAvailable || c.RecommendedSize == c.CurrentSize {
+		return fmt.Sprintf(`osdctl cluster resize request-serving-nodes --cluster-id %s --remove-override --reason "${REASON}"`, c.ClusterID)
+	}
+
+	return fmt.Sprintf(`osdctl cluster resize request-serving-nodes --cluster-id %s --size %s --reason "${REASON}"`, c.ClusterID, c.RecommendedSize)
+}
+
+func findings(c clusterInfo) []string {
+	var f []string
+	if c.Drift {
+		f = append(f, "drift")
+	}
+	if c.StaleOverride {
+		f = append(f, "stale-override")
+	}
+	if c.Oversized {
+		f = append(f, "oversized")
+	}
+	return f
+}
+
+func formatOverrideAge(appliedAt *time.Time, now time.Time) string {
+	if appliedAt == nil {
+		return notAvailable
+	}
+	days := int(now.Sub(*appliedAt).Hours() / 24)
+	return fmt.Sprintf("%dd", days)
+}
+
+func sortClusters(clusters []clusterInfo) {
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].ManagementCluster != clusters[j].ManagementCluster {
+			return clusters[i].ManagementCluster < clusters[j].ManagementCluster
+		}
+		return clusters[i].ClusterName < clusters[j].ClusterName
+	})
+}
@@ -3,6 +3,9 @@ package getcpautoscalingstatus
 import (
 	"testing"
 	"time"
+
+	hypershiftv1beta1 "github.com/openshift/hypershift/api/hypershift/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestApplyFilter(t *testing.T) {
@@ -363,21 +366,223 @@ func TestValidateShowOnly(t *testing.T) {
 }
 
 func TestRequiredFlag(t *testing.T) {
-	cmd := newCmdAutoscalingAudit()
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{
+			name:    "neither mgmt-cluster-id nor all",
+			args:    []string{},
+			wantErr: true,
+		},
+		{
+			name:    "both mgmt-cluster-id and all",
+			args:    []string{"--mgmt-cluster-id", "mc-1", "--all"},
+			wantErr: true,
+		},
+	}
 
-	mgmtClusterFlag := cmd.Flags().Lookup("mgmt-cluster-id")
-	if mgmtClusterFlag == nil {
-		t.Fatal("mgmt-cluster-id flag not found")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newCmdAutoscalingAudit()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("failed to parse flags: %v", err)
+			}
+
+			err := cmd.ValidateFlagGroups()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFlagGroups() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
 	}
+}
+
+func TestEvaluateSizing(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-30 * 24 * time.Hour)
+	recent := now.Add(-1 * time.Hour)
+	sizeOrder := map[string]int{"small": 0, "medium": 1, "large": 2}
 
-	annotations := mgmtClusterFlag.Annotations
-	if annotations == nil {
-		t.Fatal("mgmt-cluster-id flag should be required")
+	tests := []struct {
+		name          string
+		cluster       clusterInfo
+		nodeSize      string
+		wantDesired   string
+		wantNodeSize  string
+		wantDrift     bool
+		wantStale     bool
+		wantOversized bool
+	}{
+		{
+			name: "no override, nodes match label",
+			cluster: clusterInfo{
+				CurrentSize:     "medium",
+				RecommendedSize: "medium",
+			},
+			nodeSize:     "medium",
+			wantDesired:  "medium",
+			wantNodeSize: "medium",
+		},
+		{
+			name: "override not yet applied to nodes",
+			cluster: clusterInfo{
+				HasOverrideAnnotation: true,
+				OverrideSize:          "large",
+				OverrideAppliedAt:     &recent,
+				CurrentSize:           "medium",
+				RecommendedSize:       "medium",
+			},
+			nodeSize:      "medium",
+			wantDesired:   "large",
+			wantNodeSize:  "medium",
+			wantDrift:     true,
+			wantOversized: true,
+		},
+		{
+			name: "old override above recommended size",
+			cluster: clusterInfo{
+				HasOverrideAnnotation: true,
+				OverrideSize:          "large",
+				OverrideAppliedAt:     &old,
+				CurrentSize:           "large",
+				RecommendedSize:       "small",
+			},
+			nodeSize:      "large",
+			wantDesired:   "large",
+			wantNodeSize:  "large",
+			wantStale:     true,
+			wantOversized: true,
+		},
+		{
+			name: "old override at recommended size",
+			cluster: clusterInfo{
+				HasOverrideAnnotation: true,
+				OverrideSize:          "medium",
+				OverrideAppliedAt:     &old,
+				CurrentSize:           "medium",
+				RecommendedSize:       "medium",
+			},
+			nodeSize:     "medium",
+			wantDesired:  "medium",
+			wantNodeSize: "medium",
+			wantStale:    true,
+		},
+		{
+			name: "no request-serving nodes found",
+			cluster: clusterInfo{
+				CurrentSize:     "small",
+				RecommendedSize: "N/A",
+			},
+			nodeSize:     "",
+			wantDesired:  "small",
+			wantNodeSize: "N/A",
+		},
+		{
+			name: "unknown size is never oversized",
+			cluster: clusterInfo{
+				HasOverrideAnnotation: true,
+				OverrideSize:          "xlarge",
+				CurrentSize:           "xlarge",
+				RecommendedSize:       "small",
+			},
+			nodeSize:     "xlarge",
+			wantDesired:  "xlarge",
+			wantNodeSize: "xlarge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.cluster
+			evaluateSizing(&c, tt.nodeSize, sizeOrder, 7*24*time.Hour, now)
+
+			if c.DesiredSize != tt.wantDesired {
+				t.Errorf("DesiredSize = %s, want %s", c.DesiredSize, tt.wantDesired)
+			}
+			if c.NodeSize != tt.wantNodeSize {
+				t.Errorf("NodeSize = %s, want %s", c.NodeSize, tt.wantNodeSize)
+			}
+			if c.Drift != tt.wantDrift {
+				t.Errorf("Drift = %v, want %v", c.Drift, tt.wantDrift)
+			}
+			if c.StaleOverride != tt.wantStale {
+				t.Errorf("StaleOverride = %v, want %v", c.StaleOverride, tt.wantStale)
+			}
+			if c.Oversized != tt.wantOversized {
+				t.Errorf("Oversized = %v, want %v", c.Oversized, tt.wantOversized)
+			}
+		})
 	}
+}
 
-	requiredAnnotation := annotations["cobra_annotation_bash_completion_one_required_flag"]
-	if len(requiredAnnotation) == 0 || requiredAnnotation[0] != "true" {
-		t.Error("mgmt-cluster-id flag should be marked as required")
+func TestOverrideAppliedAt(t *testing.T) {
+	applied := metav1.NewTime(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	other := metav1.NewTime(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	hc := &hypershiftv1beta1.HostedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{hypershiftv1beta1.ClusterSizeOverrideAnnotation: "large"},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:  "kubectl",
+					Time:     &applied,
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:hypershift.openshift.io/cluster-size-override":{}}}}`)},
+				},
+				{
+					Manager:  "hypershift-operator",
+					Time:     &other,
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{}}`)},
+				},
+			},
+		},
+	}
+
+	got := overrideAppliedAt(hc)
+	if got == nil || !got.Equal(applied.Time) {
+		t.Errorf("overrideAppliedAt() = %v, want %v", got, applied.Time)
+	}
+
+	delete(hc.Annotations, hypershiftv1beta1.ClusterSizeOverrideAnnotation)
+	if got := overrideAppliedAt(hc); got != nil {
+		t.Errorf("overrideAppliedAt() without override = %v, want nil", got)
+	}
+}
+
+func TestRemediationCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster clusterInfo
+		want    string
+	}{
+		{
+			name:    "no findings",
+			cluster: clusterInfo{ClusterID: "c1", Drift: true},
+			want:    "",
+		},
+		{
+			name:    "stale override with autoscaling",
+			cluster: clusterInfo{ClusterID: "c1", AutoscalingEnabled: true, StaleOverride: true, CurrentSize: "large", RecommendedSize: "small"},
+			want:    `osdctl cluster resize request-serving-nodes --cluster-id c1 --remove-override --reason "${REASON}"`,
+		},
+		{
+			name:    "oversized without autoscaling",
+			cluster: clusterInfo{ClusterID: "c1", Oversized: true, CurrentSize: "large", RecommendedSize: "small"},
+			want:    `osdctl cluster resize request-serving-nodes --cluster-id c1 --size small --reason "${REASON}"`,
+		},
+		{
+			name:    "stale override already at recommended size",
+			cluster: clusterInfo{ClusterID: "c1", StaleOverride: true, CurrentSize: "small", RecommendedSize: "small"},
+			want:    `osdctl cluster resize request-serving-nodes --cluster-id c1 --remove-override --reason "${REASON}"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := remediationCommand(tt.cluster); got != tt.want {
+				t.Errorf("remediationCommand() = %q, want %q", got, tt.want)
+			}
+		})
 	}
 }
 
@@ -16,6 +16,7 @@ var Cmd = &cobra.Command{
 func init() {
 	Cmd.AddCommand(quickTaskCmd)
 	Cmd.AddCommand(createHandoverAnnouncmentCmd)
+	Cmd.AddCommand(newCmdOHSS())
 
 	createHandoverAnnouncmentCmd.Flags().String("summary", "", "Enter Summary/Title for the Announcment")
 	createHandoverAnnouncmentCmd.Flags().String("description", "", "Enter Description for the Announcment")
@@ -23,8 +24,9 @@ func init() {
 	createHandoverAnnouncmentCmd.Flags().String("customer", "", "Customer name")
 	createHandoverAnnouncmentCmd.Flags().String("cluster", "", "Cluster ID")
 	createHandoverAnnouncmentCmd.Flags().String("version", "", "Affected Openshift Version (e.g 4.16 or 4.15.32)")
+	createHandoverAnnouncmentCmd.Flags().Bool("non-interactive", false, "Fail on missing values instead of prompting for them")
 
-	flags := []string{"summary", "description", "products", "customer", "cluster", "version"}
+	flags := []string{"summary", "description", "products", "customer", "cluster", "version", "non-interactive"}
 	for _, flag := range flags {
 		if err := viper.BindPFlag(flag, createHandoverAnnouncmentCmd.Flags().Lookup(flag)); err != nil {
 			log.Printf("Failed to bind flag '%s': %v", flag, err)
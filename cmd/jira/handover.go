@@ -36,12 +36,24 @@ Select the appropriate product type:
 	- Choose Multiple if it affects the fleet
 	- Otherwise, select the specific product involved
 
-5. Description - Add a brief description of the announcement.`
+5. Description - Add a brief description of the announcement.
+
+When a cluster ID is given, the customer name, version and product are looked up in OCM unless they are set with flags.
+With --non-interactive, missing values are an error instead of being prompted for, so announcements can be created from scripts.`
+
+const handoverExample = `  # Create an announcement for a cluster, looking up its customer, version and product in OCM
+  osdctl jira create-handover-announcement --non-interactive --cluster ${CLUSTER_ID} \
+    --summary "Ingress degraded after upgrade" --description "The customer is aware and a fix is being rolled out"
+
+  # Create a fleet-wide announcement
+  osdctl jira create-handover-announcement --non-interactive --cluster All --customer N/A --version 4.16 \
+    --products "OpenShift Dedicated,Red Hat Openshift on AWS" --summary "..." --description "..."`
 
 var createHandoverAnnouncmentCmd = &cobra.Command{
-	Use:   "create-handover-announcement",
-	Short: "Create a new Handover announcement for SREPHOA Project",
-	Long:  longDescription,
+	Use:     "create-handover-announcement",
+	Short:   "Create a new Handover announcement for SREPHOA Project",
+	Long:    longDescription,
+	Example: handoverExample,
 	Run: func(cmd *cobra.Command, args []string) {
 		CreateHandoverAnnouncment()
 	},
@@ -61,6 +73,11 @@ func CreateHandoverAnnouncment() {
 		log.Fatalf("Failed to create Jira client: %v", err)
 	}
 
+	clusterID := promptInput("cluster", "Enter Cluster ID:")
+	if err := fillFromCluster(clusterID); err != nil {
+		log.Fatalf("Failed to look up cluster %s: %v", clusterID, err)
+	}
+
 	summary := promptInput("summary", "Enter Summary/Title for the Announcment:")
 	description := promptInput("description", "Enter Description for the Announcment:")
 	products, err := getProducts()
@@ -68,7 +85,6 @@ func CreateHandoverAnnouncment() {
 		log.Fatalf("Product validation failed: %v", err)
 	}
 	customer := promptInput("customer", "Enter Customer Name:")
-	clusterID := promptInput("cluster", "Enter Cluster ID:")
 	version := promptInput("version", "Enter Affects Version (e.g. 4.16 or 4.15.32):")
 
 	affectsVersion, err := createVersionIfNotExists(jiraClient, version)
@@ -108,6 +124,9 @@ func getProducts() ([]string, error) {
 	var selected []string
 
 	if productInput == "" {
+		if viper.GetBool("non-interactive") {
+			return nil, fmt.Errorf("--products is required with --non-interactive (one of: %v)", allowedProducts)
+		}
 		fmt.Println("Available products:")
 		for _, p := range allowedProducts {
 			fmt.Printf("  - %s\n", p)
@@ -184,6 +203,9 @@ func promptInput(flagName, promptMsg string) string {
 	if val != "" {
 		return val
 	}
+	if viper.GetBool("non-interactive") {
+		log.Fatalf("--%s is required with --non-interactive", flagName)
+	}
 
 	prompt := promptui.Prompt{
 		Label: promptMsg,
@@ -202,3 +224,91 @@ func promptInput(flagName, promptMsg string) string {
 
 	return strings.TrimSpace(result)
 }
+
+// handoverCluster holds the announcement fields that can be derived from a cluster
+type handoverCluster struct {
+	Customer string
+	Version  string
+	Product  string
+}
+
+// getHandoverCluster looks up a cluster in OCM, overridden in tests
+var getHandoverCluster = func(clusterID string) (*handoverCluster, error) {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetCluster(ocmClient, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	subscription, err := utils.GetSubFromClusterID(ocmClient, *cluster)
+	if err != nil {
+		return nil, err
+	}
+	organization, err := utils.GetOrganization(ocmClient, subscription.OrganizationID())
+	if err != nil {
+		return nil, err
+	}
+
+	return &handoverCluster{
+		Customer: organization.Name(),
+		Version:  cluster.Version().RawID(),
+		Product:  clusterProduct(cluster.Product().ID(), cluster.CloudProvider().ID(), cluster.Hypershift().Enabled()),
+	}, nil
+}
+
+// clusterProduct returns the announcement product of a cluster, or "" when it isn't one of allowedProducts
+func clusterProduct(productID string, cloudProvider string, hostedControlPlane bool) string {
+	switch {
+	case productID == "rosa" && hostedControlPlane:
+		return "Red Hat Openshift on AWS with Hosted Control Planes"
+	case productID == "rosa":
+		return "Red Hat Openshift on AWS"
+	case productID == "osd" && cloudProvider == "aws":
+		return "OpenShift Dedicated on AWS"
+	case productID == "osd" && cloudProvider == "gcp":
+		return "OpenShift Dedicated on GCP"
+	case productID == "osd":
+		return "OpenShift Dedicated"
+	}
+	return ""
+}
+
+// isFleetWide tells whether the cluster id of an announcement doesn't name a single cluster
+func isFleetWide(clusterID string) bool {
+	return containsIgnoreCase([]string{"None", "N/A", "All"}, strings.TrimSpace(clusterID))
+}
+
+// fillFromCluster sets the customer, version and products that weren't given from the cluster in OCM
+func fillFromCluster(clusterID string) error {
+	fields := []string{"customer", "version", "products"}
+	missing := false
+	for _, field := range fields {
+		if viper.GetString(field) == "" {
+			missing = true
+		}
+	}
+	if !missing || isFleetWide(clusterID) {
+		return nil
+	}
+
+	cluster, err := getHandoverCluster(clusterID)
+	if err != nil {
+		return err
+	}
+	values := map[string]string{
+		"customer": cluster.Customer,
+		"version":  cluster.Version,
+		"products": cluster.Product,
+	}
+	for _, field := range fields {
+		if viper.GetString(field) == "" && values[field] != "" {
+			viper.Set(field, values[field])
+			fmt.Printf("Using %s %q of cluster %s\n", field, values[field], clusterID)
+		}
+	}
+	return nil
+}
@@ -65,3 +65,55 @@ func TestMapProducts_EmptyInput(t *testing.T) {
 func TestContainsIgnoreCase_EmptyList(t *testing.T) {
 	assert.False(t, containsIgnoreCase([]string{}, "Anything"))
 }
+
+func TestClusterProduct(t *testing.T) {
+	assert.Equal(t, "Red Hat Openshift on AWS with Hosted Control Planes", clusterProduct("rosa", "aws", true))
+	assert.Equal(t, "Red Hat Openshift on AWS", clusterProduct("rosa", "aws", false))
+	assert.Equal(t, "OpenShift Dedicated on AWS", clusterProduct("osd", "aws", false))
+	assert.Equal(t, "OpenShift Dedicated on GCP", clusterProduct("osd", "gcp", false))
+	assert.Equal(t, "", clusterProduct("ocp", "aws", false))
+	for _, product := range []string{"rosa", "osd"} {
+		assert.True(t, containsIgnoreCase(allowedProducts, clusterProduct(product, "aws", false)))
+	}
+}
+
+func TestFillFromCluster(t *testing.T) {
+	original := getHandoverCluster
+	defer func() { getHandoverCluster = original }()
+	defer func() {
+		viper.Set("customer", "")
+		viper.Set("version", "")
+		viper.Set("products", "")
+	}()
+
+	lookups := 0
+	getHandoverCluster = func(clusterID string) (*handoverCluster, error) {
+		lookups++
+		return &handoverCluster{Customer: "ACME Corp", Version: "4.16.5", Product: "Red Hat Openshift on AWS"}, nil
+	}
+
+	// Fleet-wide announcements aren't looked up
+	assert.NoError(t, fillFromCluster("All"))
+	assert.Equal(t, 0, lookups)
+
+	// Flags win over the cluster
+	viper.Set("version", "4.16")
+	assert.NoError(t, fillFromCluster("abc"))
+	assert.Equal(t, 1, lookups)
+	assert.Equal(t, "ACME Corp", viper.GetString("customer"))
+	assert.Equal(t, "4.16", viper.GetString("version"))
+	products, err := getProducts()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Red Hat Openshift on AWS"}, products)
+
+	// Nothing left to look up
+	assert.NoError(t, fillFromCluster("abc"))
+	assert.Equal(t, 1, lookups)
+}
+
+func TestGetProducts_NonInteractive(t *testing.T) {
+	viper.Set("non-interactive", true)
+	defer viper.Set("non-interactive", false)
+	_, err := getProducts()
+	assert.ErrorContains(t, err, "--products is required with --non-interactive")
+}
@@ -0,0 +1,478 @@
+package jira
+
+//go:generate mockgen -source=ohss.go -package=mock -destination=mock/ohss.go
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/kballard/go-shellquote"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultLinkType = "Relates"
+
+	// maxCommentLength is the maximum length of a Jira comment
+	maxCommentLength = 32767
+
+	// maxAttachmentSize caps the size of an attached file or archived directory
+	maxAttachmentSize = 100 << 20
+)
+
+var attachmentNameRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ClusterResolver looks up the ids of a cluster, which its OHSS issues reference
+type ClusterResolver interface {
+	ClusterIDs(clusterID string) (internalID string, externalID string, err error)
+}
+
+// ohssOptions selects the issue an action applies to, either by key or by cluster
+type ohssOptions struct {
+	clusterID string
+
+	// jiraClient is created on the first use
+	jiraClient utils.JiraClientInterface
+	clusters   ClusterResolver
+	osdctl     utils.OsdctlRunner
+	out        io.Writer
+}
+
+func newCmdOHSS() *cobra.Command {
+	return ohssCmd(&ohssOptions{
+		clusters: ocmClusterResolver{},
+		osdctl:   utils.NewOsdctlRunner(),
+		out:      os.Stdout,
+	})
+}
+
+func ohssCmd(ops *ohssOptions) *cobra.Command {
+	ohssCmd := &cobra.Command{
+		Use:   "ohss",
+		Short: "Comment on, transition, link and attach files to OHSS issues",
+		Long: `Comment on, transition, link and attach files to OHSS issues.
+
+The issue is given by its key, or with --cluster-id by the cluster, in which case the cluster must have exactly one open OHSS issue.
+The output of osdctl commands can be added to comments and attachments with --attach. ` + utils.ClusterIDPlaceholder + ` in those
+commands is replaced by the id of the cluster given with --cluster-id.`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+	ohssCmd.PersistentFlags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Act on the open OHSS issue of this cluster instead of the given issue key")
+
+	ohssCmd.AddCommand(newCmdOHSSComment(ops))
+	ohssCmd.AddCommand(newCmdOHSSTransition(ops))
+	ohssCmd.AddCommand(newCmdOHSSLink(ops))
+	ohssCmd.AddCommand(newCmdOHSSAttach(ops))
+
+	return ohssCmd
+}
+
+func newCmdOHSSComment(ops *ohssOptions) *cobra.Command {
+	var message, file string
+	var outputs []string
+	commentCmd := &cobra.Command{
+		Use:   "comment [issue-key]",
+		Short: "Comment on an issue",
+		Example: `  # Comment on an issue
+  osdctl jira ohss comment OHSS-12345 -m "The customer confirmed the fix"
+
+  # Add the context of the cluster to its open OHSS issue
+  osdctl jira ohss comment --cluster-id ${CLUSTER_ID} -m "Cluster context" --attach "cluster context -C {cluster_id}"`,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if message == "" && file == "" && len(outputs) == 0 {
+				return errors.New("the comment is empty, set --message, --file or --attach")
+			}
+			client, key, _, err := ops.issue(args, 0)
+			if err != nil {
+				return err
+			}
+
+			var body strings.Builder
+			body.WriteString(message)
+			if file != "" {
+				content, err := readFileOrStdin(file, cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				appendParagraph(&body, strings.TrimSpace(string(content)))
+			}
+			for _, output := range outputs {
+				args, result, err := ops.run(cmd.Context(), output)
+				if err != nil {
+					return err
+				}
+				// The output is truncated to what fits in the comment, keeping the block closed
+				header := fmt.Sprintf("*osdctl %s*\n{noformat}\n", shellquote.Join(args...))
+				const footer = "\n{noformat}"
+				room := maxCommentLength - body.Len() - len("\n\n") - len(header) - len(footer)
+				appendParagraph(&body, header+truncateOutput(strings.TrimSpace(result), room)+footer)
+			}
+			if body.Len() > maxCommentLength {
+				return fmt.Errorf("the comment is longer than the %d characters Jira accepts", maxCommentLength)
+			}
+
+			if _, err := client.AddComment(key, body.String()); err != nil {
+				return fmt.Errorf("failed to comment on %s: %w", key, err)
+			}
+			fmt.Fprintf(ops.out, "Commented on %s/browse/%s\n", utils.JiraBaseURL, key)
+			return nil
+		},
+	}
+	commentCmd.Flags().StringVarP(&message, "message", "m", "", "Text of the comment")
+	commentCmd.Flags().StringVarP(&file, "file", "f", "", "File to read the comment from, - reads stdin")
+	commentCmd.Flags().StringArrayVar(&outputs, "attach", nil, "osdctl command whose output is added to the comment, truncated to fit the comment. Can be repeated")
+	return commentCmd
+}
+
+func newCmdOHSSTransition(ops *ohssOptions) *cobra.Command {
+	var message string
+	transitionCmd := &cobra.Command{
+		Use:   "transition [issue-key] <status>",
+		Short: "Move an issue to another status",
+		Long:  "Move an issue to another status. The status is matched against the name of the transition or of its target status, ignoring case.",
+		Example: `  # Move an issue to "Waiting on Customer" with a comment
+  osdctl jira ohss transition OHSS-12345 "Waiting on Customer" -m "Asked the customer to restore the IAM role"`,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, key, rest, err := ops.issue(args, 1)
+			if err != nil {
+				return err
+			}
+			status := rest[0]
+
+			transitions, err := client.GetTransitions(key)
+			if err != nil {
+				return fmt.Errorf("failed to get the transitions of %s: %w", key, err)
+			}
+			transition, err := findTransition(transitions, status)
+			if err != nil {
+				return fmt.Errorf("can't move %s: %w", key, err)
+			}
+			if err := client.DoTransition(key, transition.ID); err != nil {
+				return fmt.Errorf("failed to move %s to %s: %w", key, transition.To.Name, err)
+			}
+			fmt.Fprintf(ops.out, "Moved %s to %s\n", key, transition.To.Name)
+
+			if message != "" {
+				if _, err := client.AddComment(key, message); err != nil {
+					return fmt.Errorf("failed to comment on %s: %w", key, err)
+				}
+			}
+			return nil
+		},
+	}
+	transitionCmd.Flags().StringVarP(&message, "message", "m", "", "Comment to add after the transition")
+	return transitionCmd
+}
+
+func newCmdOHSSLink(ops *ohssOptions) *cobra.Command {
+	var linkType string
+	linkCmd := &cobra.Command{
+		Use:   "link [issue-key] <other-issue-key>",
+		Short: "Link an issue to another issue",
+		Example: `  # Link the open OHSS issue of a cluster to an OCPBUGS issue
+  osdctl jira ohss link --cluster-id ${CLUSTER_ID} OCPBUGS-12345
+
+  # Mark an issue as a duplicate of another
+  osdctl jira ohss link OHSS-12345 OHSS-12300 --type Duplicate`,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, key, rest, err := ops.issue(args, 1)
+			if err != nil {
+				return err
+			}
+			other := rest[0]
+
+			link := &jira.IssueLink{
+				Type:         jira.IssueLinkType{Name: linkType},
+				InwardIssue:  &jira.Issue{Key: key},
+				OutwardIssue: &jira.Issue{Key: other},
+			}
+			if err := client.AddLink(link); err != nil {
+				return fmt.Errorf("failed to link %s to %s: %w", key, other, err)
+			}
+			fmt.Fprintf(ops.out, "Linked %s to %s (%s)\n", key, other, linkType)
+			return nil
+		},
+	}
+	linkCmd.Flags().StringVar(&linkType, "type", defaultLinkType, "Name of the link type, like Relates, Blocks, Duplicate or Cloners")
+	return linkCmd
+}
+
+func newCmdOHSSAttach(ops *ohssOptions) *cobra.Command {
+	var outputs []string
+	attachCmd := &cobra.Command{
+		Use:   "attach [issue-key] [file...]",
+		Short: "Attach files and osdctl command outputs to an issue",
+		Long: `Attach files and osdctl command outputs to an issue.
+
+Directories, like must-gather outputs, are attached as a .tar.gz archive. Files and archives larger than 100MiB are
+rejected. The output of every --attach command is attached as a text file named after the command.`,
+		Example: `  # Attach a must-gather and the cloudtrail summary of a cluster to its open OHSS issue
+  osdctl jira ohss attach --cluster-id ${CLUSTER_ID} ./must-gather.local.123 --attach "cloudtrail write-events -C {cluster_id}"`,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, key, files, err := ops.issue(args, 0)
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 && len(outputs) == 0 {
+				return errors.New("nothing to attach, pass files or --attach")
+			}
+
+			for _, path := range files {
+				name, content, err := openAttachment(path)
+				if err != nil {
+					return err
+				}
+				err = client.AddAttachment(key, name, content)
+				content.Close()
+				if err != nil {
+					return fmt.Errorf("failed to attach %s to %s: %w", name, key, err)
+				}
+				fmt.Fprintf(ops.out, "Attached %s to %s\n", name, key)
+			}
+
+			for _, output := range outputs {
+				args, result, err := ops.run(cmd.Context(), output)
+				if err != nil {
+					return err
+				}
+				name := outputAttachmentName(args)
+				if err := client.AddAttachment(key, name, strings.NewReader(result)); err != nil {
+					return fmt.Errorf("failed to attach %s to %s: %w", name, key, err)
+				}
+				fmt.Fprintf(ops.out, "Attached %s to %s\n", name, key)
+			}
+			return nil
+		},
+	}
+	attachCmd.Flags().StringArrayVar(&outputs, "attach", nil, "osdctl command whose output is attached. Can be repeated")
+	return attachCmd
+}
+
+// issue returns the issue key and the remaining arguments, which must be at least minArgs. The key is
+// the first argument, or the open OHSS issue of the cluster given with --cluster-id.
+func (o *ohssOptions) issue(args []string, minArgs int) (utils.JiraClientInterface, string, []string, error) {
+	if o.clusterID == "" {
+		if len(args) < minArgs+1 {
+			return nil, "", nil, errors.New("pass an issue key or --cluster-id")
+		}
+	} else if len(args) < minArgs {
+		return nil, "", nil, fmt.Errorf("expected %d argument(s) after the issue", minArgs)
+	}
+
+	if o.jiraClient == nil {
+		client, err := utils.NewJiraClient("")
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("failed to create Jira client: %w", err)
+		}
+		o.jiraClient = client
+	}
+	client := o.jiraClient
+	if o.clusterID == "" {
+		return client, args[0], args[1:], nil
+	}
+
+	internalID, externalID, err := o.clusters.ClusterIDs(o.clusterID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	issues, err := utils.GetJiraIssuesForClusterWithClient(client, internalID, externalID)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to search the OHSS issues of cluster %s: %w", o.clusterID, err)
+	}
+
+	var open []string
+	for _, issue := range issues {
+		if issue.Fields == nil || issue.Fields.Status == nil || issue.Fields.Status.StatusCategory.Key != jira.StatusCategoryComplete {
+			open = append(open, issue.Key)
+		}
+	}
+	switch len(open) {
+	case 0:
+		return nil, "", nil, fmt.Errorf("cluster %s has no open OHSS issue", o.clusterID)
+	case 1:
+		fmt.Fprintf(o.out, "Using %s, the open OHSS issue of cluster %s\n", open[0], o.clusterID)
+		return client, open[0], args, nil
+	default:
+		return nil, "", nil, fmt.Errorf("cluster %s has %d open OHSS issues (%s), pass the issue key instead of --cluster-id", o.clusterID, len(open), strings.Join(open, ", "))
+	}
+}
+
+// run runs an osdctl command, returning its arguments and output
+func (o *ohssOptions) run(ctx context.Context, command string) ([]string, string, error) {
+	command, err := utils.ExpandClusterID(command, o.clusterID)
+	if err != nil {
+		return nil, "", err
+	}
+	args, err := utils.SplitOsdctlCommand(command)
+	if err != nil {
+		return nil, "", err
+	}
+
+	fmt.Fprintf(o.out, "Running osdctl %s\n", shellquote.Join(args...))
+	output, err := o.osdctl.Run(ctx, args)
+	if err != nil {
+		return nil, "", fmt.Errorf("'osdctl %s' failed: %w\n%s", shellquote.Join(args...), err, output)
+	}
+	return args, output, nil
+}
+
+// findTransition returns the transition whose name or target status is status
+func findTransition(transitions []jira.Transition, status string) (jira.Transition, error) {
+	var available []string
+	for _, transition := range transitions {
+		if strings.EqualFold(transition.Name, status) || strings.EqualFold(transition.To.Name, status) {
+			return transition, nil
+		}
+		available = append(available, transition.To.Name)
+	}
+	return jira.Transition{}, fmt.Errorf("no transition to %q, available statuses: %s", status, strings.Join(available, ", "))
+}
+
+// openAttachment opens a file to attach, archiving directories as .tar.gz while they are read
+func openAttachment(path string) (string, io.ReadCloser, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", nil, err
+	}
+	if !info.IsDir() {
+		if info.Size() > maxAttachmentSize {
+			return "", nil, fmt.Errorf("%s is larger than %dMiB", path, maxAttachmentSize>>20)
+		}
+		file, err := os.Open(path) //#nosec G304 -- path is a file the user asked to attach
+		return info.Name(), file, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeArchive(pw, filepath.Clean(path)))
+	}()
+	return info.Name() + ".tar.gz", &sizeLimitedReader{ReadCloser: pr, name: path, remaining: maxAttachmentSize}, nil
+}
+
+// writeArchive writes a directory to w as a .tar.gz archive
+func writeArchive(w io.Writer, root string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err := filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() && !entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(filepath.Dir(root), file)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relative)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		f, err := os.Open(file) //#nosec G304 -- file is inside a directory the user asked to attach
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", root, err)
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// sizeLimitedReader fails once more than remaining bytes are read
+type sizeLimitedReader struct {
+	io.ReadCloser
+	name      string
+	remaining int64
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, fmt.Errorf("the archive of %s is larger than %dMiB", r.name, maxAttachmentSize>>20)
+	}
+	return n, err
+}
+
+// outputAttachmentName names the attached output of an osdctl command after the command
+func outputAttachmentName(args []string) string {
+	name := attachmentNameRe.ReplaceAllString(strings.Join(append([]string{"osdctl"}, args...), "_"), "_")
+	return strings.Trim(name, "_") + ".txt"
+}
+
+// truncateOutput cuts a command output to at most n bytes, telling how to get all of it
+func truncateOutput(output string, n int) string {
+	if len(output) <= n {
+		return output
+	}
+	const truncated = "\n... (truncated, attach the whole output with 'osdctl jira ohss attach --attach')"
+	if n < len(truncated) {
+		return ""
+	}
+	return strings.ToValidUTF8(output[:n-len(truncated)], "") + truncated
+}
+
+func appendParagraph(b *strings.Builder, paragraph string) {
+	if b.Len() > 0 {
+		b.WriteString("\n\n")
+	}
+	b.WriteString(paragraph)
+}
+
+func readFileOrStdin(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path) //#nosec G304 -- path is a file the user asked to read
+}
+
+// ocmClusterResolver looks up clusters in OCM
+type ocmClusterResolver struct{}
+
+func (ocmClusterResolver) ClusterIDs(clusterID string) (string, string, error) {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return "", "", err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetCluster(ocmClient, clusterID)
+	if err != nil {
+		return "", "", err
+	}
+	return cluster.ID(), cluster.ExternalID(), nil
+}
@@ -0,0 +1,240 @@
+package jira
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/openshift/osdctl/cmd/jira/mock"
+	mocks "github.com/openshift/osdctl/pkg/utils/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// runOHSSCmd runs an ohss subcommand with a mocked Jira client, returning its output
+func runOHSSCmd(t *testing.T, client *mocks.MockJiraClientInterface, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	ctrl := gomock.NewController(t)
+	clusters := mock.NewMockClusterResolver(ctrl)
+	clusters.EXPECT().ClusterIDs(gomock.Any()).DoAndReturn(func(clusterID string) (string, string, error) {
+		return "internal-" + clusterID, "external-" + clusterID, nil
+	}).AnyTimes()
+	osdctl := mocks.NewMockOsdctlRunner(ctrl)
+	osdctl.EXPECT().Run(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, args []string) (string, error) {
+		switch args[0] {
+		case "fail":
+			return "boom\n", errors.New("exit status 1")
+		case "long":
+			return strings.Repeat("x", maxCommentLength), nil
+		}
+		return "output of " + strings.Join(args, " ") + "\n", nil
+	}).AnyTimes()
+	ops := &ohssOptions{
+		jiraClient: client,
+		clusters:   clusters,
+		osdctl:     osdctl,
+		out:        &out,
+	}
+
+	cmd := ohssCmd(ops)
+	cmd.SetArgs(args)
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func ohssIssue(key string, statusCategory string) jira.Issue {
+	return jira.Issue{Key: key, Fields: &jira.IssueFields{Status: &jira.Status{StatusCategory: jira.StatusCategory{Key: statusCategory}}}}
+}
+
+func TestOHSSIssueFromCluster(t *testing.T) {
+	tests := []struct {
+		name    string
+		issues  []jira.Issue
+		wantErr string
+	}{
+		{name: "single open issue", issues: []jira.Issue{ohssIssue("OHSS-2", jira.StatusCategoryInProgress), ohssIssue("OHSS-1", jira.StatusCategoryComplete)}},
+		{name: "no open issue", issues: []jira.Issue{ohssIssue("OHSS-1", jira.StatusCategoryComplete)}, wantErr: "cluster abc has no open OHSS issue"},
+		{name: "several open issues", issues: []jira.Issue{ohssIssue("OHSS-2", jira.StatusCategoryToDo), ohssIssue("OHSS-1", jira.StatusCategoryInProgress)}, wantErr: "cluster abc has 2 open OHSS issues (OHSS-2, OHSS-1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+			client.EXPECT().SearchIssues(gomock.Cond(func(jql string) bool {
+				return strings.Contains(jql, "internal-abc") && strings.Contains(jql, "external-abc")
+			})).Return(tt.issues, nil)
+			if tt.wantErr == "" {
+				client.EXPECT().AddComment("OHSS-2", "hello").Return(&jira.Comment{}, nil)
+			}
+
+			out, err := runOHSSCmd(t, client, "comment", "--cluster-id", "abc", "-m", "hello")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, out, "Using OHSS-2, the open OHSS issue of cluster abc")
+		})
+	}
+
+	t.Run("missing issue key", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		_, err := runOHSSCmd(t, client, "transition", "Done")
+		assert.EqualError(t, err, "pass an issue key or --cluster-id")
+	})
+}
+
+func TestOHSSComment(t *testing.T) {
+	t.Run("empty comment", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		_, err := runOHSSCmd(t, client, "comment", "OHSS-1")
+		assert.EqualError(t, err, "the comment is empty, set --message, --file or --attach")
+	})
+
+	t.Run("message, file and command output", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "comment.txt")
+		require.NoError(t, os.WriteFile(file, []byte("from a file\n"), 0600))
+
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		client.EXPECT().AddComment("OHSS-1", "Checked the cluster\n\nfrom a file\n\n"+
+			"*osdctl cluster context -C abc*\n{noformat}\noutput of cluster context -C abc\n{noformat}").Return(&jira.Comment{}, nil)
+
+		_, err := runOHSSCmd(t, client, "comment", "OHSS-1", "-m", "Checked the cluster", "--file", file, "--attach", "osdctl cluster context -C abc")
+		require.NoError(t, err)
+	})
+
+	t.Run("placeholder without cluster", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		_, err := runOHSSCmd(t, client, "comment", "OHSS-1", "--attach", "cluster context -C {cluster_id}")
+		assert.EqualError(t, err, "{cluster_id} can only be used with --cluster-id")
+	})
+
+	t.Run("truncated command output", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		client.EXPECT().AddComment("OHSS-1", gomock.Cond(func(body string) bool {
+			return len(body) == maxCommentLength && strings.HasPrefix(body, "Checked\n\n*osdctl long 'two words'*\n{noformat}\nxxx") &&
+				strings.HasSuffix(body, "... (truncated, attach the whole output with 'osdctl jira ohss attach --attach')\n{noformat}")
+		})).Return(&jira.Comment{}, nil)
+
+		out, err := runOHSSCmd(t, client, "comment", "OHSS-1", "-m", "Checked", "--attach", "long 'two words'")
+		require.NoError(t, err)
+		assert.Contains(t, out, "Running osdctl long 'two words'")
+	})
+
+	t.Run("failing command", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		_, err := runOHSSCmd(t, client, "comment", "OHSS-1", "--attach", "fail")
+		assert.EqualError(t, err, "'osdctl fail' failed: exit status 1\nboom\n")
+	})
+}
+
+func TestOHSSTransition(t *testing.T) {
+	transitions := []jira.Transition{
+		{ID: "11", Name: "Start Progress", To: jira.Status{Name: "In Progress"}},
+		{ID: "21", Name: "Close", To: jira.Status{Name: "Closed"}},
+	}
+
+	t.Run("by target status with a comment", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		client.EXPECT().GetTransitions("OHSS-1").Return(transitions, nil)
+		client.EXPECT().DoTransition("OHSS-1", "21").Return(nil)
+		client.EXPECT().AddComment("OHSS-1", "Fixed").Return(&jira.Comment{}, nil)
+
+		out, err := runOHSSCmd(t, client, "transition", "OHSS-1", "closed", "-m", "Fixed")
+		require.NoError(t, err)
+		assert.Equal(t, "Moved OHSS-1 to Closed\n", out)
+	})
+
+	t.Run("by transition name", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		client.EXPECT().GetTransitions("OHSS-1").Return(transitions, nil)
+		client.EXPECT().DoTransition("OHSS-1", "11").Return(nil)
+
+		_, err := runOHSSCmd(t, client, "transition", "OHSS-1", "Start Progress")
+		require.NoError(t, err)
+	})
+
+	t.Run("unavailable status", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		client.EXPECT().GetTransitions("OHSS-1").Return(transitions, nil)
+
+		_, err := runOHSSCmd(t, client, "transition", "OHSS-1", "Done")
+		assert.EqualError(t, err, `can't move OHSS-1: no transition to "Done", available statuses: In Progress, Closed`)
+	})
+}
+
+func TestOHSSLink(t *testing.T) {
+	client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+	client.EXPECT().AddLink(&jira.IssueLink{
+		Type:         jira.IssueLinkType{Name: "Blocks"},
+		InwardIssue:  &jira.Issue{Key: "OHSS-1"},
+		OutwardIssue: &jira.Issue{Key: "OCPBUGS-2"},
+	}).Return(nil)
+
+	out, err := runOHSSCmd(t, client, "link", "OHSS-1", "OCPBUGS-2", "--type", "Blocks")
+	require.NoError(t, err)
+	assert.Equal(t, "Linked OHSS-1 to OCPBUGS-2 (Blocks)\n", out)
+}
+
+func TestOHSSAttach(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "context.md")
+	require.NoError(t, os.WriteFile(file, []byte("# Context"), 0600))
+	mustGather := filepath.Join(dir, "must-gather")
+	require.NoError(t, os.MkdirAll(filepath.Join(mustGather, "namespaces"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(mustGather, "namespaces", "pods.yaml"), []byte("pods"), 0600))
+
+	attached := map[string][]byte{}
+	client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+	client.EXPECT().AddAttachment("OHSS-1", gomock.Any(), gomock.Any()).DoAndReturn(func(key string, name string, content io.Reader) error {
+		b, err := io.ReadAll(content)
+		attached[name] = b
+		return err
+	}).Times(3)
+
+	_, err := runOHSSCmd(t, client, "attach", "OHSS-1", file, mustGather, "--attach", "cloudtrail write-events -C abc")
+	require.NoError(t, err)
+
+	assert.Equal(t, "# Context", string(attached["context.md"]))
+	assert.Equal(t, "output of cloudtrail write-events -C abc\n", string(attached["osdctl_cloudtrail_write-events_-C_abc.txt"]))
+
+	require.Contains(t, attached, "must-gather.tar.gz")
+	gz, err := gzip.NewReader(bytes.NewReader(attached["must-gather.tar.gz"]))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+	}
+	assert.Equal(t, []string{"must-gather", "must-gather/namespaces", "must-gather/namespaces/pods.yaml"}, names)
+
+	_, err = runOHSSCmd(t, mocks.NewMockJiraClientInterface(gomock.NewController(t)), "attach", "OHSS-1")
+	assert.EqualError(t, err, "nothing to attach, pass files or --attach")
+}
+
+func TestSizeLimitedReader(t *testing.T) {
+	r := &sizeLimitedReader{ReadCloser: io.NopCloser(strings.NewReader("12345")), name: "must-gather", remaining: 5}
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "12345", string(b))
+
+	r = &sizeLimitedReader{ReadCloser: io.NopCloser(strings.NewReader("123456")), name: "must-gather", remaining: 5}
+	_, err = io.ReadAll(r)
+	assert.EqualError(t, err, "the archive of must-gather is larger than 100MiB")
+}
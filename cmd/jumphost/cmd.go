@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
@@ -18,6 +21,13 @@ const (
 	awsResourceName     = "red-hat-sre-jumphost"
 	publicSubnetTagKey  = "kubernetes.io/role/elb"
 	privateSubnetTagKey = "kubernetes.io/role/internal-elb"
+
+	// expiresAtTagKey holds the RFC3339 time after which a jumphost is deleted by "jumphost reap"
+	expiresAtTagKey = "osdctl-jumphost-expires-at"
+	// createdByTagKey holds the AWS identity which created a jumphost
+	createdByTagKey = "osdctl-jumphost-created-by"
+
+	defaultJumphostTTL = 8 * time.Hour
 )
 
 func NewCmdJumphost() *cobra.Command {
@@ -29,6 +39,9 @@ func NewCmdJumphost() *cobra.Command {
 	jumphost.AddCommand(
 		newCmdCreateJumphost(),
 		newCmdDeleteJumphost(),
+		newCmdListJumphosts(),
+		newCmdReapJumphosts(),
+		newCmdConnectJumphost(),
 	)
 
 	return jumphost
@@ -42,6 +55,14 @@ type jumphostConfig struct {
 
 	keyFilepath string
 	ec2PublicIp string
+	instanceId  string
+
+	// createdBy and expiresAt are tagged on all created resources, but unlike tags not used to search for them
+	createdBy string
+	expiresAt time.Time
+	// useSsm skips opening port 22 and attaches instanceProfile for SSM Session Manager access instead
+	useSsm          bool
+	instanceProfile string
 }
 
 type jumphostAWSClient interface {
@@ -57,6 +78,7 @@ type jumphostAWSClient interface {
 	DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(options *ec2.Options)) (*ec2.DescribeImagesOutput, error)
 	DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(options *ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
 	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(options *ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeRegions(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(options *ec2.Options)) (*ec2.DescribeRegionsOutput, error)
 	RunInstances(ctx context.Context, params *ec2.RunInstancesInput, optFns ...func(options *ec2.Options)) (*ec2.RunInstancesOutput, error)
 	TerminateInstances(ctx context.Context, params *ec2.TerminateInstancesInput, optFns ...func(options *ec2.Options)) (*ec2.TerminateInstancesOutput, error)
 
@@ -97,24 +119,55 @@ func initJumphostConfig(ctx context.Context, clusterId, subnetId string) (*jumph
 	return &jumphostConfig{
 		awsClient: ec2.NewFromConfig(cfg),
 		subnetId:  subnetId,
-		tags: []types.Tag{
-			//{
-			//	// This tag will allow the uninstaller to clean up orphaned resources in worst-case scenarios
-			//	Key:   aws.String(fmt.Sprintf("kubernetes.io/cluster/%s", cluster.InfraID())),
-			//	Value: aws.String("owned"),
-			//},
-			{
-				Key:   aws.String("red-hat-managed"),
-				Value: aws.String("true"),
-			},
-			{
-				Key:   aws.String("Name"),
-				Value: aws.String("red-hat-sre-jumphost"),
-			},
-		},
+		tags:      jumphostTags(),
+		createdBy: callerIdentity(ctx, sts.NewFromConfig(cfg)),
 	}, nil
 }
 
+// jumphostTags returns the tags identifying all AWS resources of a jumphost
+func jumphostTags() []types.Tag {
+	return []types.Tag{
+		//{
+		//	// This tag will allow the uninstaller to clean up orphaned resources in worst-case scenarios
+		//	Key:   aws.String(fmt.Sprintf("kubernetes.io/cluster/%s", cluster.InfraID())),
+		//	Value: aws.String("owned"),
+		//},
+		{
+			Key:   aws.String("red-hat-managed"),
+			Value: aws.String("true"),
+		},
+		{
+			Key:   aws.String("Name"),
+			Value: aws.String(awsResourceName),
+		},
+	}
+}
+
+type stsClient interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+// callerIdentity returns the ARN of the AWS identity in use, falling back to the local user
+func callerIdentity(ctx context.Context, client stsClient) string {
+	resp, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err == nil && resp.Arn != nil {
+		return *resp.Arn
+	}
+	return os.Getenv("USER")
+}
+
+// resourceTags returns the tags to create resources with, j.tags plus the creator and expiry
+func (j *jumphostConfig) resourceTags() []types.Tag {
+	tags := append([]types.Tag{}, j.tags...)
+	if j.createdBy != "" {
+		tags = append(tags, types.Tag{Key: aws.String(createdByTagKey), Value: aws.String(j.createdBy)})
+	}
+	if !j.expiresAt.IsZero() {
+		tags = append(tags, types.Tag{Key: aws.String(expiresAtTagKey), Value: aws.String(j.expiresAt.UTC().Format(time.RFC3339))})
+	}
+	return tags
+}
+
 // validateCluster is currently unused as the --cluster-id flag is not supported yet.
 // Eventually, it will gate the usage of the --cluster-id flag based on types of supported clusters.
 func validateCluster(cluster *cmv1.Cluster) error {
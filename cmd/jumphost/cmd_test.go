@@ -1,11 +1,15 @@
 package jumphost
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/stretchr/testify/assert"
 )
@@ -147,3 +151,55 @@ func TestValidateCluster(t *testing.T) {
 		})
 	}
 }
+
+type mockStsClient struct {
+	arn     *string
+	account *string
+	err     error
+}
+
+func (m *mockStsClient) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &sts.GetCallerIdentityOutput{Arn: m.arn, Account: m.account}, nil
+}
+
+func TestCallerIdentity(t *testing.T) {
+	t.Setenv("USER", "local-user")
+
+	assert.Equal(t, "arn:aws:iam::123456789012:user/sre", callerIdentity(context.TODO(), &mockStsClient{arn: aws.String("arn:aws:iam::123456789012:user/sre")}))
+	assert.Equal(t, "local-user", callerIdentity(context.TODO(), &mockStsClient{err: errors.New("expired token")}))
+}
+
+func TestResourceTags(t *testing.T) {
+	expiresAt := time.Date(2024, 1, 1, 8, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	tests := []struct {
+		name     string
+		jumphost *jumphostConfig
+		expected []types.Tag
+	}{
+		{
+			name:     "base_tags_only",
+			jumphost: &jumphostConfig{tags: jumphostTags()},
+			expected: jumphostTags(),
+		},
+		{
+			name:     "creator_and_expiry",
+			jumphost: &jumphostConfig{tags: jumphostTags(), createdBy: "sre", expiresAt: expiresAt},
+			expected: append(jumphostTags(),
+				types.Tag{Key: aws.String(createdByTagKey), Value: aws.String("sre")},
+				types.Tag{Key: aws.String(expiresAtTagKey), Value: aws.String("2024-01-01T07:00:00Z")},
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.jumphost.resourceTags())
+			// The search tags must not be modified
+			assert.Equal(t, jumphostTags(), tt.jumphost.tags)
+		})
+	}
+}
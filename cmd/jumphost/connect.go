@@ -0,0 +1,186 @@
+package jumphost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/spf13/cobra"
+)
+
+const (
+	sshConfigBlockBegin = "# BEGIN osdctl jumphost %s"
+	sshConfigBlockEnd   = "# END osdctl jumphost %s"
+	jumphostUser        = "ec2-user"
+)
+
+type connectOptions struct {
+	instanceId   string
+	region       string
+	profile      string
+	identityFile string
+	sshConfig    string
+	useSsm       bool
+}
+
+func newCmdConnectJumphost() *cobra.Command {
+	opts := &connectOptions{}
+
+	connect := &cobra.Command{
+		Use:          "connect",
+		SilenceUsage: true,
+		Short:        "Add an SSH config entry for a jumphost created by `osdctl jumphost create`",
+		Long: `Add an SSH config entry for a jumphost created by "osdctl jumphost create"
+
+  Writes a Host entry named after the instance ID to --ssh-config, replacing a previous entry for the
+  same jumphost, so the jumphost can be reached with "ssh <instance-id>" and used as a ProxyJump.
+
+  With --ssm, the entry tunnels SSH through SSM Session Manager instead of connecting to the public IP,
+  which requires the AWS CLI and its session-manager-plugin. This works for jumphosts created with
+  "osdctl jumphost create --ssm", which do not allow inbound SSH.
+
+  Requires the ec2:DescribeInstances permission.`,
+		Example: `
+  # Connect to a jumphost through its public IP
+  osdctl jumphost connect --instance-id i-0123456789abcdef0 --identity-file /tmp/jumphost_123.pem
+
+  # Connect to a jumphost through SSM Session Manager
+  osdctl jumphost connect --instance-id i-0123456789abcdef0 --identity-file /tmp/jumphost_123.pem --ssm`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(context.TODO())
+		},
+	}
+
+	connect.Flags().StringVar(&opts.instanceId, "instance-id", "", "EC2 instance id of the jumphost")
+	connect.Flags().StringVar(&opts.region, "region", "", "AWS region of the jumphost, defaults to the region of the AWS config")
+	connect.Flags().StringVar(&opts.profile, "profile", "", "AWS profile to use, defaults to the default credential chain")
+	connect.Flags().StringVar(&opts.identityFile, "identity-file", "", "private key file printed by \"osdctl jumphost create\"")
+	connect.Flags().StringVar(&opts.sshConfig, "ssh-config", "", "SSH config file to write the entry to, defaults to ~/.ssh/config")
+	connect.Flags().BoolVar(&opts.useSsm, "ssm", false, "connect through SSM Session Manager instead of the public IP")
+
+	_ = connect.MarkFlagRequired("instance-id")
+	_ = connect.MarkFlagRequired("identity-file")
+
+	return connect
+}
+
+func (o *connectOptions) run(ctx context.Context) error {
+	if o.sshConfig == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to determine home directory, set --ssh-config: %w", err)
+		}
+		o.sshConfig = filepath.Join(home, ".ssh", "config")
+	}
+
+	identityFile, err := filepath.Abs(o.identityFile)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(identityFile); err != nil {
+		return fmt.Errorf("failed to read identity file: %w", err)
+	}
+
+	cfg, err := loadJumphostAWSConfig(ctx, o.profile, o.region)
+	if err != nil {
+		return err
+	}
+
+	hostName := o.instanceId
+	if !o.useSsm {
+		hostName, err = jumphostPublicIp(ctx, ec2.NewFromConfig(cfg), o.instanceId)
+		if err != nil {
+			return err
+		}
+	}
+
+	entry := sshConfigEntry(o.instanceId, hostName, identityFile, o.useSsm, cfg.Region)
+	if err := writeSshConfigEntry(o.sshConfig, o.instanceId, entry); err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote SSH config entry for %s to %s, connect with:\nssh %s\n", o.instanceId, o.sshConfig, o.instanceId)
+	return nil
+}
+
+// jumphostPublicIp returns the public IP of a running jumphost
+func jumphostPublicIp(ctx context.Context, client jumphostAWSClient, instanceId string) (string, error) {
+	resp, err := client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{instanceId},
+		Filters:     generateTagFilters(jumphostTags()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe %s: %w", instanceId, err)
+	}
+
+	if len(resp.Reservations) == 0 || len(resp.Reservations[0].Instances) == 0 {
+		return "", fmt.Errorf("jumphost %s not found", instanceId)
+	}
+
+	ip := aws.ToString(resp.Reservations[0].Instances[0].PublicIpAddress)
+	if ip == "" {
+		return "", fmt.Errorf("jumphost %s has no public ip, try --ssm", instanceId)
+	}
+	return ip, nil
+}
+
+// sshConfigEntry returns the marked SSH config block for a jumphost
+func sshConfigEntry(instanceId, hostName, identityFile string, useSsm bool, region string) string {
+	lines := []string{
+		fmt.Sprintf(sshConfigBlockBegin, instanceId),
+		fmt.Sprintf("Host %s", instanceId),
+		fmt.Sprintf("  HostName %s", hostName),
+		fmt.Sprintf("  User %s", jumphostUser),
+		fmt.Sprintf("  IdentityFile %s", identityFile),
+		"  IdentitiesOnly yes",
+	}
+	if useSsm {
+		proxy := "  ProxyCommand aws ssm start-session --target %h --document-name AWS-StartSSHSession --parameters portNumber=%p"
+		if region != "" {
+			proxy += " --region " + region
+		}
+		lines = append(lines, proxy)
+	}
+	lines = append(lines, fmt.Sprintf(sshConfigBlockEnd, instanceId))
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// writeSshConfigEntry writes entry to the SSH config at path, replacing an existing block for the same jumphost.
+// New entries are prepended, as SSH uses the first matching value for each option.
+func writeSshConfigEntry(path, instanceId, entry string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read SSH config: %w", err)
+	}
+
+	content := string(existing)
+	begin := fmt.Sprintf(sshConfigBlockBegin, instanceId)
+	end := fmt.Sprintf(sshConfigBlockEnd, instanceId)
+	if start := strings.Index(content, begin); start != -1 {
+		stop := strings.Index(content[start:], end)
+		if stop == -1 {
+			return fmt.Errorf("found %q without %q in %s, please fix it by hand", begin, end, path)
+		}
+		stop += start + len(end)
+		if stop < len(content) && content[stop] == '\n' {
+			stop++
+		}
+		content = content[:start] + entry + content[stop:]
+	} else if content == "" {
+		content = entry
+	} else {
+		content = entry + "\n" + content
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0600)
+}
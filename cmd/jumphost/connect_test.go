@@ -0,0 +1,107 @@
+package jumphost
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSshConfigEntry(t *testing.T) {
+	assert.Equal(t, `# BEGIN osdctl jumphost i-1
+Host i-1
+  HostName 1.2.3.4
+  User ec2-user
+  IdentityFile /tmp/key.pem
+  IdentitiesOnly yes
+# END osdctl jumphost i-1
+`, sshConfigEntry("i-1", "1.2.3.4", "/tmp/key.pem", false, "us-east-1"))
+
+	assert.Equal(t, `# BEGIN osdctl jumphost i-1
+Host i-1
+  HostName i-1
+  User ec2-user
+  IdentityFile /tmp/key.pem
+  IdentitiesOnly yes
+  ProxyCommand aws ssm start-session --target %h --document-name AWS-StartSSHSession --parameters portNumber=%p --region us-east-1
+# END osdctl jumphost i-1
+`, sshConfigEntry("i-1", "i-1", "/tmp/key.pem", true, "us-east-1"))
+}
+
+func TestWriteSshConfigEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".ssh", "config")
+
+	// Creates the file and its directory
+	first := sshConfigEntry("i-1", "1.2.3.4", "/tmp/key.pem", false, "")
+	require.NoError(t, writeSshConfigEntry(path, "i-1", first))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, first, string(content))
+
+	// New entries are prepended to existing configuration
+	require.NoError(t, os.WriteFile(path, []byte(first+"\nHost *\n  ServerAliveInterval 30\n"), 0600))
+	second := sshConfigEntry("i-2", "5.6.7.8", "/tmp/key.pem", false, "")
+	require.NoError(t, writeSshConfigEntry(path, "i-2", second))
+
+	// Existing entries are replaced in place
+	replaced := sshConfigEntry("i-1", "9.9.9.9", "/tmp/key.pem", false, "")
+	require.NoError(t, writeSshConfigEntry(path, "i-1", replaced))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, second+"\n"+replaced+"\nHost *\n  ServerAliveInterval 30\n", string(content))
+
+	// An unterminated block is not overwritten
+	require.NoError(t, os.WriteFile(path, []byte("# BEGIN osdctl jumphost i-3\nHost i-3\n"), 0600))
+	assert.ErrorContains(t, writeSshConfigEntry(path, "i-3", first), "please fix it by hand")
+}
+
+func TestJumphostPublicIp(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   *ec2.DescribeInstancesOutput
+		expected string
+		errorMsg string
+	}{
+		{
+			name: "public_ip",
+			output: &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{
+				{Instances: []types.Instance{{PublicIpAddress: aws.String("1.2.3.4")}}},
+			}},
+			expected: "1.2.3.4",
+		},
+		{
+			name:     "not_found",
+			output:   &ec2.DescribeInstancesOutput{},
+			errorMsg: "jumphost i-1 not found",
+		},
+		{
+			name: "no_public_ip",
+			output: &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{
+				{Instances: []types.Instance{{}}},
+			}},
+			errorMsg: "jumphost i-1 has no public ip, try --ssm",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(mockAWSClient)
+			mockClient.On("DescribeInstances", mock.Anything, mock.Anything).Return(tt.output, nil)
+
+			ip, err := jumphostPublicIp(context.TODO(), mockClient, "i-1")
+			if tt.errorMsg != "" {
+				assert.EqualError(t, err, tt.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ip)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package jumphost
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -20,8 +21,11 @@ import (
 
 func newCmdCreateJumphost() *cobra.Command {
 	var (
-		clusterId string
-		subnetId  string
+		clusterId       string
+		subnetId        string
+		ttl             time.Duration
+		useSsm          bool
+		instanceProfile string
 	)
 
 	create := &cobra.Command{
@@ -38,6 +42,14 @@ func newCmdCreateJumphost() *cobra.Command {
 
   When the cluster's API server is accessible, prefer "oc debug node".
 
+  Jumphosts are tagged with the identity that created them and an expiry time after --ttl.
+  The instance shuts itself down (and is terminated) when it expires, and "osdctl jumphost reap"
+  deletes the remaining key pair and security group of expired jumphosts.
+
+  With --ssm, no SSH ingress is opened on the security group. Instead, the instance is launched
+  with --instance-profile, which must allow SSM Session Manager (e.g. by attaching the
+  AmazonSSMManagedInstanceCore policy), and is reached with "osdctl jumphost connect --ssm".
+
   Requires these permissions:
   {
     "Version": "2012-10-17",
@@ -56,7 +68,9 @@ func newCmdCreateJumphost() *cobra.Command {
           "ec2:DescribeSecurityGroups",
           "ec2:DescribeSubnets",
           "ec2:RunInstances",
-          "ec2:TerminateInstances"
+          "ec2:TerminateInstances",
+          "iam:PassRole",
+          "sts:GetCallerIdentity"
         ],
         "Effect": "Allow",
         "Resource": "*"
@@ -66,19 +80,35 @@ func newCmdCreateJumphost() *cobra.Command {
 		Example: `
   # Create and delete a jumphost
   osdctl jumphost create --subnet-id public-subnet-id
-  osdctl jumphost delete --subnet-id public-subnet-id`,
+  osdctl jumphost delete --subnet-id public-subnet-id
+
+  # Create a jumphost expiring after 2 hours, only reachable through SSM Session Manager
+  osdctl jumphost create --subnet-id public-subnet-id --ttl 2h --ssm --instance-profile ssm-instance-profile`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if ttl <= 0 {
+				return errors.New("--ttl must be positive")
+			}
+			if useSsm && instanceProfile == "" {
+				return errors.New("--ssm requires --instance-profile")
+			}
+
 			j, err := initJumphostConfig(context.TODO(), clusterId, subnetId)
 			if err != nil {
 				return err
 			}
+			j.expiresAt = time.Now().Add(ttl)
+			j.useSsm = useSsm
+			j.instanceProfile = instanceProfile
 
 			return j.runCreate(context.TODO())
 		},
 	}
 
 	create.Flags().StringVar(&subnetId, "subnet-id", "", "public subnet id to create a jumphost in")
+	create.Flags().DurationVar(&ttl, "ttl", defaultJumphostTTL, "time after which the jumphost shuts down and can be deleted by \"osdctl jumphost reap\"")
+	create.Flags().BoolVar(&useSsm, "ssm", false, "use SSM Session Manager for access instead of opening port 22 to your public IP")
+	create.Flags().StringVar(&instanceProfile, "instance-profile", "", "IAM instance profile name allowing SSM Session Manager access, required with --ssm")
 
 	_ = create.MarkFlagRequired("subnet-id")
 
@@ -114,7 +144,7 @@ func (j *jumphostConfig) createKeyPair(ctx context.Context) error {
 		TagSpecifications: []types.TagSpecification{
 			{
 				ResourceType: types.ResourceTypeKeyPair,
-				Tags:         j.tags,
+				Tags:         j.resourceTags(),
 			},
 		},
 	})
@@ -160,7 +190,7 @@ func (j *jumphostConfig) createSecurityGroup(ctx context.Context) (string, error
 		TagSpecifications: []types.TagSpecification{
 			{
 				ResourceType: types.ResourceTypeSecurityGroup,
-				Tags:         j.tags,
+				Tags:         j.resourceTags(),
 			},
 		},
 		VpcId: aws.String(vpcId),
@@ -176,6 +206,11 @@ func (j *jumphostConfig) createSecurityGroup(ctx context.Context) (string, error
 	}
 	log.Printf("created security group: %s", *resp.GroupId)
 
+	if j.useSsm {
+		log.Println("skipping SSH ingress, the jumphost is reachable through SSM Session Manager")
+		return *resp.GroupId, nil
+	}
+
 	if err := j.allowJumphostSshFromIp(ctx, *resp.GroupId); err != nil {
 		return *resp.GroupId, fmt.Errorf("failed to allow SSH to jumphost: %w", err)
 	}
@@ -194,7 +229,7 @@ func (j *jumphostConfig) createEc2Jumphost(ctx context.Context, securityGroupId
 		return err
 	}
 
-	resp, err := j.awsClient.RunInstances(ctx, &ec2.RunInstancesInput{
+	input := &ec2.RunInstancesInput{
 		MaxCount: aws.Int32(1),
 		MinCount: aws.Int32(1),
 		BlockDeviceMappings: []types.BlockDeviceMapping{
@@ -222,13 +257,17 @@ func (j *jumphostConfig) createEc2Jumphost(ctx context.Context, securityGroupId
 		TagSpecifications: []types.TagSpecification{
 			{
 				ResourceType: types.ResourceTypeInstance,
-				Tags:         j.tags,
+				Tags:         j.resourceTags(),
 			},
 		},
-		// TODO: It would be nice to add user data which shut down the EC2 instance after 8 hours.
-		// This would automatically terminate the EC2 instance in case we forget to clean up.
-		UserData: nil,
-	})
+		// Shut down the EC2 instance once it expires, which terminates it in case we forget to clean up
+		UserData: j.shutdownUserData(time.Now()),
+	}
+	if j.instanceProfile != "" {
+		input.IamInstanceProfile = &types.IamInstanceProfileSpecification{Name: aws.String(j.instanceProfile)}
+	}
+
+	resp, err := j.awsClient.RunInstances(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to create jumphost EC2 instace: %w", err)
 	}
@@ -249,14 +288,36 @@ func (j *jumphostConfig) createEc2Jumphost(ctx context.Context, securityGroupId
 	describeInstancesResp, err := j.awsClient.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
 		InstanceIds: []string{*resp.Instances[0].InstanceId},
 	})
+	if err != nil {
+		return err
+	}
+	j.instanceId = *resp.Instances[0].InstanceId
 
-	log.Printf("created EC2 jumphost: %s with public ip: %s", *describeInstancesResp.Reservations[0].Instances[0].InstanceId, *describeInstancesResp.Reservations[0].Instances[0].PublicIpAddress)
-	j.ec2PublicIp = *describeInstancesResp.Reservations[0].Instances[0].PublicIpAddress
+	log.Printf("created EC2 jumphost: %s with public ip: %s", *describeInstancesResp.Reservations[0].Instances[0].InstanceId, aws.ToString(describeInstancesResp.Reservations[0].Instances[0].PublicIpAddress))
+	j.ec2PublicIp = aws.ToString(describeInstancesResp.Reservations[0].Instances[0].PublicIpAddress)
 	return nil
 }
 
+// shutdownUserData returns the base64 encoded user data which shuts the instance down at j.expiresAt
+func (j *jumphostConfig) shutdownUserData(now time.Time) *string {
+	if j.expiresAt.IsZero() {
+		return nil
+	}
+
+	minutes := int(j.expiresAt.Sub(now).Minutes())
+	if minutes < 1 {
+		minutes = 1
+	}
+	script := fmt.Sprintf("#!/bin/bash\nshutdown -h +%d\n", minutes)
+	return aws.String(base64.StdEncoding.EncodeToString([]byte(script)))
+}
+
 // assembleNextSteps returns a string with helpful next steps for connecting to the created jumphost
 func (j *jumphostConfig) assembleNextSteps() string {
+	if j.useSsm && j.instanceId != "" {
+		return fmt.Sprintf("osdctl jumphost connect --instance-id %s --ssm --identity-file %s", j.instanceId, j.keyFileOrPlaceholder())
+	}
+
 	if j.ec2PublicIp == "" {
 		return fmt.Sprintf("could not determine EC2 public ip - please verify, but something likely went wrong")
 	}
@@ -268,6 +329,13 @@ func (j *jumphostConfig) assembleNextSteps() string {
 	return fmt.Sprintf("ssh -i ${private_key} ec2-user@%s", j.ec2PublicIp)
 }
 
+func (j *jumphostConfig) keyFileOrPlaceholder() string {
+	if j.keyFilepath != "" {
+		return j.keyFilepath
+	}
+	return "${private_key}"
+}
+
 // findVpcId returns the AWS VPC ID of a provided jumphostConfig.
 // Currently, requires that subnetId be defined.
 func (j *jumphostConfig) findVpcId(ctx context.Context) (string, error) {
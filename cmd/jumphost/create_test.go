@@ -2,10 +2,12 @@ package jumphost
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -243,6 +245,15 @@ func TestAssembleNextSteps(t *testing.T) {
 			jumphost: &jumphostConfig{},
 			expected: "could not determine EC2 public ip - please verify, but something likely went wrong",
 		},
+		{
+			name: "ssm",
+			jumphost: &jumphostConfig{
+				instanceId:  "i-123",
+				keyFilepath: "test-key.pem",
+				useSsm:      true,
+			},
+			expected: "osdctl jumphost connect --instance-id i-123 --ssm --identity-file test-key.pem",
+		},
 	}
 
 	for _, tt := range tests {
@@ -252,6 +263,23 @@ func TestAssembleNextSteps(t *testing.T) {
 	}
 }
 
+func TestShutdownUserData(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, (&jumphostConfig{}).shutdownUserData(now))
+
+	userData := (&jumphostConfig{expiresAt: now.Add(2 * time.Hour)}).shutdownUserData(now)
+	script, err := base64.StdEncoding.DecodeString(aws.ToString(userData))
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/bin/bash\nshutdown -h +120\n", string(script))
+
+	// Already expired jumphosts still get a delay to finish booting
+	userData = (&jumphostConfig{expiresAt: now.Add(-time.Hour)}).shutdownUserData(now)
+	script, err = base64.StdEncoding.DecodeString(aws.ToString(userData))
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/bin/bash\nshutdown -h +1\n", string(script))
+}
+
 func TestCreateKeyPair(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -0,0 +1,349 @@
+package jumphost
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentRegions limits how many profile/region combinations are searched at the same time
+const maxConcurrentRegions = 8
+
+// jumphostInstance is a jumphost EC2 instance found by "osdctl jumphost list"
+type jumphostInstance struct {
+	// Profile is the AWS profile the jumphost was found with, empty for the default credential chain
+	Profile    string
+	Account    string
+	Region     string
+	InstanceID string
+	State      string
+	SubnetID   string
+	// KeyName and SecurityGroupIDs are the key pair and security groups created with the jumphost
+	KeyName          string
+	SecurityGroupIDs []string
+	PublicIP         string
+	LaunchTime       time.Time
+	CreatedBy        string
+	// ExpiresAt is zero for jumphosts created before expiry tags were added
+	ExpiresAt time.Time
+}
+
+// expired returns true if the jumphost is past its expiry, or, if it has none, older than maxAge
+func (i jumphostInstance) expired(now time.Time, maxAge time.Duration) bool {
+	return expiredAt(i.ExpiresAt, i.LaunchTime, now, maxAge)
+}
+
+// expiredAt returns true if now is past expiresAt, or, without expiry, if createdAt is known and older than maxAge
+func expiredAt(expiresAt, createdAt, now time.Time, maxAge time.Duration) bool {
+	if !expiresAt.IsZero() {
+		return now.After(expiresAt)
+	}
+	return !createdAt.IsZero() && now.Sub(createdAt) > maxAge
+}
+
+func newCmdListJumphosts() *cobra.Command {
+	var (
+		profiles []string
+		regions  []string
+	)
+
+	list := &cobra.Command{
+		Use:          "list",
+		SilenceUsage: true,
+		Short:        "List jumphosts created by `osdctl jumphost create`",
+		Long: `List jumphosts created by "osdctl jumphost create"
+
+  Searches every region enabled in the account, or only --regions, for jumphost EC2 instances and
+  prints their age, creator and expiry. Use --profiles to search multiple AWS accounts at once.
+
+  Requires the ec2:DescribeInstances, ec2:DescribeRegions and sts:GetCallerIdentity permissions.`,
+		Example: `
+  # List jumphosts in all regions of the current AWS account
+  osdctl jumphost list
+
+  # List jumphosts in two accounts and a single region
+  osdctl jumphost list --profiles account-a,account-b --regions us-east-1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instances, err := listJumphosts(context.TODO(), profiles, regions)
+			if err != nil {
+				return err
+			}
+
+			printJumphosts(os.Stdout, instances, time.Now())
+			return nil
+		},
+	}
+
+	list.Flags().StringSliceVar(&profiles, "profiles", nil, "AWS profiles to search, defaults to the default credential chain")
+	list.Flags().StringSliceVar(&regions, "regions", nil, "AWS regions to search, defaults to all regions enabled in the account")
+
+	return list
+}
+
+// listJumphosts searches all combinations of profiles and regions for jumphosts concurrently
+func listJumphosts(ctx context.Context, profiles, regions []string) ([]jumphostInstance, error) {
+	var (
+		mu        sync.Mutex
+		instances []jumphostInstance
+	)
+
+	err := searchRegions(ctx, profiles, regions, func(ctx context.Context, client jumphostAWSClient, profile, account, region string) error {
+		found, err := findJumphosts(ctx, client, region)
+		if err != nil {
+			return fmt.Errorf("failed to list jumphosts in %s/%s: %w", account, region, err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, instance := range found {
+			instance.Profile = profile
+			instance.Account = account
+			instances = append(instances, instance)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortJumphosts(instances)
+	return instances, nil
+}
+
+// searchRegions calls searchFn for all combinations of profiles and regions concurrently
+func searchRegions(ctx context.Context, profiles, regions []string, searchFn func(ctx context.Context, client jumphostAWSClient, profile, account, region string) error) error {
+	if len(profiles) == 0 {
+		// The empty profile falls back to the default credential chain
+		profiles = []string{""}
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentRegions)
+	for _, profile := range profiles {
+		cfg, err := loadJumphostAWSConfig(ctx, profile, "")
+		if err != nil {
+			return err
+		}
+
+		account := callerIdentityAccount(ctx, sts.NewFromConfig(cfg), profile)
+		profileRegions := regions
+		if len(profileRegions) == 0 {
+			profileRegions, err = enabledRegions(ctx, ec2.NewFromConfig(cfg))
+			if err != nil {
+				return fmt.Errorf("failed to list regions for %s: %w", account, err)
+			}
+		}
+
+		for _, region := range profileRegions {
+			g.Go(func() error {
+				cfg, err := loadJumphostAWSConfig(ctx, profile, region)
+				if err != nil {
+					return err
+				}
+				return searchFn(ctx, ec2.NewFromConfig(cfg), profile, account, region)
+			})
+		}
+	}
+	return g.Wait()
+}
+
+// sortJumphosts sorts instances by account, region and launch time
+func sortJumphosts(instances []jumphostInstance) {
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Account != instances[j].Account {
+			return instances[i].Account < instances[j].Account
+		}
+		if instances[i].Region != instances[j].Region {
+			return instances[i].Region < instances[j].Region
+		}
+		return instances[i].LaunchTime.Before(instances[j].LaunchTime)
+	})
+}
+
+// loadJumphostAWSConfig loads the AWS config for an optional profile and region
+func loadJumphostAWSConfig(ctx context.Context, profile, region string) (aws.Config, error) {
+	var opts []func(*config.LoadOptions) error
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS config for profile %q: %w", profile, err)
+	}
+	return cfg, nil
+}
+
+// callerIdentityAccount returns the AWS account ID in use, falling back to the profile name
+func callerIdentityAccount(ctx context.Context, client stsClient, profile string) string {
+	resp, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err == nil && resp.Account != nil {
+		return *resp.Account
+	}
+	if profile == "" {
+		return "default"
+	}
+	return profile
+}
+
+// enabledRegions returns the names of all regions enabled in the account
+func enabledRegions(ctx context.Context, client jumphostAWSClient) ([]string, error) {
+	resp, err := client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	regions := make([]string, 0, len(resp.Regions))
+	for _, region := range resp.Regions {
+		regions = append(regions, aws.ToString(region.RegionName))
+	}
+	sort.Strings(regions)
+
+	return regions, nil
+}
+
+// findJumphosts returns all jumphost EC2 instances in a region which are not terminated yet
+func findJumphosts(ctx context.Context, client jumphostAWSClient, region string) ([]jumphostInstance, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: append(generateTagFilters(jumphostTags()), types.Filter{
+			Name: aws.String("instance-state-name"),
+			Values: []string{
+				string(types.InstanceStateNamePending),
+				string(types.InstanceStateNameRunning),
+				string(types.InstanceStateNameShuttingDown),
+				string(types.InstanceStateNameStopping),
+				string(types.InstanceStateNameStopped),
+			},
+		}),
+	}
+
+	var instances []jumphostInstance
+	paginator := ec2.NewDescribeInstancesPaginator(client, input)
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reservation := range resp.Reservations {
+			for _, instance := range reservation.Instances {
+				instances = append(instances, newJumphostInstance(region, instance))
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+func newJumphostInstance(region string, instance types.Instance) jumphostInstance {
+	j := jumphostInstance{
+		Region:     region,
+		InstanceID: aws.ToString(instance.InstanceId),
+		SubnetID:   aws.ToString(instance.SubnetId),
+		PublicIP:   aws.ToString(instance.PublicIpAddress),
+		LaunchTime: aws.ToTime(instance.LaunchTime),
+	}
+	if instance.State != nil {
+		j.State = string(instance.State.Name)
+	}
+	if aws.ToString(instance.KeyName) == awsResourceName {
+		j.KeyName = awsResourceName
+	}
+	for _, group := range instance.SecurityGroups {
+		if aws.ToString(group.GroupName) == awsResourceName {
+			j.SecurityGroupIDs = append(j.SecurityGroupIDs, aws.ToString(group.GroupId))
+		}
+	}
+
+	j.CreatedBy, j.ExpiresAt = parseJumphostTags(instance.Tags)
+	return j
+}
+
+// parseJumphostTags returns the creator and expiry tagged on a jumphost resource
+func parseJumphostTags(tags []types.Tag) (string, time.Time) {
+	var (
+		createdBy string
+		expiresAt time.Time
+	)
+	for _, tag := range tags {
+		switch aws.ToString(tag.Key) {
+		case createdByTagKey:
+			createdBy = aws.ToString(tag.Value)
+		case expiresAtTagKey:
+			// An unparsable expiry is treated the same as a missing one
+			if t, err := time.Parse(time.RFC3339, aws.ToString(tag.Value)); err == nil {
+				expiresAt = t
+			}
+		}
+	}
+	return createdBy, expiresAt
+}
+
+func printJumphosts(w io.Writer, instances []jumphostInstance, now time.Time) {
+	if len(instances) == 0 {
+		fmt.Fprintln(w, "no jumphosts found")
+		return
+	}
+
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"ACCOUNT", "REGION", "INSTANCE", "STATE", "AGE", "CREATED BY", "EXPIRES", "PUBLIC IP"})
+	for _, instance := range instances {
+		expires := "-"
+		if !instance.ExpiresAt.IsZero() {
+			if now.After(instance.ExpiresAt) {
+				expires = "expired"
+			} else {
+				expires = "in " + formatJumphostDuration(instance.ExpiresAt.Sub(now))
+			}
+		}
+
+		p.AddRow([]string{
+			instance.Account,
+			instance.Region,
+			instance.InstanceID,
+			instance.State,
+			formatJumphostDuration(now.Sub(instance.LaunchTime)),
+			valueOrDash(instance.CreatedBy),
+			expires,
+			valueOrDash(instance.PublicIP),
+		})
+	}
+	_ = p.Flush()
+}
+
+// formatJumphostDuration formats a duration with minute precision, e.g. 1d2h or 3h15m
+func formatJumphostDuration(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd%dh", d/(24*time.Hour), (d%(24*time.Hour))/time.Hour)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%dm", d/time.Hour, (d%time.Hour)/time.Minute)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,146 @@
+package jumphost
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *mockAWSClient) DescribeRegions(ctx context.Context, params *ec2.DescribeRegionsInput, optFns ...func(options *ec2.Options)) (*ec2.DescribeRegionsOutput, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*ec2.DescribeRegionsOutput), args.Error(1)
+}
+
+func TestEnabledRegions(t *testing.T) {
+	mockClient := new(mockAWSClient)
+	mockClient.On("DescribeRegions", mock.Anything, mock.Anything).Return(&ec2.DescribeRegionsOutput{
+		Regions: []types.Region{{RegionName: aws.String("us-west-2")}, {RegionName: aws.String("eu-west-1")}},
+	}, nil)
+
+	regions, err := enabledRegions(context.TODO(), mockClient)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eu-west-1", "us-west-2"}, regions)
+}
+
+func TestFindJumphosts(t *testing.T) {
+	launchTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockClient := new(mockAWSClient)
+	mockClient.On("DescribeInstances", mock.Anything, mock.MatchedBy(func(input *ec2.DescribeInstancesInput) bool {
+		return len(input.Filters) == len(jumphostTags())+1 && aws.ToString(input.Filters[len(input.Filters)-1].Name) == "instance-state-name"
+	})).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []types.Reservation{
+			{
+				Instances: []types.Instance{
+					{
+						InstanceId:      aws.String("i-tagged"),
+						SubnetId:        aws.String("subnet-a"),
+						PublicIpAddress: aws.String("1.2.3.4"),
+						LaunchTime:      aws.Time(launchTime),
+						State:           &types.InstanceState{Name: types.InstanceStateNameRunning},
+						Tags: []types.Tag{
+							{Key: aws.String(createdByTagKey), Value: aws.String("sre")},
+							{Key: aws.String(expiresAtTagKey), Value: aws.String("2024-01-01T08:00:00Z")},
+						},
+					},
+					{
+						InstanceId: aws.String("i-untagged"),
+						LaunchTime: aws.Time(launchTime),
+						Tags:       []types.Tag{{Key: aws.String(expiresAtTagKey), Value: aws.String("tomorrow")}},
+					},
+				},
+			},
+		},
+	}, nil)
+
+	instances, err := findJumphosts(context.TODO(), mockClient, "us-east-1")
+	require.NoError(t, err)
+	assert.Equal(t, []jumphostInstance{
+		{
+			Region:     "us-east-1",
+			InstanceID: "i-tagged",
+			State:      "running",
+			SubnetID:   "subnet-a",
+			PublicIP:   "1.2.3.4",
+			LaunchTime: launchTime,
+			CreatedBy:  "sre",
+			ExpiresAt:  launchTime.Add(8 * time.Hour),
+		},
+		{
+			Region:     "us-east-1",
+			InstanceID: "i-untagged",
+			LaunchTime: launchTime,
+		},
+	}, instances)
+	mockClient.AssertExpectations(t)
+}
+
+func TestJumphostInstanceExpired(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		instance jumphostInstance
+		expected bool
+	}{
+		{
+			name:     "before_expiry",
+			instance: jumphostInstance{LaunchTime: now.Add(-48 * time.Hour), ExpiresAt: now.Add(time.Minute)},
+			expected: false,
+		},
+		{
+			name:     "after_expiry",
+			instance: jumphostInstance{LaunchTime: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)},
+			expected: true,
+		},
+		{
+			name:     "no_expiry_young",
+			instance: jumphostInstance{LaunchTime: now.Add(-time.Hour)},
+			expected: false,
+		},
+		{
+			name:     "no_expiry_old",
+			instance: jumphostInstance{LaunchTime: now.Add(-25 * time.Hour)},
+			expected: true,
+		},
+		{
+			name:     "unknown_launch_time",
+			instance: jumphostInstance{},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.instance.expired(now, 24*time.Hour))
+		})
+	}
+}
+
+func TestPrintJumphosts(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	printJumphosts(&buf, nil, now)
+	assert.Equal(t, "no jumphosts found\n", buf.String())
+
+	buf.Reset()
+	printJumphosts(&buf, []jumphostInstance{
+		{Account: "123456789012", Region: "us-east-1", InstanceID: "i-1", State: "running", LaunchTime: now.Add(-90 * time.Minute), CreatedBy: "sre", ExpiresAt: now.Add(30 * time.Minute), PublicIP: "1.2.3.4"},
+		{Account: "123456789012", Region: "us-east-1", InstanceID: "i-2", State: "stopped", LaunchTime: now.Add(-26 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+	}, now)
+	out := buf.String()
+	assert.Contains(t, out, "CREATED BY")
+	assert.Contains(t, out, "1h30m")
+	assert.Contains(t, out, "in 30m")
+	assert.Contains(t, out, "1d2h")
+	assert.Contains(t, out, "expired")
+}
@@ -0,0 +1,342 @@
+package jumphost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/spf13/cobra"
+)
+
+// Types of the jumphost resources which outlive their instance
+const (
+	keyPairResource       = "key pair"
+	securityGroupResource = "security group"
+)
+
+// jumphostResource is a key pair or security group of a jumphost whose instance is gone, usually because it
+// expired and terminated itself
+type jumphostResource struct {
+	Profile   string
+	Account   string
+	Region    string
+	Type      string
+	ID        string
+	CreatedBy string
+	// CreateTime is zero for security groups, which have none
+	CreateTime time.Time
+	// ExpiresAt is zero for jumphosts created before expiry tags were added
+	ExpiresAt time.Time
+}
+
+// expired returns true if the resource is past its expiry, or, if it has none, older than maxAge
+func (r jumphostResource) expired(now time.Time, maxAge time.Duration) bool {
+	return expiredAt(r.ExpiresAt, r.CreateTime, now, maxAge)
+}
+
+func newCmdReapJumphosts() *cobra.Command {
+	var (
+		profiles []string
+		regions  []string
+		maxAge   time.Duration
+		dryRun   bool
+	)
+
+	reap := &cobra.Command{
+		Use:          "reap",
+		SilenceUsage: true,
+		Short:        "Delete expired jumphosts created by `osdctl jumphost create`",
+		Long: `Delete expired jumphosts created by "osdctl jumphost create"
+
+  Searches for jumphosts the same way as "osdctl jumphost list" and deletes all jumphosts past the
+  expiry set by "osdctl jumphost create --ttl", along with their key pair and security group.
+  Jumphosts without an expiry are deleted once they are older than --max-age.
+
+  Expired jumphosts shut themselves down and are terminated, which leaves their key pair and
+  security group behind. Jumphost key pairs and security groups not used by any jumphost instance
+  are deleted once past the same expiry. Key pairs without an expiry are deleted once older than
+  --max-age, security groups without an expiry are kept, as their age is unknown.
+
+  Requires the permissions of "osdctl jumphost delete" as well as ec2:DescribeRegions and
+  sts:GetCallerIdentity.`,
+		Example: `
+  # Show which jumphosts would be deleted across two accounts
+  osdctl jumphost reap --profiles account-a,account-b --dry-run
+
+  # Delete expired jumphosts in a single region
+  osdctl jumphost reap --regions us-east-1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if maxAge <= 0 {
+				return errors.New("--max-age must be positive")
+			}
+
+			instances, orphans, err := listJumphostResources(context.TODO(), profiles, regions)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			return errors.Join(
+				reapJumphosts(context.TODO(), expiredJumphosts(instances, now, maxAge), dryRun, reapJumphost),
+				reapOrphans(context.TODO(), expiredResources(orphans, now, maxAge), dryRun, reapResource),
+			)
+		},
+	}
+
+	reap.Flags().StringSliceVar(&profiles, "profiles", nil, "AWS profiles to search, defaults to the default credential chain")
+	reap.Flags().StringSliceVar(&regions, "regions", nil, "AWS regions to search, defaults to all regions enabled in the account")
+	reap.Flags().DurationVar(&maxAge, "max-age", 3*defaultJumphostTTL, "age after which jumphosts without an expiry are deleted")
+	reap.Flags().BoolVar(&dryRun, "dry-run", false, "only print the jumphosts which would be deleted")
+
+	return reap
+}
+
+// listJumphostResources searches all combinations of profiles and regions for jumphosts, and for the key pairs and
+// security groups no jumphost uses anymore
+func listJumphostResources(ctx context.Context, profiles, regions []string) ([]jumphostInstance, []jumphostResource, error) {
+	var (
+		mu        sync.Mutex
+		instances []jumphostInstance
+		orphans   []jumphostResource
+	)
+
+	err := searchRegions(ctx, profiles, regions, func(ctx context.Context, client jumphostAWSClient, profile, account, region string) error {
+		found, err := findJumphosts(ctx, client, region)
+		if err != nil {
+			return fmt.Errorf("failed to list jumphosts in %s/%s: %w", account, region, err)
+		}
+		orphaned, err := findOrphanedResources(ctx, client, region, found)
+		if err != nil {
+			return fmt.Errorf("failed to list jumphost resources in %s/%s: %w", account, region, err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, instance := range found {
+			instance.Profile = profile
+			instance.Account = account
+			instances = append(instances, instance)
+		}
+		for _, resource := range orphaned {
+			resource.Profile = profile
+			resource.Account = account
+			orphans = append(orphans, resource)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sortJumphosts(instances)
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Account != orphans[j].Account {
+			return orphans[i].Account < orphans[j].Account
+		}
+		if orphans[i].Region != orphans[j].Region {
+			return orphans[i].Region < orphans[j].Region
+		}
+		return orphans[i].ID < orphans[j].ID
+	})
+	return instances, orphans, nil
+}
+
+// findOrphanedResources returns the jumphost key pairs and security groups of a region which none of the instances,
+// found by findJumphosts, uses
+func findOrphanedResources(ctx context.Context, client jumphostAWSClient, region string, instances []jumphostInstance) ([]jumphostResource, error) {
+	usedKeys := map[string]bool{}
+	usedGroups := map[string]bool{}
+	for _, instance := range instances {
+		usedKeys[instance.KeyName] = true
+		for _, groupID := range instance.SecurityGroupIDs {
+			usedGroups[groupID] = true
+		}
+	}
+
+	keyPairs, err := client.DescribeKeyPairs(ctx, &ec2.DescribeKeyPairsInput{
+		Filters: generateTagFilters(jumphostTags()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe key pairs: %w", err)
+	}
+
+	var orphans []jumphostResource
+	for _, keyPair := range keyPairs.KeyPairs {
+		if usedKeys[aws.ToString(keyPair.KeyName)] {
+			continue
+		}
+		createdBy, expiresAt := parseJumphostTags(keyPair.Tags)
+		orphans = append(orphans, jumphostResource{
+			Region:     region,
+			Type:       keyPairResource,
+			ID:         aws.ToString(keyPair.KeyPairId),
+			CreatedBy:  createdBy,
+			CreateTime: aws.ToTime(keyPair.CreateTime),
+			ExpiresAt:  expiresAt,
+		})
+	}
+
+	securityGroups, err := client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: append(generateTagFilters(jumphostTags()), types.Filter{
+			Name:   aws.String("group-name"),
+			Values: []string{awsResourceName},
+		}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe security groups: %w", err)
+	}
+
+	for _, group := range securityGroups.SecurityGroups {
+		if usedGroups[aws.ToString(group.GroupId)] {
+			continue
+		}
+		createdBy, expiresAt := parseJumphostTags(group.Tags)
+		orphans = append(orphans, jumphostResource{
+			Region:    region,
+			Type:      securityGroupResource,
+			ID:        aws.ToString(group.GroupId),
+			CreatedBy: createdBy,
+			ExpiresAt: expiresAt,
+		})
+	}
+
+	return orphans, nil
+}
+
+// expiredJumphosts returns the instances which are expired at now
+func expiredJumphosts(instances []jumphostInstance, now time.Time, maxAge time.Duration) []jumphostInstance {
+	var expired []jumphostInstance
+	for _, instance := range instances {
+		if instance.expired(now, maxAge) {
+			expired = append(expired, instance)
+		}
+	}
+	return expired
+}
+
+// expiredResources returns the resources which are expired at now
+func expiredResources(resources []jumphostResource, now time.Time, maxAge time.Duration) []jumphostResource {
+	var expired []jumphostResource
+	for _, resource := range resources {
+		if resource.expired(now, maxAge) {
+			expired = append(expired, resource)
+		}
+	}
+	return expired
+}
+
+// reapJumphosts deletes every instance with deleteFn, continuing past failures so one broken jumphost
+// does not keep the others around
+func reapJumphosts(ctx context.Context, instances []jumphostInstance, dryRun bool, deleteFn func(context.Context, jumphostInstance) error) error {
+	if len(instances) == 0 {
+		log.Println("no expired jumphosts found")
+		return nil
+	}
+
+	var errs []error
+	for _, instance := range instances {
+		if dryRun {
+			log.Printf("would delete jumphost %s in %s/%s created by %s", instance.InstanceID, instance.Account, instance.Region, valueOrDash(instance.CreatedBy))
+			continue
+		}
+
+		log.Printf("deleting jumphost %s in %s/%s created by %s", instance.InstanceID, instance.Account, instance.Region, valueOrDash(instance.CreatedBy))
+		if err := deleteFn(ctx, instance); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete jumphost %s in %s/%s: %w", instance.InstanceID, instance.Account, instance.Region, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// reapOrphans deletes every orphaned resource with deleteFn, continuing past failures like reapJumphosts
+func reapOrphans(ctx context.Context, resources []jumphostResource, dryRun bool, deleteFn func(context.Context, jumphostResource) error) error {
+	if len(resources) == 0 {
+		log.Println("no expired jumphost key pairs or security groups found")
+		return nil
+	}
+
+	var errs []error
+	for _, resource := range resources {
+		if dryRun {
+			log.Printf("would delete orphaned %s %s in %s/%s created by %s", resource.Type, resource.ID, resource.Account, resource.Region, valueOrDash(resource.CreatedBy))
+			continue
+		}
+
+		log.Printf("deleting orphaned %s %s in %s/%s created by %s", resource.Type, resource.ID, resource.Account, resource.Region, valueOrDash(resource.CreatedBy))
+		if err := deleteFn(ctx, resource); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s %s in %s/%s: %w", resource.Type, resource.ID, resource.Account, resource.Region, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// reapJumphost deletes the jumphost instance and its key pair and security group
+func reapJumphost(ctx context.Context, instance jumphostInstance) error {
+	cfg, err := loadJumphostAWSConfig(ctx, instance.Profile, instance.Region)
+	if err != nil {
+		return err
+	}
+
+	return deleteJumphostInstance(ctx, ec2.NewFromConfig(cfg), instance)
+}
+
+// deleteJumphostInstance terminates the jumphost instance by ID, then deletes the key pair and security groups it
+// was launched with, which can't be deleted while the instance uses them
+func deleteJumphostInstance(ctx context.Context, client jumphostAWSClient, instance jumphostInstance) error {
+	if _, err := client.TerminateInstances(ctx, &ec2.TerminateInstancesInput{
+		InstanceIds: []string{instance.InstanceID},
+	}); err != nil {
+		return err
+	}
+
+	waiter := ec2.NewInstanceTerminatedWaiter(client)
+	if err := waiter.Wait(ctx, &ec2.DescribeInstancesInput{InstanceIds: []string{instance.InstanceID}}, 5*time.Minute); err != nil {
+		return err
+	}
+
+	if instance.KeyName != "" {
+		if _, err := client.DeleteKeyPair(ctx, &ec2.DeleteKeyPairInput{KeyName: aws.String(instance.KeyName)}); err != nil {
+			return fmt.Errorf("failed to delete keypair: %w", err)
+		}
+	}
+
+	for _, groupID := range instance.SecurityGroupIDs {
+		if _, err := client.DeleteSecurityGroup(ctx, &ec2.DeleteSecurityGroupInput{GroupId: aws.String(groupID)}); err != nil {
+			return fmt.Errorf("failed to delete security group: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// reapResource deletes an orphaned key pair or security group by ID
+func reapResource(ctx context.Context, resource jumphostResource) error {
+	cfg, err := loadJumphostAWSConfig(ctx, resource.Profile, resource.Region)
+	if err != nil {
+		return err
+	}
+
+	return deleteJumphostResource(ctx, ec2.NewFromConfig(cfg), resource)
+}
+
+func deleteJumphostResource(ctx context.Context, client jumphostAWSClient, resource jumphostResource) error {
+	switch resource.Type {
+	case keyPairResource:
+		_, err := client.DeleteKeyPair(ctx, &ec2.DeleteKeyPairInput{KeyPairId: aws.String(resource.ID)})
+		return err
+	case securityGroupResource:
+		_, err := client.DeleteSecurityGroup(ctx, &ec2.DeleteSecurityGroupInput{GroupId: aws.String(resource.ID)})
+		return err
+	}
+	return fmt.Errorf("unknown jumphost resource type %q", resource.Type)
+}
@@ -0,0 +1,165 @@
+package jumphost
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiredJumphosts(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	instances := []jumphostInstance{
+		{InstanceID: "i-expired", ExpiresAt: now.Add(-time.Minute)},
+		{InstanceID: "i-active", ExpiresAt: now.Add(time.Hour)},
+		{InstanceID: "i-old", LaunchTime: now.Add(-48 * time.Hour)},
+	}
+
+	var ids []string
+	for _, instance := range expiredJumphosts(instances, now, 24*time.Hour) {
+		ids = append(ids, instance.InstanceID)
+	}
+	assert.Equal(t, []string{"i-expired", "i-old"}, ids)
+}
+
+func TestReapJumphosts(t *testing.T) {
+	instances := []jumphostInstance{
+		{InstanceID: "i-1", Account: "123456789012", Region: "us-east-1"},
+		{InstanceID: "i-2", Account: "123456789012", Region: "us-west-2"},
+	}
+
+	tests := []struct {
+		name      string
+		dryRun    bool
+		failOn    string
+		deleted   []string
+		errorMsgs []string
+	}{
+		{
+			name:   "dry_run",
+			dryRun: true,
+		},
+		{
+			name:    "delete_all",
+			deleted: []string{"i-1", "i-2"},
+		},
+		{
+			name:      "continue_after_failure",
+			failOn:    "i-1",
+			deleted:   []string{"i-1", "i-2"},
+			errorMsgs: []string{"failed to delete jumphost i-1 in 123456789012/us-east-1: boom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted []string
+			err := reapJumphosts(context.TODO(), instances, tt.dryRun, func(ctx context.Context, instance jumphostInstance) error {
+				deleted = append(deleted, instance.InstanceID)
+				if instance.InstanceID == tt.failOn {
+					return errors.New("boom")
+				}
+				return nil
+			})
+
+			assert.Equal(t, tt.deleted, deleted)
+			if len(tt.errorMsgs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, msg := range tt.errorMsgs {
+				assert.ErrorContains(t, err, msg)
+			}
+		})
+	}
+}
+
+func TestFindOrphanedResources(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	expiry := []types.Tag{{Key: aws.String(expiresAtTagKey), Value: aws.String("2024-01-01T08:00:00Z")}}
+
+	mockClient := new(mockAWSClient)
+	mockClient.On("DescribeKeyPairs", mock.Anything, mock.Anything).Return(&ec2.DescribeKeyPairsOutput{
+		KeyPairs: []types.KeyPairInfo{
+			{KeyPairId: aws.String("key-orphan"), KeyName: aws.String("other-key"), Tags: expiry},
+			{KeyPairId: aws.String("key-used"), KeyName: aws.String(awsResourceName), Tags: expiry},
+			{KeyPairId: aws.String("key-old"), KeyName: aws.String("old-key"), CreateTime: aws.Time(now.Add(-48 * time.Hour))},
+		},
+	}, nil)
+	mockClient.On("DescribeSecurityGroups", mock.Anything, mock.Anything).Return(&ec2.DescribeSecurityGroupsOutput{
+		SecurityGroups: []types.SecurityGroup{
+			{GroupId: aws.String("sg-orphan"), Tags: expiry},
+			{GroupId: aws.String("sg-used"), Tags: expiry},
+			{GroupId: aws.String("sg-unknown-age")},
+		},
+	}, nil)
+
+	orphans, err := findOrphanedResources(context.TODO(), mockClient, "us-east-1", []jumphostInstance{
+		{InstanceID: "i-stopped", KeyName: awsResourceName, SecurityGroupIDs: []string{"sg-used"}},
+	})
+	require.NoError(t, err)
+
+	var ids []string
+	for _, orphan := range orphans {
+		ids = append(ids, orphan.ID)
+	}
+	assert.Equal(t, []string{"key-orphan", "key-old", "sg-orphan", "sg-unknown-age"}, ids)
+
+	ids = nil
+	for _, orphan := range expiredResources(orphans, now, 24*time.Hour) {
+		ids = append(ids, orphan.ID)
+	}
+	assert.Equal(t, []string{"key-orphan", "key-old", "sg-orphan"}, ids)
+}
+
+func TestDeleteJumphostInstance(t *testing.T) {
+	mockClient := new(mockAWSClient)
+	mockClient.On("TerminateInstances", mock.Anything, &ec2.TerminateInstancesInput{InstanceIds: []string{"i-1"}}).Return(&ec2.TerminateInstancesOutput{}, nil)
+	mockClient.On("DescribeInstances", mock.Anything, mock.Anything).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []types.Reservation{{Instances: []types.Instance{
+			{InstanceId: aws.String("i-1"), State: &types.InstanceState{Name: types.InstanceStateNameTerminated}},
+		}}},
+	}, nil)
+	mockClient.On("DeleteKeyPair", mock.Anything, &ec2.DeleteKeyPairInput{KeyName: aws.String(awsResourceName)}).Return(&ec2.DeleteKeyPairOutput{}, nil)
+	mockClient.On("DeleteSecurityGroup", mock.Anything, &ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-1")}).Return(&ec2.DeleteSecurityGroupOutput{}, nil)
+
+	err := deleteJumphostInstance(context.TODO(), mockClient, jumphostInstance{InstanceID: "i-1", KeyName: awsResourceName, SecurityGroupIDs: []string{"sg-1"}})
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDeleteJumphostResource(t *testing.T) {
+	mockClient := new(mockAWSClient)
+	mockClient.On("DeleteKeyPair", mock.Anything, &ec2.DeleteKeyPairInput{KeyPairId: aws.String("key-1")}).Return(&ec2.DeleteKeyPairOutput{}, nil)
+	mockClient.On("DeleteSecurityGroup", mock.Anything, &ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-1")}).Return(&ec2.DeleteSecurityGroupOutput{}, nil)
+
+	require.NoError(t, deleteJumphostResource(context.TODO(), mockClient, jumphostResource{Type: keyPairResource, ID: "key-1"}))
+	require.NoError(t, deleteJumphostResource(context.TODO(), mockClient, jumphostResource{Type: securityGroupResource, ID: "sg-1"}))
+	assert.EqualError(t, deleteJumphostResource(context.TODO(), mockClient, jumphostResource{Type: "volume", ID: "vol-1"}), `unknown jumphost resource type "volume"`)
+	mockClient.AssertExpectations(t)
+}
+
+func TestReapOrphans(t *testing.T) {
+	resources := []jumphostResource{
+		{Type: keyPairResource, ID: "key-1", Account: "123456789012", Region: "us-east-1"},
+		{Type: securityGroupResource, ID: "sg-1", Account: "123456789012", Region: "us-east-1"},
+	}
+
+	var deleted []string
+	err := reapOrphans(context.TODO(), resources, false, func(ctx context.Context, resource jumphostResource) error {
+		deleted = append(deleted, resource.ID)
+		if resource.ID == "key-1" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	assert.Equal(t, []string{"key-1", "sg-1"}, deleted)
+	assert.EqualError(t, err, "failed to delete key pair key-1 in 123456789012/us-east-1: boom")
+}
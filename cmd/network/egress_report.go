@@ -0,0 +1,574 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/osd-network-verifier/pkg/data/cloud"
+	"github.com/openshift/osd-network-verifier/pkg/output"
+	"github.com/openshift/osd-network-verifier/pkg/probes"
+	"github.com/openshift/osd-network-verifier/pkg/probes/curl"
+	"github.com/openshift/osd-network-verifier/pkg/probes/legacy"
+	onv "github.com/openshift/osd-network-verifier/pkg/verifier"
+
+	"github.com/openshift/osdctl/pkg/printer"
+)
+
+type egressStatus string
+
+const (
+	egressPass    egressStatus = "pass"
+	egressFail    egressStatus = "fail"
+	egressTimeout egressStatus = "timeout"
+
+	// podModeSubnet is the subnet reported for runs in pod mode, which are not bound to a subnet
+	podModeSubnet = "pod"
+)
+
+var (
+	// The curl probe logs every probe result as a %+v formatted CurlJSONProbeResult in its debug logs,
+	// which is the only place the verifier exposes endpoints that passed and their latency
+	curlProbeResultRegexp = regexp.MustCompile(`ExitCode:(\d+) .*TimeTotal:([0-9.e+-]+) URL:(\S+) URLEffective:`)
+	// The curl probe reports failures as "<url> (<curl error message>)", the legacy probe only as "<url>"
+	egressFailureRegexp = regexp.MustCompile(`^(\S+) \((.*)\)$`)
+)
+
+// curl exits with 28 when an operation timed out
+const curlTimeoutExitCode = 28
+
+// reporting is true when the results are aggregated into a report rather than printed per run
+func (e *EgressVerification) reporting() bool {
+	return e.AllProbes || e.Output != "" || e.Baseline != "" || e.SaveBaseline != ""
+}
+
+// reportProbes returns the probes to run for the report, by name
+func (e *EgressVerification) reportProbes(ctx context.Context) map[string]probes.Probe {
+	if !e.AllProbes {
+		name := strings.ToLower(e.Probe)
+		if name == "legacy" {
+			return map[string]probes.Probe{name: legacy.Probe{}}
+		}
+		return map[string]probes.Probe{"curl": curl.Probe{}}
+	}
+
+	if e.PodMode {
+		e.log.Info(ctx, "Pod mode only supports curl probe, skipping legacy probe")
+		return map[string]probes.Probe{"curl": curl.Probe{}}
+	}
+	return map[string]probes.Probe{"curl": curl.Probe{}, "legacy": legacy.Probe{}}
+}
+
+// runReport runs every probe against every input and reports the aggregated results. As the report
+// is meant for comparing runs, no service logs are sent for failures.
+func (e *EgressVerification) runReport(ctx context.Context, verifier networkVerifier, inputs []*onv.ValidateEgressInput, platform cloud.Platform) error {
+	var baseline *egressReport
+	if e.Baseline != "" {
+		var err error
+		baseline, err = loadEgressReport(e.Baseline)
+		if err != nil {
+			return err
+		}
+	}
+
+	report := &egressReport{
+		ClusterID: e.ClusterId,
+		Platform:  platform.String(),
+		Timestamp: time.Now().UTC(),
+	}
+
+	probesByName := e.reportProbes(ctx)
+	names := make([]string, 0, len(probesByName))
+	for name := range probesByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, input := range inputs {
+		for _, name := range names {
+			probeInput := *input
+			probeInput.Probe = probesByName[name]
+			e.log.Info(ctx, "running network verifier with %s probe for subnet %+v", name, subnetOrPod(input.SubnetID))
+
+			start := time.Now()
+			out := onv.ValidateEgress(verifier, probeInput)
+			report.addRun(input.SubnetID, name, time.Since(start), out)
+		}
+	}
+	report.complete()
+
+	if baseline != nil {
+		report.Changes = report.diff(baseline)
+	}
+
+	if e.SaveBaseline != "" {
+		if err := report.save(e.SaveBaseline); err != nil {
+			return err
+		}
+		e.log.Info(ctx, "Saved egress report baseline to %s", e.SaveBaseline)
+	}
+
+	if err := report.render(os.Stdout, e.Output); err != nil {
+		return fmt.Errorf("failed to render egress report: %w", err)
+	}
+
+	if report.failed() {
+		fmt.Fprintln(os.Stderr, "Network verification failed. Service logs are not sent when reporting, run without --all-probes, --output and --baseline flags to send one.")
+		os.Exit(1)
+	}
+	return nil
+}
+
+func subnetOrPod(subnet string) string {
+	if subnet == "" {
+		return podModeSubnet
+	}
+	return subnet
+}
+
+// egressResult is the outcome of probing one endpoint from one subnet with one probe
+type egressResult struct {
+	Endpoint string       `json:"endpoint"`
+	Subnet   string       `json:"subnet"`
+	Probe    string       `json:"probe"`
+	Status   egressStatus `json:"status"`
+	// LatencyMs is only known for probes reporting per endpoint timings, i.e. curl
+	LatencyMs float64 `json:"latencyMs,omitempty"`
+	Message   string  `json:"message,omitempty"`
+}
+
+func (r egressResult) key() string {
+	return r.Endpoint + "|" + r.Subnet + "|" + r.Probe
+}
+
+// egressRun is a single osd-network-verifier run for one subnet and probe
+type egressRun struct {
+	Subnet     string   `json:"subnet"`
+	Probe      string   `json:"probe"`
+	DurationMs int64    `json:"durationMs"`
+	Errors     []string `json:"errors,omitempty"`
+	// perEndpoint is set when the probe reported every endpoint it tested, not only failures
+	perEndpoint bool
+}
+
+// egressChange is an endpoint whose status differs between a baseline and the current report
+type egressChange struct {
+	Endpoint string       `json:"endpoint"`
+	Subnet   string       `json:"subnet"`
+	Probe    string       `json:"probe"`
+	Before   egressStatus `json:"before,omitempty"`
+	After    egressStatus `json:"after,omitempty"`
+}
+
+// regression is true for endpoints that passed (or were not tested) in the baseline and no longer pass
+func (c egressChange) regression() bool {
+	return c.After != "" && c.After != egressPass && (c.Before == "" || c.Before == egressPass)
+}
+
+// egressReport aggregates the results of several verifier runs into an endpoint x subnet x probe matrix
+type egressReport struct {
+	ClusterID string         `json:"clusterId,omitempty"`
+	Platform  string         `json:"platform"`
+	Timestamp time.Time      `json:"timestamp"`
+	Runs      []egressRun    `json:"runs"`
+	Results   []egressResult `json:"results"`
+	Changes   []egressChange `json:"changes,omitempty"`
+}
+
+// addRun records the output of a verifier run
+func (r *egressReport) addRun(subnet string, probe string, duration time.Duration, out *output.Output) {
+	subnet = subnetOrPod(subnet)
+	run := egressRun{Subnet: subnet, Probe: probe, DurationMs: duration.Milliseconds()}
+
+	results := map[string]*egressResult{}
+	var endpoints []string
+	add := func(result egressResult) {
+		if existing, ok := results[result.Endpoint]; ok {
+			// A failure reported for an endpoint takes precedence over its debug log, unless the
+			// debug log already identified it as a timeout
+			if result.Status != egressPass {
+				if existing.Status != egressTimeout {
+					existing.Status = result.Status
+				}
+				existing.Message = result.Message
+			}
+			return
+		}
+		results[result.Endpoint] = &result
+		endpoints = append(endpoints, result.Endpoint)
+	}
+
+	for _, line := range strings.Split(out.Format(true), "\n") {
+		match := curlProbeResultRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		run.perEndpoint = true
+		result := egressResult{
+			// Failures are reported with "tcp" in place of "telnet", keep the endpoints consistent
+			Endpoint: strings.Replace(match[3], "telnet", "tcp", 1),
+			Subnet:   subnet,
+			Probe:    probe,
+			Status:   egressPass,
+		}
+		if seconds, err := strconv.ParseFloat(match[2], 64); err == nil {
+			result.LatencyMs = seconds * 1000
+		}
+		if exitCode, err := strconv.Atoi(match[1]); err == nil && exitCode == curlTimeoutExitCode {
+			result.Status = egressTimeout
+		}
+		add(result)
+	}
+
+	for _, failure := range out.GetEgressURLFailures() {
+		endpoint, message := parseEgressFailure(failure.EgressURL())
+		status := egressFail
+		if isEgressTimeout(message) {
+			status = egressTimeout
+		}
+		add(egressResult{Endpoint: endpoint, Subnet: subnet, Probe: probe, Status: status, Message: message})
+	}
+
+	_, exceptions, errs := out.Parse()
+	for _, err := range append(exceptions, errs...) {
+		run.Errors = append(run.Errors, err.Error())
+	}
+
+	for _, endpoint := range endpoints {
+		r.Results = append(r.Results, *results[endpoint])
+	}
+	r.Runs = append(r.Runs, run)
+}
+
+// parseEgressFailure splits a failure reported by a probe into its endpoint and error message
+func parseEgressFailure(failure string) (string, string) {
+	if match := egressFailureRegexp.FindStringSubmatch(failure); match != nil {
+		return match[1], match[2]
+	}
+	return failure, ""
+}
+
+func isEgressTimeout(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "timed out") || strings.Contains(message, "timeout")
+}
+
+// complete fills in passing results for runs of probes that only report failures. An endpoint that
+// failed in any run was tested by every run, so if such a run completed without errors and did not
+// report the endpoint, it passed there.
+func (r *egressReport) complete() {
+	tested := map[string]bool{}
+	for _, result := range r.Results {
+		tested[result.key()] = true
+	}
+
+	for _, endpoint := range r.endpoints() {
+		for _, run := range r.Runs {
+			if run.perEndpoint || len(run.Errors) > 0 {
+				continue
+			}
+			result := egressResult{Endpoint: endpoint, Subnet: run.Subnet, Probe: run.Probe, Status: egressPass}
+			if !tested[result.key()] {
+				tested[result.key()] = true
+				r.Results = append(r.Results, result)
+			}
+		}
+	}
+
+	sort.SliceStable(r.Results, func(i, j int) bool {
+		return r.Results[i].key() < r.Results[j].key()
+	})
+}
+
+// endpoints returns every endpoint in the report, sorted
+func (r *egressReport) endpoints() []string {
+	seen := map[string]bool{}
+	var endpoints []string
+	for _, result := range r.Results {
+		if !seen[result.Endpoint] {
+			seen[result.Endpoint] = true
+			endpoints = append(endpoints, result.Endpoint)
+		}
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
+
+// failed is true if any endpoint did not pass or any run could not complete
+func (r *egressReport) failed() bool {
+	for _, run := range r.Runs {
+		if len(run.Errors) > 0 {
+			return true
+		}
+	}
+	for _, result := range r.Results {
+		if result.Status != egressPass {
+			return true
+		}
+	}
+	return false
+}
+
+// diff returns the endpoints whose status changed since the baseline. Endpoints missing from either
+// report are only included when they do not pass in the other one.
+func (r *egressReport) diff(baseline *egressReport) []egressChange {
+	before := map[string]egressResult{}
+	for _, result := range baseline.Results {
+		before[result.key()] = result
+	}
+
+	var changes []egressChange
+	for _, result := range r.Results {
+		previous, ok := before[result.key()]
+		delete(before, result.key())
+		if ok && previous.Status == result.Status {
+			continue
+		}
+		if !ok && result.Status == egressPass {
+			continue
+		}
+		changes = append(changes, egressChange{
+			Endpoint: result.Endpoint,
+			Subnet:   result.Subnet,
+			Probe:    result.Probe,
+			Before:   previous.Status,
+			After:    result.Status,
+		})
+	}
+	for _, previous := range before {
+		if previous.Status == egressPass {
+			continue
+		}
+		changes = append(changes, egressChange{
+			Endpoint: previous.Endpoint,
+			Subnet:   previous.Subnet,
+			Probe:    previous.Probe,
+			Before:   previous.Status,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		return a.Endpoint+"|"+a.Subnet+"|"+a.Probe < b.Endpoint+"|"+b.Subnet+"|"+b.Probe
+	})
+	return changes
+}
+
+func loadEgressReport(path string) (*egressReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline %s: %w", path, err)
+	}
+
+	report := &egressReport{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return report, nil
+}
+
+func (r *egressReport) save(path string) error {
+	baseline := *r
+	baseline.Changes = nil
+
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write baseline %s: %w", path, err)
+	}
+	return nil
+}
+
+func (r *egressReport) render(w io.Writer, format string) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case "junit":
+		return r.renderJUnit(w)
+	default:
+		return r.renderText(w)
+	}
+}
+
+// renderText prints the matrix with one row per endpoint and one column per subnet and probe
+func (r *egressReport) renderText(w io.Writer) error {
+	cells := map[string]egressResult{}
+	for _, result := range r.Results {
+		cells[result.key()] = result
+	}
+
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	header := []string{"ENDPOINT"}
+	for _, run := range r.Runs {
+		header = append(header, strings.ToUpper(run.Subnet+"/"+run.Probe))
+	}
+	p.AddRow(header)
+
+	for _, endpoint := range r.endpoints() {
+		row := []string{endpoint}
+		for _, run := range r.Runs {
+			result, ok := cells[endpoint+"|"+run.Subnet+"|"+run.Probe]
+			switch {
+			case !ok:
+				row = append(row, "-")
+			case result.LatencyMs > 0:
+				row = append(row, fmt.Sprintf("%s (%dms)", result.Status, int64(result.LatencyMs)))
+			default:
+				row = append(row, string(result.Status))
+			}
+		}
+		p.AddRow(row)
+	}
+
+	durations := []string{"DURATION"}
+	for _, run := range r.Runs {
+		durations = append(durations, (time.Duration(run.DurationMs) * time.Millisecond).String())
+	}
+	p.AddRow(durations)
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	for _, run := range r.Runs {
+		for _, err := range run.Errors {
+			fmt.Fprintf(w, "%s/%s: %s\n", run.Subnet, run.Probe, err)
+		}
+	}
+
+	if r.Changes != nil {
+		fmt.Fprintln(w, "\nChanges since baseline:")
+		for _, change := range r.Changes {
+			prefix := "  "
+			if change.regression() {
+				prefix = "! "
+			}
+			fmt.Fprintf(w, "%s%s %s/%s: %s -> %s\n", prefix, change.Endpoint, change.Subnet, change.Probe,
+				statusOrNone(change.Before), statusOrNone(change.After))
+		}
+	}
+	return nil
+}
+
+func statusOrNone(status egressStatus) string {
+	if status == "" {
+		return "none"
+	}
+	return string(status)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+// renderJUnit prints one test suite per subnet and probe, with a test case per endpoint
+func (r *egressReport) renderJUnit(w io.Writer) error {
+	regressions := map[string]egressChange{}
+	for _, change := range r.Changes {
+		if change.regression() {
+			regressions[change.Endpoint+"|"+change.Subnet+"|"+change.Probe] = change
+		}
+	}
+
+	suites := junitTestSuites{Name: "osdctl network verify-egress"}
+	for _, run := range r.Runs {
+		suite := junitTestSuite{
+			Name:      run.Subnet + "/" + run.Probe,
+			Time:      seconds(float64(run.DurationMs)),
+			Timestamp: r.Timestamp.UTC().Format(time.RFC3339),
+		}
+
+		for _, result := range r.Results {
+			if result.Subnet != run.Subnet || result.Probe != run.Probe {
+				continue
+			}
+			testCase := junitTestCase{
+				Name:      result.Endpoint,
+				ClassName: suite.Name,
+				Time:      seconds(result.LatencyMs),
+			}
+			if result.Status != egressPass {
+				message := result.Message
+				if change, ok := regressions[result.key()]; ok {
+					message = strings.TrimSpace(fmt.Sprintf("regressed from %s since baseline %s", statusOrNone(change.Before), message))
+				}
+				testCase.Failure = &junitMessage{Message: message, Type: string(result.Status)}
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+		for _, err := range run.Errors {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      "verifier",
+				ClassName: suite.Name,
+				Time:      seconds(0),
+				Error:     &junitMessage{Message: err, Type: "error"},
+			})
+			suite.Errors++
+		}
+
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func seconds(ms float64) string {
+	return strconv.FormatFloat(ms/1000, 'f', 3, 64)
+}
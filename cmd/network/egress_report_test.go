@@ -0,0 +1,204 @@
+package network
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/osd-network-verifier/pkg/output"
+	"github.com/openshift/osd-network-verifier/pkg/probes/curl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// curlOutput builds the output of a curl probe run the way the probe reports it
+func curlOutput(results ...curl.CurlJSONProbeResult) *output.Output {
+	out := &output.Output{}
+	for _, result := range results {
+		out.AddDebugLogs(fmt.Sprintf("%+v\n", result))
+		if !result.IsSuccessfulConnection() {
+			out.SetEgressFailures([]string{fmt.Sprintf("%s (%s)", result.URL, result.ErrorMsg)})
+		}
+	}
+	return out
+}
+
+func legacyOutput(failures ...string) *output.Output {
+	out := &output.Output{}
+	out.SetEgressFailures(failures)
+	return out
+}
+
+func TestEgressReport_AddRun(t *testing.T) {
+	report := &egressReport{}
+	report.addRun("subnet-a", "curl", 3*time.Second, curlOutput(
+		curl.CurlJSONProbeResult{Scheme: "HTTPS", URL: "https://a.example.com:443", TimeTotal: 0.25},
+		curl.CurlJSONProbeResult{URL: "https://b.example.com:443", ExitCode: 7, ErrorMsg: "Failed to connect to b.example.com port 443"},
+		curl.CurlJSONProbeResult{URL: "https://c.example.com:443", ExitCode: 28, ErrorMsg: "Connection timed out after 2000 milliseconds", TimeTotal: 2},
+	))
+	report.addRun("subnet-a", "legacy", time.Second, legacyOutput("b.example.com:443"))
+	report.complete()
+
+	assert.Equal(t, []egressRun{
+		{Subnet: "subnet-a", Probe: "curl", DurationMs: 3000, perEndpoint: true},
+		{Subnet: "subnet-a", Probe: "legacy", DurationMs: 1000},
+	}, report.Runs)
+
+	assert.Equal(t, []egressResult{
+		{Endpoint: "b.example.com:443", Subnet: "subnet-a", Probe: "legacy", Status: egressFail},
+		{Endpoint: "https://a.example.com:443", Subnet: "subnet-a", Probe: "curl", Status: egressPass, LatencyMs: 250},
+		{Endpoint: "https://a.example.com:443", Subnet: "subnet-a", Probe: "legacy", Status: egressPass},
+		{Endpoint: "https://b.example.com:443", Subnet: "subnet-a", Probe: "curl", Status: egressFail, Message: "Failed to connect to b.example.com port 443"},
+		{Endpoint: "https://b.example.com:443", Subnet: "subnet-a", Probe: "legacy", Status: egressPass},
+		{Endpoint: "https://c.example.com:443", Subnet: "subnet-a", Probe: "curl", Status: egressTimeout, LatencyMs: 2000, Message: "Connection timed out after 2000 milliseconds"},
+		{Endpoint: "https://c.example.com:443", Subnet: "subnet-a", Probe: "legacy", Status: egressPass},
+	}, report.Results)
+	assert.True(t, report.failed())
+}
+
+func TestEgressReport_AddRunErrors(t *testing.T) {
+	out := legacyOutput("a.example.com:443")
+	out.AddError(fmt.Errorf("instance failed to start"))
+
+	report := &egressReport{}
+	report.addRun("", "curl", time.Second, curlOutput(curl.CurlJSONProbeResult{Scheme: "HTTPS", URL: "https://b.example.com:443"}))
+	report.addRun("", "legacy", time.Second, out)
+	report.complete()
+
+	require.Len(t, report.Runs, 2)
+	assert.Equal(t, podModeSubnet, report.Runs[0].Subnet)
+	assert.Equal(t, []string{"network verifier error: instance failed to start"}, report.Runs[1].Errors)
+
+	// The legacy run did not complete, so the endpoint only tested by curl is not assumed to pass there
+	for _, result := range report.Results {
+		assert.False(t, result.Probe == "legacy" && result.Endpoint == "https://b.example.com:443", "unexpected result %+v", result)
+	}
+}
+
+func TestParseEgressFailure(t *testing.T) {
+	tests := []struct {
+		failure  string
+		endpoint string
+		message  string
+	}{
+		{failure: "https://a.example.com:443 (Could not resolve host: a.example.com)", endpoint: "https://a.example.com:443", message: "Could not resolve host: a.example.com"},
+		{failure: "tcp://a.example.com:9997 (Operation timed out)", endpoint: "tcp://a.example.com:9997", message: "Operation timed out"},
+		{failure: "a.example.com:443", endpoint: "a.example.com:443"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.failure, func(t *testing.T) {
+			endpoint, message := parseEgressFailure(tt.failure)
+			assert.Equal(t, tt.endpoint, endpoint)
+			assert.Equal(t, tt.message, message)
+		})
+	}
+}
+
+func TestEgressReport_Diff(t *testing.T) {
+	baseline := &egressReport{Results: []egressResult{
+		{Endpoint: "a", Subnet: "s1", Probe: "curl", Status: egressPass},
+		{Endpoint: "b", Subnet: "s1", Probe: "curl", Status: egressFail},
+		{Endpoint: "c", Subnet: "s1", Probe: "curl", Status: egressPass},
+		{Endpoint: "d", Subnet: "s1", Probe: "curl", Status: egressFail},
+		{Endpoint: "e", Subnet: "s1", Probe: "curl", Status: egressPass},
+	}}
+	current := &egressReport{Results: []egressResult{
+		{Endpoint: "a", Subnet: "s1", Probe: "curl", Status: egressTimeout},
+		{Endpoint: "b", Subnet: "s1", Probe: "curl", Status: egressPass},
+		{Endpoint: "c", Subnet: "s1", Probe: "curl", Status: egressPass},
+		{Endpoint: "f", Subnet: "s1", Probe: "curl", Status: egressFail},
+		{Endpoint: "g", Subnet: "s1", Probe: "curl", Status: egressPass},
+	}}
+
+	changes := current.diff(baseline)
+	assert.Equal(t, []egressChange{
+		{Endpoint: "a", Subnet: "s1", Probe: "curl", Before: egressPass, After: egressTimeout},
+		{Endpoint: "b", Subnet: "s1", Probe: "curl", Before: egressFail, After: egressPass},
+		{Endpoint: "d", Subnet: "s1", Probe: "curl", Before: egressFail},
+		{Endpoint: "f", Subnet: "s1", Probe: "curl", After: egressFail},
+	}, changes)
+
+	var regressions []string
+	for _, change := range changes {
+		if change.regression() {
+			regressions = append(regressions, change.Endpoint)
+		}
+	}
+	assert.Equal(t, []string{"a", "f"}, regressions)
+}
+
+func TestEgressReport_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	report := &egressReport{
+		ClusterID: "abc",
+		Platform:  "aws-classic",
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Runs:      []egressRun{{Subnet: "s1", Probe: "curl", DurationMs: 10}},
+		Results:   []egressResult{{Endpoint: "a", Subnet: "s1", Probe: "curl", Status: egressPass, LatencyMs: 12}},
+		Changes:   []egressChange{{Endpoint: "a", Subnet: "s1", Probe: "curl", After: egressPass}},
+	}
+	require.NoError(t, report.save(path))
+
+	loaded, err := loadEgressReport(path)
+	require.NoError(t, err)
+	assert.Equal(t, report.Results, loaded.Results)
+	assert.Equal(t, report.Runs, loaded.Runs)
+	assert.Nil(t, loaded.Changes)
+
+	_, err = loadEgressReport(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestEgressReport_Render(t *testing.T) {
+	report := &egressReport{
+		Runs: []egressRun{
+			{Subnet: "s1", Probe: "curl", DurationMs: 1500},
+			{Subnet: "s1", Probe: "legacy", DurationMs: 2000, Errors: []string{"boom"}},
+		},
+		Results: []egressResult{
+			{Endpoint: "a", Subnet: "s1", Probe: "curl", Status: egressPass, LatencyMs: 120},
+			{Endpoint: "b", Subnet: "s1", Probe: "curl", Status: egressTimeout, Message: "Operation timed out"},
+		},
+		Changes: []egressChange{{Endpoint: "b", Subnet: "s1", Probe: "curl", Before: egressPass, After: egressTimeout}},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.render(&buf, "text"))
+		out := buf.String()
+		assert.Contains(t, out, "S1/CURL")
+		assert.Contains(t, out, "pass (120ms)")
+		assert.Contains(t, out, "s1/legacy: boom")
+		assert.Contains(t, out, "! b s1/curl: pass -> timeout")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.render(&buf, "json"))
+		assert.Contains(t, buf.String(), `"status": "timeout"`)
+		assert.Contains(t, buf.String(), `"changes"`)
+	})
+
+	t.Run("junit", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.render(&buf, "junit"))
+		assert.True(t, strings.HasPrefix(buf.String(), xml.Header))
+
+		var suites junitTestSuites
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+		assert.Equal(t, 3, suites.Tests)
+		assert.Equal(t, 1, suites.Failures)
+		assert.Equal(t, 1, suites.Errors)
+		require.Len(t, suites.Suites, 2)
+		require.Len(t, suites.Suites[0].TestCases, 2)
+		assert.Equal(t, "0.120", suites.Suites[0].TestCases[0].Time)
+		require.NotNil(t, suites.Suites[0].TestCases[1].Failure)
+		assert.Equal(t, "timeout", suites.Suites[0].TestCases[1].Failure.Type)
+		assert.Equal(t, "regressed from pass since baseline Operation timed out", suites.Suites[0].TestCases[1].Failure.Message)
+	})
+}
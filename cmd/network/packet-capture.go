@@ -8,7 +8,10 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -36,15 +39,48 @@ const (
 	nodeLabelValue           = ""
 	packetCaptureDurationSec = 60
 	singlePod                = false
+	captureOutputPath        = "/tmp/capture-output"
+	captureFileName          = "capture.pcap"
+	captureTimeFormat        = "20060102T150405"
+	targetPodAnnotation      = "osdctl.openshift.io/target-pod"
+	targetPodInterface       = "eth0"
+	summaryTopTalkers        = 10
+	// captureWaitMargin is added to the capture duration to schedule the capture pods and pull their image
+	captureWaitMargin = 600 * time.Second
 )
 
 // newCmdPacketCapture implements the packet-capture command to run a packet capture
 func newCmdPacketCapture(streams genericclioptions.IOStreams, client *k8s.LazyClient) *cobra.Command {
 	ops := newPacketCaptureOptions(streams, client)
 	packetCaptureCmd := &cobra.Command{
-		Use:               "packet-capture",
-		Aliases:           []string{"pcap"},
-		Short:             "Start packet capture",
+		Use:     "packet-capture",
+		Aliases: []string{"pcap"},
+		Short:   "Start packet capture",
+		Long: `Start packet capture
+
+  Runs tcpdump on every worker node (or a single node with --single-pod) for --duration seconds, copies the
+  captures to ./capture-output and prints a summary of them: top talkers, TCP resets and retransmits, and
+  DNS failures.
+
+  --filter takes a tcpdump BPF expression to only capture the traffic of interest. --file-size enables
+  ring-buffer rotation, keeping at most --file-count files of --file-size MB per node, so long captures
+  cannot fill the node disk. --target-pod captures in the network namespace of the given pods instead,
+  on the nodes they run on.`,
+		Example: `
+  # Capture DNS traffic on all workers for 2 minutes
+  osdctl network packet-capture --duration 120 --filter 'port 53'
+
+  # Capture on eth0 instead of the overlay interface, on one node ens5
+  osdctl network packet-capture --interface eth0 --node-interface ip-10-0-1-2.ec2.internal=ens5
+
+  # Capture for an hour, keeping at most 5 files of 100MB per node
+  osdctl network packet-capture --duration 3600 --file-size 100 --file-count 5
+
+  # Capture the traffic of two pods in their network namespaces
+  osdctl network packet-capture --target-pod openshift-ingress/router-default-abc --target-pod my-ns/my-pod
+
+  # Summarize previously retrieved captures without capturing again
+  osdctl network packet-capture summarize capture-output/*.pcap`,
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -60,6 +96,17 @@ func newCmdPacketCapture(streams genericclioptions.IOStreams, client *k8s.LazyCl
 	packetCaptureCmd.Flags().StringVarP(&ops.nodeLabelValue, "node-label-value", "", nodeLabelValue, "Node label value")
 	packetCaptureCmd.Flags().BoolVarP(&ops.singlePod, "single-pod", "", singlePod, "toggle deployment as single pod (default: deploy a daemonset)")
 	packetCaptureCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for this command, which requires elevation, to be run (usualy an OHSS or PD ticket)")
+	packetCaptureCmd.Flags().StringVarP(&ops.filter, "filter", "f", "", "tcpdump BPF filter expression, e.g. 'tcp port 443 and host 10.0.0.1'")
+	packetCaptureCmd.Flags().StringVar(&ops.interfaceOverride, "interface", "", "Interface to capture on (default: the overlay interface of the cluster network type, or eth0 for --target-pod)")
+	packetCaptureCmd.Flags().StringToStringVar(&ops.nodeInterfaces, "node-interface", map[string]string{}, "Interface to capture on for specific nodes, as node=interface")
+	packetCaptureCmd.Flags().IntVar(&ops.fileSizeMB, "file-size", 0, "Rotate capture files when they reach this size in MB (default: no rotation)")
+	packetCaptureCmd.Flags().IntVar(&ops.fileCount, "file-count", 3, "Number of rotated capture files to keep per node, requires --file-size")
+	packetCaptureCmd.Flags().StringArrayVar(&ops.targetPods, "target-pod", nil, "Capture in the network namespace of a pod, as namespace/name. Can be specified multiple times")
+	packetCaptureCmd.Flags().BoolVar(&ops.summarize, "summarize", true, "Summarize the retrieved captures")
+	packetCaptureCmd.MarkFlagsMutuallyExclusive("target-pod", "single-pod")
+	packetCaptureCmd.MarkFlagsMutuallyExclusive("target-pod", "node-interface")
+
+	packetCaptureCmd.AddCommand(newCmdSummarizePacketCapture(streams))
 
 	ops.startTime = time.Now()
 	return packetCaptureCmd
@@ -76,6 +123,14 @@ type packetCaptureOptions struct {
 	captureInterface string
 	reason           string
 
+	filter            string
+	interfaceOverride string
+	nodeInterfaces    map[string]string
+	fileSizeMB        int
+	fileCount         int
+	targetPods        []string
+	summarize         bool
+
 	genericclioptions.IOStreams
 	kubeCli   *k8s.LazyClient
 	startTime time.Time
@@ -89,6 +144,17 @@ func newPacketCaptureOptions(streams genericclioptions.IOStreams, client *k8s.La
 }
 
 func (o *packetCaptureOptions) complete(cmd *cobra.Command, _ []string) error {
+	if o.fileSizeMB < 0 {
+		return fmt.Errorf("--file-size must not be negative")
+	}
+	if o.fileSizeMB > 0 && o.fileCount < 1 {
+		return fmt.Errorf("--file-count must be at least 1")
+	}
+	for _, target := range o.targetPods {
+		if _, err := parseTargetPod(target); err != nil {
+			return err
+		}
+	}
 	if len(o.reason) > 0 {
 		// This action requires elevation
 		o.kubeCli.Impersonate("backplane-cluster-admin", o.reason, fmt.Sprintf("Elevation required to capture network"))
@@ -97,78 +163,83 @@ func (o *packetCaptureOptions) complete(cmd *cobra.Command, _ []string) error {
 }
 
 func (o *packetCaptureOptions) run() error {
-	if o.singlePod {
-		return o.runPod()
+	var err error
+	switch {
+	case len(o.targetPods) > 0:
+		err = o.runTargetPods()
+	case o.singlePod:
+		err = o.runPod()
+	default:
+		err = o.runDaemonSet()
 	}
-	return o.runDaemonSet()
+	if err != nil {
+		return err
+	}
+
+	if !o.summarize {
+		return nil
+	}
+	files, err := capturedFiles(outputDir, o.startTime)
+	if err != nil {
+		return err
+	}
+	return summarizeCaptureFiles(o.Out, files, summaryTopTalkers)
 }
 
 func (o *packetCaptureOptions) runDaemonSet() error {
 	log.Println("Confirming the interface for capturing")
-	err := setCaptureInterface(o)
-	if err != nil {
-		log.Fatalf("Error setting the interface for capture")
-		return err
+	if err := setCaptureInterface(o); err != nil {
+		return fmt.Errorf("error setting the interface for capture: %w", err)
 	}
 
 	log.Println("Ensuring Packet Capture Daemonset")
 	ds, err := ensurePacketCaptureDaemonSet(o)
 	if err != nil {
-		log.Fatalf("Error ensuring packet capture daemonset %v", err)
-		return err
+		return fmt.Errorf("error ensuring packet capture daemonset: %w", err)
 	}
+	defer func() {
+		log.Println("Deleting Packet Capture Daemonset")
+		if err := deletePacketCaptureDaemonSet(o, ds); err != nil {
+			log.Println(err)
+		}
+	}()
+
 	log.Println("Waiting For Packet Capture Daemonset")
-	err = waitForPacketCaptureDaemonset(o, ds)
-	if err != nil {
-		log.Fatalf("Error Waiting for daemonset %v", err)
-		return err
+	if err := waitForPacketCaptureDaemonset(o, ds); err != nil {
+		return fmt.Errorf("error waiting for daemonset: %w", err)
 	}
 	log.Println("Copying Files From Packet Capture Pods")
-	err = copyFilesFromPacketCapturePods(o)
-	if err != nil {
-		log.Fatalf("Error copying files %v", err)
-		return err
-	}
-	log.Println("Deleting Packet Capture Daemonset")
-	err = deletePacketCaptureDaemonSet(o, ds)
-	if err != nil {
-		log.Fatalf("Error deleting packet capture daemonset %v", err)
-		return err
+	if err := copyFilesFromPacketCapturePods(o); err != nil {
+		return fmt.Errorf("error copying files: %w", err)
 	}
 	return nil
 }
 
 func (o *packetCaptureOptions) runPod() error {
 	log.Println("Confirming the interface for capturing")
-	err := setCaptureInterface(o)
-	if err != nil {
-		log.Fatalf("Error setting the interface for capture")
-		return err
+	if err := setCaptureInterface(o); err != nil {
+		return fmt.Errorf("error setting the interface for capture: %w", err)
 	}
 
-	log.Println("Ensuring Packet Capture Daemonset")
+	log.Println("Ensuring Packet Capture Pod")
 	capturePod, err := ensurePacketCapturePod(o)
 	if err != nil {
-		log.Fatalf("Error ensuring packet capture Pod %v", err)
-		return err
+		return fmt.Errorf("error ensuring packet capture Pod: %w", err)
 	}
+	defer func() {
+		log.Println("Deleting Packet Capture Pod")
+		if err := deletePacketCapturePod(o, capturePod); err != nil {
+			log.Println(err)
+		}
+	}()
+
 	log.Println("Waiting For Packet Capture Pod")
-	err = waitForPacketCapturePod(o, capturePod)
-	if err != nil {
-		log.Fatalf("Error Waiting for daemonset %v", err)
-		return err
+	if err := waitForPacketCapturePod(o, capturePod); err != nil {
+		return fmt.Errorf("error waiting for Pod: %w", err)
 	}
 	log.Println("Copying Files From Packet Capture Pods")
-	err = copyFilesFromPacketCapturePods(o)
-	if err != nil {
-		log.Fatalf("Error copying files %v", err)
-		return err
-	}
-	log.Println("Deleting Packet Capture Pod")
-	err = deletePacketCapturePod(o, capturePod)
-	if err != nil {
-		log.Fatalf("Error deleting packet capture daemonset %v", err)
-		return err
+	if err := copyFilesFromPacketCapturePods(o); err != nil {
+		return fmt.Errorf("error copying files: %w", err)
 	}
 	return nil
 }
@@ -179,8 +250,7 @@ func ensurePacketCaptureDaemonSet(o *packetCaptureOptions) (*appsv1.DaemonSet, e
 	desired := desiredPacketCaptureDaemonSet(o, key)
 	haveDs, err := hasPacketCaptureDaemonSet(o, key)
 	if err != nil {
-		log.Fatalf("Error getting current daemonset %v", err)
-		return nil, err
+		return nil, fmt.Errorf("error getting current daemonset: %w", err)
 	}
 
 	if haveDs {
@@ -190,7 +260,6 @@ func ensurePacketCaptureDaemonSet(o *packetCaptureOptions) (*appsv1.DaemonSet, e
 
 	err = createPacketCaptureDaemonSet(o, desired)
 	if err != nil {
-		log.Fatalf("Error creating packet capture daemonset %v", err)
 		return nil, err
 	}
 
@@ -265,12 +334,13 @@ func desiredPacketCaptureDaemonSet(o *packetCaptureOptions, key types.Namespaced
 			Name:            "init-capture",
 			Image:           packetCaptureImage,
 			ImagePullPolicy: corev1.PullIfNotPresent,
-			Command:         []string{"/bin/bash", "-c", "tcpdump -G " + strconv.Itoa(o.duration) + " -W 1 -w /tmp/capture-output/capture.pcap -i " + o.captureInterface + " -nn -s0; sync"},
+			Command:         []string{"/bin/bash", "-c", o.captureCommand(nil)},
+			Env:             []corev1.EnvVar{nodeNameEnvVar()},
 			SecurityContext: &corev1.SecurityContext{Privileged: &t},
 			VolumeMounts: []corev1.VolumeMount{
 				{
 					Name:      "capture-output",
-					MountPath: "/tmp/capture-output",
+					MountPath: captureOutputPath,
 					ReadOnly:  false,
 				},
 			},
@@ -286,7 +356,7 @@ func desiredPacketCaptureDaemonSet(o *packetCaptureOptions, key types.Namespaced
 			VolumeMounts: []corev1.VolumeMount{
 				{
 					Name:      "capture-output",
-					MountPath: "/tmp/capture-output",
+					MountPath: captureOutputPath,
 					ReadOnly:  false,
 				},
 			},
@@ -301,8 +371,20 @@ func copyFilesFromPod(o *packetCaptureOptions, pod *corev1.Pod) error {
 	if err != nil {
 		return err
 	}
-	fileName := fmt.Sprintf("%s-%s.pcap", pod.Spec.NodeName, o.startTime.UTC().Format("20060102T150405"))
-	cmd := exec.Command("oc", "cp", pod.Namespace+"/"+pod.Name+":/tmp/capture-output/capture.pcap", outputDir+"/"+fileName, "--as", "backplane-cluster-admin") //#nosec G204 -- Subprocess launched with a potential tainted input or cmd arguments
+	baseName := pod.Spec.NodeName
+	if target, ok := pod.Annotations[targetPodAnnotation]; ok {
+		baseName += "-" + strings.ReplaceAll(target, "/", "-")
+	}
+	baseName += "-" + o.startTime.UTC().Format(captureTimeFormat)
+
+	// Rotated captures are numbered capture.pcap0, capture.pcap1..., so copy the whole directory
+	src := pod.Namespace + "/" + pod.Name + ":" + captureOutputPath + "/" + captureFileName
+	dst := outputDir + "/" + baseName + ".pcap"
+	if o.fileSizeMB > 0 {
+		src = pod.Namespace + "/" + pod.Name + ":" + captureOutputPath
+		dst = outputDir + "/" + baseName
+	}
+	cmd := exec.Command("oc", "cp", src, dst, "--as", "backplane-cluster-admin") //#nosec G204 -- Subprocess launched with a potential tainted input or cmd arguments
 	var stdBuffer bytes.Buffer
 	mw := io.MultiWriter(os.Stdout, &stdBuffer)
 
@@ -318,8 +400,13 @@ func copyFilesFromPod(o *packetCaptureOptions, pod *corev1.Pod) error {
 	return err
 }
 
+// waitTimeout returns how long to wait for the capture pods, which only start once the capture is over
+func (o *packetCaptureOptions) waitTimeout() time.Duration {
+	return time.Duration(o.duration)*time.Second + captureWaitMargin
+}
+
 func waitForPacketCaptureDaemonset(o *packetCaptureOptions, ds *appsv1.DaemonSet) error {
-	pollErr := wait.PollImmediate(10*time.Second, time.Duration(600)*time.Second, func() (bool, error) {
+	pollErr := wait.PollImmediate(10*time.Second, o.waitTimeout(), func() (bool, error) {
 		var err error
 		tmp := &appsv1.DaemonSet{}
 		key := types.NamespacedName{Name: ds.Name, Namespace: ds.Namespace}
@@ -335,7 +422,7 @@ func waitForPacketCaptureDaemonset(o *packetCaptureOptions, ds *appsv1.DaemonSet
 }
 
 func waitForPacketCaptureContainerRunning(o *packetCaptureOptions, pod *corev1.Pod) error {
-	pollErr := wait.PollImmediate(10*time.Second, time.Duration(600)*time.Second, func() (bool, error) {
+	pollErr := wait.PollImmediate(10*time.Second, o.waitTimeout(), func() (bool, error) {
 		var err error
 		tmp := &corev1.Pod{}
 		key := types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}
@@ -365,16 +452,12 @@ func copyFilesFromPacketCapturePods(o *packetCaptureOptions) error {
 		if len(pod.Status.ContainerStatuses) == 0 {
 			continue
 		}
-		err := waitForPacketCaptureContainerRunning(o, &pods.Items[i])
-		if err != nil {
-			log.Fatalf("Error waiting for pods %v", err)
-			return err
+		if err := waitForPacketCaptureContainerRunning(o, &pods.Items[i]); err != nil {
+			return fmt.Errorf("error waiting for Pod %s: %w", pod.Name, err)
 		}
 		log.Printf("Copying files from %s\n", pod.Name)
-		err = copyFilesFromPod(o, &pods.Items[i])
-		if err != nil {
-			log.Fatalf("error copying files %v", err)
-			return err
+		if err := copyFilesFromPod(o, &pods.Items[i]); err != nil {
+			return fmt.Errorf("error copying files from Pod %s: %w", pod.Name, err)
 		}
 	}
 
@@ -417,12 +500,13 @@ func desiredPacketCapturePod(o *packetCaptureOptions, key types.NamespacedName)
 			Name:            "init-capture",
 			Image:           packetCaptureImage,
 			ImagePullPolicy: corev1.PullIfNotPresent,
-			Command:         []string{"/bin/bash", "-c", "tcpdump -G " + strconv.Itoa(o.duration) + " -W 1 -w /tmp/capture-output/capture.pcap -i " + o.captureInterface + " -nn -s0; sync"},
+			Command:         []string{"/bin/bash", "-c", o.captureCommand(nil)},
+			Env:             []corev1.EnvVar{nodeNameEnvVar()},
 			SecurityContext: &corev1.SecurityContext{Privileged: &t},
 			VolumeMounts: []corev1.VolumeMount{
 				{
 					Name:      "capture-output",
-					MountPath: "/tmp/capture-output",
+					MountPath: captureOutputPath,
 					ReadOnly:  false,
 				},
 			},
@@ -438,7 +522,7 @@ func desiredPacketCapturePod(o *packetCaptureOptions, key types.NamespacedName)
 			VolumeMounts: []corev1.VolumeMount{
 				{
 					Name:      "capture-output",
-					MountPath: "/tmp/capture-output",
+					MountPath: captureOutputPath,
 					ReadOnly:  false,
 				},
 			},
@@ -474,8 +558,7 @@ func ensurePacketCapturePod(o *packetCaptureOptions) (*corev1.Pod, error) {
 	desired := desiredPacketCapturePod(o, key)
 	havePod, err := hasPacketCapturePod(o, key)
 	if err != nil {
-		log.Fatalf("Error getting current Pod %v", err)
-		return nil, err
+		return nil, fmt.Errorf("error getting current Pod: %w", err)
 	}
 
 	if havePod {
@@ -485,7 +568,6 @@ func ensurePacketCapturePod(o *packetCaptureOptions) (*corev1.Pod, error) {
 
 	err = createPacketCapturePod(o, desired)
 	if err != nil {
-		log.Fatalf("Error creating packet capture Pod %v", err)
 		return nil, err
 	}
 
@@ -503,7 +585,7 @@ func createPacketCapturePod(o *packetCaptureOptions, capturePod *corev1.Pod) err
 
 // waitForPacketCapturePod creates the given Pod resource
 func waitForPacketCapturePod(o *packetCaptureOptions, capturePod *corev1.Pod) error {
-	pollErr := wait.PollImmediate(10*time.Second, time.Duration(600)*time.Second, func() (bool, error) {
+	pollErr := wait.PollImmediate(10*time.Second, o.waitTimeout(), func() (bool, error) {
 		var err error
 		tmp := &corev1.Pod{}
 		key := types.NamespacedName{Name: capturePod.Name, Namespace: capturePod.Namespace}
@@ -533,3 +615,218 @@ func setCaptureInterface(o *packetCaptureOptions) error {
 		return fmt.Errorf("failed to determine network type. Network type %s unknown", networkConfig.Spec.NetworkType)
 	}
 }
+
+// captureTarget is a pod to capture the traffic of, in its own network namespace
+type captureTarget struct {
+	namespace string
+	name      string
+	node      string
+}
+
+func (t captureTarget) String() string {
+	return t.namespace + "/" + t.name
+}
+
+// parseTargetPod parses a --target-pod value of the form namespace/name
+func parseTargetPod(target string) (captureTarget, error) {
+	parts := strings.Split(target, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return captureTarget{}, fmt.Errorf("invalid target pod '%s', expecting namespace/name", target)
+	}
+	return captureTarget{namespace: parts[0], name: parts[1]}, nil
+}
+
+// shellQuote quotes s as a single bash word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func nodeNameEnvVar() corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: "NODE_NAME",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+		},
+	}
+}
+
+// captureCommand returns the bash script run by the capture init container. Without a target it captures
+// on the node, on the interface configured for the node it runs on. With a target it captures inside the
+// network namespace of the target pod, found through the container runtime of the node.
+func (o *packetCaptureOptions) captureCommand(target *captureTarget) string {
+	var script strings.Builder
+
+	iface := o.captureInterface
+	if target != nil {
+		iface = targetPodInterface
+	}
+	if o.interfaceOverride != "" {
+		iface = o.interfaceOverride
+	}
+	fmt.Fprintf(&script, "iface=%s\n", shellQuote(iface))
+
+	if target == nil && len(o.nodeInterfaces) > 0 {
+		nodes := make([]string, 0, len(o.nodeInterfaces))
+		for node := range o.nodeInterfaces {
+			nodes = append(nodes, node)
+		}
+		sort.Strings(nodes)
+
+		script.WriteString("case \"$NODE_NAME\" in\n")
+		for _, node := range nodes {
+			fmt.Fprintf(&script, "  %s) iface=%s ;;\n", shellQuote(node), shellQuote(o.nodeInterfaces[node]))
+		}
+		script.WriteString("esac\n")
+	}
+
+	prefix := ""
+	if target != nil {
+		fmt.Fprintf(&script, "sandbox=$(chroot /host crictl pods --namespace %s --name %s --state ready -q | head -n 1)\n",
+			shellQuote(target.namespace), shellQuote("^"+target.name+"$"))
+		script.WriteString("[ -n \"$sandbox\" ] || { echo 'target pod sandbox not found' >&2; exit 1; }\n")
+		script.WriteString("pid=$(chroot /host crictl inspectp -o go-template --template '{{.info.pid}}' \"$sandbox\")\n")
+		prefix = `nsenter -t "$pid" -n -- `
+	}
+
+	output := captureOutputPath + "/" + captureFileName
+	if o.fileSizeMB > 0 {
+		// tcpdump keeps a ring buffer of -W files of -C MB, the capture is stopped by timeout instead of -G
+		fmt.Fprintf(&script, "timeout -s INT %d %stcpdump -i \"$iface\" -nn -s0 -C %d -W %d -w %s", o.duration, prefix, o.fileSizeMB, o.fileCount, output)
+	} else {
+		fmt.Fprintf(&script, "%stcpdump -G %d -W 1 -w %s -i \"$iface\" -nn -s0", prefix, o.duration, output)
+	}
+	if o.filter != "" {
+		script.WriteString(" " + shellQuote(o.filter))
+	}
+	script.WriteString("; sync")
+
+	return script.String()
+}
+
+// resolveTargetPods looks up the nodes the target pods run on
+func (o *packetCaptureOptions) resolveTargetPods() ([]captureTarget, error) {
+	targets := make([]captureTarget, 0, len(o.targetPods))
+	for _, value := range o.targetPods {
+		target, err := parseTargetPod(value)
+		if err != nil {
+			return nil, err
+		}
+
+		pod := &corev1.Pod{}
+		if err := o.kubeCli.Get(context.TODO(), types.NamespacedName{Name: target.name, Namespace: target.namespace}, pod); err != nil {
+			return nil, fmt.Errorf("failed to get target pod %s: %v", target, err)
+		}
+		if pod.Spec.HostNetwork {
+			return nil, fmt.Errorf("target pod %s uses the host network, capture on its node %s instead", target, pod.Spec.NodeName)
+		}
+		if pod.Spec.NodeName == "" {
+			return nil, fmt.Errorf("target pod %s is not scheduled to a node", target)
+		}
+
+		target.node = pod.Spec.NodeName
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// desiredTargetPacketCapturePod returns a capture Pod pinned to the node of the target, with access to the
+// host processes and container runtime to enter the network namespace of the target
+func desiredTargetPacketCapturePod(o *packetCaptureOptions, key types.NamespacedName, target captureTarget) *corev1.Pod {
+	capturePod := desiredPacketCapturePod(o, key)
+	capturePod.Annotations = map[string]string{targetPodAnnotation: target.String()}
+	capturePod.Spec.NodeSelector = nil
+	capturePod.Spec.NodeName = target.node
+	capturePod.Spec.HostPID = true
+	// The target may run on any node, including control plane and infra nodes
+	capturePod.Spec.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+	capturePod.Spec.Volumes = append(capturePod.Spec.Volumes, corev1.Volume{
+		Name: "host",
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{Path: "/"},
+		},
+	})
+
+	initContainer := &capturePod.Spec.InitContainers[0]
+	initContainer.Command = []string{"/bin/bash", "-c", o.captureCommand(&target)}
+	initContainer.VolumeMounts = append(initContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      "host",
+		MountPath: "/host",
+	})
+
+	return capturePod
+}
+
+func (o *packetCaptureOptions) runTargetPods() error {
+	targets, err := o.resolveTargetPods()
+	if err != nil {
+		return err
+	}
+
+	var capturePods []*corev1.Pod
+	defer func() {
+		log.Println("Deleting Packet Capture Pods")
+		for _, capturePod := range capturePods {
+			if err := deletePacketCapturePod(o, capturePod); err != nil {
+				log.Println(err)
+			}
+		}
+	}()
+
+	for i, target := range targets {
+		key := types.NamespacedName{Name: o.name + "-" + strconv.Itoa(i), Namespace: o.namespace}
+		havePod, err := hasPacketCapturePod(o, key)
+		if err != nil {
+			return err
+		}
+		if havePod {
+			return fmt.Errorf("%s Pod already exists in the %s namespace", key.Name, key.Namespace)
+		}
+
+		log.Printf("Ensuring Packet Capture Pod for %s on %s\n", target, target.node)
+		capturePod := desiredTargetPacketCapturePod(o, key, target)
+		if err := createPacketCapturePod(o, capturePod); err != nil {
+			return err
+		}
+		capturePods = append(capturePods, capturePod)
+	}
+
+	log.Println("Waiting For Packet Capture Pods")
+	for _, capturePod := range capturePods {
+		if err := waitForPacketCapturePod(o, capturePod); err != nil {
+			return fmt.Errorf("error waiting for Pod %s: %v", capturePod.Name, err)
+		}
+	}
+
+	log.Println("Copying Files From Packet Capture Pods")
+	return copyFilesFromPacketCapturePods(o)
+}
+
+// capturedFiles returns the capture files retrieved into dir by a capture started at startTime
+func capturedFiles(dir string, startTime time.Time) ([]string, error) {
+	suffix := "-" + startTime.UTC().Format(captureTimeFormat)
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && !strings.HasSuffix(d.Name(), suffix) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// Single captures are named <node>-<time>.pcap, rotated ones are capture.pcapN in a <node>-<time> directory
+		rotated := filepath.Dir(path) != dir && strings.HasPrefix(d.Name(), captureFileName)
+		if rotated || strings.HasSuffix(d.Name(), suffix+".pcap") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list captures in %s: %v", dir, err)
+	}
+
+	return files, nil
+}
@@ -842,3 +842,154 @@ type LazyClient struct {
 func (m *MockKubeClient) ToLazyClient() *k8s.LazyClient {
 	return k8s.LazyClientMock(m)
 }
+
+func TestParseTargetPod(t *testing.T) {
+	target, err := parseTargetPod("openshift-dns/dns-default-abc")
+	assert.NoError(t, err)
+	assert.Equal(t, captureTarget{namespace: "openshift-dns", name: "dns-default-abc"}, target)
+
+	for _, invalid := range []string{"dns-default-abc", "/pod", "ns/", "a/b/c"} {
+		_, err := parseTargetPod(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestCaptureCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		ops      *packetCaptureOptions
+		target   *captureTarget
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "default",
+			ops:      &packetCaptureOptions{duration: 60, captureInterface: "genev_sys_6081"},
+			contains: []string{"iface='genev_sys_6081'", `tcpdump -G 60 -W 1 -w /tmp/capture-output/capture.pcap -i "$iface" -nn -s0; sync`},
+			excludes: []string{"timeout", "case", "nsenter"},
+		},
+		{
+			name: "filter_and_rotation",
+			ops: &packetCaptureOptions{
+				duration:         3600,
+				captureInterface: "genev_sys_6081",
+				filter:           "port 53 and host '10.0.0.1'",
+				fileSizeMB:       100,
+				fileCount:        5,
+			},
+			contains: []string{`timeout -s INT 3600 tcpdump -i "$iface" -nn -s0 -C 100 -W 5 -w /tmp/capture-output/capture.pcap 'port 53 and host '\''10.0.0.1'\'''; sync`},
+			excludes: []string{"-G"},
+		},
+		{
+			name: "node_interfaces",
+			ops: &packetCaptureOptions{
+				duration:          60,
+				captureInterface:  "genev_sys_6081",
+				interfaceOverride: "eth0",
+				nodeInterfaces:    map[string]string{"node-b": "ens6", "node-a": "ens5"},
+			},
+			contains: []string{"iface='eth0'\ncase \"$NODE_NAME\" in\n  'node-a') iface='ens5' ;;\n  'node-b') iface='ens6' ;;\nesac\n"},
+		},
+		{
+			name:   "target_pod",
+			ops:    &packetCaptureOptions{duration: 30, captureInterface: "genev_sys_6081", nodeInterfaces: map[string]string{"node-a": "ens5"}},
+			target: &captureTarget{namespace: "my-ns", name: "my-pod", node: "node-a"},
+			contains: []string{
+				"iface='eth0'",
+				"crictl pods --namespace 'my-ns' --name '^my-pod$' --state ready -q",
+				`nsenter -t "$pid" -n -- tcpdump -G 30`,
+			},
+			excludes: []string{"case"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := tt.ops.captureCommand(tt.target)
+			for _, s := range tt.contains {
+				assert.Contains(t, cmd, s)
+			}
+			for _, s := range tt.excludes {
+				assert.NotContains(t, cmd, s)
+			}
+		})
+	}
+}
+
+func TestDesiredTargetPacketCapturePod(t *testing.T) {
+	ops := &packetCaptureOptions{
+		name:             "test-capture",
+		namespace:        "test-ns",
+		nodeLabelKey:     "node-role.kubernetes.io/worker",
+		duration:         60,
+		captureInterface: "genev_sys_6081",
+	}
+	target := captureTarget{namespace: "my-ns", name: "my-pod", node: "node-a"}
+
+	key := types.NamespacedName{Name: "test-capture-0", Namespace: ops.namespace}
+	pod := desiredTargetPacketCapturePod(ops, key, target)
+
+	assert.Equal(t, "test-capture-0", pod.Name)
+	assert.Equal(t, map[string]string{"app": "test-capture-0"}, pod.Labels)
+	assert.Equal(t, "my-ns/my-pod", pod.Annotations[targetPodAnnotation])
+	assert.Equal(t, "node-a", pod.Spec.NodeName)
+	assert.Nil(t, pod.Spec.NodeSelector)
+	assert.True(t, pod.Spec.HostPID)
+	assert.Len(t, pod.Spec.Volumes, 2)
+	assert.Len(t, pod.Spec.InitContainers[0].VolumeMounts, 2)
+	assert.Contains(t, pod.Spec.InitContainers[0].Command[2], "nsenter")
+}
+
+func TestResolveTargetPods(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns"}, Spec: corev1.PodSpec{NodeName: "node-a"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "host", Namespace: "ns"}, Spec: corev1.PodSpec{NodeName: "node-b", HostNetwork: true}},
+	).Build()
+
+	ops := &packetCaptureOptions{kubeCli: k8s.LazyClientInit(fakeClient), targetPods: []string{"ns/app"}}
+	targets, err := ops.resolveTargetPods()
+	assert.NoError(t, err)
+	assert.Equal(t, []captureTarget{{namespace: "ns", name: "app", node: "node-a"}}, targets)
+
+	for _, invalid := range []string{"ns/host", "ns/missing"} {
+		ops.targetPods = []string{invalid}
+		_, err := ops.resolveTargetPods()
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestCapturedFiles(t *testing.T) {
+	dir := t.TempDir()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := startTime.Format(captureTimeFormat)
+
+	for _, file := range []string{
+		"node-a-" + ts + ".pcap",
+		"node-b-my-ns-my-pod-" + ts + ".pcap",
+		"node-a-20230101T000000.pcap",
+		"node-c-" + ts + "/capture.pcap0",
+		"node-c-" + ts + "/capture.pcap1",
+		"node-c-20230101T000000/capture.pcap0",
+	} {
+		path := dir + "/" + file
+		assert.NoError(t, os.MkdirAll(path[:strings.LastIndex(path, "/")], 0750))
+		assert.NoError(t, os.WriteFile(path, nil, 0600))
+	}
+
+	files, err := capturedFiles(dir, startTime)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		dir + "/node-a-" + ts + ".pcap",
+		dir + "/node-b-my-ns-my-pod-" + ts + ".pcap",
+		dir + "/node-c-" + ts + "/capture.pcap0",
+		dir + "/node-c-" + ts + "/capture.pcap1",
+	}, files)
+}
+
+func TestWaitTimeout(t *testing.T) {
+	// The capture pods only become ready after the capture, so long captures must not time out
+	o := &packetCaptureOptions{duration: 3600}
+	assert.Equal(t, 3600*time.Second+captureWaitMargin, o.waitTimeout())
+}
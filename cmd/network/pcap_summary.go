@@ -0,0 +1,474 @@
+package network
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// Link types of the captures taken by tcpdump, see https://www.tcpdump.org/linktypes.html
+const (
+	linkTypeEthernet = 1
+	linkTypeRaw      = 101
+	linkTypeLinuxSLL = 113
+	linkTypeIPv4     = 228
+	linkTypeIPv6     = 229
+	linkTypeSLL2     = 276
+
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+	etherTypeVLAN = 0x8100
+
+	ipProtoTCP = 6
+	ipProtoUDP = 17
+
+	tcpFlagFIN = 0x01
+	tcpFlagSYN = 0x02
+	tcpFlagRST = 0x04
+
+	// maxPcapRecordLen is well above the largest snapshot length tcpdump uses
+	maxPcapRecordLen = 1 << 20
+)
+
+// CoreDNS listens on 5353 in its pods, so DNS traffic captured after service translation uses that port
+var dnsPorts = map[uint16]bool{53: true, 5353: true}
+
+var dnsRcodes = map[int]string{
+	1: "FORMERR",
+	2: "SERVFAIL",
+	3: "NXDOMAIN",
+	4: "NOTIMP",
+	5: "REFUSED",
+}
+
+func newCmdSummarizePacketCapture(streams genericclioptions.IOStreams) *cobra.Command {
+	top := summaryTopTalkers
+	summarizeCmd := &cobra.Command{
+		Use:               "summarize <file>...",
+		Short:             "Summarize pcap files: top talkers, TCP resets and retransmits, and DNS failures",
+		Args:              cobra.MinimumNArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(summarizeCaptureFiles(streams.Out, args, top))
+		},
+	}
+	summarizeCmd.Flags().IntVar(&top, "top", summaryTopTalkers, "Number of entries to print per section")
+
+	return summarizeCmd
+}
+
+type talkerKey struct {
+	src string
+	dst string
+}
+
+type talkerStats struct {
+	packets int
+	bytes   int
+}
+
+// captureSummary aggregates the packets of one or more captures
+type captureSummary struct {
+	files   int
+	packets int
+	bytes   int
+	// skipped counts packets that are not IP, or of an unsupported link type
+	skipped int
+	first   time.Time
+	last    time.Time
+
+	talkers     map[talkerKey]*talkerStats
+	resets      map[string]int
+	retransmits map[string]int
+	dnsFailures map[string]int
+	// nextSeq is the sequence number following the highest byte seen for each TCP flow
+	nextSeq map[string]uint32
+}
+
+func newCaptureSummary() *captureSummary {
+	return &captureSummary{
+		talkers:     map[talkerKey]*talkerStats{},
+		resets:      map[string]int{},
+		retransmits: map[string]int{},
+		dnsFailures: map[string]int{},
+		nextSeq:     map[string]uint32{},
+	}
+}
+
+func summarizeCaptureFiles(w io.Writer, files []string, top int) error {
+	if len(files) == 0 {
+		return fmt.Errorf("no capture files to summarize")
+	}
+
+	summary := newCaptureSummary()
+	for _, file := range files {
+		if err := summary.addFile(file); err != nil {
+			return err
+		}
+	}
+
+	return summary.render(w, top)
+}
+
+func (s *captureSummary) addFile(path string) error {
+	f, err := os.Open(path) //#nosec G304 -- Reading a capture file requested by the user
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// The same flow is usually captured on both of its nodes, only track retransmits within a capture
+	s.nextSeq = map[string]uint32{}
+	if err := s.read(bufio.NewReader(f)); err != nil {
+		return fmt.Errorf("failed to read capture %s: %v", path, err)
+	}
+	s.files++
+	return nil
+}
+
+// read parses a capture in the classic pcap format written by tcpdump
+func (s *captureSummary) read(r io.Reader) error {
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("failed to read pcap header: %v", err)
+	}
+
+	var order binary.ByteOrder
+	nanos := false
+	switch binary.LittleEndian.Uint32(header) {
+	case 0xa1b2c3d4:
+		order = binary.LittleEndian
+	case 0xa1b23c4d:
+		order, nanos = binary.LittleEndian, true
+	case 0xd4c3b2a1:
+		order = binary.BigEndian
+	case 0x4d3cb2a1:
+		order, nanos = binary.BigEndian, true
+	default:
+		return fmt.Errorf("not a pcap file")
+	}
+	linkType := order.Uint32(header[20:]) & 0x0fffffff
+
+	record := make([]byte, 16)
+	for {
+		if _, err := io.ReadFull(r, record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			// tcpdump may be stopped while writing a packet, ignore the truncated last record
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		}
+
+		sec, frac := order.Uint32(record), order.Uint32(record[4:])
+		capLen, origLen := order.Uint32(record[8:]), order.Uint32(record[12:])
+		if capLen > maxPcapRecordLen {
+			return fmt.Errorf("invalid record length %d", capLen)
+		}
+		data := make([]byte, capLen)
+		if _, err := io.ReadFull(r, data); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		}
+
+		ts := time.Unix(int64(sec), int64(frac)*1000)
+		if nanos {
+			ts = time.Unix(int64(sec), int64(frac))
+		}
+		s.addPacket(linkType, ts, int(origLen), data)
+	}
+}
+
+func (s *captureSummary) addPacket(linkType uint32, ts time.Time, length int, data []byte) {
+	s.packets++
+	s.bytes += length
+	if s.first.IsZero() || ts.Before(s.first) {
+		s.first = ts
+	}
+	if ts.After(s.last) {
+		s.last = ts
+	}
+
+	etherType, payload, ok := linkPayload(linkType, data)
+	if !ok {
+		s.skipped++
+		return
+	}
+
+	var src, dst net.IP
+	var proto byte
+	switch etherType {
+	case etherTypeIPv4:
+		if len(payload) < 20 || payload[0]>>4 != 4 {
+			s.skipped++
+			return
+		}
+		headerLen := int(payload[0]&0x0f) * 4
+		totalLen := int(binary.BigEndian.Uint16(payload[2:]))
+		if headerLen < 20 || len(payload) < headerLen {
+			s.skipped++
+			return
+		}
+		src, dst, proto = net.IP(payload[12:16]), net.IP(payload[16:20]), payload[9]
+		payload = payload[headerLen:min(max(totalLen, headerLen), len(payload))]
+	case etherTypeIPv6:
+		if len(payload) < 40 || payload[0]>>4 != 6 {
+			s.skipped++
+			return
+		}
+		payloadLen := int(binary.BigEndian.Uint16(payload[4:]))
+		src, dst, proto = net.IP(payload[8:24]), net.IP(payload[24:40]), payload[6]
+		payload = payload[40:min(40+payloadLen, len(payload))]
+	default:
+		s.skipped++
+		return
+	}
+
+	key := talkerKey{src: src.String(), dst: dst.String()}
+	stats, ok := s.talkers[key]
+	if !ok {
+		stats = &talkerStats{}
+		s.talkers[key] = stats
+	}
+	stats.packets++
+	stats.bytes += length
+
+	switch proto {
+	case ipProtoTCP:
+		s.addTCP(key, payload)
+	case ipProtoUDP:
+		s.addUDP(payload)
+	}
+}
+
+// linkPayload returns the ethertype and network layer payload of a link layer frame
+func linkPayload(linkType uint32, data []byte) (uint16, []byte, bool) {
+	switch linkType {
+	case linkTypeEthernet:
+		if len(data) < 14 {
+			return 0, nil, false
+		}
+		etherType, offset := binary.BigEndian.Uint16(data[12:]), 14
+		for etherType == etherTypeVLAN && len(data) >= offset+4 {
+			etherType = binary.BigEndian.Uint16(data[offset+2:])
+			offset += 4
+		}
+		return etherType, data[offset:], true
+	case linkTypeLinuxSLL:
+		if len(data) < 16 {
+			return 0, nil, false
+		}
+		return binary.BigEndian.Uint16(data[14:]), data[16:], true
+	case linkTypeSLL2:
+		if len(data) < 20 {
+			return 0, nil, false
+		}
+		return binary.BigEndian.Uint16(data[0:]), data[20:], true
+	case linkTypeRaw, linkTypeIPv4, linkTypeIPv6:
+		if len(data) == 0 {
+			return 0, nil, false
+		}
+		if data[0]>>4 == 6 {
+			return etherTypeIPv6, data, true
+		}
+		return etherTypeIPv4, data, true
+	default:
+		return 0, nil, false
+	}
+}
+
+func (s *captureSummary) addTCP(key talkerKey, segment []byte) {
+	if len(segment) < 20 {
+		return
+	}
+	srcPort, dstPort := binary.BigEndian.Uint16(segment[0:]), binary.BigEndian.Uint16(segment[2:])
+	seq := binary.BigEndian.Uint32(segment[4:])
+	headerLen := int(segment[12]>>4) * 4
+	flags := segment[13]
+	if headerLen < 20 || headerLen > len(segment) {
+		return
+	}
+
+	flow := fmt.Sprintf("%s -> %s", net.JoinHostPort(key.src, strconv.Itoa(int(srcPort))), net.JoinHostPort(key.dst, strconv.Itoa(int(dstPort))))
+	if flags&tcpFlagRST != 0 {
+		s.resets[flow]++
+	}
+
+	// SYN and FIN each take up one sequence number
+	length := uint32(len(segment) - headerLen)
+	if flags&(tcpFlagSYN|tcpFlagFIN) != 0 {
+		length++
+	}
+	if length == 0 || flags&tcpFlagRST != 0 {
+		return
+	}
+
+	end := seq + length
+	next, seen := s.nextSeq[flow]
+	if seen && int32(end-next) <= 0 {
+		s.retransmits[flow]++
+		return
+	}
+	s.nextSeq[flow] = end
+}
+
+func (s *captureSummary) addUDP(datagram []byte) {
+	if len(datagram) < 8 {
+		return
+	}
+	srcPort := binary.BigEndian.Uint16(datagram[0:])
+	if !dnsPorts[srcPort] {
+		return
+	}
+
+	msg := datagram[8:]
+	if len(msg) < 12 {
+		return
+	}
+	flags := binary.BigEndian.Uint16(msg[2:])
+	isResponse := flags&0x8000 != 0
+	rcode := int(flags & 0x000f)
+	if !isResponse || rcode == 0 {
+		return
+	}
+
+	rcodeName, ok := dnsRcodes[rcode]
+	if !ok {
+		rcodeName = "RCODE" + strconv.Itoa(rcode)
+	}
+	name := "<unknown>"
+	if binary.BigEndian.Uint16(msg[4:]) > 0 {
+		if qname, ok := dnsQuestionName(msg[12:]); ok {
+			name = qname
+		}
+	}
+	s.dnsFailures[name+" "+rcodeName]++
+}
+
+// dnsQuestionName decodes the name of the first question of a DNS message. Question names are not compressed.
+func dnsQuestionName(data []byte) (string, bool) {
+	var labels []string
+	for i := 0; i < len(data); {
+		labelLen := int(data[i])
+		if labelLen == 0 {
+			return strings.Join(labels, ".") + ".", true
+		}
+		if labelLen&0xc0 != 0 || i+1+labelLen > len(data) {
+			return "", false
+		}
+		labels = append(labels, string(data[i+1:i+1+labelLen]))
+		i += 1 + labelLen
+	}
+	return "", false
+}
+
+type summaryRow struct {
+	key   string
+	count int
+}
+
+// topCounts returns the n highest counts, ties ordered by key
+func topCounts(counts map[string]int, n int) []summaryRow {
+	rows := make([]summaryRow, 0, len(counts))
+	for key, count := range counts {
+		rows = append(rows, summaryRow{key: key, count: count})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].count != rows[j].count {
+			return rows[i].count > rows[j].count
+		}
+		return rows[i].key < rows[j].key
+	})
+	if n > 0 && len(rows) > n {
+		rows = rows[:n]
+	}
+	return rows
+}
+
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
+
+func (s *captureSummary) render(w io.Writer, top int) error {
+	fmt.Fprintf(w, "Summary of %d capture file(s): %d packets, %d bytes", s.files, s.packets, s.bytes)
+	if s.packets > 0 {
+		fmt.Fprintf(w, " over %s", s.last.Sub(s.first).Round(time.Millisecond))
+	}
+	fmt.Fprintln(w)
+	if s.skipped > 0 {
+		fmt.Fprintf(w, "%d packet(s) were not IP or of an unsupported link type\n", s.skipped)
+	}
+
+	talkers := make([]talkerKey, 0, len(s.talkers))
+	for key := range s.talkers {
+		talkers = append(talkers, key)
+	}
+	sort.Slice(talkers, func(i, j int) bool {
+		a, b := s.talkers[talkers[i]], s.talkers[talkers[j]]
+		if a.bytes != b.bytes {
+			return a.bytes > b.bytes
+		}
+		return talkers[i].src+talkers[i].dst < talkers[j].src+talkers[j].dst
+	})
+	if top > 0 && len(talkers) > top {
+		talkers = talkers[:top]
+	}
+
+	fmt.Fprintln(w, "\nTop talkers:")
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	p.AddRow([]string{"SOURCE", "DESTINATION", "PACKETS", "BYTES"})
+	for _, key := range talkers {
+		stats := s.talkers[key]
+		p.AddRow([]string{key.src, key.dst, strconv.Itoa(stats.packets), strconv.Itoa(stats.bytes)})
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		title  string
+		header []string
+		counts map[string]int
+	}{
+		{title: "TCP resets", header: []string{"FLOW", "RESETS"}, counts: s.resets},
+		{title: "TCP retransmits", header: []string{"FLOW", "RETRANSMITS"}, counts: s.retransmits},
+		{title: "DNS failures", header: []string{"QUERY", "COUNT"}, counts: s.dnsFailures},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(w, "\n%s: %d\n", section.title, sumCounts(section.counts))
+		if len(section.counts) == 0 {
+			continue
+		}
+		p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+		p.AddRow(section.header)
+		for _, row := range topCounts(section.counts, top) {
+			p.AddRow([]string{row.key, strconv.Itoa(row.count)})
+		}
+		if err := p.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package network
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pcapWriter writes a little endian, microsecond resolution pcap
+type pcapWriter struct {
+	buf bytes.Buffer
+}
+
+func newPcapWriter(linkType uint32) *pcapWriter {
+	w := &pcapWriter{}
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint32(header[0:], 0xa1b2c3d4)
+	binary.LittleEndian.PutUint16(header[4:], 2)
+	binary.LittleEndian.PutUint16(header[6:], 4)
+	binary.LittleEndian.PutUint32(header[16:], 262144)
+	binary.LittleEndian.PutUint32(header[20:], linkType)
+	w.buf.Write(header)
+	return w
+}
+
+func (w *pcapWriter) write(ts time.Time, frame []byte) {
+	record := make([]byte, 16)
+	binary.LittleEndian.PutUint32(record[0:], uint32(ts.Unix()))
+	binary.LittleEndian.PutUint32(record[4:], uint32(ts.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(record[8:], uint32(len(frame)))
+	binary.LittleEndian.PutUint32(record[12:], uint32(len(frame)))
+	w.buf.Write(record)
+	w.buf.Write(frame)
+}
+
+func ethernetFrame(packet []byte) []byte {
+	frame := make([]byte, 14, 14+len(packet))
+	binary.BigEndian.PutUint16(frame[12:], etherTypeIPv4)
+	return append(frame, packet...)
+}
+
+func ipv4Packet(src, dst string, proto byte, payload []byte) []byte {
+	packet := make([]byte, 20, 20+len(payload))
+	packet[0] = 0x45
+	binary.BigEndian.PutUint16(packet[2:], uint16(20+len(payload)))
+	packet[9] = proto
+	copy(packet[12:], net.ParseIP(src).To4())
+	copy(packet[16:], net.ParseIP(dst).To4())
+	return append(packet, payload...)
+}
+
+func tcpSegment(srcPort, dstPort uint16, seq uint32, flags byte, payload []byte) []byte {
+	segment := make([]byte, 20, 20+len(payload))
+	binary.BigEndian.PutUint16(segment[0:], srcPort)
+	binary.BigEndian.PutUint16(segment[2:], dstPort)
+	binary.BigEndian.PutUint32(segment[4:], seq)
+	segment[12] = 5 << 4
+	segment[13] = flags
+	return append(segment, payload...)
+}
+
+func dnsResponse(name string, rcode uint16) []byte {
+	msg := make([]byte, 12)
+	binary.BigEndian.PutUint16(msg[2:], 0x8180|rcode)
+	binary.BigEndian.PutUint16(msg[4:], 1)
+	for _, label := range bytes.Split([]byte(name), []byte(".")) {
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0, 0, 1, 0, 1)
+
+	datagram := make([]byte, 8, 8+len(msg))
+	binary.BigEndian.PutUint16(datagram[0:], 53)
+	binary.BigEndian.PutUint16(datagram[2:], 40000)
+	binary.BigEndian.PutUint16(datagram[4:], uint16(8+len(msg)))
+	return append(datagram, msg...)
+}
+
+func testCapture() []byte {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := newPcapWriter(linkTypeEthernet)
+	data := bytes.Repeat([]byte("x"), 100)
+
+	packets := [][]byte{
+		ipv4Packet("10.0.0.1", "10.0.0.2", ipProtoTCP, tcpSegment(40000, 443, 1000, tcpFlagSYN, nil)),
+		ipv4Packet("10.0.0.1", "10.0.0.2", ipProtoTCP, tcpSegment(40000, 443, 1001, 0, data)),
+		ipv4Packet("10.0.0.1", "10.0.0.2", ipProtoTCP, tcpSegment(40000, 443, 1101, 0, data)),
+		// Retransmit of the second segment
+		ipv4Packet("10.0.0.1", "10.0.0.2", ipProtoTCP, tcpSegment(40000, 443, 1101, 0, data)),
+		ipv4Packet("10.0.0.2", "10.0.0.1", ipProtoTCP, tcpSegment(443, 40000, 5000, tcpFlagRST, nil)),
+		ipv4Packet("172.30.0.10", "10.0.0.1", ipProtoUDP, dnsResponse("missing.example.com", 3)),
+		ipv4Packet("172.30.0.10", "10.0.0.1", ipProtoUDP, dnsResponse("ok.example.com", 0)),
+	}
+	for i, packet := range packets {
+		w.write(start.Add(time.Duration(i)*time.Second), ethernetFrame(packet))
+	}
+	// Not an IP packet
+	w.write(start.Add(10*time.Second), make([]byte, 60))
+
+	return w.buf.Bytes()
+}
+
+func TestCaptureSummary_Read(t *testing.T) {
+	summary := newCaptureSummary()
+	require.NoError(t, summary.read(bytes.NewReader(testCapture())))
+
+	assert.Equal(t, 8, summary.packets)
+	assert.Equal(t, 1, summary.skipped)
+	assert.Equal(t, 10*time.Second, summary.last.Sub(summary.first))
+	assert.Equal(t, 4, summary.talkers[talkerKey{src: "10.0.0.1", dst: "10.0.0.2"}].packets)
+	assert.Equal(t, map[string]int{"10.0.0.2:443 -> 10.0.0.1:40000": 1}, summary.resets)
+	assert.Equal(t, map[string]int{"10.0.0.1:40000 -> 10.0.0.2:443": 1}, summary.retransmits)
+	assert.Equal(t, map[string]int{"missing.example.com. NXDOMAIN": 1}, summary.dnsFailures)
+}
+
+func TestCaptureSummary_ReadTruncated(t *testing.T) {
+	capture := testCapture()
+
+	summary := newCaptureSummary()
+	require.NoError(t, summary.read(bytes.NewReader(capture[:len(capture)-10])))
+	assert.Equal(t, 7, summary.packets)
+
+	assert.Error(t, newCaptureSummary().read(bytes.NewReader([]byte("not a pcap file at all!!"))))
+}
+
+func TestLinkPayload(t *testing.T) {
+	packet := ipv4Packet("10.0.0.1", "10.0.0.2", ipProtoUDP, nil)
+
+	sll := make([]byte, 16)
+	binary.BigEndian.PutUint16(sll[14:], etherTypeIPv4)
+	sll2 := make([]byte, 20)
+	binary.BigEndian.PutUint16(sll2[0:], etherTypeIPv4)
+	vlan := make([]byte, 18)
+	binary.BigEndian.PutUint16(vlan[12:], etherTypeVLAN)
+	binary.BigEndian.PutUint16(vlan[16:], etherTypeIPv4)
+
+	tests := []struct {
+		name     string
+		linkType uint32
+		frame    []byte
+		ok       bool
+	}{
+		{name: "ethernet", linkType: linkTypeEthernet, frame: ethernetFrame(packet), ok: true},
+		{name: "vlan", linkType: linkTypeEthernet, frame: append(vlan, packet...), ok: true},
+		{name: "linux_sll", linkType: linkTypeLinuxSLL, frame: append(sll, packet...), ok: true},
+		{name: "linux_sll2", linkType: linkTypeSLL2, frame: append(sll2, packet...), ok: true},
+		{name: "raw", linkType: linkTypeRaw, frame: packet, ok: true},
+		{name: "unsupported", linkType: 0, frame: packet, ok: false},
+		{name: "short", linkType: linkTypeEthernet, frame: packet[:10], ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			etherType, payload, ok := linkPayload(tt.linkType, tt.frame)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, uint16(etherTypeIPv4), etherType)
+				assert.Equal(t, packet, payload)
+			}
+		})
+	}
+}
+
+func TestSummarizeCaptureFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node-a.pcap")
+	require.NoError(t, os.WriteFile(path, testCapture(), 0600))
+
+	var out bytes.Buffer
+	require.NoError(t, summarizeCaptureFiles(&out, []string{path, path}, 1))
+
+	assert.Contains(t, out.String(), "Summary of 2 capture file(s): 16 packets")
+	assert.Contains(t, out.String(), "TCP resets: 2")
+	assert.Contains(t, out.String(), "TCP retransmits: 2")
+	assert.Contains(t, out.String(), "DNS failures: 2")
+	assert.Contains(t, out.String(), "missing.example.com. NXDOMAIN")
+	// Only the top talker is printed
+	assert.Contains(t, out.String(), "10.0.0.1")
+	assert.NotContains(t, out.String(), "172.30.0.10")
+
+	assert.Error(t, summarizeCaptureFiles(&out, nil, 1))
+}
@@ -107,6 +107,14 @@ type EgressVerification struct {
 	SkipServiceLog bool
 	// hiveOcmUrl is the OCM environment URL for Hive operations (Classic clusters only)
 	hiveOcmUrl string
+	// AllProbes runs every supported probe against every subnet and reports the results as a matrix
+	AllProbes bool
+	// Output is the format of the egress report: text, json or junit
+	Output string
+	// Baseline is the path to a previously saved egress report to diff the results against
+	Baseline string
+	// SaveBaseline is the path to save the egress report to, for later runs to be diffed against
+	SaveBaseline string
 }
 
 func NewCmdValidateEgress() *cobra.Command {
@@ -137,6 +145,9 @@ func NewCmdValidateEgress() *cobra.Command {
      3. User-provided kubeconfig (when --kubeconfig is specified)
      4. Default kubeconfig (from ~/.kube/config)
 
+  With -o text, -o json or -o junit, the results are reported as an endpoint x subnet x probe matrix in that format
+  instead of the verifier summary.
+
   Docs: https://docs.openshift.com/rosa/rosa_install_access_delete_clusters/rosa_getting_started_iam/rosa-aws-prereqs.html#osd-aws-privatelink-firewall-prerequisites_prerequisites`,
 		Example: `
   # Run against a cluster registered in OCM
@@ -174,6 +185,12 @@ func NewCmdValidateEgress() *cobra.Command {
   ocm login
   osdctl network verify-egress --cluster-id my-staging-cluster --hive-ocm-url production
 
+  # Run all probes against all subnets and save the results as a baseline
+  osdctl network verify-egress --cluster-id my-rosa-cluster --all-probes --save-baseline egress-baseline.json
+
+  # After a firewall change, show which egresses regressed since the baseline as a JUnit report
+  osdctl network verify-egress --cluster-id my-rosa-cluster --all-probes --baseline egress-baseline.json -o junit
+
   # (Not recommended) Run against a specific VPC, without specifying cluster-id
   <export environment variables like AWS_ACCESS_KEY_ID or use aws configure>
   osdctl network verify-egress --subnet-id subnet-abcdefg123 --security-group sg-abcdefgh123 --region us-east-1`,
@@ -181,6 +198,12 @@ func NewCmdValidateEgress() *cobra.Command {
 			if e.Version {
 				printVersion()
 			}
+			// The format of the report is read from the global --output flag
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				log.Fatalf("error reading flag 'output': %v", err)
+			}
+			e.Output = output
 			e.Run(context.Background())
 		},
 	}
@@ -204,6 +227,10 @@ func NewCmdValidateEgress() *cobra.Command {
 	validateEgressCmd.Flags().StringVar(&e.KubeConfig, "kubeconfig", "", "(optional) path to kubeconfig file for pod mode (uses default kubeconfig if not specified)")
 	validateEgressCmd.Flags().StringVar(&e.Namespace, "namespace", "openshift-network-diagnostics", "(optional) Kubernetes namespace to run verification pods in")
 	validateEgressCmd.Flags().BoolVar(&e.SkipServiceLog, "skip-service-log", false, "(optional) disable automatic service log sending when verification fails")
+	validateEgressCmd.Flags().BoolVar(&e.AllProbes, "all-probes", false, "(optional) run every supported probe against every subnet and report the results as an endpoint x subnet x probe matrix")
+	validateEgressCmd.Flags().StringVar(&e.Baseline, "baseline", "", "(optional) path to a report saved with --save-baseline to diff the results against")
+	validateEgressCmd.Flags().StringVar(&e.SaveBaseline, "save-baseline", "", "(optional) path to save the report to as a baseline for later runs")
+	validateEgressCmd.MarkFlagsMutuallyExclusive("all-probes", "probe")
 	validateEgressCmd.Flags().StringVar(&e.hiveOcmUrl, "hive-ocm-url", "", "(optional) OCM environment URL for hive operations. Aliases: 'production', 'staging', 'integration'. If not specified, uses the same OCM environment as the target cluster.")
 
 	return validateEgressCmd
@@ -256,6 +283,11 @@ func (e *EgressVerification) Run(ctx context.Context) {
 		log.Fatal(err)
 	}
 
+	// Pod mode is not bound to subnets, otherwise compare all of them unless overridden
+	if e.AllProbes && !e.PodMode && len(e.SubnetIds) == 0 {
+		e.AllSubnets = true
+	}
+
 	// Setup verifier and inputs based on mode
 	var inputs []*onv.ValidateEgressInput
 	var verifier networkVerifier
@@ -272,6 +304,13 @@ func (e *EgressVerification) Run(ctx context.Context) {
 		log.Fatal(err)
 	}
 
+	if e.reporting() {
+		if err := e.runReport(ctx, verifier, inputs, platform); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	var failures int
 	for i := range inputs {
 		if !e.PodMode {
@@ -660,6 +699,12 @@ func (e *EgressVerification) validateInput() error {
 			"--subnet-id foo --subnet-id bar")
 	}
 
+	switch e.Output {
+	case "", "text", "json", "junit":
+	default:
+		return fmt.Errorf("invalid output format '%s', expecting 'text', 'json' or 'junit'", e.Output)
+	}
+
 	// Pod mode validation
 	if e.PodMode {
 		// Require cluster-id or explicit platform for platform determination
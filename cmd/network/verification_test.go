@@ -115,6 +115,22 @@ func TestEgressVerification_ValidateInput(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "valid_junit_output",
+			ev: &EgressVerification{
+				SubnetIds: []string{"subnet-123"},
+				Output:    "junit",
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid_output",
+			ev: &EgressVerification{
+				SubnetIds: []string{"subnet-123"},
+				Output:    "yaml",
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
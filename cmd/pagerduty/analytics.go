@@ -0,0 +1,366 @@
+package pagerduty
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	pdProvider "github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	viewAlerts    = "alerts"
+	viewClusters  = "clusters"
+	viewFrequency = "frequency"
+
+	bucketDateFormat = "2006-01-02"
+
+	// maxConcurrentLookups bounds the clusters whose PagerDuty services are looked up at once
+	maxConcurrentLookups = 10
+)
+
+var bucketSizes = map[string]time.Duration{
+	"day":  24 * time.Hour,
+	"week": 7 * 24 * time.Hour,
+}
+
+const analyticsExample = `  # Summarize the alerts of the configured teams over the last 4 weeks
+  osdctl pagerduty analytics
+
+  # Show which alerts fire more often day by day over the last week, as CSV
+  osdctl pagerduty analytics --days 7 --bucket day --view frequency -o csv
+
+  # Find the noisiest clusters of an organization
+  osdctl pagerduty analytics --org-id 1a2B3c4D5e6F7g8H9i0J --view clusters`
+
+type analyticsOptions struct {
+	days       int
+	bucket     string
+	flapWindow time.Duration
+	teamIDs    []string
+	clusterID  string
+	orgID      string
+	view       string
+	output     string
+	top        int
+	// until is the end of the analyzed window, the time the command runs
+	until   time.Time
+	errOut  io.Writer
+	clients Clients
+}
+
+func newCmdAnalytics(tokens *tokenOptions) *cobra.Command {
+	ops := &analyticsOptions{
+		output:  "table",
+		errOut:  os.Stderr,
+		clients: newClients(tokens),
+	}
+	analyticsCmd := &cobra.Command{
+		Use:   "analytics",
+		Short: "Compute flapping alerts, response times, alert frequency and noisy clusters from PagerDuty incidents",
+		Long: `Compute analytics over the PagerDuty incidents of the configured teams, of a cluster or of an organization.
+
+Incidents are grouped by alert name, which is the incident title without cluster names, ids and counters.
+An incident is flapping when the same alert triggers again on the same cluster within --flap-window of the
+previous incident being resolved.
+
+Views:
+  alerts     incidents, affected clusters, flaps, mean time to acknowledge (MTTA) and resolve (MTTR) per alert,
+             and the incident count of the last bucket compared to the previous one
+  clusters   the noisiest clusters (PagerDuty services), with their flaps and most frequent alert
+  frequency  incidents per alert and per --bucket
+
+The output is a table by default, -o json and -o csv are also supported. The JSON output contains every view.
+Clusters whose PagerDuty services can't be looked up are listed in the report, or on stderr for CSV output, and
+the others are still analyzed.`,
+		Example:           analyticsExample,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("team-ids") {
+				ops.teamIDs = viper.GetStringSlice(pdProvider.PagerDutyTeamIDsKey)
+			}
+			// The format is read from the global --output flag, table is its empty default
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				return fmt.Errorf("error reading flag 'output': %w", err)
+			}
+			if output != "" {
+				ops.output = output
+			}
+			defer ops.clients.Close()
+			ops.until = time.Now()
+			return ops.run(cmd.OutOrStdout())
+		},
+	}
+
+	analyticsCmd.Flags().IntVar(&ops.days, "days", 28, "Number of days of incidents to analyze")
+	analyticsCmd.Flags().StringVar(&ops.bucket, "bucket", "week", "Size of the time buckets: day, week")
+	analyticsCmd.Flags().DurationVar(&ops.flapWindow, "flap-window", time.Hour, "Maximum time between an incident being resolved and the same alert triggering again to count as a flap")
+	analyticsCmd.Flags().StringSliceVar(&ops.teamIDs, "team-ids", nil, fmt.Sprintf("PagerDuty team ids to analyze (defaults to %s from the config)", pdProvider.PagerDutyTeamIDsKey))
+	analyticsCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Only analyze the incidents of this cluster")
+	analyticsCmd.Flags().StringVar(&ops.orgID, "org-id", "", "Only analyze the incidents of the clusters of this organization")
+	analyticsCmd.Flags().StringVar(&ops.view, "view", viewAlerts, "View to print in table and CSV output: alerts, clusters, frequency")
+	analyticsCmd.Flags().IntVar(&ops.top, "top", 20, "Maximum number of alerts or clusters to print, 0 prints all")
+	analyticsCmd.MarkFlagsMutuallyExclusive("cluster-id", "org-id")
+
+	return analyticsCmd
+}
+
+func (o *analyticsOptions) validate() error {
+	if o.days < 1 {
+		return errors.New("--days must be at least 1")
+	}
+	if _, ok := bucketSizes[o.bucket]; !ok {
+		return fmt.Errorf("invalid bucket '%s', expecting 'day' or 'week'", o.bucket)
+	}
+	if o.view != viewAlerts && o.view != viewClusters && o.view != viewFrequency {
+		return fmt.Errorf("invalid view '%s', expecting '%s', '%s' or '%s'", o.view, viewAlerts, viewClusters, viewFrequency)
+	}
+	if o.output != "table" && o.output != "json" && o.output != "csv" {
+		return fmt.Errorf("invalid output format '%s', expecting 'table', 'json' or 'csv'", o.output)
+	}
+	if o.top < 0 {
+		return errors.New("--top can't be negative")
+	}
+	if o.clusterID == "" && o.orgID == "" && len(o.teamIDs) == 0 {
+		return fmt.Errorf("no scope to analyze, set --team-ids, %s in the config, --cluster-id or --org-id", pdProvider.PagerDutyTeamIDsKey)
+	}
+	return nil
+}
+
+func (o *analyticsOptions) run(w io.Writer) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+	until := o.until.UTC().Truncate(time.Minute)
+	since := until.AddDate(0, 0, -o.days)
+
+	client, err := o.clients.NewPagerDutyClient("", o.teamIDs)
+	if err != nil {
+		return err
+	}
+	serviceIDs, failures, err := o.serviceIDs()
+	if err != nil {
+		return err
+	}
+	if serviceIDs != nil && len(serviceIDs) == 0 {
+		if len(failures) > 0 {
+			return fmt.Errorf("no PagerDuty service found for the given clusters, %d failed to be looked up: %s", len(failures), failures[0].Error)
+		}
+		return errors.New("no PagerDuty service found for the given clusters")
+	}
+
+	incidents, err := client.GetIncidents(serviceIDs, since, until)
+	if err != nil {
+		return err
+	}
+	// Without services, the incidents are the teams' and so are the log entries worth listing
+	var acknowledgedAt map[string]time.Time
+	if serviceIDs == nil {
+		acknowledgedAt, err = client.GetAcknowledgeTimes(since, until)
+	} else {
+		incidentIDs := make([]string, 0, len(incidents))
+		for _, incident := range incidents {
+			incidentIDs = append(incidentIDs, incident.ID)
+		}
+		acknowledgedAt, err = client.GetIncidentAcknowledgeTimes(incidentIDs)
+	}
+	if err != nil {
+		return err
+	}
+	records, err := newIncidentRecords(incidents, acknowledgedAt)
+	if err != nil {
+		return err
+	}
+
+	r := buildReport(records, since, until, bucketSizes[o.bucket], o.flapWindow)
+	if o.top > 0 {
+		r.Alerts = r.Alerts[:min(o.top, len(r.Alerts))]
+		r.Clusters = r.Clusters[:min(o.top, len(r.Clusters))]
+		r.Frequency = r.Frequency[:min(o.top, len(r.Frequency))]
+	}
+	r.Failures = failures
+	return o.render(w, r, len(records))
+}
+
+// serviceIDs returns the PagerDuty services of the analyzed clusters, or nil to analyze the teams.
+// The clusters are looked up concurrently, and those which fail are returned instead of failing the analysis.
+func (o *analyticsOptions) serviceIDs() ([]string, []clusterFailure, error) {
+	if o.clusterID == "" && o.orgID == "" {
+		return nil, nil, nil
+	}
+
+	clusterIDs := []string{o.clusterID}
+	if o.orgID != "" {
+		var err error
+		clusterIDs, err = o.clients.OrganizationClusterIDs(o.orgID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var (
+		mu         sync.Mutex
+		serviceIDs = make([][]string, len(clusterIDs))
+		failures   []clusterFailure
+	)
+	g := errgroup.Group{}
+	g.SetLimit(maxConcurrentLookups)
+	for i, clusterID := range clusterIDs {
+		g.Go(func() error {
+			ids, err := o.clusterServiceIDs(clusterID)
+			if err != nil {
+				mu.Lock()
+				failures = append(failures, clusterFailure{ClusterID: clusterID, Error: err.Error()})
+				mu.Unlock()
+				return nil
+			}
+			serviceIDs[i] = ids
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	// A single cluster can't be partially analyzed
+	if o.clusterID != "" && len(failures) > 0 {
+		return nil, nil, errors.New(failures[0].Error)
+	}
+
+	sort.Slice(failures, func(i, j int) bool { return failures[i].ClusterID < failures[j].ClusterID })
+	all := []string{}
+	for _, ids := range serviceIDs {
+		all = append(all, ids...)
+	}
+	return all, failures, nil
+}
+
+// clusterServiceIDs returns the PagerDuty services of a cluster
+func (o *analyticsOptions) clusterServiceIDs(clusterID string) ([]string, error) {
+	baseDomain, err := o.clients.ClusterBaseDomain(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", clusterID, err)
+	}
+	client, err := o.clients.NewPagerDutyClient(baseDomain, o.teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := client.GetPDServiceIDs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the PagerDuty services of cluster %s: %w", clusterID, err)
+	}
+	return ids, nil
+}
+
+func (o *analyticsOptions) render(w io.Writer, r report, incidents int) error {
+	if o.output == "json" {
+		out, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %v", err)
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	}
+
+	var header []string
+	var rows [][]string
+	table := o.output == "table"
+	switch o.view {
+	case viewAlerts:
+		header = []string{"alert", "incidents", "clusters", "flaps", "mtta", "mttr", "current", "previous", "change"}
+		for _, a := range r.Alerts {
+			rows = append(rows, []string{
+				a.Alert, strconv.Itoa(a.Incidents), strconv.Itoa(a.Clusters), strconv.Itoa(a.Flaps),
+				formatSeconds(a.MTTASeconds, a.Acknowledged, table), formatSeconds(a.MTTRSeconds, a.Resolved, table),
+				strconv.Itoa(a.Current), strconv.Itoa(a.Previous), fmt.Sprintf("%+d", a.Current-a.Previous),
+			})
+		}
+	case viewClusters:
+		header = []string{"service_id", "service", "incidents", "alerts", "flaps", "top_alert"}
+		for _, c := range r.Clusters {
+			rows = append(rows, []string{c.ServiceID, c.ServiceName, strconv.Itoa(c.Incidents), strconv.Itoa(c.Alerts), strconv.Itoa(c.Flaps), c.TopAlert})
+		}
+	case viewFrequency:
+		header = []string{"alert"}
+		for _, start := range r.Buckets {
+			header = append(header, start.Format(bucketDateFormat))
+		}
+		for _, f := range r.Frequency {
+			row := []string{f.Alert}
+			for _, count := range f.Counts {
+				row = append(row, strconv.Itoa(count))
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	if o.output == "csv" {
+		if o.view == viewAlerts {
+			header[4], header[5] = "mtta_seconds", "mttr_seconds"
+		}
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return fmt.Errorf("failed to write CSV header: %v", err)
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return fmt.Errorf("failed to write CSV rows: %v", err)
+		}
+		printFailures(o.errOut, r.Failures)
+		return nil
+	}
+
+	fmt.Fprintf(w, "%d incidents from %s to %s, %s buckets\n\n", incidents, r.Since.Format(time.RFC3339), r.Until.Format(time.RFC3339), o.bucket)
+	p := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	for i := range header {
+		header[i] = strings.ToUpper(strings.ReplaceAll(header[i], "_", " "))
+	}
+	p.AddRow(header)
+	for _, row := range rows {
+		p.AddRow(row)
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+	if len(r.Failures) > 0 {
+		fmt.Fprintln(w)
+	}
+	printFailures(w, r.Failures)
+	return nil
+}
+
+// printFailures lists the clusters left out of the analysis
+func printFailures(w io.Writer, failures []clusterFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%d clusters were left out, their PagerDuty services could not be looked up:\n", len(failures))
+	for _, f := range failures {
+		fmt.Fprintf(w, "  %s: %s\n", f.ClusterID, f.Error)
+	}
+}
+
+// formatSeconds formats a mean time, printed as a duration in tables and as seconds in CSV
+func formatSeconds(seconds int64, samples int, table bool) string {
+	if samples == 0 {
+		if table {
+			return "-"
+		}
+		return ""
+	}
+	if table {
+		return (time.Duration(seconds) * time.Second).String()
+	}
+	return strconv.FormatInt(seconds, 10)
+}
@@ -0,0 +1,194 @@
+package pagerduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/osdctl/cmd/pagerduty/mock"
+	pdProvider "github.com/openshift/osdctl/pkg/provider/pagerduty"
+	pdMock "github.com/openshift/osdctl/pkg/provider/pagerduty/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newTestAnalyticsOptions returns options analyzing the incidents of team TEAM, read from client
+func newTestAnalyticsOptions(t *testing.T, client pdProvider.Client) (*analyticsOptions, *mock.MockClients) {
+	clients := mock.NewMockClients(gomock.NewController(t))
+	clients.EXPECT().NewPagerDutyClient("", []string{"TEAM"}).Return(client, nil).AnyTimes()
+	return &analyticsOptions{
+		days:       14,
+		bucket:     "week",
+		flapWindow: time.Hour,
+		teamIDs:    []string{"TEAM"},
+		view:       viewAlerts,
+		output:     "table",
+		until:      time.Date(2026, 1, 15, 0, 0, 30, 0, time.UTC),
+		errOut:     &bytes.Buffer{},
+		clients:    clients,
+	}, clients
+}
+
+// newTeamClient returns a PagerDuty client returning testIncidents for the teams
+func newTeamClient(t *testing.T) *pdMock.MockClient {
+	client := pdMock.NewMockClient(gomock.NewController(t))
+	client.EXPECT().GetIncidents(gomock.Nil(), gomock.Any(), gomock.Any()).Return(testIncidents(), nil).AnyTimes()
+	client.EXPECT().GetAcknowledgeTimes(gomock.Any(), gomock.Any()).Return(map[string]time.Time{}, nil).AnyTimes()
+	return client
+}
+
+func testIncidents() []pd.Incident {
+	return []pd.Incident{
+		{APIObject: pd.APIObject{ID: "1"}, Title: "ClusterOperatorDown (1)", CreatedAt: "2026-01-02T00:00:00Z", Service: pd.APIObject{ID: "S1", Summary: "cluster-a"}, Status: "resolved", ResolvedAt: "2026-01-02T01:00:00Z"},
+		{APIObject: pd.APIObject{ID: "2"}, Title: "ClusterOperatorDown (2)", CreatedAt: "2026-01-10T00:00:00Z", Service: pd.APIObject{ID: "S1", Summary: "cluster-a"}, Status: "triggered"},
+		{APIObject: pd.APIObject{ID: "3"}, Title: "ClusterOperatorDown (1)", CreatedAt: "2026-01-11T00:00:00Z", Service: pd.APIObject{ID: "S2", Summary: "cluster-b"}, Status: "triggered"},
+	}
+}
+
+func TestAnalyticsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*analyticsOptions)
+		wantErr string
+	}{
+		{name: "valid", modify: func(*analyticsOptions) {}},
+		{name: "days", modify: func(o *analyticsOptions) { o.days = 0 }, wantErr: "--days"},
+		{name: "bucket", modify: func(o *analyticsOptions) { o.bucket = "month" }, wantErr: "invalid bucket"},
+		{name: "view", modify: func(o *analyticsOptions) { o.view = "all" }, wantErr: "invalid view"},
+		{name: "output", modify: func(o *analyticsOptions) { o.output = "yaml" }, wantErr: "invalid output"},
+		{name: "top", modify: func(o *analyticsOptions) { o.top = -1 }, wantErr: "--top"},
+		{name: "no scope", modify: func(o *analyticsOptions) { o.teamIDs = nil }, wantErr: "no scope"},
+		{name: "cluster scope without teams", modify: func(o *analyticsOptions) { o.teamIDs, o.clusterID = nil, "abc" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _ := newTestAnalyticsOptions(t, nil)
+			tt.modify(o)
+			err := o.validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnalyticsRunTable(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	client := pdMock.NewMockClient(gomock.NewController(t))
+	client.EXPECT().GetIncidents(gomock.Nil(), since, until).Return(testIncidents(), nil)
+	client.EXPECT().GetAcknowledgeTimes(since, until).Return(map[string]time.Time{}, nil)
+	o, _ := newTestAnalyticsOptions(t, client)
+
+	var out bytes.Buffer
+	require.NoError(t, o.run(&out))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "3 incidents from 2026-01-01T00:00:00Z to 2026-01-15T00:00:00Z, week buckets", lines[0])
+	assert.Equal(t, []string{"ALERT", "INCIDENTS", "CLUSTERS", "FLAPS", "MTTA", "MTTR", "CURRENT", "PREVIOUS", "CHANGE"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"ClusterOperatorDown", "3", "2", "0", "-", "1h0m0s", "2", "1", "+1"}, strings.Fields(lines[3]))
+}
+
+func TestAnalyticsRunCSV(t *testing.T) {
+	tests := []struct {
+		view string
+		want string
+	}{
+		{
+			view: viewAlerts,
+			want: "alert,incidents,clusters,flaps,mtta_seconds,mttr_seconds,current,previous,change\n" +
+				"ClusterOperatorDown,3,2,0,,3600,2,1,+1\n",
+		},
+		{
+			view: viewClusters,
+			want: "service_id,service,incidents,alerts,flaps,top_alert\n" +
+				"S1,cluster-a,2,1,0,ClusterOperatorDown\n" +
+				"S2,cluster-b,1,1,0,ClusterOperatorDown\n",
+		},
+		{
+			view: viewFrequency,
+			want: "alert,2026-01-01,2026-01-08\n" +
+				"ClusterOperatorDown,1,2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.view, func(t *testing.T) {
+			o, _ := newTestAnalyticsOptions(t, newTeamClient(t))
+			o.view, o.output = tt.view, "csv"
+
+			var out bytes.Buffer
+			require.NoError(t, o.run(&out))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestAnalyticsRunJSON(t *testing.T) {
+	o, _ := newTestAnalyticsOptions(t, newTeamClient(t))
+	o.output, o.top = "json", 1
+
+	var out bytes.Buffer
+	require.NoError(t, o.run(&out))
+
+	var r report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &r))
+	assert.Len(t, r.Buckets, 2)
+	assert.Len(t, r.Alerts, 1)
+	assert.Len(t, r.Frequency, 1)
+	// --top limits every view
+	assert.Len(t, r.Clusters, 1)
+}
+
+func TestAnalyticsRunError(t *testing.T) {
+	client := pdMock.NewMockClient(gomock.NewController(t))
+	client.EXPECT().GetIncidents(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("boom"))
+	o, _ := newTestAnalyticsOptions(t, client)
+	assert.EqualError(t, o.run(&bytes.Buffer{}), "boom")
+}
+
+func TestAnalyticsRunOrg(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := pdMock.NewMockClient(ctrl)
+	client.EXPECT().GetIncidents([]string{"S2", "S1"}, gomock.Any(), gomock.Any()).Return(testIncidents(), nil)
+	client.EXPECT().GetIncidentAcknowledgeTimes([]string{"1", "2", "3"}).Return(map[string]time.Time{}, nil)
+
+	o, clients := newTestAnalyticsOptions(t, client)
+	o.orgID, o.output = "org", "json"
+	clients.EXPECT().OrganizationClusterIDs("org").Return([]string{"c", "b", "a", "gone"}, nil)
+	clients.EXPECT().ClusterBaseDomain("gone").Return("", errors.New("not found")).Times(2)
+	for clusterID, serviceIDs := range map[string][]string{"a": {"S1"}, "b": {"S2"}, "c": nil} {
+		baseDomain := clusterID + ".example.com"
+		clients.EXPECT().ClusterBaseDomain(clusterID).Return(baseDomain, nil)
+
+		clusterClient := pdMock.NewMockClient(ctrl)
+		if serviceIDs == nil {
+			clusterClient.EXPECT().GetPDServiceIDs().Return(nil, errors.New("no service found"))
+		} else {
+			clusterClient.EXPECT().GetPDServiceIDs().Return(serviceIDs, nil)
+		}
+		clients.EXPECT().NewPagerDutyClient(baseDomain, []string{"TEAM"}).Return(clusterClient, nil)
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, o.run(&out))
+
+	// The clusters which failed are reported, the others are analyzed
+	var r report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &r))
+	assert.Equal(t, []clusterFailure{
+		{ClusterID: "c", Error: "failed to get the PagerDuty services of cluster c: no service found"},
+		{ClusterID: "gone", Error: "failed to get cluster gone: not found"},
+	}, r.Failures)
+
+	// A single cluster fails the analysis
+	o.orgID, o.clusterID = "", "gone"
+	assert.EqualError(t, o.run(&bytes.Buffer{}), "failed to get cluster gone: not found")
+}
@@ -0,0 +1,138 @@
+package pagerduty
+
+//go:generate mockgen -source=cmd.go -package=mock -destination=mock/cmd.go
+
+import (
+	"fmt"
+	"sync"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift/osdctl/cmd/org"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	pdProvider "github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Clients looks up clusters in OCM and creates the PagerDuty clients of the pagerduty commands
+type Clients interface {
+	// ClusterBaseDomain returns the base domain of a cluster, which names its PagerDuty services
+	ClusterBaseDomain(clusterID string) (string, error)
+	// OrganizationClusterIDs returns the ids of the active managed clusters of an organization
+	OrganizationClusterIDs(orgID string) ([]string, error)
+	// NewPagerDutyClient returns a client of the PagerDuty services of the base domain, or of the teams
+	NewPagerDutyClient(baseDomain string, teamIDs []string) (pdProvider.Client, error)
+	Close() error
+}
+
+// tokenOptions holds the PagerDuty tokens passed as flags, which default to the same config keys as `cluster context`
+type tokenOptions struct {
+	userToken  string
+	oauthToken string
+}
+
+func (t *tokenOptions) user() string {
+	if t.userToken != "" {
+		return t.userToken
+	}
+	return viper.GetString(pdProvider.PagerDutyUserTokenConfigKey)
+}
+
+func (t *tokenOptions) oauth() string {
+	if t.oauthToken != "" {
+		return t.oauthToken
+	}
+	return viper.GetString(pdProvider.PagerDutyOauthTokenConfigKey)
+}
+
+// NewCmdPagerDuty implements the base pagerduty command
+func NewCmdPagerDuty() *cobra.Command {
+	tokens := &tokenOptions{}
+	pdCmd := &cobra.Command{
+		Use:               "pagerduty",
+		Aliases:           []string{"pd"},
+		Short:             "PagerDuty related utilities",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+
+	pdCmd.PersistentFlags().StringVar(&tokens.oauthToken, "oauthtoken", "", fmt.Sprintf("Pass in PD oauthtoken directly. If not passed in, by default will read `%s` from ~/.config/%s", pdProvider.PagerDutyOauthTokenConfigKey, osdctlConfig.ConfigFileName))
+	pdCmd.PersistentFlags().StringVar(&tokens.userToken, "usertoken", "", fmt.Sprintf("Pass in PD usertoken directly. If not passed in, by default will read `%s` from ~/.config/%s", pdProvider.PagerDutyUserTokenConfigKey, osdctlConfig.ConfigFileName))
+
+	pdCmd.AddCommand(newCmdAnalytics(tokens))
+	pdCmd.AddCommand(newCmdIncident(tokens))
+
+	return pdCmd
+}
+
+// ocmClients implements Clients with a single OCM connection, opened on the first lookup
+type ocmClients struct {
+	tokens *tokenOptions
+
+	mu        sync.Mutex
+	ocmClient *sdk.Connection
+}
+
+func newClients(tokens *tokenOptions) *ocmClients {
+	return &ocmClients{tokens: tokens}
+}
+
+func (c *ocmClients) connection() (*sdk.Connection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ocmClient == nil {
+		ocmClient, err := utils.CreateConnection()
+		if err != nil {
+			return nil, err
+		}
+		c.ocmClient = ocmClient
+	}
+	return c.ocmClient, nil
+}
+
+func (c *ocmClients) ClusterBaseDomain(clusterID string) (string, error) {
+	ocmClient, err := c.connection()
+	if err != nil {
+		return "", err
+	}
+	cluster, err := utils.GetCluster(ocmClient, clusterID)
+	if err != nil {
+		return "", err
+	}
+	return cluster.DNS().BaseDomain(), nil
+}
+
+func (c *ocmClients) OrganizationClusterIDs(orgID string) ([]string, error) {
+	subscriptions, err := org.SearchAllSubscriptionsByOrg(orgID, "Active", true)
+	if err != nil {
+		return nil, err
+	}
+	clusterIDs := make([]string, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		clusterIDs = append(clusterIDs, subscription.ClusterID())
+	}
+	return clusterIDs, nil
+}
+
+func (c *ocmClients) NewPagerDutyClient(baseDomain string, teamIDs []string) (pdProvider.Client, error) {
+	client, err := pdProvider.NewClient().
+		WithBaseDomain(baseDomain).
+		WithTeamIdList(teamIDs).
+		WithUserToken(c.tokens.user()).
+		WithOauthToken(c.tokens.oauth()).
+		Init()
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *ocmClients) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ocmClient == nil {
+		return nil
+	}
+	return c.ocmClient.Close()
+}
@@ -0,0 +1,339 @@
+package pagerduty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/kballard/go-shellquote"
+	pdProvider "github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const (
+	// maxNoteLength is the maximum length of a PagerDuty incident note
+	maxNoteLength = 25000
+)
+
+// incidentOptions selects the incidents an action applies to, either by id or by cluster
+type incidentOptions struct {
+	clusterID string
+	yes       bool
+
+	clients Clients
+	osdctl  utils.OsdctlRunner
+	genericclioptions.IOStreams
+}
+
+func newCmdIncident(tokens *tokenOptions) *cobra.Command {
+	return incidentCmd(&incidentOptions{
+		clients:   newClients(tokens),
+		osdctl:    utils.NewOsdctlRunner(),
+		IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr},
+	})
+}
+
+func incidentCmd(ops *incidentOptions) *cobra.Command {
+	incidentCmd := &cobra.Command{
+		Use:   "incident",
+		Short: "Acknowledge, resolve, annotate, reassign and merge PagerDuty incidents",
+		Long: `Acknowledge, resolve, annotate, reassign and merge PagerDuty incidents, selected by incident id or by cluster.
+
+With --cluster-id, the action applies to every triggered or acknowledged incident of the cluster's PagerDuty services.
+Every change is made as the user of the PagerDuty token, read from the same config as 'osdctl cluster context'.`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			return ops.clients.Close()
+		},
+	}
+	incidentCmd.PersistentFlags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Apply the action to the open incidents of this cluster instead of the given incident ids")
+
+	incidentCmd.AddCommand(newCmdIncidentAck(ops))
+	incidentCmd.AddCommand(newCmdIncidentResolve(ops))
+	incidentCmd.AddCommand(newCmdIncidentNote(ops))
+	incidentCmd.AddCommand(newCmdIncidentReassign(ops))
+	incidentCmd.AddCommand(newCmdIncidentMerge(ops))
+
+	return incidentCmd
+}
+
+func newCmdIncidentAck(ops *incidentOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:     "ack [incident-id...]",
+		Aliases: []string{"acknowledge"},
+		Short:   "Acknowledge incidents",
+		Example: `  # Acknowledge two incidents
+  osdctl pd incident ack Q1AB2CD3EF4GH5 Q2AB2CD3EF4GH6
+
+  # Acknowledge every open incident of a cluster
+  osdctl pd incident ack --cluster-id ${CLUSTER_ID}`,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, incidentIDs, err := ops.targets(args)
+			if err != nil {
+				return err
+			}
+			if err := client.AcknowledgeIncidents(incidentIDs); err != nil {
+				return err
+			}
+			fmt.Fprintf(ops.Out, "Acknowledged %d incident(s): %s\n", len(incidentIDs), strings.Join(incidentIDs, ", "))
+			return nil
+		},
+	}
+}
+
+func newCmdIncidentResolve(ops *incidentOptions) *cobra.Command {
+	resolveCmd := &cobra.Command{
+		Use:   "resolve [incident-id...]",
+		Short: "Resolve incidents",
+		Example: `  # Resolve an incident
+  osdctl pd incident resolve Q1AB2CD3EF4GH5
+
+  # Resolve every open incident of a cluster without confirmation
+  osdctl pd incident resolve --cluster-id ${CLUSTER_ID} --yes`,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, incidentIDs, err := ops.targets(args)
+			if err != nil {
+				return err
+			}
+			if !ops.confirmed(fmt.Sprintf("This resolves %d incident(s).", len(incidentIDs))) {
+				return nil
+			}
+			if err := client.ResolveIncidents(incidentIDs); err != nil {
+				return err
+			}
+			fmt.Fprintf(ops.Out, "Resolved %d incident(s): %s\n", len(incidentIDs), strings.Join(incidentIDs, ", "))
+			return nil
+		},
+	}
+	resolveCmd.Flags().BoolVarP(&ops.yes, "yes", "y", false, "Don't ask for confirmation when resolving the incidents of a cluster")
+	return resolveCmd
+}
+
+func newCmdIncidentNote(ops *incidentOptions) *cobra.Command {
+	var message string
+	var attachments []string
+	noteCmd := &cobra.Command{
+		Use:   "note [incident-id...]",
+		Short: "Add a note to incidents, optionally with the output of osdctl commands",
+		Long: `Add a note to incidents, optionally with the output of osdctl commands.
+
+Every --attach runs the given osdctl command and adds its output to the note. ` + utils.ClusterIDPlaceholder + ` in the
+command is replaced by the id of the cluster given with --cluster-id.`,
+		Example: `  # Add a note to an incident
+  osdctl pd incident note Q1AB2CD3EF4GH5 -m "Investigating, the node is being replaced"
+
+  # Add the health of the cluster to every open incident of the cluster
+  osdctl pd incident note --cluster-id ${CLUSTER_ID} -m "Cluster health" --attach "cluster health -C {cluster_id}"`,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if message == "" && len(attachments) == 0 {
+				return errors.New("the note is empty, set --message or --attach")
+			}
+			client, incidentIDs, err := ops.targets(args)
+			if err != nil {
+				return err
+			}
+			content, err := ops.noteContent(cmd.Context(), message, attachments)
+			if err != nil {
+				return err
+			}
+			for _, id := range incidentIDs {
+				if err := client.AddIncidentNote(id, content); err != nil {
+					return fmt.Errorf("failed to add the note to incident %s: %w", id, err)
+				}
+			}
+			fmt.Fprintf(ops.Out, "Added the note to %d incident(s): %s\n", len(incidentIDs), strings.Join(incidentIDs, ", "))
+			return nil
+		},
+	}
+	noteCmd.Flags().StringVarP(&message, "message", "m", "", "Text of the note")
+	noteCmd.Flags().StringArrayVar(&attachments, "attach", nil, "osdctl command whose output is added to the note, like \"cluster health -C "+utils.ClusterIDPlaceholder+"\". Can be repeated")
+	return noteCmd
+}
+
+func newCmdIncidentReassign(ops *incidentOptions) *cobra.Command {
+	var userEmails []string
+	var escalationPolicyID string
+	reassignCmd := &cobra.Command{
+		Use:   "reassign [incident-id...]",
+		Short: "Reassign incidents to users or to an escalation policy",
+		Example: `  # Hand an incident over to a colleague
+  osdctl pd incident reassign Q1AB2CD3EF4GH5 --user colleague@redhat.com
+
+  # Escalate every open incident of a cluster to another escalation policy
+  osdctl pd incident reassign --cluster-id ${CLUSTER_ID} --escalation-policy P1AB2CD`,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, incidentIDs, err := ops.targets(args)
+			if err != nil {
+				return err
+			}
+			if err := client.ReassignIncidents(incidentIDs, userEmails, escalationPolicyID); err != nil {
+				return err
+			}
+			fmt.Fprintf(ops.Out, "Reassigned %d incident(s): %s\n", len(incidentIDs), strings.Join(incidentIDs, ", "))
+			return nil
+		},
+	}
+	reassignCmd.Flags().StringSliceVar(&userEmails, "user", nil, "Email of the PagerDuty user to assign the incidents to. Can be repeated")
+	reassignCmd.Flags().StringVar(&escalationPolicyID, "escalation-policy", "", "ID of the escalation policy to assign the incidents to")
+	reassignCmd.MarkFlagsOneRequired("user", "escalation-policy")
+	reassignCmd.MarkFlagsMutuallyExclusive("user", "escalation-policy")
+	return reassignCmd
+}
+
+func newCmdIncidentMerge(ops *incidentOptions) *cobra.Command {
+	var into string
+	mergeCmd := &cobra.Command{
+		Use:   "merge [target-incident-id source-incident-id...]",
+		Short: "Merge incidents into a target incident",
+		Long: `Merge incidents into a target incident. The merged incidents are resolved and their alerts move to the target incident.
+
+With --cluster-id, the open incidents of the cluster are merged into the incident given with --into, or into the oldest one.`,
+		Example: `  # Merge two incidents into a third one
+  osdctl pd incident merge Q1AB2CD3EF4GH5 Q2AB2CD3EF4GH6 Q3AB2CD3EF4GH7
+
+  # Merge every open incident of a cluster into the oldest one
+  osdctl pd incident merge --cluster-id ${CLUSTER_ID}`,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if into != "" && ops.clusterID == "" {
+				return errors.New("--into can only be used with --cluster-id, otherwise the first incident id is the target")
+			}
+			client, incidentIDs, err := ops.targets(args)
+			if err != nil {
+				return err
+			}
+			if len(incidentIDs) < 2 {
+				return errors.New("at least two incidents are needed to merge")
+			}
+
+			// incidentIDs are sorted from the oldest
+			target := incidentIDs[0]
+			if into != "" {
+				if !slices.Contains(incidentIDs, into) {
+					return fmt.Errorf("incident %s is not an open incident of cluster %s", into, ops.clusterID)
+				}
+				target = into
+			}
+			sources := slices.DeleteFunc(slices.Clone(incidentIDs), func(id string) bool { return id == target })
+
+			if !ops.confirmed(fmt.Sprintf("This merges %d incident(s) into %s.", len(sources), target)) {
+				return nil
+			}
+			if err := client.MergeIncidents(target, sources); err != nil {
+				return err
+			}
+			fmt.Fprintf(ops.Out, "Merged %d incident(s) into %s: %s\n", len(sources), target, strings.Join(sources, ", "))
+			return nil
+		},
+	}
+	mergeCmd.Flags().StringVar(&into, "into", "", "Incident to merge the incidents of the cluster into (defaults to the oldest)")
+	mergeCmd.Flags().BoolVarP(&ops.yes, "yes", "y", false, "Don't ask for confirmation when merging the incidents of a cluster")
+	return mergeCmd
+}
+
+// targets returns the given incident ids, or the open incidents of the cluster from the oldest
+func (o *incidentOptions) targets(args []string) (pdProvider.Client, []string, error) {
+	if len(args) > 0 && o.clusterID != "" {
+		return nil, nil, errors.New("incident ids and --cluster-id can't be used together")
+	}
+	if len(args) == 0 && o.clusterID == "" {
+		return nil, nil, errors.New("pass incident ids or --cluster-id")
+	}
+	if o.clusterID == "" {
+		client, err := o.clients.NewPagerDutyClient("", nil)
+		return client, args, err
+	}
+
+	baseDomain, err := o.clients.ClusterBaseDomain(o.clusterID)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := o.clients.NewPagerDutyClient(baseDomain, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	serviceIDs, err := client.GetPDServiceIDs()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(serviceIDs) == 0 {
+		return nil, nil, fmt.Errorf("no PagerDuty service found for cluster %s", o.clusterID)
+	}
+	firing, err := client.GetFiringAlertsForCluster(serviceIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var incidents []pd.Incident
+	for _, serviceID := range serviceIDs {
+		incidents = append(incidents, firing[serviceID]...)
+	}
+	if len(incidents) == 0 {
+		return nil, nil, fmt.Errorf("cluster %s has no triggered or acknowledged incident", o.clusterID)
+	}
+	// RFC3339 timestamps in UTC sort chronologically
+	slices.SortStableFunc(incidents, func(a, b pd.Incident) int { return strings.Compare(a.CreatedAt, b.CreatedAt) })
+
+	fmt.Fprintf(o.Out, "Open incidents of cluster %s:\n", o.clusterID)
+	incidentIDs := make([]string, 0, len(incidents))
+	for _, incident := range incidents {
+		fmt.Fprintf(o.Out, "  %s  %-12s  %s\n", incident.ID, incident.Status, incident.Title)
+		incidentIDs = append(incidentIDs, incident.ID)
+	}
+	return client, incidentIDs, nil
+}
+
+// confirmed asks for confirmation before changing all the incidents of a cluster
+func (o *incidentOptions) confirmed(summary string) bool {
+	if o.clusterID == "" || o.yes {
+		return true
+	}
+	fmt.Fprintln(o.Out, summary)
+	return utils.StreamConfirmPrompt(o.IOStreams)
+}
+
+// noteContent returns the message followed by the output of every attached osdctl command
+func (o *incidentOptions) noteContent(ctx context.Context, message string, attachments []string) (string, error) {
+	var content strings.Builder
+	content.WriteString(message)
+
+	for _, attachment := range attachments {
+		attachment, err := utils.ExpandClusterID(attachment, o.clusterID)
+		if err != nil {
+			return "", err
+		}
+		args, err := utils.SplitOsdctlCommand(attachment)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(o.Out, "Running osdctl %s\n", shellquote.Join(args...))
+		output, err := o.osdctl.Run(ctx, args)
+		if err != nil {
+			return "", fmt.Errorf("attached command 'osdctl %s' failed: %w\n%s", shellquote.Join(args...), err, output)
+		}
+
+		if content.Len() > 0 {
+			content.WriteString("\n\n")
+		}
+		fmt.Fprintf(&content, "$ osdctl %s\n%s", shellquote.Join(args...), strings.TrimSpace(output))
+	}
+
+	note := content.String()
+	if len(note) > maxNoteLength {
+		const truncated = "\n... (truncated)"
+		note = strings.ToValidUTF8(note[:maxNoteLength-len(truncated)], "") + truncated
+	}
+	return note, nil
+}
@@ -0,0 +1,242 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/osdctl/cmd/pagerduty/mock"
+	pdMock "github.com/openshift/osdctl/pkg/provider/pagerduty/mocks"
+	utilsMocks "github.com/openshift/osdctl/pkg/utils/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// runIncidentCmd runs an incident subcommand with a mocked PagerDuty client, answering confirm to the
+// confirmation prompt, and returns its output
+func runIncidentCmd(t *testing.T, client *pdMock.MockClient, confirm bool, args ...string) (string, error) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	clients := mock.NewMockClients(ctrl)
+	clients.EXPECT().ClusterBaseDomain(gomock.Any()).DoAndReturn(func(clusterID string) (string, error) {
+		return clusterID + ".example.com", nil
+	}).AnyTimes()
+	clients.EXPECT().NewPagerDutyClient(gomock.Any(), gomock.Nil()).Return(client, nil).AnyTimes()
+	clients.EXPECT().Close().AnyTimes()
+
+	osdctl := utilsMocks.NewMockOsdctlRunner(ctrl)
+	osdctl.EXPECT().Run(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, args []string) (string, error) {
+		if args[0] == "fail" {
+			return "boom\n", errors.New("exit status 1")
+		}
+		return "output of " + strings.Join(args, " ") + "\n", nil
+	}).AnyTimes()
+
+	answer := "n\n"
+	if confirm {
+		answer = "y\n"
+	}
+	var out bytes.Buffer
+	cmd := incidentCmd(&incidentOptions{
+		clients:   clients,
+		osdctl:    osdctl,
+		IOStreams: genericclioptions.IOStreams{In: strings.NewReader(answer), Out: &out, ErrOut: &out},
+	})
+	cmd.SetArgs(args)
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func clusterIncidents() map[string][]pd.Incident {
+	return map[string][]pd.Incident{
+		"S1": {
+			{APIObject: pd.APIObject{ID: "NEW"}, Title: "KubeNodeNotReady", Status: "triggered", CreatedAt: "2026-01-02T00:00:00Z"},
+		},
+		"S2": {
+			{APIObject: pd.APIObject{ID: "OLD"}, Title: "ClusterOperatorDown", Status: "acknowledged", CreatedAt: "2026-01-01T00:00:00Z"},
+		},
+	}
+}
+
+func newClusterClient(t *testing.T) *pdMock.MockClient {
+	client := pdMock.NewMockClient(gomock.NewController(t))
+	client.EXPECT().GetPDServiceIDs().Return([]string{"S1", "S2"}, nil)
+	client.EXPECT().GetFiringAlertsForCluster([]string{"S1", "S2"}).Return(clusterIncidents(), nil)
+	return client
+}
+
+func TestIncidentTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "nothing selected", args: []string{"ack"}, wantErr: "pass incident ids or --cluster-id"},
+		{name: "ids and cluster", args: []string{"ack", "A", "--cluster-id", "abc"}, wantErr: "can't be used together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runIncidentCmd(t, pdMock.NewMockClient(gomock.NewController(t)), true, tt.args...)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("cluster without open incidents", func(t *testing.T) {
+		client := pdMock.NewMockClient(gomock.NewController(t))
+		client.EXPECT().GetPDServiceIDs().Return([]string{"S1"}, nil)
+		client.EXPECT().GetFiringAlertsForCluster([]string{"S1"}).Return(map[string][]pd.Incident{}, nil)
+		_, err := runIncidentCmd(t, client, true, "ack", "--cluster-id", "abc")
+		assert.EqualError(t, err, "cluster abc has no triggered or acknowledged incident")
+	})
+}
+
+func TestIncidentAck(t *testing.T) {
+	t.Run("by incident id", func(t *testing.T) {
+		client := pdMock.NewMockClient(gomock.NewController(t))
+		client.EXPECT().AcknowledgeIncidents([]string{"A", "B"}).Return(nil)
+
+		out, err := runIncidentCmd(t, client, false, "ack", "A", "B")
+		require.NoError(t, err)
+		assert.Equal(t, "Acknowledged 2 incident(s): A, B\n", out)
+	})
+
+	t.Run("by cluster, oldest first", func(t *testing.T) {
+		client := newClusterClient(t)
+		client.EXPECT().AcknowledgeIncidents([]string{"OLD", "NEW"}).Return(nil)
+
+		out, err := runIncidentCmd(t, client, false, "ack", "--cluster-id", "abc")
+		require.NoError(t, err)
+		assert.Contains(t, out, "Open incidents of cluster abc:")
+		assert.Contains(t, out, "Acknowledged 2 incident(s): OLD, NEW")
+	})
+}
+
+func TestIncidentResolve(t *testing.T) {
+	t.Run("by incident id without confirmation", func(t *testing.T) {
+		client := pdMock.NewMockClient(gomock.NewController(t))
+		client.EXPECT().ResolveIncidents([]string{"A"}).Return(nil)
+
+		_, err := runIncidentCmd(t, client, false, "resolve", "A")
+		require.NoError(t, err)
+	})
+
+	t.Run("by cluster declined", func(t *testing.T) {
+		client := newClusterClient(t)
+
+		// ResolveIncidents is not expected
+		out, err := runIncidentCmd(t, client, false, "resolve", "--cluster-id", "abc")
+		require.NoError(t, err)
+		assert.Contains(t, out, "This resolves 2 incident(s).")
+	})
+
+	t.Run("by cluster with --yes", func(t *testing.T) {
+		client := newClusterClient(t)
+		client.EXPECT().ResolveIncidents([]string{"OLD", "NEW"}).Return(nil)
+
+		_, err := runIncidentCmd(t, client, false, "resolve", "--cluster-id", "abc", "--yes")
+		require.NoError(t, err)
+	})
+}
+
+func TestIncidentNote(t *testing.T) {
+	t.Run("empty note", func(t *testing.T) {
+		_, err := runIncidentCmd(t, pdMock.NewMockClient(gomock.NewController(t)), true, "note", "A")
+		assert.EqualError(t, err, "the note is empty, set --message or --attach")
+	})
+
+	t.Run("message and attached commands", func(t *testing.T) {
+		client := newClusterClient(t)
+		content := "Checked the cluster\n\n" +
+			"$ osdctl cluster health -C abc\noutput of cluster health -C abc\n\n" +
+			"$ osdctl cluster context -C abc -o short\noutput of cluster context -C abc -o short"
+		client.EXPECT().AddIncidentNote("OLD", content).Return(nil)
+		client.EXPECT().AddIncidentNote("NEW", content).Return(nil)
+
+		_, err := runIncidentCmd(t, client, true, "note", "--cluster-id", "abc", "-m", "Checked the cluster",
+			"--attach", "cluster health -C {cluster_id}", "--attach", "osdctl cluster context -C '{cluster_id}' -o short")
+		require.NoError(t, err)
+	})
+
+	t.Run("placeholder without cluster", func(t *testing.T) {
+		_, err := runIncidentCmd(t, pdMock.NewMockClient(gomock.NewController(t)), true, "note", "A", "--attach", "cluster health -C {cluster_id}")
+		assert.EqualError(t, err, "{cluster_id} can only be used with --cluster-id")
+	})
+
+	t.Run("failing attached command", func(t *testing.T) {
+		client := pdMock.NewMockClient(gomock.NewController(t))
+		_, err := runIncidentCmd(t, client, true, "note", "A", "--attach", "fail")
+		assert.ErrorContains(t, err, "attached command 'osdctl fail' failed: exit status 1\nboom")
+	})
+
+	t.Run("truncated note", func(t *testing.T) {
+		client := pdMock.NewMockClient(gomock.NewController(t))
+		client.EXPECT().AddIncidentNote("A", gomock.Cond(func(content string) bool {
+			return len(content) == maxNoteLength && strings.HasSuffix(content, "... (truncated)")
+		})).Return(nil)
+
+		_, err := runIncidentCmd(t, client, true, "note", "A", "-m", strings.Repeat("x", maxNoteLength+1))
+		require.NoError(t, err)
+	})
+}
+
+func TestIncidentReassign(t *testing.T) {
+	client := pdMock.NewMockClient(gomock.NewController(t))
+	client.EXPECT().ReassignIncidents([]string{"A"}, []string{"a@example.com", "b@example.com"}, "").Return(nil)
+
+	_, err := runIncidentCmd(t, client, true, "reassign", "A", "--user", "a@example.com", "--user", "b@example.com")
+	require.NoError(t, err)
+
+	_, err = runIncidentCmd(t, pdMock.NewMockClient(gomock.NewController(t)), true, "reassign", "A")
+	assert.ErrorContains(t, err, "at least one of the flags in the group [user escalation-policy] is required")
+}
+
+func TestIncidentMerge(t *testing.T) {
+	t.Run("by incident id", func(t *testing.T) {
+		client := pdMock.NewMockClient(gomock.NewController(t))
+		client.EXPECT().MergeIncidents("A", []string{"B", "C"}).Return(nil)
+
+		out, err := runIncidentCmd(t, client, false, "merge", "A", "B", "C")
+		require.NoError(t, err)
+		assert.Equal(t, "Merged 2 incident(s) into A: B, C\n", out)
+	})
+
+	t.Run("single incident", func(t *testing.T) {
+		_, err := runIncidentCmd(t, pdMock.NewMockClient(gomock.NewController(t)), true, "merge", "A")
+		assert.EqualError(t, err, "at least two incidents are needed to merge")
+	})
+
+	t.Run("by cluster into the oldest", func(t *testing.T) {
+		client := newClusterClient(t)
+		client.EXPECT().MergeIncidents("OLD", []string{"NEW"}).Return(nil)
+
+		out, err := runIncidentCmd(t, client, true, "merge", "--cluster-id", "abc")
+		require.NoError(t, err)
+		assert.Contains(t, out, "This merges 1 incident(s) into OLD.")
+	})
+
+	t.Run("by cluster into the given incident", func(t *testing.T) {
+		client := newClusterClient(t)
+		client.EXPECT().MergeIncidents("NEW", []string{"OLD"}).Return(nil)
+
+		_, err := runIncidentCmd(t, client, true, "merge", "--cluster-id", "abc", "--into", "NEW")
+		require.NoError(t, err)
+	})
+
+	t.Run("into an incident of another cluster", func(t *testing.T) {
+		_, err := runIncidentCmd(t, newClusterClient(t), true, "merge", "--cluster-id", "abc", "--into", "OTHER")
+		assert.EqualError(t, err, "incident OTHER is not an open incident of cluster abc")
+	})
+
+	t.Run("--into without cluster", func(t *testing.T) {
+		_, err := runIncidentCmd(t, pdMock.NewMockClient(gomock.NewController(t)), true, "merge", "A", "B", "--into", "A")
+		assert.ErrorContains(t, err, "--into can only be used with --cluster-id")
+	})
+}
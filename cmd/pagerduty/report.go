@@ -0,0 +1,258 @@
+package pagerduty
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	pdProvider "github.com/openshift/osdctl/pkg/provider/pagerduty"
+)
+
+// incidentRecord is the part of an incident the analytics are computed from
+type incidentRecord struct {
+	ID          string
+	Alert       string
+	ServiceID   string
+	ServiceName string
+	CreatedAt   time.Time
+	// AcknowledgedAt and ResolvedAt are zero when the incident wasn't acknowledged or resolved
+	AcknowledgedAt time.Time
+	ResolvedAt     time.Time
+}
+
+// newIncidentRecords converts PagerDuty incidents, using acknowledgedAt for the first
+// acknowledgement of resolved incidents, as PagerDuty only returns the current acknowledgements
+func newIncidentRecords(incidents []pd.Incident, acknowledgedAt map[string]time.Time) ([]incidentRecord, error) {
+	records := make([]incidentRecord, 0, len(incidents))
+	for _, incident := range incidents {
+		createdAt, err := time.Parse(time.RFC3339, incident.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse creation time of incident %s: %w", incident.ID, err)
+		}
+		record := incidentRecord{
+			ID:          incident.ID,
+			Alert:       pdProvider.AlertName(incident.Title),
+			ServiceID:   incident.Service.ID,
+			ServiceName: incident.Service.Summary,
+			CreatedAt:   createdAt,
+		}
+		if record.ServiceName == "" {
+			record.ServiceName = incident.Service.ID
+		}
+
+		record.AcknowledgedAt = acknowledgedAt[incident.ID]
+		for _, ack := range incident.Acknowledgements {
+			at, err := time.Parse(time.RFC3339, ack.At)
+			if err == nil && (record.AcknowledgedAt.IsZero() || at.Before(record.AcknowledgedAt)) {
+				record.AcknowledgedAt = at
+			}
+		}
+
+		if incident.Status == "resolved" {
+			resolvedAt := incident.ResolvedAt
+			if resolvedAt == "" {
+				resolvedAt = incident.LastStatusChangeAt
+			}
+			if record.ResolvedAt, err = time.Parse(time.RFC3339, resolvedAt); err != nil {
+				return nil, fmt.Errorf("failed to parse resolution time of incident %s: %w", incident.ID, err)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// alertStats summarizes the incidents of an alert
+type alertStats struct {
+	Alert     string `json:"alert"`
+	Incidents int    `json:"incidents"`
+	Clusters  int    `json:"clusters"`
+	// Flaps counts the incidents which triggered again shortly after the previous one on the same cluster resolved
+	Flaps        int `json:"flaps"`
+	Acknowledged int `json:"acknowledged"`
+	Resolved     int `json:"resolved"`
+	// MTTASeconds and MTTRSeconds are the mean time to acknowledge and to resolve, 0 without data
+	MTTASeconds int64 `json:"mttaSeconds"`
+	MTTRSeconds int64 `json:"mttrSeconds"`
+	// Current and Previous count the incidents of the last two buckets, to spot alerts which got worse
+	Current  int `json:"current"`
+	Previous int `json:"previous"`
+}
+
+// clusterStats summarizes the incidents of a PagerDuty service, which is one per cluster
+type clusterStats struct {
+	ServiceID   string `json:"serviceId"`
+	ServiceName string `json:"serviceName"`
+	Incidents   int    `json:"incidents"`
+	Alerts      int    `json:"alerts"`
+	Flaps       int    `json:"flaps"`
+	TopAlert    string `json:"topAlert"`
+}
+
+// alertFrequency counts the incidents of an alert per bucket
+type alertFrequency struct {
+	Alert  string `json:"alert"`
+	Counts []int  `json:"counts"`
+}
+
+type report struct {
+	Since     time.Time        `json:"since"`
+	Until     time.Time        `json:"until"`
+	Buckets   []time.Time      `json:"buckets"`
+	Alerts    []alertStats     `json:"alerts"`
+	Clusters  []clusterStats   `json:"clusters"`
+	Frequency []alertFrequency `json:"frequency"`
+	// Failures are the clusters left out of the analysis
+	Failures []clusterFailure `json:"failures,omitempty"`
+}
+
+// clusterFailure is a cluster whose PagerDuty services could not be looked up
+type clusterFailure struct {
+	ClusterID string `json:"clusterId"`
+	Error     string `json:"error"`
+}
+
+// bucketStarts splits since..until in buckets of the given size, aligned on until so the last
+// bucket is always complete
+func bucketStarts(since time.Time, until time.Time, size time.Duration) []time.Time {
+	var starts []time.Time
+	for start := until.Add(-size); start.Add(size).After(since); start = start.Add(-size) {
+		starts = append(starts, start)
+	}
+	slices.Reverse(starts)
+	return starts
+}
+
+// bucketIndex returns the bucket of t, or -1 when t is outside of every bucket
+func bucketIndex(buckets []time.Time, size time.Duration, t time.Time) int {
+	for i, start := range buckets {
+		if !t.Before(start) && t.Before(start.Add(size)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// flaps returns the ids of the incidents which triggered within flapWindow of the end of the
+// previous incident of the same alert on the same cluster
+func flaps(records []incidentRecord, flapWindow time.Duration) map[string]bool {
+	type alertOnService struct{ alert, service string }
+	previous := map[alertOnService]incidentRecord{}
+	flapping := map[string]bool{}
+
+	sorted := slices.Clone(records)
+	slices.SortStableFunc(sorted, func(a, b incidentRecord) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	for _, record := range sorted {
+		key := alertOnService{record.Alert, record.ServiceID}
+		if last, found := previous[key]; found {
+			end := last.ResolvedAt
+			if end.IsZero() || end.After(record.CreatedAt) {
+				end = last.CreatedAt
+			}
+			if record.CreatedAt.Sub(end) <= flapWindow {
+				flapping[record.ID] = true
+			}
+		}
+		previous[key] = record
+	}
+	return flapping
+}
+
+// buildReport computes the analytics of the incidents created between since and until
+func buildReport(records []incidentRecord, since time.Time, until time.Time, bucketSize time.Duration, flapWindow time.Duration) report {
+	r := report{Since: since, Until: until, Buckets: bucketStarts(since, until, bucketSize)}
+	flapping := flaps(records, flapWindow)
+
+	type alertTotals struct {
+		stats              alertStats
+		clusters           map[string]bool
+		ackTotal, resTotal time.Duration
+		counts             []int
+	}
+	type clusterTotals struct {
+		stats  clusterStats
+		alerts map[string]int
+	}
+	alerts := map[string]*alertTotals{}
+	clusters := map[string]*clusterTotals{}
+
+	for _, record := range records {
+		a, found := alerts[record.Alert]
+		if !found {
+			a = &alertTotals{stats: alertStats{Alert: record.Alert}, clusters: map[string]bool{}, counts: make([]int, len(r.Buckets))}
+			alerts[record.Alert] = a
+		}
+		a.stats.Incidents++
+		a.clusters[record.ServiceID] = true
+		if flapping[record.ID] {
+			a.stats.Flaps++
+		}
+		if !record.AcknowledgedAt.IsZero() {
+			a.stats.Acknowledged++
+			a.ackTotal += record.AcknowledgedAt.Sub(record.CreatedAt)
+		}
+		if !record.ResolvedAt.IsZero() {
+			a.stats.Resolved++
+			a.resTotal += record.ResolvedAt.Sub(record.CreatedAt)
+		}
+		if i := bucketIndex(r.Buckets, bucketSize, record.CreatedAt); i >= 0 {
+			a.counts[i]++
+		}
+
+		c, found := clusters[record.ServiceID]
+		if !found {
+			c = &clusterTotals{stats: clusterStats{ServiceID: record.ServiceID, ServiceName: record.ServiceName}, alerts: map[string]int{}}
+			clusters[record.ServiceID] = c
+		}
+		c.stats.Incidents++
+		c.alerts[record.Alert]++
+		if flapping[record.ID] {
+			c.stats.Flaps++
+		}
+	}
+
+	for _, a := range alerts {
+		a.stats.Clusters = len(a.clusters)
+		if a.stats.Acknowledged > 0 {
+			a.stats.MTTASeconds = int64((a.ackTotal / time.Duration(a.stats.Acknowledged)).Seconds())
+		}
+		if a.stats.Resolved > 0 {
+			a.stats.MTTRSeconds = int64((a.resTotal / time.Duration(a.stats.Resolved)).Seconds())
+		}
+		if n := len(a.counts); n > 0 {
+			a.stats.Current = a.counts[n-1]
+		}
+		if n := len(a.counts); n > 1 {
+			a.stats.Previous = a.counts[n-2]
+		}
+		r.Alerts = append(r.Alerts, a.stats)
+	}
+	for _, c := range clusters {
+		c.stats.Alerts = len(c.alerts)
+		for alert, count := range c.alerts {
+			if count > c.alerts[c.stats.TopAlert] || (count == c.alerts[c.stats.TopAlert] && alert < c.stats.TopAlert) {
+				c.stats.TopAlert = alert
+			}
+		}
+		r.Clusters = append(r.Clusters, c.stats)
+	}
+
+	slices.SortFunc(r.Alerts, func(a, b alertStats) int {
+		if a.Incidents != b.Incidents {
+			return b.Incidents - a.Incidents
+		}
+		return strings.Compare(a.Alert, b.Alert)
+	})
+	slices.SortFunc(r.Clusters, func(a, b clusterStats) int {
+		if a.Incidents != b.Incidents {
+			return b.Incidents - a.Incidents
+		}
+		return strings.Compare(a.ServiceName, b.ServiceName)
+	})
+	for _, stats := range r.Alerts {
+		r.Frequency = append(r.Frequency, alertFrequency{Alert: stats.Alert, Counts: alerts[stats.Alert].counts})
+	}
+	return r
+}
@@ -0,0 +1,137 @@
+package pagerduty
+
+import (
+	"testing"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIncidentRecords(t *testing.T) {
+	incidents := []pd.Incident{
+		{
+			APIObject:  pd.APIObject{ID: "resolved"},
+			Title:      "ClusterOperatorDown CRITICAL (1)",
+			CreatedAt:  "2026-01-01T00:00:00Z",
+			Service:    pd.APIObject{ID: "S1", Summary: "cluster-a"},
+			Status:     "resolved",
+			ResolvedAt: "2026-01-01T01:00:00Z",
+		},
+		{
+			APIObject:          pd.APIObject{ID: "no-resolved-at"},
+			Title:              "ClusterOperatorDown CRITICAL (2)",
+			CreatedAt:          "2026-01-02T00:00:00Z",
+			Service:            pd.APIObject{ID: "S2"},
+			Status:             "resolved",
+			LastStatusChangeAt: "2026-01-02T00:30:00Z",
+		},
+		{
+			APIObject:        pd.APIObject{ID: "acknowledged"},
+			Title:            "KubeNodeNotReady",
+			CreatedAt:        "2026-01-03T00:00:00Z",
+			Service:          pd.APIObject{ID: "S1", Summary: "cluster-a"},
+			Status:           "acknowledged",
+			Acknowledgements: []pd.Acknowledgement{{At: "2026-01-03T00:05:00Z"}},
+		},
+	}
+	acknowledgedAt := map[string]time.Time{
+		"resolved":     time.Date(2026, 1, 1, 0, 10, 0, 0, time.UTC),
+		"acknowledged": time.Date(2026, 1, 3, 0, 7, 0, 0, time.UTC),
+	}
+
+	records, err := newIncidentRecords(incidents, acknowledgedAt)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, "ClusterOperatorDown CRITICAL", records[0].Alert)
+	assert.Equal(t, "cluster-a", records[0].ServiceName)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 10, 0, 0, time.UTC), records[0].AcknowledgedAt)
+	assert.Equal(t, time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC), records[0].ResolvedAt)
+
+	assert.Equal(t, records[0].Alert, records[1].Alert)
+	assert.Equal(t, "S2", records[1].ServiceName)
+	assert.True(t, records[1].AcknowledgedAt.IsZero())
+	assert.Equal(t, time.Date(2026, 1, 2, 0, 30, 0, 0, time.UTC), records[1].ResolvedAt)
+
+	assert.Equal(t, time.Date(2026, 1, 3, 0, 5, 0, 0, time.UTC), records[2].AcknowledgedAt)
+	assert.True(t, records[2].ResolvedAt.IsZero())
+
+	_, err = newIncidentRecords([]pd.Incident{{CreatedAt: "yesterday"}}, nil)
+	assert.Error(t, err)
+}
+
+func TestBucketStarts(t *testing.T) {
+	until := time.Date(2026, 1, 29, 12, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+
+	starts := bucketStarts(until.AddDate(0, 0, -28), until, week)
+	require.Len(t, starts, 4)
+	assert.Equal(t, until.AddDate(0, 0, -28), starts[0])
+	assert.Equal(t, until.Add(-week), starts[3])
+
+	// A partial bucket at the start of the range is kept
+	starts = bucketStarts(until.AddDate(0, 0, -10), until, week)
+	require.Len(t, starts, 2)
+	assert.Equal(t, until.AddDate(0, 0, -14), starts[0])
+
+	assert.Equal(t, 0, bucketIndex(starts, week, until.AddDate(0, 0, -10)))
+	assert.Equal(t, 1, bucketIndex(starts, week, until.Add(-time.Minute)))
+	assert.Equal(t, -1, bucketIndex(starts, week, until))
+}
+
+func TestBuildReport(t *testing.T) {
+	until := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	since := until.AddDate(0, 0, -14)
+	at := func(days int, hours int, minutes int) time.Time {
+		return since.AddDate(0, 0, days).Add(time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute)
+	}
+	records := []incidentRecord{
+		// Flapping on cluster a: triggers 30 minutes after being resolved, twice
+		{ID: "1", Alert: "Flappy", ServiceID: "a", ServiceName: "cluster-a", CreatedAt: at(1, 0, 0), AcknowledgedAt: at(1, 0, 10), ResolvedAt: at(1, 1, 0)},
+		{ID: "2", Alert: "Flappy", ServiceID: "a", ServiceName: "cluster-a", CreatedAt: at(1, 1, 30), AcknowledgedAt: at(1, 1, 50), ResolvedAt: at(1, 2, 30)},
+		{ID: "3", Alert: "Flappy", ServiceID: "a", ServiceName: "cluster-a", CreatedAt: at(1, 3, 0), ResolvedAt: at(1, 4, 0)},
+		// Same alert on another cluster isn't a flap of cluster a
+		{ID: "4", Alert: "Flappy", ServiceID: "b", ServiceName: "cluster-b", CreatedAt: at(1, 4, 30)},
+		// Got worse in the last week
+		{ID: "5", Alert: "Worse", ServiceID: "b", ServiceName: "cluster-b", CreatedAt: at(2, 0, 0), ResolvedAt: at(2, 2, 0)},
+		{ID: "6", Alert: "Worse", ServiceID: "b", ServiceName: "cluster-b", CreatedAt: at(8, 0, 0), ResolvedAt: at(8, 4, 0)},
+		{ID: "7", Alert: "Worse", ServiceID: "c", ServiceName: "cluster-c", CreatedAt: at(9, 0, 0)},
+	}
+
+	r := buildReport(records, since, until, 7*24*time.Hour, time.Hour)
+	require.Len(t, r.Buckets, 2)
+
+	require.Len(t, r.Alerts, 2)
+	flappy := r.Alerts[0]
+	assert.Equal(t, alertStats{
+		Alert:        "Flappy",
+		Incidents:    4,
+		Clusters:     2,
+		Flaps:        2,
+		Acknowledged: 2,
+		Resolved:     3,
+		MTTASeconds:  int64((15 * time.Minute).Seconds()),
+		MTTRSeconds:  int64(time.Hour.Seconds()),
+		Current:      0,
+		Previous:     4,
+	}, flappy)
+	worse := r.Alerts[1]
+	assert.Equal(t, "Worse", worse.Alert)
+	assert.Equal(t, 0, worse.Flaps)
+	assert.Equal(t, 0, worse.Acknowledged)
+	assert.Equal(t, int64((3 * time.Hour).Seconds()), worse.MTTRSeconds)
+	assert.Equal(t, 2, worse.Current)
+	assert.Equal(t, 1, worse.Previous)
+
+	require.Len(t, r.Clusters, 3)
+	assert.Equal(t, clusterStats{ServiceID: "a", ServiceName: "cluster-a", Incidents: 3, Alerts: 1, Flaps: 2, TopAlert: "Flappy"}, r.Clusters[0])
+	assert.Equal(t, clusterStats{ServiceID: "b", ServiceName: "cluster-b", Incidents: 3, Alerts: 2, Flaps: 0, TopAlert: "Worse"}, r.Clusters[1])
+	assert.Equal(t, "c", r.Clusters[2].ServiceID)
+
+	assert.Equal(t, []alertFrequency{
+		{Alert: "Flappy", Counts: []int{4, 0}},
+		{Alert: "Worse", Counts: []int{1, 2}},
+	}, r.Frequency)
+}
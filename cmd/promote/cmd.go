@@ -6,6 +6,7 @@ import (
 	"github.com/openshift/osdctl/cmd/promote/dynatrace"
 	"github.com/openshift/osdctl/cmd/promote/pko"
 	"github.com/openshift/osdctl/cmd/promote/saas"
+	"github.com/openshift/osdctl/cmd/promote/status"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +22,7 @@ func NewCmdPromote() *cobra.Command {
 	promoteCmd.AddCommand(saas.NewCmdSaas())
 	promoteCmd.AddCommand(pko.NewCmdPKO())
 	promoteCmd.AddCommand(dynatrace.NewCmdDynatrace())
+	promoteCmd.AddCommand(status.NewCmdStatus())
 
 	return promoteCmd
 }
@@ -147,6 +147,10 @@ func GetCurrentGitHashAndPathFromAppInterface(saarYamlFile []byte, serviceName s
 
 	if namespaceRef != "" {
 		for _, resourceTemplate := range service.ResourceTemplates {
+			// Package templates deploy the same namespaces with a PACKAGE_TAG instead of a ref
+			if strings.Contains(resourceTemplate.Name, "package") {
+				continue
+			}
 			for _, target := range resourceTemplate.Targets {
 				if strings.Contains(target.Namespace["$ref"], namespaceRef) {
 					currentGitHash = target.Ref
@@ -239,8 +243,13 @@ func GetCurrentPackageTagFromAppInterface(saasFile string) (string, error) {
 		return "", fmt.Errorf("failed to read file '%s': %w", saasFile, err)
 	}
 
+	return GetPackageTagFromSaasData(saasData)
+}
+
+// GetPackageTagFromSaasData returns the PACKAGE_TAG deployed to the prod hives in the given saas file content
+func GetPackageTagFromSaasData(saasData []byte) (string, error) {
 	service := Service{}
-	err = yaml.Unmarshal(saasData, &service)
+	err := yaml.Unmarshal(saasData, &service)
 	if err != nil {
 		return "", fmt.Errorf("failed to unmarshal service definition: %w", err)
 	}
@@ -264,8 +273,180 @@ func GetCurrentPackageTagFromAppInterface(saasFile string) (string, error) {
 	return currentPackageTag, nil
 }
 
+// GetPackageRepoFromSaasData returns the repository the package of a package-operator service is built from
+func GetPackageRepoFromSaasData(saasData []byte) (string, error) {
+	service := Service{}
+	if err := yaml.Unmarshal(saasData, &service); err != nil {
+		return "", fmt.Errorf("failed to unmarshal service definition: %w", err)
+	}
+
+	for _, resourceTemplate := range service.ResourceTemplates {
+		if strings.Contains(resourceTemplate.Name, "package") && resourceTemplate.URL != "" {
+			return resourceTemplate.URL, nil
+		}
+	}
+	return "", fmt.Errorf("package repo not found for service %s", service.Name)
+}
+
+// FindPreviousValue walks the app-interface master history of saasFile, newest first, and returns the
+// first value extracted by extract that differs from current, along with the commit it was found in.
+// It is used to find the hash or tag that was deployed before the current promotion.
+func (a *AppInterface) FindPreviousValue(saasFile, current string, extract func(saasData []byte) (string, error)) (string, string, error) {
+	relPath, err := filepath.Rel(a.GitDirectory, saasFile)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get path of %s relative to %s: %v", saasFile, a.GitDirectory, err)
+	}
+
+	output, err := a.GitExecutor.Output(a.GitDirectory, "git", "log", "--format=%H", "master", "--", relPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read git history of %s: %v", relPath, err)
+	}
+
+	for _, commit := range strings.Fields(output) {
+		content, err := a.GitExecutor.Output(a.GitDirectory, "git", "show", fmt.Sprintf("%s:%s", commit, relPath))
+		if err != nil {
+			// The file may have been moved or renamed at this point in history
+			break
+		}
+
+		value, err := extract([]byte(content))
+		if err != nil || value == "" {
+			continue
+		}
+		if value != current {
+			return value, commit, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("no previous value found in the history of %s", relPath)
+}
+
+// RenderSaasRollback returns the saas file content with every target currently at currentGitHash set
+// to rollbackGitHash. When namespaceRefs is not empty only targets whose namespace $ref contains one
+// of them are changed. The returned bool is false if no target was changed.
+func RenderSaasRollback(fileContent, currentGitHash, rollbackGitHash string, namespaceRefs []string) (string, error, bool) {
+	node, err := kyaml.Parse(fileContent)
+	if err != nil {
+		return "", fmt.Errorf("error parsing saas YAML: %v", err), false
+	}
+	targetFound := false
+	rts, err := kyaml.Lookup("resourceTemplates").Filter(node)
+	if err != nil {
+		return "", fmt.Errorf("error querying resource templates: %v", err), false
+	}
+	for i := range len(rts.Content()) {
+		targets, err := kyaml.Lookup("resourceTemplates", strconv.Itoa(i), "targets").Filter(node)
+		if err != nil {
+			return "", fmt.Errorf("error querying saas YAML: %v", err), false
+		}
+		err = targets.VisitElements(func(element *kyaml.RNode) error {
+			ref, _ := element.GetString("ref")
+			if ref != currentGitHash {
+				return nil
+			}
+			namespaceRef, _ := element.GetString("namespace.$ref")
+			if !matchesAnyNamespaceRef(namespaceRef, namespaceRefs) {
+				return nil
+			}
+			targetFound = true
+			name, _ := element.GetString("name")
+			fmt.Printf("rolling back target: %s %s\n", name, namespaceRef)
+			if _, err := element.Pipe(kyaml.SetField("ref", kyaml.NewStringRNode(rollbackGitHash))); err != nil {
+				return fmt.Errorf("error setting ref: %v", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err, false
+		}
+	}
+	return node.MustString(), nil, targetFound
+}
+
+// RenderPackageTagRollback returns the saas file content with the PACKAGE_TAG parameter of every package
+// target currently at currentTag set to rollbackTag. The returned bool is false if no target was changed.
+func RenderPackageTagRollback(fileContent, currentTag, rollbackTag string) (string, error, bool) {
+	node, err := kyaml.Parse(fileContent)
+	if err != nil {
+		return "", fmt.Errorf("error parsing saas YAML: %v", err), false
+	}
+	targetFound := false
+	rts, err := kyaml.Lookup("resourceTemplates").Filter(node)
+	if err != nil {
+		return "", fmt.Errorf("error querying resource templates: %v", err), false
+	}
+	rtElements, err := rts.Elements()
+	if err != nil {
+		return "", fmt.Errorf("error querying resource templates: %v", err), false
+	}
+	for _, rt := range rtElements {
+		name, _ := rt.GetString("name")
+		if !strings.Contains(name, "package") {
+			continue
+		}
+		targets, err := kyaml.Lookup("targets").Filter(rt)
+		if err != nil {
+			return "", fmt.Errorf("error querying saas YAML: %v", err), false
+		}
+		if targets == nil {
+			continue
+		}
+		err = targets.VisitElements(func(element *kyaml.RNode) error {
+			tag, _ := element.GetString("parameters.PACKAGE_TAG")
+			if tag != currentTag {
+				return nil
+			}
+			targetFound = true
+			if _, err := element.Pipe(kyaml.Lookup("parameters"), kyaml.SetField("PACKAGE_TAG", kyaml.NewStringRNode(rollbackTag))); err != nil {
+				return fmt.Errorf("error setting PACKAGE_TAG: %v", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err, false
+		}
+	}
+	return node.MustString(), nil, targetFound
+}
+
+func matchesAnyNamespaceRef(namespaceRef string, namespaceRefs []string) bool {
+	if len(namespaceRefs) == 0 {
+		return true
+	}
+	for _, ref := range namespaceRefs {
+		if strings.Contains(namespaceRef, ref) {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *AppInterface) UpdateAppInterface(_, saasFile, currentGitHash, promotionGitHash, branchName string, hotfix bool) error {
+	if err := a.CreatePromotionBranch(branchName); err != nil {
+		return err
+	}
+
+	// Update the hash in the SAAS file
+	fileContent, err := os.ReadFile(saasFile)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %v", saasFile, err)
+	}
+
+	newContent, err := RenderSaasPromotion(string(fileContent), currentGitHash, promotionGitHash, hotfix)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(saasFile, []byte(newContent), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write to file %s: %v", saasFile, err)
+	}
+
+	return nil
+}
 
+// CreatePromotionBranch (re)creates branchName from master and checks it out
+func (a *AppInterface) CreatePromotionBranch(branchName string) error {
 	if err := a.GitExecutor.Run(a.GitDirectory, "git", "checkout", "master"); err != nil {
 		return fmt.Errorf("failed to checkout master: branch %v", err)
 	}
@@ -277,37 +458,52 @@ func (a *AppInterface) UpdateAppInterface(_, saasFile, currentGitHash, promotion
 	if err := a.GitExecutor.Run(a.GitDirectory, "git", "checkout", "-b", branchName, "master"); err != nil {
 		return fmt.Errorf("failed to create branch %s: %v, does it already exist? If so, please delete it with `git branch -D %s` first", branchName, err, branchName)
 	}
-	// Update the hash in the SAAS file
+
+	return nil
+}
+
+// RewriteSaasFile (re)creates branchName from master and rewrites saasFile with render. The file is read
+// once the branch is created, so changes merged to master since it was last read are kept.
+func (a *AppInterface) RewriteSaasFile(saasFile, branchName string, render func(content string) (string, error)) error {
+	if err := a.CreatePromotionBranch(branchName); err != nil {
+		return err
+	}
+
 	fileContent, err := os.ReadFile(saasFile)
 	if err != nil {
-		return fmt.Errorf("failed to read file %s: %v", saasFile, err)
+		return fmt.Errorf("failed to read file %s: %w", saasFile, err)
+	}
+	newContent, err := render(string(fileContent))
+	if err != nil {
+		return err
 	}
-	var newContent string
-	var canaryTargetsSetUp bool
+	if err := os.WriteFile(saasFile, []byte(newContent), 0600); err != nil {
+		return fmt.Errorf("failed to write to file %s: %w", saasFile, err)
+	}
+	return nil
+}
 
+// RenderSaasPromotion returns the saas file content with currentGitHash promoted to promotionGitHash.
+// Only canary targets are updated when they exist, unless hotfix is set.
+func RenderSaasPromotion(fileContent, currentGitHash, promotionGitHash string, hotfix bool) (string, error) {
 	// If this is a hotfix, update all targets to bypass progressive delivery
 	if hotfix {
 		fmt.Println("hotfix mode: updating all targets to bypass progressive delivery")
-		newContent = strings.ReplaceAll(string(fileContent), currentGitHash, promotionGitHash)
-	} else {
-		// If canary targets are set up in saas, replace the hash only in canary targets in the file content
-		// Otherwise proceed to promoting to all prod hives.
-		newContent, err, canaryTargetsSetUp = replaceTargetSha(string(fileContent), canaryStr, promotionGitHash)
-		if err != nil {
-			return fmt.Errorf("error modifying YAML: %v", err)
-		}
-		if !canaryTargetsSetUp {
-			fmt.Println("canary targets not set, continuing to replace all occurrences of sha.")
-			newContent = strings.ReplaceAll(string(fileContent), currentGitHash, promotionGitHash)
-		}
+		return strings.ReplaceAll(fileContent, currentGitHash, promotionGitHash), nil
 	}
 
-	err = os.WriteFile(saasFile, []byte(newContent), 0600)
+	// If canary targets are set up in saas, replace the hash only in canary targets in the file content
+	// Otherwise proceed to promoting to all prod hives.
+	newContent, err, canaryTargetsSetUp := replaceTargetSha(fileContent, canaryStr, promotionGitHash)
 	if err != nil {
-		return fmt.Errorf("failed to write to file %s: %v", saasFile, err)
+		return "", fmt.Errorf("error modifying YAML: %v", err)
+	}
+	if !canaryTargetsSetUp {
+		fmt.Println("canary targets not set, continuing to replace all occurrences of sha.")
+		newContent = strings.ReplaceAll(fileContent, currentGitHash, promotionGitHash)
 	}
 
-	return nil
+	return newContent, nil
 }
 
 func (a *AppInterface) UpdatePackageTag(saasFile, oldTag, promotionTag, branchName string) error {
@@ -781,3 +781,252 @@ func TestCheckAppInterfaceCheckout(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderSaasPromotion(t *testing.T) {
+	canaryContent := `resourceTemplates:
+- name: template1
+  targets:
+  - name: target-prod-canary
+    ref: currentGitHash
+  - name: target-prod
+    ref: currentGitHash
+`
+	noCanaryContent := `resourceTemplates:
+- name: template1
+  targets:
+  - name: target-prod
+    ref: currentGitHash
+  - name: target-stage
+    ref: currentGitHash
+`
+
+	tests := map[string]struct {
+		content      string
+		hotfix       bool
+		expectedRefs int
+		untouched    int
+	}{
+		"canary_targets_only": {
+			content:      canaryContent,
+			expectedRefs: 1,
+			untouched:    1,
+		},
+		"hotfix_updates_all_targets": {
+			content:      canaryContent,
+			hotfix:       true,
+			expectedRefs: 2,
+			untouched:    0,
+		},
+		"no_canary_updates_all_targets": {
+			content:      noCanaryContent,
+			expectedRefs: 2,
+			untouched:    0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			newContent, err := RenderSaasPromotion(tt.content, "currentGitHash", "promotionGitHash", tt.hotfix)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRefs, strings.Count(newContent, "promotionGitHash"))
+			assert.Equal(t, tt.untouched, strings.Count(newContent, "currentGitHash"))
+		})
+	}
+}
+
+func TestFindPreviousValue(t *testing.T) {
+	gitDir := "/path/to/app-interface"
+	saasFile := filepath.Join(gitDir, "data", "saas.yaml")
+	extract := func(saasData []byte) (string, error) {
+		value := strings.TrimSpace(string(saasData))
+		if value == "broken" {
+			return "", errors.New("cannot parse")
+		}
+		return value, nil
+	}
+
+	tests := map[string]struct {
+		setupMock      func(m *MockExec)
+		expectedValue  string
+		expectedCommit string
+		expectedErr    string
+	}{
+		"finds_first_different_value": {
+			setupMock: func(m *MockExec) {
+				m.On("Output", gitDir, "git", []string{"log", "--format=%H", "master", "--", "data/saas.yaml"}).Return("c3\nc2\nc1\n", nil)
+				m.On("Output", gitDir, "git", []string{"show", "c3:data/saas.yaml"}).Return("current", nil)
+				m.On("Output", gitDir, "git", []string{"show", "c2:data/saas.yaml"}).Return("broken", nil)
+				m.On("Output", gitDir, "git", []string{"show", "c1:data/saas.yaml"}).Return("previous", nil)
+			},
+			expectedValue:  "previous",
+			expectedCommit: "c1",
+		},
+		"no_previous_value": {
+			setupMock: func(m *MockExec) {
+				m.On("Output", gitDir, "git", []string{"log", "--format=%H", "master", "--", "data/saas.yaml"}).Return("c1\n", nil)
+				m.On("Output", gitDir, "git", []string{"show", "c1:data/saas.yaml"}).Return("current", nil)
+			},
+			expectedErr: "no previous value found",
+		},
+		"git_log_fails": {
+			setupMock: func(m *MockExec) {
+				m.On("Output", gitDir, "git", []string{"log", "--format=%H", "master", "--", "data/saas.yaml"}).Return("", errors.New("boom"))
+			},
+			expectedErr: "failed to read git history",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockExec := new(MockExec)
+			tt.setupMock(mockExec)
+			app := AppInterface{GitDirectory: gitDir, GitExecutor: mockExec}
+
+			value, commit, err := app.FindPreviousValue(saasFile, "current", extract)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedValue, value)
+				assert.Equal(t, tt.expectedCommit, commit)
+			}
+			mockExec.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRenderSaasRollback(t *testing.T) {
+	content := `resourceTemplates:
+- name: template1
+  targets:
+  - name: hivep01ue1-prod-canary
+    namespace:
+      $ref: /services/osd-operators/namespaces/hivep01ue1/foo.yml
+    ref: currentGitHash
+  - name: hivep02ue1-prod
+    namespace:
+      $ref: /services/osd-operators/namespaces/hivep02ue1/foo.yml
+    ref: currentGitHash
+  - name: hives02ue1-stage
+    namespace:
+      $ref: /services/osd-operators/namespaces/hives02ue1/foo.yml
+    ref: newerGitHash
+`
+
+	tests := map[string]struct {
+		namespaceRefs []string
+		rolledBack    int
+		found         bool
+	}{
+		"all_targets_at_current_hash": {
+			rolledBack: 2,
+			found:      true,
+		},
+		"selected_namespace_refs": {
+			namespaceRefs: []string{"hivep02ue1"},
+			rolledBack:    1,
+			found:         true,
+		},
+		"no_matching_namespace_refs": {
+			namespaceRefs: []string{"hivep99ue1"},
+			rolledBack:    0,
+			found:         false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			newContent, err, found := RenderSaasRollback(content, "currentGitHash", "previousGitHash", tt.namespaceRefs)
+			require.NoError(t, err)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.rolledBack, strings.Count(newContent, "previousGitHash"))
+			assert.Equal(t, 1, strings.Count(newContent, "newerGitHash"))
+		})
+	}
+}
+
+func TestRenderPackageTagRollback(t *testing.T) {
+	// The tag also appears in the ref of the deploy template and in a comment, which must be left alone
+	content := `resourceTemplates:
+- name: foo-deploy
+  url: https://github.com/openshift/foo
+  targets:
+  - name: hivep01ue1-prod
+    ref: abc1234 # matches the package
+- name: foo-package
+  url: https://github.com/openshift/foo
+  targets:
+  - name: hivep01ue1-prod
+    parameters:
+      PACKAGE_TAG: abc1234
+  - name: hives02ue1-stage
+    parameters:
+      PACKAGE_TAG: def5678
+`
+
+	newContent, err, found := RenderPackageTagRollback(content, "abc1234", "0123abc")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Contains(t, newContent, "PACKAGE_TAG: 0123abc")
+	assert.Contains(t, newContent, "ref: abc1234 # matches the package")
+	assert.Contains(t, newContent, "PACKAGE_TAG: def5678")
+
+	_, err, found = RenderPackageTagRollback(content, "missing", "0123abc")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	repo, err := GetPackageRepoFromSaasData([]byte(content))
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/openshift/foo", repo)
+}
+
+func TestRewriteSaasFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	saasFile := filepath.Join(tmpDir, "saas.yaml")
+	require.NoError(t, os.WriteFile(saasFile, []byte(`resourceTemplates:
+- name: template
+  targets:
+  - name: target-prod
+    ref: currentGitHash
+`), 0600))
+
+	// A change merged to master since the file was first read shows up once the branch is created from master
+	merged := `resourceTemplates:
+- name: template
+  parameters:
+    REPLICAS: 3
+  targets:
+  - name: target-prod
+    ref: currentGitHash
+`
+	mockExec := new(MockExec)
+	mockExec.On("Run", tmpDir, "git", []string{"checkout", "master"}).Return(nil).Once()
+	mockExec.On("Run", tmpDir, "git", []string{"branch", "-D", "rollback"}).Return(nil).Once()
+	mockExec.On("Run", tmpDir, "git", []string{"checkout", "-b", "rollback", "master"}).Run(func(mock.Arguments) {
+		require.NoError(t, os.WriteFile(saasFile, []byte(merged), 0600))
+	}).Return(nil).Once()
+
+	app := AppInterface{GitDirectory: tmpDir, GitExecutor: mockExec}
+	err := app.RewriteSaasFile(saasFile, "rollback", func(content string) (string, error) {
+		newContent, err, found := RenderSaasRollback(content, "currentGitHash", "rollbackGitHash", nil)
+		require.True(t, found)
+		return newContent, err
+	})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(saasFile)
+	require.NoError(t, err)
+	assert.Equal(t, strings.ReplaceAll(merged, "currentGitHash", "rollbackGitHash"), string(content))
+	mockExec.AssertExpectations(t)
+
+	t.Run("render error", func(t *testing.T) {
+		mockExec := new(MockExec)
+		mockExec.On("Run", tmpDir, "git", mock.Anything).Return(nil)
+		app := AppInterface{GitDirectory: tmpDir, GitExecutor: mockExec}
+		err := app.RewriteSaasFile(saasFile, "rollback", func(string) (string, error) {
+			return "", errors.New("no targets")
+		})
+		assert.EqualError(t, err, "no targets")
+	})
+}
@@ -1,13 +1,18 @@
 package git
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
 
 	"github.com/openshift/osdctl/cmd/promote/iexec"
 )
 
+// ErrAlreadyAtHead is returned by CheckoutAndCompareGitHash when there is nothing new to promote
+var ErrAlreadyAtHead = errors.New("already at HEAD")
+
 func CheckoutAndCompareGitHash(gitExecutor iexec.IExec, gitURL, gitHash, currentGitHash string, serviceFullPath string) (string, string, error) {
 	tempDir, err := os.MkdirTemp("", "")
 	if err != nil {
@@ -46,7 +51,7 @@ func CheckoutAndCompareGitHash(gitExecutor iexec.IExec, gitURL, gitHash, current
 	}
 
 	if currentGitHash == gitHash {
-		return "", "", fmt.Errorf("git hash %s is already at HEAD", gitHash)
+		return "", "", fmt.Errorf("git hash %s is %w", gitHash, ErrAlreadyAtHead)
 	} else {
 		var commitLog string
 		var err error
@@ -62,3 +67,29 @@ func CheckoutAndCompareGitHash(gitExecutor iexec.IExec, gitURL, gitHash, current
 		return gitHash, commitLog, nil
 	}
 }
+
+// CompareURL returns the web URL comparing from with to in the repository at repoURL, along with the name
+// of the forge hosting it. Both are empty for hosts whose compare URLs are unknown.
+func CompareURL(repoURL, from, to string) (string, string) {
+	repo := strings.TrimSuffix(strings.TrimSuffix(repoURL, "/"), ".git")
+	u, err := url.Parse(repo)
+	if err != nil {
+		return "", ""
+	}
+
+	switch {
+	case u.Host == "github.com":
+		return fmt.Sprintf("%s/compare/%s...%s", repo, from, to), "GitHub"
+	case strings.Contains(u.Host, "gitlab"):
+		return fmt.Sprintf("%s/-/compare/%s...%s", repo, from, to), "GitLab"
+	}
+	return "", ""
+}
+
+// ShortHash abbreviates a git hash to the 7 characters git shows by default
+func ShortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
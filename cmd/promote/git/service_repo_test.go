@@ -180,3 +180,38 @@ func TestCheckoutAndCompareGitHash_WithEmptyPathFilter(t *testing.T) {
 	assert.Equal(t, expectedCommitLog, commitLog)
 	mockExec.AssertExpectations(t)
 }
+
+func TestCompareURL(t *testing.T) {
+	tests := map[string]struct {
+		repoURL string
+		url     string
+		forge   string
+	}{
+		"github": {
+			repoURL: "https://github.com/openshift/foo",
+			url:     "https://github.com/openshift/foo/compare/old...new",
+			forge:   "GitHub",
+		},
+		"github_with_git_suffix": {
+			repoURL: "https://github.com/openshift/foo.git",
+			url:     "https://github.com/openshift/foo/compare/old...new",
+			forge:   "GitHub",
+		},
+		"gitlab": {
+			repoURL: "https://gitlab.cee.redhat.com/service/foo/",
+			url:     "https://gitlab.cee.redhat.com/service/foo/-/compare/old...new",
+			forge:   "GitLab",
+		},
+		"unknown_host": {
+			repoURL: "https://git.example.com/foo",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			url, forge := CompareURL(tt.repoURL, "old", "new")
+			assert.Equal(t, tt.url, url)
+			assert.Equal(t, tt.forge, forge)
+		})
+	}
+}
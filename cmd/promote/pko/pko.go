@@ -2,6 +2,7 @@ package pko
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/openshift/osdctl/cmd/promote/git"
 	"github.com/openshift/osdctl/cmd/promote/iexec"
@@ -20,7 +21,10 @@ func NewCmdPKO() *cobra.Command {
 		DisableAutoGenTag: true,
 		Example: `
  # Promote a package-operator service
- osdctl promote package --serviceName <serviceName> --gitHash <git-hash>`,
+ osdctl promote package --serviceName <serviceName> --gitHash <git-hash>
+
+ # Roll a package-operator service back to the previously promoted package tag
+ osdctl promote package --serviceName <serviceName> --rollback`,
 		Run: func(cmd *cobra.Command, args []string) {
 			// Set default directory if not provided
 			if ops.appInterfaceCheckoutDir == "" {
@@ -29,6 +33,10 @@ func NewCmdPKO() *cobra.Command {
 
 			cmdutil.CheckErr(ops.ValidatePKOOptions())
 			appInterface := git.BootstrapOsdCtlForAppInterfaceAndServicePromotions(ops.appInterfaceCheckoutDir, iexec.Exec{})
+			if ops.rollback {
+				cmdutil.CheckErr(RollbackPackage(appInterface, ops.serviceName, ops.packageTag, ops.hcp))
+				return
+			}
 			cmdutil.CheckErr(PromotePackage(appInterface, ops.serviceName, ops.packageTag, ops.hcp))
 		},
 	}
@@ -37,6 +45,7 @@ func NewCmdPKO() *cobra.Command {
 	pkoCmd.Flags().StringVarP(&ops.packageTag, "tag", "t", "", "Package tag being promoted to")
 	pkoCmd.Flags().StringVarP(&ops.appInterfaceCheckoutDir, "appInterfaceDir", "", "", "location of app-interface checkout. Falls back to current working directory")
 	pkoCmd.Flags().BoolVar(&ops.hcp, "hcp", false, "The service being promoted conforms to the HyperShift progressive delivery definition")
+	pkoCmd.Flags().BoolVar(&ops.rollback, "rollback", false, "Roll back to the package tag promoted before the current one, found in app-interface history (or to --tag if set)")
 
 	return pkoCmd
 }
@@ -47,13 +56,14 @@ type pkoOptions struct {
 	packageTag              string
 	appInterfaceCheckoutDir string
 	hcp                     bool
+	rollback                bool
 }
 
 func (p pkoOptions) ValidatePKOOptions() error {
 	if p.serviceName == "" {
 		return fmt.Errorf("the service name must be specified with --serviceName/-s")
 	}
-	if p.packageTag == "" {
+	if p.packageTag == "" && !p.rollback {
 		return fmt.Errorf("a new package tag must be provided with '--tag' or '-t'")
 	}
 	return nil
@@ -98,6 +108,100 @@ func PromotePackage(appInterface git.AppInterface, serviceName string, packageTa
 	return nil
 }
 
+// RollbackPackage restores the package tag that was deployed before the current promotion of a service.
+// The previous tag is taken from the app-interface history of the saas file unless packageTag is set.
+func RollbackPackage(appInterface git.AppInterface, serviceName string, packageTag string, hcp bool) error {
+	services, err := saas.GetServiceNames(appInterface, saas.OSDSaasDir, saas.BPSaasDir, saas.CADSaasDir)
+	if err != nil {
+		return err
+	}
+	serviceName, err = saas.ValidateServiceName(services, serviceName)
+	if err != nil {
+		return err
+	}
+	saasFile, err := saas.GetSaasDir(serviceName, !hcp, hcp)
+	if err != nil {
+		return err
+	}
+	currentTag, err := git.GetCurrentPackageTagFromAppInterface(saasFile)
+	if err != nil {
+		return err
+	}
+
+	rollbackTag := packageTag
+	if rollbackTag == "" {
+		var commit string
+		rollbackTag, commit, err = appInterface.FindPreviousValue(saasFile, currentTag, git.GetPackageTagFromSaasData)
+		if err != nil {
+			return fmt.Errorf("failed to find previously promoted package tag: %w", err)
+		}
+		fmt.Printf("Previously promoted package tag %s found in app-interface commit %s\n", rollbackTag, commit)
+	}
+	if currentTag == rollbackTag {
+		return fmt.Errorf("current tag is already at '%s'. Nothing to do", rollbackTag)
+	}
+
+	fileContent, err := os.ReadFile(saasFile)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", saasFile, err)
+	}
+
+	// Package tags are the hashes of the package repo, so its log between them lists the commits being reverted
+	var commitLog string
+	packageRepo, err := git.GetPackageRepoFromSaasData(fileContent)
+	if err == nil {
+		_, commitLog, err = git.CheckoutAndCompareGitHash(appInterface.GitExecutor, packageRepo, currentTag, rollbackTag, "")
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to compute the commits being reverted: %v\n", err)
+		commitLog = "unable to compute the commits being reverted"
+	}
+
+	branchName := fmt.Sprintf("rollback-%s-package-%s", serviceName, rollbackTag)
+	err = appInterface.RewriteSaasFile(saasFile, branchName, func(content string) (string, error) {
+		newContent, err, found := git.RenderPackageTagRollback(content, currentTag, rollbackTag)
+		if err != nil {
+			return "", fmt.Errorf("error modifying YAML: %w", err)
+		}
+		if !found {
+			return "", fmt.Errorf("no package targets at %s found in %s", currentTag, saasFile)
+		}
+		return newContent, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	commitMessage := buildPackageRollbackCommitMessage(serviceName, packageRepo, currentTag, rollbackTag, commitLog)
+	err = appInterface.CommitSaasFile(saasFile, commitMessage)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("The current branch (%s) is ready to be pushed\n", branchName)
+	fmt.Println("")
+	fmt.Printf("Service: %s\n", serviceName)
+	fmt.Printf("Previous Tag: %s\n", currentTag)
+	fmt.Printf("Rollback Tag: %s\n", rollbackTag)
+	return nil
+}
+
+// buildPackageRollbackCommitMessage builds the GitLab Markdown formatted commit message for a package rollback
+func buildPackageRollbackCommitMessage(serviceName, packageRepo, currentTag, rollbackTag, commitLog string) string {
+	commitMessage := fmt.Sprintf("Rollback %s package from %s to %s\n\n", serviceName, currentTag, rollbackTag)
+
+	commitMessage += "## Reverted Changes\n\n"
+	if compareURL, forge := git.CompareURL(packageRepo, rollbackTag, currentTag); compareURL != "" {
+		commitMessage += fmt.Sprintf("[Compare changes on %s](%s)\n\n", forge, compareURL)
+	}
+
+	commitMessage += "### Reverted Commits\n\n```\n"
+	commitMessage += commitLog
+	commitMessage += "\n```"
+
+	return commitMessage
+}
+
 func updatePackageHash(gitHash, saasFile string) error {
 	return nil
 }
@@ -0,0 +1,20 @@
+package pko
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPackageRollbackCommitMessage(t *testing.T) {
+	msg := buildPackageRollbackCommitMessage("saas-foo", "https://github.com/openshift/foo", "newtag", "oldtag", "commit newtag\n    Break foo")
+
+	assert.Contains(t, msg, "Rollback saas-foo package from newtag to oldtag\n\n")
+	assert.Contains(t, msg, "[Compare changes on GitHub](https://github.com/openshift/foo/compare/oldtag...newtag)")
+	assert.Contains(t, msg, "### Reverted Commits\n\n```\ncommit newtag\n    Break foo\n```")
+
+	// Without a known repo there is no compare link, but the commits are still listed
+	msg = buildPackageRollbackCommitMessage("saas-foo", "", "newtag", "oldtag", "unable to compute the commits being reverted")
+	assert.NotContains(t, msg, "Compare changes")
+	assert.Contains(t, msg, "unable to compute the commits being reverted")
+}
@@ -0,0 +1,364 @@
+package saas
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// errNotHCPService is returned when planning the HCP promotion of a service without an HCP saas file
+var errNotHCPService = errors.New("service has no HCP saas file")
+
+// batchManifest lists the services promoted together by `promote saas --manifest`. Services are
+// promoted after the services they depend on, otherwise in the order of the manifest.
+//
+// Example:
+//
+//	services:
+//	  - name: saas-managed-cluster-config
+//	    gitHash: 1a2b3c4
+//	  - name: saas-route-monitor-operator
+//	    namespaceRef: hivep01ue1
+//	    dependsOn:
+//	      - saas-managed-cluster-config
+type batchManifest struct {
+	Services []batchService `yaml:"services"`
+}
+
+type batchService struct {
+	Name string `yaml:"name"`
+	// GitHash defaults to HEAD of the service repository when empty
+	GitHash      string `yaml:"gitHash"`
+	NamespaceRef string `yaml:"namespaceRef"`
+	// DependsOn lists the services of the manifest whose promotion is committed first
+	DependsOn []string `yaml:"dependsOn"`
+}
+
+// batchFailure is a service of a batch whose promotion could not be planned
+type batchFailure struct {
+	serviceName string
+	err         error
+}
+
+// batchPromotion is a single resolved service promotion within a batch
+type batchPromotion struct {
+	serviceName      string
+	saasFile         string
+	serviceRepo      string
+	currentGitHash   string
+	promotionGitHash string
+	commitLog        string
+}
+
+func loadBatchManifest(path string) (*batchManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %v", path, err)
+	}
+
+	manifest := &batchManifest{}
+	if err := yaml.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %v", path, err)
+	}
+
+	if len(manifest.Services) == 0 {
+		return nil, fmt.Errorf("manifest %s does not list any services", path)
+	}
+
+	for i, service := range manifest.Services {
+		if service.Name == "" {
+			return nil, fmt.Errorf("manifest %s: service #%d has no name", path, i+1)
+		}
+	}
+
+	services, err := orderBatchServices(manifest.Services)
+	if err != nil {
+		return nil, fmt.Errorf("manifest %s: %v", path, err)
+	}
+	manifest.Services = services
+
+	return manifest, nil
+}
+
+// orderBatchServices orders services after the services they depend on, keeping the given order otherwise.
+// Service names are compared with or without their saas- prefix.
+func orderBatchServices(services []batchService) ([]batchService, error) {
+	key := func(name string) string { return strings.TrimPrefix(name, "saas-") }
+
+	listed := map[string]bool{}
+	for _, service := range services {
+		listed[key(service.Name)] = true
+	}
+	for _, service := range services {
+		for _, dependency := range service.DependsOn {
+			if !listed[key(dependency)] {
+				return nil, fmt.Errorf("service %s depends on %s, which is not listed", service.Name, dependency)
+			}
+		}
+	}
+
+	ordered := make([]batchService, 0, len(services))
+	placed := map[string]bool{}
+	for len(ordered) < len(services) {
+		progress := false
+		for _, service := range services {
+			if placed[key(service.Name)] {
+				continue
+			}
+			ready := true
+			for _, dependency := range service.DependsOn {
+				ready = ready && placed[key(dependency)]
+			}
+			if ready {
+				ordered = append(ordered, service)
+				placed[key(service.Name)] = true
+				progress = true
+				break
+			}
+		}
+		if !progress {
+			var cycle []string
+			for _, service := range services {
+				if !placed[key(service.Name)] {
+					cycle = append(cycle, service.Name)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between services %s", strings.Join(cycle, ", "))
+		}
+	}
+	return ordered, nil
+}
+
+// batchServicePromotion promotes several services on a single branch with one commit per service.
+// When manifestPath is empty every known service is checked and only those whose repository has
+// new commits are promoted. With dryRun set, the saas file changes are printed instead of committed.
+func batchServicePromotion(appInterface git.AppInterface, manifestPath string, osd, hcp, dryRun bool) error {
+	_, err := GetServiceNames(appInterface, OSDSaasDir, BPSaasDir, CADSaasDir)
+	if err != nil {
+		return err
+	}
+
+	var services []batchService
+	allServices := manifestPath == ""
+	if allServices {
+		for _, serviceName := range ServicesSlice {
+			services = append(services, batchService{Name: serviceName})
+		}
+	} else {
+		manifest, err := loadBatchManifest(manifestPath)
+		if err != nil {
+			return err
+		}
+		services = manifest.Services
+	}
+
+	promotions, failures, err := planBatchPromotions(services, allServices, func(service batchService) (*batchPromotion, error) {
+		return planServicePromotion(appInterface, service, osd, hcp)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(promotions) == 0 {
+		fmt.Println("No services have new commits to promote")
+		return batchFailuresError(failures)
+	}
+
+	branchName := fmt.Sprintf("promote-batch-%s", time.Now().UTC().Format("20060102-150405"))
+	description := buildBatchDescription(promotions)
+
+	if dryRun {
+		for _, p := range promotions {
+			diff, err := previewPromotion(appInterface, p)
+			if err != nil {
+				return err
+			}
+			fmt.Println(diff)
+		}
+		fmt.Printf("### Merge request description for %s ###\n\n%s\n", branchName, description)
+		return batchFailuresError(failures)
+	}
+
+	if err := appInterface.CreatePromotionBranch(branchName); err != nil {
+		return err
+	}
+
+	for _, p := range promotions {
+		fileContent, err := os.ReadFile(p.saasFile)
+		if err != nil {
+			return fmt.Errorf("failed to read file %s: %v", p.saasFile, err)
+		}
+
+		newContent, err := git.RenderSaasPromotion(string(fileContent), p.currentGitHash, p.promotionGitHash, false)
+		if err != nil {
+			return fmt.Errorf("failed to promote %s: %v", p.serviceName, err)
+		}
+
+		if err := os.WriteFile(p.saasFile, []byte(newContent), 0600); err != nil {
+			return fmt.Errorf("failed to write to file %s: %v", p.saasFile, err)
+		}
+
+		commitMessage := buildPromotionCommitMessage(appInterface, p.serviceName, p.serviceRepo, p.currentGitHash, p.promotionGitHash, p.commitLog, false)
+		if err := appInterface.CommitSaasFile(p.saasFile, commitMessage); err != nil {
+			return fmt.Errorf("failed to commit promotion of %s: %w", p.serviceName, err)
+		}
+		fmt.Printf("Committed promotion of %s from %s to %s\n", p.serviceName, p.currentGitHash, p.promotionGitHash)
+	}
+
+	descriptionFile := filepath.Join(os.TempDir(), branchName+".md")
+	if err := os.WriteFile(descriptionFile, []byte(description), 0600); err != nil {
+		fmt.Printf("Warning: failed to write merge request description to %s: %v\n", descriptionFile, err)
+	} else {
+		fmt.Printf("Merge request description written to %s\n", descriptionFile)
+	}
+
+	fmt.Printf("The branch %s is ready to be pushed\n", branchName)
+	fmt.Println("")
+	fmt.Println(description)
+	fmt.Println("READY TO PUSH,", len(promotions), "promotion commits are ready locally")
+	return batchFailuresError(failures)
+}
+
+// planBatchPromotions resolves the current and target git hash of every service with plan.
+// When allServices is set, services already at HEAD or without an HCP saas file in HCP mode are skipped
+// and the other failures are returned so that the remaining services can still be promoted. Otherwise
+// any failure aborts the batch before app-interface is modified.
+func planBatchPromotions(services []batchService, allServices bool, plan func(service batchService) (*batchPromotion, error)) ([]batchPromotion, []batchFailure, error) {
+	var promotions []batchPromotion
+	var failures []batchFailure
+	for _, service := range services {
+		p, err := plan(service)
+		if err != nil {
+			if !allServices {
+				return nil, nil, fmt.Errorf("failed to plan promotion of %s: %v", service.Name, err)
+			}
+			if errors.Is(err, git.ErrAlreadyAtHead) || errors.Is(err, errNotHCPService) {
+				fmt.Printf("Skipping %s: %v\n", service.Name, err)
+				continue
+			}
+			fmt.Printf("Failed to plan promotion of %s: %v\n", service.Name, err)
+			failures = append(failures, batchFailure{serviceName: service.Name, err: err})
+			continue
+		}
+		promotions = append(promotions, *p)
+	}
+
+	return promotions, failures, nil
+}
+
+// batchFailuresError returns an error listing the services that could not be promoted, or nil if there are none
+func batchFailuresError(failures []batchFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("failed to plan the promotion of %d services:", len(failures)))
+	for _, f := range failures {
+		sb.WriteString(fmt.Sprintf("\n  %s: %v", f.serviceName, f.err))
+	}
+	return errors.New(sb.String())
+}
+
+func planServicePromotion(appInterface git.AppInterface, service batchService, osd, hcp bool) (*batchPromotion, error) {
+	serviceName, err := ValidateServiceName(ServicesSlice, service.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	saasFile, err := GetSaasDir(serviceName, osd, hcp)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceData, err := os.ReadFile(saasFile)
+	if hcp && errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s does not exist", errNotHCPService, saasFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SAAS file: %v", err)
+	}
+
+	currentGitHash, serviceRepo, err := git.GetCurrentGitHashFromAppInterface(serviceData, serviceName, service.NamespaceRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current git hash or service repo: %v", err)
+	}
+
+	promotionGitHash, commitLog, err := git.CheckoutAndCompareGitHash(appInterface.GitExecutor, serviceRepo, service.GitHash, currentGitHash, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to checkout and compare git hash: %w", err)
+	}
+	if promotionGitHash == "" {
+		return nil, fmt.Errorf("unable to find a git hash to promote")
+	}
+
+	fmt.Printf("Service: %s will be promoted from %s to %s\n", serviceName, currentGitHash, promotionGitHash)
+
+	return &batchPromotion{
+		serviceName:      serviceName,
+		saasFile:         saasFile,
+		serviceRepo:      serviceRepo,
+		currentGitHash:   currentGitHash,
+		promotionGitHash: promotionGitHash,
+		commitLog:        commitLog,
+	}, nil
+}
+
+// previewPromotion returns a unified diff of the saas file change a promotion would make
+func previewPromotion(appInterface git.AppInterface, p batchPromotion) (string, error) {
+	fileContent, err := os.ReadFile(p.saasFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %v", p.saasFile, err)
+	}
+
+	newContent, err := git.RenderSaasPromotion(string(fileContent), p.currentGitHash, p.promotionGitHash, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to promote %s: %v", p.serviceName, err)
+	}
+
+	relPath, err := filepath.Rel(appInterface.GitDirectory, p.saasFile)
+	if err != nil {
+		relPath = p.saasFile
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(fileContent)),
+		B:        difflib.SplitLines(newContent),
+		FromFile: "a/" + relPath,
+		ToFile:   "b/" + relPath,
+		Context:  3,
+	})
+}
+
+// buildBatchDescription builds the combined GitLab Markdown merge request description for a batch
+func buildBatchDescription(promotions []batchPromotion) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Promote %d services\n\n", len(promotions)))
+	sb.WriteString("| Service | From | To | Changes |\n")
+	sb.WriteString("|---------|------|----|---------|\n")
+	for _, p := range promotions {
+		changes := "-"
+		if compareURL, _ := git.CompareURL(p.serviceRepo, p.currentGitHash, p.promotionGitHash); compareURL != "" {
+			changes = fmt.Sprintf("[compare](%s)", compareURL)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+			p.serviceName, git.ShortHash(p.currentGitHash), git.ShortHash(p.promotionGitHash), changes))
+	}
+
+	for _, p := range promotions {
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", p.serviceName))
+		sb.WriteString("```\n")
+		sb.WriteString(strings.TrimSpace(p.commitLog))
+		sb.WriteString("\n```\n")
+	}
+
+	return sb.String()
+}
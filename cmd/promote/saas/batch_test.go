@@ -0,0 +1,228 @@
+package saas
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBatchManifest(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		expected  []batchService
+		expectErr string
+	}{
+		{
+			name: "valid_manifest",
+			content: `services:
+  - name: saas-foo
+    gitHash: abc123
+  - name: bar
+    namespaceRef: hivep01ue1
+`,
+			expected: []batchService{
+				{Name: "saas-foo", GitHash: "abc123"},
+				{Name: "bar", NamespaceRef: "hivep01ue1"},
+			},
+		},
+		{
+			name: "dependencies_first",
+			content: `services:
+  - name: saas-foo
+    dependsOn: [bar]
+  - name: bar
+`,
+			expected: []batchService{
+				{Name: "bar"},
+				{Name: "saas-foo", DependsOn: []string{"bar"}},
+			},
+		},
+		{
+			name: "unlisted_dependency",
+			content: `services:
+  - name: saas-foo
+    dependsOn: [saas-bar]
+`,
+			expectErr: "service saas-foo depends on saas-bar, which is not listed",
+		},
+		{
+			name:      "empty_manifest",
+			content:   "services: []\n",
+			expectErr: "does not list any services",
+		},
+		{
+			name: "service_without_name",
+			content: `services:
+  - gitHash: abc123
+`,
+			expectErr: "service #1 has no name",
+		},
+		{
+			name:      "invalid_yaml",
+			content:   "services: [",
+			expectErr: "failed to parse manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "manifest.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			manifest, err := loadBatchManifest(path)
+			if tt.expectErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, manifest.Services)
+		})
+	}
+}
+
+func TestOrderBatchServices(t *testing.T) {
+	ordered, err := orderBatchServices([]batchService{
+		{Name: "saas-a", DependsOn: []string{"c"}},
+		{Name: "saas-b"},
+		{Name: "saas-c", DependsOn: []string{"saas-b"}},
+		{Name: "saas-d"},
+	})
+	require.NoError(t, err)
+	var names []string
+	for _, service := range ordered {
+		names = append(names, service.Name)
+	}
+	assert.Equal(t, []string{"saas-b", "saas-c", "saas-a", "saas-d"}, names)
+
+	_, err = orderBatchServices([]batchService{
+		{Name: "saas-a", DependsOn: []string{"saas-b"}},
+		{Name: "saas-b", DependsOn: []string{"saas-a"}},
+		{Name: "saas-c"},
+	})
+	assert.EqualError(t, err, "dependency cycle between services saas-a, saas-b")
+}
+
+func TestBuildBatchDescription(t *testing.T) {
+	promotions := []batchPromotion{
+		{
+			serviceName:      "saas-foo",
+			serviceRepo:      "https://github.com/openshift/foo",
+			currentGitHash:   "1111111111",
+			promotionGitHash: "2222222222",
+			commitLog:        "commit 2222222222\n    Fix foo\n",
+		},
+		{
+			serviceName:      "saas-bar",
+			serviceRepo:      "https://github.com/openshift/bar",
+			currentGitHash:   "3333333333",
+			promotionGitHash: "4444444444",
+			commitLog:        "commit 4444444444\n    Fix bar\n",
+		},
+	}
+
+	description := buildBatchDescription(promotions)
+
+	assert.True(t, strings.HasPrefix(description, "Promote 2 services\n"))
+	assert.Contains(t, description, "| saas-foo | 1111111 | 2222222 | [compare](https://github.com/openshift/foo/compare/1111111111...2222222222) |")
+	assert.Contains(t, description, "| saas-bar | 3333333 | 4444444 | [compare](https://github.com/openshift/bar/compare/3333333333...4444444444) |")
+	assert.Contains(t, description, "## saas-foo\n\n```\ncommit 2222222222\n    Fix foo\n```")
+	assert.Contains(t, description, "## saas-bar\n\n```\ncommit 4444444444\n    Fix bar\n```")
+
+	description = buildBatchDescription([]batchPromotion{
+		{serviceName: "saas-baz", serviceRepo: "https://gitlab.cee.redhat.com/service/baz", currentGitHash: "5555555555", promotionGitHash: "6666666666"},
+		{serviceName: "saas-qux", serviceRepo: "https://git.example.com/qux", currentGitHash: "7777777777", promotionGitHash: "8888888888"},
+	})
+	assert.Contains(t, description, "| saas-baz | 5555555 | 6666666 | [compare](https://gitlab.cee.redhat.com/service/baz/-/compare/5555555555...6666666666) |")
+	assert.Contains(t, description, "| saas-qux | 7777777 | 8888888 | - |")
+}
+
+func TestPlanBatchPromotions(t *testing.T) {
+	services := []batchService{{Name: "saas-foo"}, {Name: "saas-bar"}, {Name: "saas-baz"}, {Name: "saas-osd-only"}}
+	plan := func(service batchService) (*batchPromotion, error) {
+		switch service.Name {
+		case "saas-bar":
+			return nil, fmt.Errorf("failed to checkout and compare git hash: git hash 1234 is %w", git.ErrAlreadyAtHead)
+		case "saas-baz":
+			return nil, errors.New("failed to clone git repository")
+		case "saas-osd-only":
+			return nil, fmt.Errorf("%w: hypershift-deploy.yaml does not exist", errNotHCPService)
+		}
+		return &batchPromotion{serviceName: service.Name}, nil
+	}
+
+	t.Run("all_services_report_failures", func(t *testing.T) {
+		promotions, failures, err := planBatchPromotions(services, true, plan)
+		require.NoError(t, err)
+		assert.Equal(t, []batchPromotion{{serviceName: "saas-foo"}}, promotions)
+
+		// Services already at HEAD or without an HCP saas file are not failures
+		require.Len(t, failures, 1)
+		assert.Equal(t, "saas-baz", failures[0].serviceName)
+		assert.EqualError(t, batchFailuresError(failures), "failed to plan the promotion of 1 services:\n  saas-baz: failed to clone git repository")
+	})
+
+	t.Run("manifest_aborts_on_failure", func(t *testing.T) {
+		_, _, err := planBatchPromotions(services, false, plan)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to plan promotion of saas-bar")
+	})
+
+	assert.NoError(t, batchFailuresError(nil))
+}
+
+func TestPlanServicePromotionWithoutHCPSaasFile(t *testing.T) {
+	ServicesSlice = []string{"saas-foo"}
+	ServicesFilesMap = map[string]string{"saas-foo": t.TempDir()}
+	t.Cleanup(func() {
+		ServicesSlice = nil
+		ServicesFilesMap = map[string]string{}
+	})
+
+	_, err := planServicePromotion(git.AppInterface{}, batchService{Name: "saas-foo"}, false, true)
+	assert.ErrorIs(t, err, errNotHCPService)
+
+	_, err = planServicePromotion(git.AppInterface{}, batchService{Name: "saas-foo"}, true, false)
+	assert.NotErrorIs(t, err, errNotHCPService)
+}
+
+func TestPreviewPromotion(t *testing.T) {
+	tmpDir := t.TempDir()
+	saasFile := filepath.Join(tmpDir, "data", "saas-foo.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(saasFile), 0755))
+
+	content := `resourceTemplates:
+  - name: foo
+    targets:
+      - name: foo-prod
+        ref: 1111111111
+`
+	require.NoError(t, os.WriteFile(saasFile, []byte(content), 0600))
+
+	diff, err := previewPromotion(git.AppInterface{GitDirectory: tmpDir}, batchPromotion{
+		serviceName:      "saas-foo",
+		saasFile:         saasFile,
+		currentGitHash:   "1111111111",
+		promotionGitHash: "2222222222",
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, diff, "--- a/data/saas-foo.yaml")
+	assert.Contains(t, diff, "+++ b/data/saas-foo.yaml")
+	assert.Contains(t, diff, "-        ref: 1111111111")
+	assert.Contains(t, diff, "+        ref: 2222222222")
+
+	// The saas file itself must be left untouched
+	after, err := os.ReadFile(saasFile)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(after))
+}
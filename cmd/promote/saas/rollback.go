@@ -0,0 +1,115 @@
+package saas
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+)
+
+// serviceRollback restores the git hash that was deployed before the current promotion of a service.
+// The previous hash is taken from the app-interface history of the saas file unless gitHash is set.
+// When namespaceRefs is not empty only the matching targets are rolled back.
+func serviceRollback(appInterface git.AppInterface, serviceName, gitHash, namespaceRef string, namespaceRefs []string, osd, hcp bool) error {
+	_, err := GetServiceNames(appInterface, OSDSaasDir, BPSaasDir, CADSaasDir)
+	if err != nil {
+		return err
+	}
+
+	serviceName, err = ValidateServiceName(ServicesSlice, serviceName)
+	if err != nil {
+		return err
+	}
+
+	saasDir, err := GetSaasDir(serviceName, osd, hcp)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("SAAS Directory: %v\n", saasDir)
+
+	serviceData, err := os.ReadFile(saasDir)
+	if err != nil {
+		return fmt.Errorf("failed to read SAAS file: %v", err)
+	}
+
+	currentGitHash, serviceRepo, err := git.GetCurrentGitHashFromAppInterface(serviceData, serviceName, namespaceRef)
+	if err != nil {
+		return fmt.Errorf("failed to get current git hash or service repo: %v", err)
+	}
+	fmt.Printf("Current Git Hash: %v\nGit Repo: %v\n\n", currentGitHash, serviceRepo)
+
+	rollbackGitHash := gitHash
+	if rollbackGitHash == "" {
+		var commit string
+		rollbackGitHash, commit, err = appInterface.FindPreviousValue(saasDir, currentGitHash, func(saasData []byte) (string, error) {
+			hash, _, err := git.GetCurrentGitHashFromAppInterface(saasData, serviceName, namespaceRef)
+			return hash, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to find previously promoted git hash: %v", err)
+		}
+		fmt.Printf("Previously promoted git hash %s found in app-interface commit %s\n", rollbackGitHash, commit)
+	}
+
+	if rollbackGitHash == currentGitHash {
+		return fmt.Errorf("service %s is already at %s", serviceName, rollbackGitHash)
+	}
+
+	// The log of currentGitHash since rollbackGitHash is exactly the set of commits being reverted
+	_, commitLog, err := git.CheckoutAndCompareGitHash(appInterface.GitExecutor, serviceRepo, currentGitHash, rollbackGitHash, "")
+	if err != nil {
+		fmt.Printf("Warning: failed to compute the commits being reverted: %v\n", err)
+		commitLog = "unable to compute the commits being reverted"
+	}
+
+	branchName := fmt.Sprintf("rollback-%s-%s", serviceName, rollbackGitHash)
+	err = appInterface.RewriteSaasFile(saasDir, branchName, func(content string) (string, error) {
+		newContent, err, found := git.RenderSaasRollback(content, currentGitHash, rollbackGitHash, namespaceRefs)
+		if err != nil {
+			return "", fmt.Errorf("error modifying YAML: %v", err)
+		}
+		if !found {
+			return "", fmt.Errorf("no targets at %s matching namespaceRefs %v found in %s", currentGitHash, namespaceRefs, saasDir)
+		}
+		return newContent, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	commitMessage := buildRollbackCommitMessage(serviceName, serviceRepo, currentGitHash, rollbackGitHash, commitLog, namespaceRefs)
+	fmt.Printf("commitMessage: %s\n", commitMessage)
+
+	if err := appInterface.CommitSaasFile(saasDir, commitMessage); err != nil {
+		return fmt.Errorf("failed to commit changes to app-interface; manual commit may still succeed: %w", err)
+	}
+
+	fmt.Printf("The branch %s is ready to be pushed\n", branchName)
+	fmt.Println("")
+	fmt.Println("service:", serviceName)
+	fmt.Println("from:", currentGitHash)
+	fmt.Println("to:", rollbackGitHash)
+	fmt.Println("READY TO PUSH,", serviceName, "rollback commit is ready locally")
+	return nil
+}
+
+// buildRollbackCommitMessage builds the GitLab Markdown formatted commit message for a service rollback
+func buildRollbackCommitMessage(serviceName, serviceRepo, currentGitHash, rollbackGitHash, commitLog string, namespaceRefs []string) string {
+	commitMessage := fmt.Sprintf("Rollback %s from %s to %s\n\n", serviceName, currentGitHash, rollbackGitHash)
+
+	if len(namespaceRefs) > 0 {
+		commitMessage += fmt.Sprintf("Only targets matching the following namespaceRefs are rolled back: %s\n\n", strings.Join(namespaceRefs, ", "))
+	}
+
+	commitMessage += "## Reverted Changes\n\n"
+	if compareURL, forge := git.CompareURL(serviceRepo, rollbackGitHash, currentGitHash); compareURL != "" {
+		commitMessage += fmt.Sprintf("[Compare changes on %s](%s)\n\n", forge, compareURL)
+	}
+
+	commitMessage += "### Reverted Commits\n\n```\n"
+	commitMessage += commitLog
+	commitMessage += "\n```"
+
+	return commitMessage
+}
@@ -0,0 +1,25 @@
+package saas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildRollbackCommitMessage(t *testing.T) {
+	msg := buildRollbackCommitMessage("saas-foo", "https://github.com/openshift/foo", "newhash", "oldhash", "commit newhash\n    Break foo", nil)
+
+	assert.Contains(t, msg, "Rollback saas-foo from newhash to oldhash\n\n")
+	assert.Contains(t, msg, "[Compare changes on GitHub](https://github.com/openshift/foo/compare/oldhash...newhash)")
+	assert.Contains(t, msg, "### Reverted Commits\n\n```\ncommit newhash\n    Break foo\n```")
+	assert.NotContains(t, msg, "namespaceRefs")
+
+	msg = buildRollbackCommitMessage("saas-foo", "https://github.com/openshift/foo", "newhash", "oldhash", "", []string{"hivep01ue1", "hivep02ue1"})
+	assert.Contains(t, msg, "Only targets matching the following namespaceRefs are rolled back: hivep01ue1, hivep02ue1")
+}
+
+func TestBuildRollbackCommitMessageGitLab(t *testing.T) {
+	msg := buildRollbackCommitMessage("saas-foo", "https://gitlab.cee.redhat.com/service/foo", "newhash", "oldhash", "", nil)
+	assert.Contains(t, msg, "[Compare changes on GitLab](https://gitlab.cee.redhat.com/service/foo/-/compare/oldhash...newhash)")
+	assert.NotContains(t, msg, "GitHub")
+}
@@ -17,6 +17,13 @@ type saasOptions struct {
 	gitHash                 string
 	namespaceRef            string
 	hotfix                  bool
+
+	manifest string
+	all      bool
+	dryRun   bool
+
+	rollback              bool
+	rollbackNamespaceRefs []string
 }
 
 // newCmdSaas implementes the saas command to interact with promoting SaaS services/operators
@@ -34,7 +41,19 @@ func NewCmdSaas() *cobra.Command {
 		# Promote a SaaS service/operator
 		osdctl promote saas --serviceName <service-name> --gitHash <git-hash> --osd
 		or
-		osdctl promote saas --serviceName <service-name> --gitHash <git-hash> --hcp`,
+		osdctl promote saas --serviceName <service-name> --gitHash <git-hash> --hcp
+
+		# Promote several services on a single branch, one commit per service
+		osdctl promote saas --manifest promotions.yaml --osd
+
+		# Preview promoting every service whose repository has new commits
+		osdctl promote saas --all --osd --dry-run
+
+		# Roll a SaaS service/operator back to the previously promoted git hash
+		osdctl promote saas --serviceName <service-name> --osd --rollback
+
+		# Roll back only the targets in the given namespaces
+		osdctl promote saas --serviceName <service-name> --osd --rollback --rollbackNamespaceRefs hivep01ue1,hivep02ue1`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ops.validateSaasFlow()
 			appInterface := git.BootstrapOsdCtlForAppInterfaceAndServicePromotions(ops.appInterfaceCheckoutDir, iexec.Exec{})
@@ -47,12 +66,47 @@ func NewCmdSaas() *cobra.Command {
 				return listServiceNames(appInterface)
 			}
 
+			if ops.manifest != "" || ops.all {
+				if ops.serviceName != "" || ops.gitHash != "" || ops.namespaceRef != "" || ops.hotfix {
+					fmt.Printf("Error: --manifest and --all cannot be used with --serviceName, --gitHash, --namespaceRef or --hotfix\n\n")
+
+					return cmd.Help()
+				}
+				if ops.osd == ops.hcp {
+					fmt.Printf("Error: --manifest and --all require exactly one of --osd or --hcp\n\n")
+
+					return cmd.Help()
+				}
+				return batchServicePromotion(appInterface, ops.manifest, ops.osd, ops.hcp, ops.dryRun)
+			}
+
+			if ops.dryRun {
+				fmt.Printf("Error: --dry-run can only be used with --manifest or --all\n\n")
+
+				return cmd.Help()
+			}
+
 			if !(ops.osd || ops.hcp) && ops.serviceName != "" {
 				fmt.Printf("Error: --serviceName cannot be used without either --osd or --hcp\n\n")
 
 				return cmd.Help()
 			}
 
+			if ops.rollback {
+				if ops.serviceName == "" || ops.hotfix {
+					fmt.Printf("Error: --rollback requires --serviceName and cannot be used with --hotfix\n\n")
+
+					return cmd.Help()
+				}
+				return serviceRollback(appInterface, ops.serviceName, ops.gitHash, ops.namespaceRef, ops.rollbackNamespaceRefs, ops.osd, ops.hcp)
+			}
+
+			if len(ops.rollbackNamespaceRefs) > 0 {
+				fmt.Printf("Error: --rollbackNamespaceRefs requires --rollback\n\n")
+
+				return cmd.Help()
+			}
+
 			if ops.hotfix && ops.gitHash == "" {
 				fmt.Printf("Error: --hotfix requires --gitHash to be specified\n\n")
 
@@ -76,12 +130,20 @@ func NewCmdSaas() *cobra.Command {
 	saasCmd.Flags().BoolVarP(&ops.hcp, "hcp", "", false, "HCP service/operator getting promoted")
 	saasCmd.Flags().StringVarP(&ops.appInterfaceCheckoutDir, "appInterfaceDir", "", "", "location of app-interface checkout. Falls back to current working directory")
 	saasCmd.Flags().BoolVarP(&ops.hotfix, "hotfix", "", false, "Add gitHash to hotfixVersions in app.yml to bypass progressive delivery (requires --gitHash)")
+	saasCmd.Flags().StringVarP(&ops.manifest, "manifest", "", "", "YAML manifest listing the services (name, optional gitHash, namespaceRef and dependsOn) to promote together on a single branch, dependencies first")
+	saasCmd.Flags().BoolVarP(&ops.all, "all", "", false, "Promote every service whose repository has new commits on a single branch. Services that can't be resolved are reported and fail the command once the others are promoted. With --hcp, services without an HCP saas file are skipped")
+	saasCmd.Flags().BoolVarP(&ops.dryRun, "dry-run", "", false, "Print the saas file changes and merge request description of a batch promotion without committing")
+	saasCmd.Flags().BoolVarP(&ops.rollback, "rollback", "", false, "Roll back to the git hash promoted before the current one, found in app-interface history (or to --gitHash if set)")
+	saasCmd.Flags().StringSliceVarP(&ops.rollbackNamespaceRefs, "rollbackNamespaceRefs", "", nil, "Only roll back targets whose namespace $ref contains one of these values (requires --rollback)")
+	saasCmd.MarkFlagsMutuallyExclusive("manifest", "all")
+	saasCmd.MarkFlagsMutuallyExclusive("rollback", "manifest")
+	saasCmd.MarkFlagsMutuallyExclusive("rollback", "all")
 
 	return saasCmd
 }
 
 func (o *saasOptions) validateSaasFlow() {
-	if o.serviceName == "" && o.gitHash == "" {
+	if o.serviceName == "" && o.gitHash == "" && o.manifest == "" && !o.all {
 		fmt.Printf("Usage: For SaaS services/operators, please provide --serviceName and (optional) --gitHash\n")
 		fmt.Printf("--serviceName is the name of the service, i.e. saas-managed-cluster-config\n")
 		fmt.Printf("--gitHash is the target git commit in the service, if not specified defaults to HEAD of master\n\n")
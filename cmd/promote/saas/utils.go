@@ -183,6 +183,34 @@ func servicePromotion(appInterface git.AppInterface, serviceName, gitHash string
 			return fmt.Errorf("failed to update app.yml with hotfix: %v", err)
 		}
 	}
+	commitMessage := buildPromotionCommitMessage(appInterface, serviceName, serviceRepo, currentGitHash, promotionGitHash, commitLog, hotfix)
+
+	fmt.Printf("commitMessage: %s\n", commitMessage)
+
+	// ovverriding appInterface.GitExecuter to iexec.Exec{}
+	appInterface.GitExecutor = iexec.Exec{}
+
+	if hotfix {
+		err = appInterface.CommitSaasAndAppYmlFile(saasDir, serviceName, commitMessage)
+	} else {
+		err = appInterface.CommitSaasFile(saasDir, commitMessage)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to commit changes to app-interface; manual commit may still succeed: %w", err)
+	}
+
+	fmt.Printf("The branch %s is ready to be pushed\n", branchName)
+	fmt.Println("")
+	fmt.Println("service:", serviceName)
+	fmt.Println("from:", currentGitHash)
+	fmt.Println("to:", promotionGitHash)
+	fmt.Println("READY TO PUSH,", serviceName, "promotion commit is ready locally")
+	return nil
+}
+
+// buildPromotionCommitMessage builds the GitLab Markdown formatted commit message for a service promotion
+func buildPromotionCommitMessage(appInterface git.AppInterface, serviceName, serviceRepo, currentGitHash, promotionGitHash, commitLog string, hotfix bool) string {
 	prefix := "saas-"
 	operatorName := strings.TrimPrefix(serviceName, prefix)
 
@@ -215,28 +243,7 @@ func servicePromotion(appInterface git.AppInterface, serviceName, gitHash string
 	commitMessage += commitLog
 	commitMessage += "\n```"
 
-	fmt.Printf("commitMessage: %s\n", commitMessage)
-
-	// ovverriding appInterface.GitExecuter to iexec.Exec{}
-	appInterface.GitExecutor = iexec.Exec{}
-
-	if hotfix {
-		err = appInterface.CommitSaasAndAppYmlFile(saasDir, serviceName, commitMessage)
-	} else {
-		err = appInterface.CommitSaasFile(saasDir, commitMessage)
-	}
-
-	if err != nil {
-		return fmt.Errorf("failed to commit changes to app-interface; manual commit may still succeed: %w", err)
-	}
-
-	fmt.Printf("The branch %s is ready to be pushed\n", branchName)
-	fmt.Println("")
-	fmt.Println("service:", serviceName)
-	fmt.Println("from:", currentGitHash)
-	fmt.Println("to:", promotionGitHash)
-	fmt.Println("READY TO PUSH,", serviceName, "promotion commit is ready locally")
-	return nil
+	return commitMessage
 }
 
 func GetServiceNames(appInterface git.AppInterface, saaDirs ...string) ([]string, error) {
@@ -0,0 +1,423 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/promote/git"
+	"github.com/openshift/osdctl/cmd/promote/iexec"
+	"github.com/openshift/osdctl/cmd/promote/pathutil"
+	"github.com/openshift/osdctl/cmd/promote/saas"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	envInt   = "int"
+	envStage = "stage"
+	envProd  = "prod"
+	envOther = "other"
+)
+
+type statusOptions struct {
+	appInterfaceCheckoutDir string
+	service                 string
+	output                  string
+	skipGit                 bool
+	hotfixOnly              bool
+}
+
+// targetStatus is the deployed state of a single saas target
+type targetStatus struct {
+	Name         string     `json:"name"`
+	NamespaceRef string     `json:"namespaceRef"`
+	Environment  string     `json:"environment"`
+	GitHash      string     `json:"gitHash"`
+	Path         string     `json:"path,omitempty"`
+	CommitDate   *time.Time `json:"commitDate,omitempty"`
+	// BehindStage is the number of commits a prod target is behind the stage targets, nil when unknown
+	BehindStage *int `json:"behindStage,omitempty"`
+}
+
+// serviceStatus is the promotion state of one saas file
+type serviceStatus struct {
+	Service        string         `json:"service"`
+	Flavor         string         `json:"flavor"`
+	SaasFile       string         `json:"saasFile"`
+	Repo           string         `json:"repo"`
+	HotfixVersions []string       `json:"hotfixVersions,omitempty"`
+	Targets        []targetStatus `json:"targets"`
+}
+
+type appYml struct {
+	CodeComponents []struct {
+		Name           string   `yaml:"name"`
+		HotfixVersions []string `yaml:"hotfixVersions"`
+	} `yaml:"codeComponents"`
+}
+
+// NewCmdStatus implements the promote status command to report deployed versions across saas targets
+func NewCmdStatus() *cobra.Command {
+	ops := &statusOptions{}
+	statusCmd := &cobra.Command{
+		Use:               "status",
+		Short:             "Report the promotion status of SaaS services/operators across int, stage and prod",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Long: `Report the promotion status of every SaaS service/operator in app-interface.
+
+Every OSD, HCP, backplane and CAD saas file is read, and for each target (namespaceRef) the
+deployed git hash is reported along with the age of the commit. For prod targets the number of
+commits they are behind stage is reported, and any hotfixVersions still set in the service's
+app.yml are listed so stale hotfix pins can be cleaned up.
+
+Commit ages and counts require cloning each service repository; use --skip-git to only report
+what is in app-interface.`,
+		Example: `
+		# Report the promotion status of all services
+		osdctl promote status
+
+		# Report the status of services matching a name, as JSON
+		osdctl promote status --service managed-cluster-config -o json
+
+		# Only list services with hotfixVersions still pinned, without cloning repositories
+		osdctl promote status --hotfix-only --skip-git`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				return fmt.Errorf("error reading flag 'output': %w", err)
+			}
+			if output != "" && output != "json" {
+				return fmt.Errorf("unsupported output format %q, only 'json' is accepted", output)
+			}
+			ops.output = output
+			appInterface := git.BootstrapOsdCtlForAppInterfaceAndServicePromotions(ops.appInterfaceCheckoutDir, iexec.Exec{})
+			return ops.run(appInterface)
+		},
+	}
+
+	statusCmd.Flags().StringVarP(&ops.appInterfaceCheckoutDir, "appInterfaceDir", "", "", "location of app-interface checkout. Falls back to current working directory")
+	statusCmd.Flags().StringVarP(&ops.service, "service", "", "", "Only report services whose name contains this string")
+	statusCmd.Flags().BoolVarP(&ops.skipGit, "skip-git", "", false, "Do not clone service repositories to compute commit ages and how far prod is behind stage")
+	statusCmd.Flags().BoolVarP(&ops.hotfixOnly, "hotfix-only", "", false, "Only report services with hotfixVersions set in app.yml")
+
+	return statusCmd
+}
+
+func (o *statusOptions) run(appInterface git.AppInterface) error {
+	statuses, err := collectStatus(appInterface, o.service)
+	if err != nil {
+		return err
+	}
+
+	if o.hotfixOnly {
+		var filtered []serviceStatus
+		for _, s := range statuses {
+			if len(s.HotfixVersions) > 0 {
+				filtered = append(filtered, s)
+			}
+		}
+		statuses = filtered
+	}
+
+	if !o.skipGit {
+		for i := range statuses {
+			if err := enrichWithGitHistory(appInterface.GitExecutor, &statuses[i]); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to read git history of %s: %v\n", statuses[i].Service, err)
+			}
+		}
+	}
+
+	if o.output == "json" {
+		out, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %v", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	printStatusTable(statuses, time.Now())
+	return nil
+}
+
+// collectStatus reads every saas file in app-interface and returns the targets of each
+func collectStatus(appInterface git.AppInterface, serviceFilter string) ([]serviceStatus, error) {
+	serviceNames, err := saas.GetServiceNames(appInterface, saas.OSDSaasDir, saas.BPSaasDir, saas.CADSaasDir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(serviceNames)
+
+	var statuses []serviceStatus
+	for _, serviceName := range serviceNames {
+		if serviceFilter != "" && !strings.Contains(serviceName, serviceFilter) {
+			continue
+		}
+
+		for flavor, saasFile := range saasFilesForService(saas.ServicesFilesMap[serviceName]) {
+			status, err := readServiceStatus(appInterface.GitDirectory, serviceName, flavor, saasFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", saasFile, err)
+				continue
+			}
+			statuses = append(statuses, *status)
+		}
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if statuses[i].Service != statuses[j].Service {
+			return statuses[i].Service < statuses[j].Service
+		}
+		return statuses[i].Flavor < statuses[j].Flavor
+	})
+
+	return statuses, nil
+}
+
+// saasFilesForService returns the existing saas files of a service keyed by flavor (osd or hcp).
+// Services are either a single saas file or a directory holding deploy.yaml and hypershift-deploy.yaml.
+func saasFilesForService(path string) map[string]string {
+	files := map[string]string{}
+	if strings.HasSuffix(path, ".yaml") {
+		files["osd"] = path
+		return files
+	}
+
+	for flavor, name := range map[string]string{"osd": "deploy.yaml", "hcp": "hypershift-deploy.yaml"} {
+		candidate := filepath.Join(path, name)
+		if _, err := os.Stat(candidate); err == nil {
+			files[flavor] = candidate
+		}
+	}
+
+	return files
+}
+
+func readServiceStatus(gitDirectory, serviceName, flavor, saasFile string) (*serviceStatus, error) {
+	data, err := os.ReadFile(saasFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saas file: %v", err)
+	}
+
+	service := git.Service{}
+	if err := yaml.Unmarshal(data, &service); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal saas file: %v", err)
+	}
+
+	status := &serviceStatus{
+		Service:  serviceName,
+		Flavor:   flavor,
+		SaasFile: saasFile,
+	}
+
+	for _, rt := range service.ResourceTemplates {
+		// Package templates track PKO image tags rather than git hashes
+		if strings.Contains(rt.Name, "package") {
+			continue
+		}
+		for _, target := range rt.Targets {
+			namespaceRef := target.Namespace["$ref"]
+			// Resolved like promote saas does for --namespaceRef
+			hash, repo, path, err := git.GetCurrentGitHashAndPathFromAppInterface(data, serviceName, namespaceRef)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: skipping target %s of %s: %v\n", namespaceRef, serviceName, err)
+				continue
+			}
+			if status.Repo == "" {
+				status.Repo = repo
+			}
+			status.Targets = append(status.Targets, targetStatus{
+				Name:         target.Name,
+				NamespaceRef: namespaceRef,
+				Environment:  namespaceEnvironment(gitDirectory, namespaceRef),
+				GitHash:      hash,
+				Path:         path,
+			})
+		}
+	}
+
+	status.HotfixVersions = readHotfixVersions(gitDirectory, serviceName, saasFile)
+
+	return status, nil
+}
+
+// namespaceEnvironment returns the environment of a namespace from the environment its app-interface
+// namespace file refers to, e.g. /products/osdv4/environments/integration.yml
+func namespaceEnvironment(gitDirectory, namespaceRef string) string {
+	data, err := os.ReadFile(filepath.Join(gitDirectory, "data", namespaceRef))
+	if err != nil {
+		return envOther
+	}
+
+	namespace := struct {
+		Environment map[string]string `yaml:"environment"`
+	}{}
+	if err := yaml.Unmarshal(data, &namespace); err != nil {
+		return envOther
+	}
+
+	return classifyEnvironment(namespace.Environment["$ref"])
+}
+
+// classifyEnvironment maps an app-interface environment file to int, stage or prod by the words of its name
+func classifyEnvironment(environmentRef string) string {
+	name := strings.TrimSuffix(filepath.Base(environmentRef), filepath.Ext(environmentRef))
+	for _, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool { return r == '-' || r == '_' }) {
+		switch word {
+		case "int", "integration":
+			return envInt
+		case "stage", "staging":
+			return envStage
+		case "prod", "production":
+			return envProd
+		}
+	}
+	return envOther
+}
+
+// readHotfixVersions returns the hotfixVersions set for the service's component in app.yml, if any
+func readHotfixVersions(gitDirectory, serviceName, saasFile string) []string {
+	componentName := strings.TrimPrefix(serviceName, "saas-")
+
+	appYmlPath, err := pathutil.DeriveAppYmlPath(gitDirectory, saasFile, componentName)
+	if err != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(appYmlPath)
+	if err != nil {
+		return nil
+	}
+
+	app := appYml{}
+	if err := yaml.Unmarshal(data, &app); err != nil {
+		return nil
+	}
+
+	for _, component := range app.CodeComponents {
+		if component.Name == componentName {
+			return component.HotfixVersions
+		}
+	}
+
+	return nil
+}
+
+// enrichWithGitHistory clones the service repository to look up the commit date of every
+// deployed hash and how many commits each prod target is behind stage
+func enrichWithGitHistory(gitExecutor iexec.IExec, status *serviceStatus) error {
+	if status.Repo == "" || len(status.Targets) == 0 {
+		return nil
+	}
+
+	tempDir, err := os.MkdirTemp("", "osdctl-promote-status-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	repoDir := filepath.Join(tempDir, "source-dir")
+	if err := gitExecutor.Run(tempDir, "git", "clone", "--quiet", "--bare", status.Repo, repoDir); err != nil {
+		return fmt.Errorf("failed to clone git repository %s: %v", status.Repo, err)
+	}
+
+	commitDates := map[string]*time.Time{}
+	for i, target := range status.Targets {
+		if _, ok := commitDates[target.GitHash]; !ok {
+			commitDates[target.GitHash] = commitDate(gitExecutor, repoDir, target.GitHash)
+		}
+		status.Targets[i].CommitDate = commitDates[target.GitHash]
+	}
+
+	stageHash := latestStageHash(status.Targets)
+	if stageHash == "" {
+		return nil
+	}
+
+	for i, target := range status.Targets {
+		if target.Environment != envProd {
+			continue
+		}
+		out, err := gitExecutor.Output(repoDir, "git", "rev-list", "--count", fmt.Sprintf("%s..%s", target.GitHash, stageHash))
+		if err != nil {
+			continue
+		}
+		behind, err := strconv.Atoi(strings.TrimSpace(out))
+		if err != nil {
+			continue
+		}
+		status.Targets[i].BehindStage = &behind
+	}
+
+	return nil
+}
+
+func commitDate(gitExecutor iexec.IExec, repoDir, hash string) *time.Time {
+	out, err := gitExecutor.Output(repoDir, "git", "show", "-s", "--format=%ct", hash)
+	if err != nil {
+		return nil
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return nil
+	}
+	t := time.Unix(seconds, 0).UTC()
+	return &t
+}
+
+// latestStageHash returns the most recently committed hash deployed to a stage target
+func latestStageHash(targets []targetStatus) string {
+	var hash string
+	var latest *time.Time
+	for _, target := range targets {
+		if target.Environment != envStage {
+			continue
+		}
+		if hash == "" || (target.CommitDate != nil && (latest == nil || target.CommitDate.After(*latest))) {
+			hash = target.GitHash
+			latest = target.CommitDate
+		}
+	}
+	return hash
+}
+
+func printStatusTable(statuses []serviceStatus, now time.Time) {
+	p := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	p.AddRow([]string{"SERVICE", "FLAVOR", "ENV", "NAMESPACE REF", "GIT HASH", "AGE", "BEHIND STAGE", "HOTFIX"})
+
+	for _, s := range statuses {
+		hotfix := strings.Join(s.HotfixVersions, ",")
+		for _, target := range s.Targets {
+			age := "N/A"
+			if target.CommitDate != nil {
+				age = fmt.Sprintf("%dd", int(now.Sub(*target.CommitDate).Hours()/24))
+			}
+			behind := ""
+			if target.BehindStage != nil {
+				behind = strconv.Itoa(*target.BehindStage)
+			}
+			p.AddRow([]string{
+				s.Service,
+				s.Flavor,
+				target.Environment,
+				filepath.Base(target.NamespaceRef),
+				git.ShortHash(target.GitHash),
+				age,
+				behind,
+				hotfix,
+			})
+		}
+	}
+
+	if err := p.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing table: %v\n", err)
+	}
+}
@@ -0,0 +1,147 @@
+package status
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/promote/iexec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockExec struct {
+	mock.Mock
+	iexec.IExec
+}
+
+func (m *MockExec) Run(dir string, name string, args ...string) error {
+	argsList := m.Called(dir, name, args)
+	return argsList.Error(0)
+}
+
+func (m *MockExec) Output(dir, cmd string, args ...string) (string, error) {
+	argsList := m.Called(dir, cmd, args)
+	return argsList.String(0), argsList.Error(1)
+}
+
+func TestClassifyEnvironment(t *testing.T) {
+	tests := []struct {
+		environmentRef string
+		expected       string
+	}{
+		{"/products/osdv4/environments/integration.yml", envInt},
+		{"/products/osdv4/environments/hive-int.yml", envInt},
+		{"/products/osdv4/environments/stage.yml", envStage},
+		{"/products/osdv4/environments/production.yml", envProd},
+		{"/products/app-sre/environments/app-sre-prod.yml", envProd},
+		// Only whole words count, internal is not integration
+		{"/products/osdv4/environments/internal.yml", envOther},
+		{"", envOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.environmentRef, func(t *testing.T) {
+			assert.Equal(t, tt.expected, classifyEnvironment(tt.environmentRef))
+		})
+	}
+}
+
+func TestReadServiceStatus(t *testing.T) {
+	gitDir := t.TempDir()
+	serviceDir := filepath.Join(gitDir, "data", "services", "osd-operators", "cicd", "saas", "saas-foo-operator")
+	require.NoError(t, os.MkdirAll(serviceDir, 0755))
+
+	saasFile := filepath.Join(serviceDir, "deploy.yaml")
+	require.NoError(t, os.WriteFile(saasFile, []byte(`name: saas-foo-operator
+resourceTemplates:
+- name: foo-operator
+  url: https://github.com/openshift/foo-operator
+  targets:
+  - namespace:
+      $ref: /services/osd-operators/namespaces/hivei01ue1/foo.yml
+    ref: master
+  - namespace:
+      $ref: /services/osd-operators/namespaces/hives02ue1/foo.yml
+    ref: bbbbbbbbbb
+  - namespace:
+      $ref: /services/osd-operators/namespaces/hivep01ue1/foo.yml
+    ref: aaaaaaaaaa
+- name: foo-operator-package
+  url: https://github.com/openshift/foo-operator
+  targets:
+  - namespace:
+      $ref: /services/osd-operators/namespaces/hivep01ue1/foo.yml
+    parameters:
+      PACKAGE_TAG: cccccccccc
+`), 0600))
+
+	namespaceDir := filepath.Join(gitDir, "data", "services", "osd-operators", "namespaces")
+	for namespace, environment := range map[string]string{"hivei01ue1": "integration", "hives02ue1": "stage", "hivep01ue1": "production"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(namespaceDir, namespace), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(namespaceDir, namespace, "foo.yml"), []byte(`name: foo
+environment:
+  $ref: /products/osdv4/environments/`+environment+`.yml
+`), 0600))
+	}
+
+	appYmlDir := filepath.Join(gitDir, "data", "services", "osd-operators", "foo-operator")
+	require.NoError(t, os.MkdirAll(appYmlDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(appYmlDir, "app.yml"), []byte(`codeComponents:
+- name: foo-operator
+  hotfixVersions:
+  - aaaaaaaaaa
+- name: other
+`), 0600))
+
+	status, err := readServiceStatus(gitDir, "saas-foo-operator", "osd", saasFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://github.com/openshift/foo-operator", status.Repo)
+	assert.Equal(t, []string{"aaaaaaaaaa"}, status.HotfixVersions)
+	require.Len(t, status.Targets, 3)
+	assert.Equal(t, envInt, status.Targets[0].Environment)
+	assert.Equal(t, "master", status.Targets[0].GitHash)
+	assert.Equal(t, envStage, status.Targets[1].Environment)
+	assert.Equal(t, envProd, status.Targets[2].Environment)
+	assert.Equal(t, "aaaaaaaaaa", status.Targets[2].GitHash)
+}
+
+func TestEnrichWithGitHistory(t *testing.T) {
+	status := &serviceStatus{
+		Service: "saas-foo-operator",
+		Repo:    "https://github.com/openshift/foo-operator",
+		Targets: []targetStatus{
+			{Environment: envStage, GitHash: "stagehash"},
+			{Environment: envProd, GitHash: "prodhash"},
+		},
+	}
+
+	mockExec := new(MockExec)
+	mockExec.On("Run", mock.Anything, "git", mock.MatchedBy(func(args []string) bool {
+		return len(args) > 0 && args[0] == "clone"
+	})).Return(nil)
+	mockExec.On("Output", mock.Anything, "git", []string{"show", "-s", "--format=%ct", "stagehash"}).Return("1700000000\n", nil)
+	mockExec.On("Output", mock.Anything, "git", []string{"show", "-s", "--format=%ct", "prodhash"}).Return("1600000000\n", nil)
+	mockExec.On("Output", mock.Anything, "git", []string{"rev-list", "--count", "prodhash..stagehash"}).Return("12\n", nil)
+
+	require.NoError(t, enrichWithGitHistory(mockExec, status))
+	mockExec.AssertExpectations(t)
+
+	require.NotNil(t, status.Targets[0].CommitDate)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), *status.Targets[0].CommitDate)
+	assert.Nil(t, status.Targets[0].BehindStage)
+	require.NotNil(t, status.Targets[1].BehindStage)
+	assert.Equal(t, 12, *status.Targets[1].BehindStage)
+}
+
+func TestStatusFlags(t *testing.T) {
+	cmd := NewCmdStatus()
+	// --output and -o are inherited from the global flags, as are -s and --server
+	assert.Nil(t, cmd.Flags().Lookup("output"))
+	assert.Nil(t, cmd.Flags().ShorthandLookup("o"))
+	assert.Nil(t, cmd.Flags().ShorthandLookup("s"))
+	assert.NotNil(t, cmd.Flags().Lookup("service"))
+}
@@ -8,238 +8,189 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 const (
-	ProdJumproleConfigKey   = "prod_jumprole_account_id"
-	AwsProxy                = "aws_proxy"
-	StageJumproleConfigKey  = "stage_jumprole_account_id"
-	PdUserToken             = "pd_user_token" // #nosec G101
-	JiraToken               = "jira_token"
-	DtVaultPath             = "dt_vault_path"
-	VaultAddress            = "vault_address"
-	CloudTrailCmdLists      = "cloudtrail_cmd_lists"
-	GitLabToken             = "gitlab_access"
-	CADGrafanaURL           = "cad_grafana_url"
-	CADAWSAccountID         = "cad_aws_account_id"
-	JiraTokenRegex          = "^[A-Z0-9]{7}$"        // #nosec G101
-	PdTokenRegex            = "^[a-zA-Z0-9+_-]{20}$" // #nosec G101
-	AwsAccountRegex         = "^[0-9]{12}$"
-	AWSProxyRegex           = `^http:\/\/[a-zA-Z0-9.-]+(:\d+)?$`
-	VaultURLRegex           = `^https:\/\/[a-zA-Z0-9.-]+\/?$`
-	DtVaultPathRegex        = `^[a-zA-Z0-9\-/]+$`
+	ProdJumproleConfigKey   = osdctlConfig.ProdJumproleAccountIDKey
+	AwsProxy                = osdctlConfig.AWSProxyKey
+	StageJumproleConfigKey  = osdctlConfig.StageJumproleAccountIDKey
+	PdUserToken             = osdctlConfig.PdUserTokenKey
+	JiraToken               = osdctlConfig.JiraTokenKey
+	JiraEmail               = osdctlConfig.JiraEmailKey
+	DtVaultPath             = osdctlConfig.DtVaultPathKey
+	VaultAddress            = osdctlConfig.VaultAddressKey
+	CloudTrailCmdLists      = osdctlConfig.CloudTrailCmdListsKey
+	GitLabToken             = osdctlConfig.GitLabTokenKey
+	CADGrafanaURL           = osdctlConfig.CADGrafanaURLKey
+	CADAWSAccountID         = osdctlConfig.CADAWSAccountIDKey
+	PdTokenRegex            = osdctlConfig.PdTokenRegex
+	AwsAccountRegex         = osdctlConfig.AwsAccountRegex
+	AWSProxyRegex           = osdctlConfig.AWSProxyRegex
+	VaultURLRegex           = osdctlConfig.VaultURLRegex
+	DtVaultPathRegex        = osdctlConfig.DtVaultPathRegex
 	CloudTrailCmdListsRegex = `^\s*-\s+.*$`
-	GitLabTokenRegex        = `^[a-zA-Z0-9]{20}$` // #nosec G101
-	URLRegex                = `^https?:\/\/[a-zA-Z0-9.-]+(:\d+)?$`
+	URLRegex                = osdctlConfig.URLRegex
 )
 
+// setupKeys are the keys configured by setup, in the order they are prompted
+var setupKeys = []setupKey{
+	{name: ProdJumproleConfigKey, required: true},
+	{name: AwsProxy, required: true},
+	{name: StageJumproleConfigKey, required: true},
+	{name: DtVaultPath},
+	{name: VaultAddress},
+	{name: PdUserToken},
+	{name: JiraToken},
+	{name: CloudTrailCmdLists},
+	{name: GitLabToken},
+	{name: CADGrafanaURL},
+	{name: CADAWSAccountID},
+	{name: JiraEmail},
+}
+
+type setupKey struct {
+	name     string
+	required bool
+}
+
+// flagName returns the setup flag of a key, e.g. --prod-jumprole-account-id
+func (k setupKey) flagName() string {
+	return strings.ReplaceAll(k.name, "_", "-")
+}
+
+type setupOptions struct {
+	nonInteractive bool
+	flagValues     map[string]*string
+}
+
 // NewCmdSetup implements the setup command
 func NewCmdSetup() *cobra.Command {
+	ops := &setupOptions{flagValues: map[string]*string{}}
 	setupCmd := &cobra.Command{
 		Use:   "setup",
 		Short: "Setup the configuration",
-		Args:  cobra.NoArgs,
+		Long: `Setup the osdctl configuration file.
+
+Values are taken from flags, then from OSDCTL_<KEY> environment variables (e.g. OSDCTL_PROD_JUMPROLE_ACCOUNT_ID),
+then prompted for. With --non-interactive nothing is prompted and the current value of the config file is kept
+for keys which are not provided, failing if a required key has no value.`,
+		Example: `
+  # Configure osdctl interactively
+  osdctl setup
+
+  # Configure osdctl in CI or onboarding scripts
+  OSDCTL_PD_USER_TOKEN=... osdctl setup --non-interactive \
+    --prod-jumprole-account-id 123456789012 \
+    --stage-jumprole-account-id 210987654321 \
+    --aws-proxy http://proxy.example.com:3128`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			keys := []string{
-				ProdJumproleConfigKey,
-				AwsProxy,
-				StageJumproleConfigKey,
-			}
+			return ops.run(cmd)
+		},
+	}
 
-			optionalKeys := []string{
-				DtVaultPath,
-				VaultAddress,
-				PdUserToken,
-				JiraToken,
-				CloudTrailCmdLists,
-				GitLabToken,
-				CADGrafanaURL,
-				CADAWSAccountID,
-			}
+	setupCmd.Flags().BoolVar(&ops.nonInteractive, "non-interactive", false, "Do not prompt, only use flags, environment variables and the current config")
+	for _, key := range setupKeys {
+		ops.flagValues[key.name] = setupCmd.Flags().String(key.flagName(), "", fmt.Sprintf("Value of %s", key.name))
+	}
+	return setupCmd
+}
 
-			values := make(map[string]string)
-			reader := bufio.NewReader(os.Stdin)
+func (o *setupOptions) run(cmd *cobra.Command) error {
+	values := make(map[string]interface{})
+	reader := bufio.NewReader(cmd.InOrStdin())
+	out := cmd.OutOrStdout()
 
-			defaults := make(map[string]string)
-			for _, key := range keys {
-				defaultValue := viper.GetString(key)
-				defaults[key] = defaultValue
-			}
+	for _, key := range setupKeys {
+		defaultValue := viper.GetString(key.name)
 
-			for _, key := range optionalKeys {
-				defaultValue := viper.GetString(key)
-				defaults[key] = defaultValue
+		value, provided := o.providedValue(cmd, key)
+		if !provided && !o.nonInteractive {
+			if key.required {
+				fmt.Fprintf(out, "\033[91mEnter %s \033[0m [\033[94mdefault %s\033[0m]:", key.name, defaultValue)
+			} else {
+				fmt.Fprintf(out, "\033[91mEnter %s (optional)\033[0m [\033[94mdefault %s\033[0m]:", key.name, defaultValue)
 			}
+			value, _ = reader.ReadString('\n')
+		}
+		value = strings.TrimSpace(value)
 
-			for _, key := range keys {
-				defaultValue := defaults[key]
-				fmt.Printf("\033[91mEnter %s \033[0m [\033[94mdefault %s\033[0m]:", key, defaultValue)
-				value, _ := reader.ReadString('\n')
-				value = strings.TrimSpace(value)
-
-				if value == "" {
-					value = defaultValue
-				}
-
-				var err error
-				switch key {
-				case JiraToken:
-					if value != "" && value != defaultValue {
-						_, err = ValidateJiraToken(value)
-					}
-				case PdUserToken:
-					if value != "" && value != defaultValue {
-						_, err = ValidatePDToken(value)
-					}
-				case ProdJumproleConfigKey, StageJumproleConfigKey:
-					if value != "" && value != defaultValue {
-						_, err = ValidateAWSAccount(value)
-					}
-				case AwsProxy:
-					if value != "" && value != defaultValue {
-						_, err = ValidateAWSProxy(value)
-					}
-				case VaultAddress:
-					if value != "" && value != defaultValue {
-						_, err = ValidateVaultAddress(value)
-					}
-				case DtVaultPath:
-					if value != "" && value != defaultValue {
-						_, err = ValidateDtVaultPath(value)
-					}
-				case CloudTrailCmdLists:
-					if value != "" && value != defaultValue {
-						_, err = ValidateCloudTrailCmdLists(value)
-					}
-				case GitLabToken:
-					if value != "" && value != defaultValue {
-						_, err = ValidateGitLabToken(value)
-					}
-				}
-
-				if err != nil {
-					return err
-				}
-
-				values[key] = value
+		if value == "" || value == strings.TrimSpace(defaultValue) {
+			if key.required && defaultValue == "" {
+				return fmt.Errorf("%s is required, set it with --%s or %s", key.name, key.flagName(), osdctlConfig.EnvVar(key.name))
 			}
+			continue
+		}
 
-			for _, key := range optionalKeys {
-				defaultValue := defaults[key]
-				fmt.Printf("\033[91mEnter %s (optional)\033[0m [\033[94mdefault %s\033[0m]:", key, defaultValue)
-				value, _ := reader.ReadString('\n')
-				value = strings.TrimSpace(value)
-
-				if value == "" {
-					value = defaultValue
-				}
-
-				var err error
-				if value != "" && value != defaultValue {
-					switch key {
-					case CADGrafanaURL:
-						_, err = ValidateURL(value)
-					case CADAWSAccountID:
-						_, err = ValidateAWSAccount(value)
-					}
-				}
-				if err != nil {
-					return err
-				}
+		parsed, err := parseSetupValue(key.name, value)
+		if err != nil {
+			return err
+		}
+		values[key.name] = parsed
+	}
 
-				if value != "" && value != defaultValue {
-					values[key] = value
-				}
-			}
+	// Store the value in the config file
+	for key, value := range values {
+		viper.Set(key, value)
+	}
+	err := viper.WriteConfig()
+	if err != nil {
+		return err
+	}
 
-			// Store the value in the config file
-			for key, value := range values {
-				viper.Set(key, value)
-			}
-			err := viper.WriteConfig()
-			if err != nil {
-				return err
-			}
+	fmt.Fprintln(out, "Configuration saved successfully")
+	return nil
+}
 
-			fmt.Println("Configuration saved successfully")
-			return nil
-		},
+// providedValue returns the value of a key given by flag or environment variable
+func (o *setupOptions) providedValue(cmd *cobra.Command, key setupKey) (string, bool) {
+	if cmd.Flags().Changed(key.flagName()) {
+		return *o.flagValues[key.name], true
 	}
-	return setupCmd
+	return os.LookupEnv(osdctlConfig.EnvVar(key.name))
 }
 
-func ValidateJiraToken(token string) (string, error) {
-	token = strings.TrimSpace(token)
-	match, err := regexp.MatchString(JiraTokenRegex, token)
-	if err != nil {
-		return "", err
+// parseSetupValue validates a value and converts it to the type of the config schema
+func parseSetupValue(name, value string) (interface{}, error) {
+	switch name {
+	case CloudTrailCmdLists:
+		// Setup takes a single "- <pattern>" item, stored as the filter_regex_patterns list
+		value, err := ValidateCloudTrailCmdLists(value)
+		if err != nil {
+			return nil, err
+		}
+		pattern := strings.TrimSpace(strings.TrimPrefix(value, "-"))
+		return map[string]interface{}{"filter_regex_patterns": []string{pattern}}, nil
 	}
-	if !match {
-		return "", errors.New("invalid jira token")
+
+	key, ok := osdctlConfig.LookupKey(name)
+	if !ok {
+		return value, nil
 	}
-	return token, nil
+	return key.Parse(value)
 }
 
 func ValidatePDToken(token string) (string, error) {
-	token = strings.TrimSpace(token)
-	match, err := regexp.MatchString(PdTokenRegex, token)
-	if err != nil {
-		return "", err
-	}
-	if !match {
-		return "", errors.New("invalid pd token")
-	}
-	return token, nil
+	return osdctlConfig.ValidatePDToken(token)
 }
 
 func ValidateAWSAccount(account string) (string, error) {
-	account = strings.TrimSpace(account)
-	match, err := regexp.MatchString(AwsAccountRegex, account)
-	if err != nil {
-		return "", err
-	}
-	if !match {
-		return "", errors.New("invalid AWS account number")
-	}
-	return account, nil
+	return osdctlConfig.ValidateAWSAccount(account)
 }
 
 func ValidateAWSProxy(proxyURL string) (string, error) {
-	proxyURL = strings.TrimSpace(proxyURL)
-	match, err := regexp.MatchString(AWSProxyRegex, proxyURL)
-	if err != nil {
-		return "", err
-	}
-	if !match {
-		return "", errors.New("invalid AWS proxy URL")
-	}
-	return proxyURL, nil
+	return osdctlConfig.ValidateAWSProxy(proxyURL)
 }
 
 func ValidateVaultAddress(vaultURL string) (string, error) {
-	vaultURL = strings.TrimSpace(vaultURL)
-	match, err := regexp.MatchString(VaultURLRegex, vaultURL)
-	if err != nil {
-		return "", err
-	}
-	if !match {
-		return "", errors.New("invalid Vault URL")
-	}
-	return vaultURL, nil
+	return osdctlConfig.ValidateVaultAddress(vaultURL)
 }
 
 func ValidateDtVaultPath(dtVaultPath string) (string, error) {
-	dtVaultPath = strings.TrimSpace(dtVaultPath)
-	match, err := regexp.MatchString(DtVaultPathRegex, dtVaultPath)
-	if err != nil {
-		return "", err
-	}
-	if !match {
-		return "", errors.New("invalid DtVault Path")
-	}
-	return dtVaultPath, nil
+	return osdctlConfig.ValidateDtVaultPath(dtVaultPath)
 }
 
 func ValidateCloudTrailCmdLists(cloudTrailCmd string) (string, error) {
@@ -254,27 +205,6 @@ func ValidateCloudTrailCmdLists(cloudTrailCmd string) (string, error) {
 	return cloudTrailCmd, nil
 }
 
-func ValidateGitLabToken(GitLabtoken string) (string, error) {
-	GitLabtoken = strings.TrimSpace(GitLabtoken)
-	match, err := regexp.MatchString(GitLabTokenRegex, GitLabtoken)
-	if err != nil {
-		return "", err
-	}
-	if !match {
-		return "", errors.New("invalid GitLab token")
-	}
-	return GitLabtoken, nil
-}
-
 func ValidateURL(url string) (string, error) {
-	url = strings.TrimSpace(url)
-	url = strings.TrimSuffix(url, "/")
-	match, err := regexp.MatchString(URLRegex, url)
-	if err != nil {
-		return "", err
-	}
-	if !match {
-		return "", errors.New("invalid URL")
-	}
-	return url, nil
+	return osdctlConfig.ValidateURL(url)
 }
@@ -19,18 +19,6 @@ func TestSetup(t *testing.T) {
 }
 
 var _ = Describe("Validation Functions", func() {
-	Context("Jira Token", func() {
-		It("should validate correct Jira token", func() {
-			token, _ := ValidateJiraToken("ABC1234")
-			Expect(token).To(Equal("ABC1234"))
-		})
-
-		It("should fail invalid Jira token", func() {
-			_, err := ValidateJiraToken("invalid")
-			Expect(err).To(HaveOccurred())
-		})
-	})
-
 	Context("PD Token", func() {
 		It("should validate correct PD token", func() {
 			token, err := ValidatePDToken("abcdEFGHijklMNOPqrst")
@@ -115,19 +103,6 @@ var _ = Describe("Validation Functions", func() {
 		})
 	})
 
-	Context("GitLab Token", func() {
-		It("should validate correct GitLab token", func() {
-			token, err := ValidateGitLabToken("abcdEFGHijklMNOPqrst")
-			Expect(err).To(BeNil())
-			Expect(token).To(Equal("abcdEFGHijklMNOPqrst"))
-		})
-
-		It("should fail invalid GitLab token", func() {
-			_, err := ValidateGitLabToken("shorttoken")
-			Expect(err).To(HaveOccurred())
-		})
-	})
-
 	Context("URL Validation", func() {
 		It("should validate correct HTTP URL", func() {
 			url, err := ValidateURL("http://grafana.example.com")
@@ -239,3 +214,70 @@ var _ = Describe("NewCmdSetup Command", func() {
 		})
 	})
 })
+
+var _ = Describe("NewCmdSetup non-interactive", func() {
+	BeforeEach(func() {
+		viper.Reset()
+		viper.SetFs(afero.NewMemMapFs())
+		viper.SetConfigType("yaml")
+		viper.SetConfigFile("/tmp/config.yaml")
+	})
+
+	It("should configure from flags and environment variables", func() {
+		Expect(os.Setenv("OSDCTL_PD_USER_TOKEN", "abcdEFGHijklMNOPqrst")).To(Succeed())
+		defer os.Unsetenv("OSDCTL_PD_USER_TOKEN")
+		Expect(os.Setenv("OSDCTL_AWS_PROXY", "http://ignored.example.com")).To(Succeed())
+		defer os.Unsetenv("OSDCTL_AWS_PROXY")
+
+		setupCmd := NewCmdSetup()
+		setupCmd.SetOut(&bytes.Buffer{})
+		setupCmd.SetArgs([]string{
+			"--non-interactive",
+			"--prod-jumprole-account-id", "123456789012",
+			"--stage-jumprole-account-id", "987654321098",
+			"--aws-proxy", "http://proxy.example.com:3128",
+			"--cloudtrail-cmd-lists", "- .*Get.*",
+			"--jira-email", "sre@example.com",
+		})
+		Expect(setupCmd.Execute()).To(Succeed())
+
+		Expect(viper.GetString(ProdJumproleConfigKey)).To(Equal("123456789012"))
+		Expect(viper.GetString(StageJumproleConfigKey)).To(Equal("987654321098"))
+		Expect(viper.GetString(AwsProxy)).To(Equal("http://proxy.example.com:3128"))
+		Expect(viper.GetString(PdUserToken)).To(Equal("abcdEFGHijklMNOPqrst"))
+		Expect(viper.GetStringSlice(CloudTrailCmdLists + ".filter_regex_patterns")).To(Equal([]string{".*Get.*"}))
+		Expect(viper.GetString(JiraEmail)).To(Equal("sre@example.com"))
+		Expect(viper.IsSet(JiraToken)).To(BeFalse())
+	})
+
+	It("should keep the current config for keys which are not provided", func() {
+		viper.Set(ProdJumproleConfigKey, "123456789012")
+		viper.Set(StageJumproleConfigKey, "987654321098")
+		viper.Set(AwsProxy, "http://proxy.example.com")
+
+		setupCmd := NewCmdSetup()
+		setupCmd.SetOut(&bytes.Buffer{})
+		setupCmd.SetArgs([]string{"--non-interactive", "--cad-aws-account-id", "844056765545"})
+		Expect(setupCmd.Execute()).To(Succeed())
+
+		Expect(viper.GetString(ProdJumproleConfigKey)).To(Equal("123456789012"))
+		Expect(viper.GetString(CADAWSAccountID)).To(Equal("844056765545"))
+	})
+
+	It("should fail when a required key is missing", func() {
+		setupCmd := NewCmdSetup()
+		setupCmd.SetOut(&bytes.Buffer{})
+		setupCmd.SetErr(&bytes.Buffer{})
+		setupCmd.SetArgs([]string{"--non-interactive", "--prod-jumprole-account-id", "123456789012"})
+		err := setupCmd.Execute()
+		Expect(err).To(MatchError(ContainSubstring("aws_proxy is required, set it with --aws-proxy or OSDCTL_AWS_PROXY")))
+	})
+
+	It("should fail on invalid values", func() {
+		setupCmd := NewCmdSetup()
+		setupCmd.SetOut(&bytes.Buffer{})
+		setupCmd.SetErr(&bytes.Buffer{})
+		setupCmd.SetArgs([]string{"--non-interactive", "--prod-jumprole-account-id", "1234"})
+		Expect(setupCmd.Execute()).To(MatchError(ContainSubstring("invalid AWS account number")))
+	})
+})
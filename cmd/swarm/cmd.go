@@ -9,5 +9,7 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
-	Cmd.AddCommand(secondaryCmd)
+	Cmd.AddCommand(newCmdSecondary())
+	Cmd.AddCommand(newCmdQueue())
+	Cmd.AddCommand(newCmdQueues())
 }
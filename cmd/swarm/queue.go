@@ -0,0 +1,592 @@
+package swarm
+
+//go:generate mockgen -source=queue.go -package=mock -destination=mock/queue.go
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/fatih/color"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const (
+	slaOK       = "OK"
+	slaAtRisk   = "AT RISK"
+	slaBreached = "BREACHED"
+
+	// slaAtRiskRatio is the part of the SLA after which a ticket is at risk
+	slaAtRiskRatio = 0.75
+	// defaultSLAKey is the SLA entry of the priorities without their own
+	defaultSLAKey = "default"
+
+	minWatchInterval = 30 * time.Second
+
+	// formatTable prints the columns of the queue, formatJira the Jira markup pasted in handovers
+	formatTable = "table"
+	formatJira  = "jira"
+)
+
+var (
+	defaultColumns = []string{"key", "priority", "status", "age", "sla", "summary"}
+	validColumns   = []string{"key", "link", "summary", "priority", "status", "type", "assignee", "reporter", "created", "updated", "age", "sla"}
+
+	orderByRe = regexp.MustCompile(`(?i)\border\s+by\b`)
+
+	atRiskColor   = color.New(color.FgYellow).SprintFunc()
+	breachedColor = color.New(color.FgRed).SprintFunc()
+)
+
+// builtinQueues are the queues available without config. A queue of the same name in the config
+// overrides their fields.
+func builtinQueues() map[string]osdctlConfig.SwarmQueue {
+	return map[string]osdctlConfig.SwarmQueue{
+		"secondary": {
+			Description: "Unassigned OHSS tickets of the secondary swarm",
+			JQL:         buildJQL(),
+			Format:      formatJira,
+		},
+	}
+}
+
+// queue is a swarm queue ready to run
+type queue struct {
+	osdctlConfig.SwarmQueue
+	name string
+	sla  map[string]time.Duration
+}
+
+func newQueue(name string, config osdctlConfig.SwarmQueue) (*queue, error) {
+	q := &queue{SwarmQueue: config, name: name, sla: map[string]time.Duration{}}
+	if strings.TrimSpace(q.JQL) == "" {
+		return nil, fmt.Errorf("queue %s has no jql", name)
+	}
+	if len(q.Columns) == 0 {
+		q.Columns = defaultColumns
+	}
+	if q.Format == "" {
+		q.Format = formatTable
+	}
+	if q.Format != formatTable && q.Format != formatJira {
+		return nil, fmt.Errorf("invalid format %q of queue %s, expecting %s or %s", q.Format, name, formatTable, formatJira)
+	}
+	for _, column := range q.Columns {
+		if !slices.Contains(validColumns, strings.ToLower(column)) {
+			return nil, fmt.Errorf("invalid column %q of queue %s, valid columns are %s", column, name, strings.Join(validColumns, ", "))
+		}
+	}
+	for priority, age := range q.SLA {
+		d, err := time.ParseDuration(age)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid sla %q of priority %s in queue %s, e.g. 4h", age, priority, name)
+		}
+		q.sla[strings.ToLower(priority)] = d
+	}
+	return q, nil
+}
+
+// mergeQueues returns the built-in queues overridden and completed by the configured ones
+func mergeQueues(configured map[string]osdctlConfig.SwarmQueue) map[string]osdctlConfig.SwarmQueue {
+	queues := builtinQueues()
+	for name, q := range configured {
+		builtin, ok := queues[name]
+		if !ok {
+			queues[name] = q
+			continue
+		}
+		if q.Description != "" {
+			builtin.Description = q.Description
+		}
+		if q.JQL != "" {
+			builtin.JQL = q.JQL
+		}
+		if q.Variables != nil {
+			builtin.Variables = q.Variables
+		}
+		if q.Columns != nil {
+			builtin.Columns = q.Columns
+		}
+		if q.Sort != "" {
+			builtin.Sort = q.Sort
+		}
+		if q.SLA != nil {
+			builtin.SLA = q.SLA
+		}
+		if q.Format != "" {
+			builtin.Format = q.Format
+		}
+		queues[name] = builtin
+	}
+	return queues
+}
+
+// QueueLoader returns the swarm queues by name
+type QueueLoader interface {
+	Queues() (map[string]osdctlConfig.SwarmQueue, error)
+}
+
+// Clock tells the time of the queue and paces its polling
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// configQueues loads the built-in queues and the queues of the osdctl config
+type configQueues struct{}
+
+func (configQueues) Queues() (map[string]osdctlConfig.SwarmQueue, error) {
+	v, err := osdctlConfig.ReadConfig()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return mergeQueues(nil), nil
+		}
+		return nil, err
+	}
+	var configured map[string]osdctlConfig.SwarmQueue
+	if err := v.UnmarshalKey(osdctlConfig.SwarmQueuesKey, &configured); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", osdctlConfig.SwarmQueuesKey, err)
+	}
+	return mergeQueues(configured), nil
+}
+
+// jql renders the JQL template of the queue, vars override the variables of the queue
+func (q *queue) jql(vars map[string]string) (string, error) {
+	values := map[string]string{}
+	for name, value := range q.Variables {
+		values[strings.ToLower(name)] = value
+	}
+	for name, value := range vars {
+		values[strings.ToLower(name)] = value
+	}
+
+	tmpl, err := template.New(q.name).Option("missingkey=error").Parse(q.JQL)
+	if err != nil {
+		return "", fmt.Errorf("invalid jql of queue %s: %w", q.name, err)
+	}
+	var jql strings.Builder
+	if err := tmpl.Execute(&jql, values); err != nil {
+		return "", fmt.Errorf("failed to render the jql of queue %s, set the missing variable with --var: %w", q.name, err)
+	}
+
+	rendered := strings.TrimSpace(jql.String())
+	if q.Sort != "" && !orderByRe.MatchString(rendered) {
+		rendered += " ORDER BY " + q.Sort
+	}
+	return rendered, nil
+}
+
+// slaState returns whether a ticket is within the SLA of its priority, "" when there is no SLA
+func (q *queue) slaState(issue jira.Issue, now time.Time) string {
+	priority := ""
+	if issue.Fields != nil && issue.Fields.Priority != nil {
+		priority = strings.ToLower(issue.Fields.Priority.Name)
+	}
+	sla, ok := q.sla[priority]
+	if !ok {
+		if sla, ok = q.sla[defaultSLAKey]; !ok {
+			return ""
+		}
+	}
+
+	switch age := issueAge(issue, now); {
+	case age >= sla:
+		return slaBreached
+	case float64(age) >= float64(sla)*slaAtRiskRatio:
+		return slaAtRisk
+	default:
+		return slaOK
+	}
+}
+
+func (q *queue) cell(column string, issue jira.Issue, now time.Time) string {
+	fields := issue.Fields
+	if fields == nil {
+		fields = &jira.IssueFields{}
+	}
+	switch strings.ToLower(column) {
+	case "key":
+		return issue.Key
+	case "link":
+		return fmt.Sprintf("%s/browse/%s", utils.JiraBaseURL, issue.Key)
+	case "summary":
+		return fields.Summary
+	case "priority":
+		if fields.Priority != nil {
+			return fields.Priority.Name
+		}
+	case "status":
+		if fields.Status != nil {
+			return fields.Status.Name
+		}
+	case "type":
+		return fields.Type.Name
+	case "assignee":
+		if fields.Assignee != nil {
+			return fields.Assignee.DisplayName
+		}
+	case "reporter":
+		if fields.Reporter != nil {
+			return fields.Reporter.DisplayName
+		}
+	case "created":
+		return time.Time(fields.Created).Format("2006-01-02 15:04")
+	case "updated":
+		return time.Time(fields.Updated).Format("2006-01-02 15:04")
+	case "age":
+		return formatAge(issueAge(issue, now))
+	case "sla":
+		if state := q.slaState(issue, now); state != "" {
+			return state
+		}
+	}
+	return "-"
+}
+
+// header prints the title of the queue before its issues
+func (q *queue) header(w io.Writer, issues []jira.Issue, now time.Time) {
+	if q.Format == formatJira {
+		fmt.Fprintf(w, "\nTimestamp:  %s\nTitle 🠒 :Swarm: %s. \n\n", now.String(), strings.ToUpper(q.name[:1])+q.name[1:])
+		return
+	}
+	fmt.Fprintf(w, "\nTimestamp:  %s\nQueue: %s (%d tickets)\n\n", now.String(), q.name, len(issues))
+}
+
+// printArrived prints the issues which arrived in the queue since it was printed, without repeating the
+// header of the Jira markup
+func (q *queue) printArrived(w io.Writer, issues []jira.Issue, now time.Time) error {
+	if q.Format == formatJira {
+		utils.FprintJiraIssueLines(w, issues)
+		return nil
+	}
+	return q.print(w, issues, now)
+}
+
+// print prints the issues as a table, highlighting the tickets at risk of breaching or breaching their SLA,
+// or in Jira markup
+func (q *queue) print(w io.Writer, issues []jira.Issue, now time.Time) error {
+	if q.Format == formatJira {
+		utils.FprintJiraIssues(w, issues)
+		return nil
+	}
+	if len(issues) == 0 {
+		_, err := fmt.Fprintln(w, "None")
+		return err
+	}
+
+	var table bytes.Buffer
+	p := printer.NewTablePrinter(&table, 20, 1, 3, ' ')
+	header := make([]string, len(q.Columns))
+	for i, column := range q.Columns {
+		header[i] = strings.ToUpper(column)
+	}
+	p.AddRow(header)
+	for _, issue := range issues {
+		row := make([]string, len(q.Columns))
+		for i, column := range q.Columns {
+			row[i] = q.cell(column, issue, now)
+		}
+		p.AddRow(row)
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	// Rows are colored after the table is laid out, color codes would break the column widths
+	lines := strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n")
+	fmt.Fprintln(w, lines[0])
+	for i, line := range lines[1:] {
+		switch q.slaState(issues[i], now) {
+		case slaBreached:
+			line = breachedColor(line)
+		case slaAtRisk:
+			line = atRiskColor(line)
+		}
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+func issueAge(issue jira.Issue, now time.Time) time.Duration {
+	if issue.Fields == nil || time.Time(issue.Fields.Created).IsZero() {
+		return 0
+	}
+	return now.Sub(time.Time(issue.Fields.Created))
+}
+
+// formatAge formats an age in days and hours, or hours and minutes when younger than a day
+func formatAge(age time.Duration) string {
+	switch {
+	case age >= 24*time.Hour:
+		return fmt.Sprintf("%dd%dh", int(age.Hours())/24, int(age.Hours())%24)
+	case age >= time.Hour:
+		return fmt.Sprintf("%dh%dm", int(age.Hours()), int(age.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	}
+}
+
+// queueOptions runs a swarm queue
+type queueOptions struct {
+	vars     map[string]string
+	watch    bool
+	interval time.Duration
+	assignMe bool
+	yes      bool
+
+	queues QueueLoader
+	// jiraClient is created on the first use
+	jiraClient utils.JiraClientInterface
+	clock      Clock
+	genericclioptions.IOStreams
+}
+
+// realClock is the wall clock
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func newQueueOptions() *queueOptions {
+	return &queueOptions{
+		queues:    configQueues{},
+		clock:     realClock{},
+		IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr},
+	}
+}
+
+func (o *queueOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringToStringVar(&o.vars, "var", nil, "Value of a variable of the JQL template, e.g. --var project=OHSS. Can be repeated")
+	cmd.Flags().BoolVarP(&o.watch, "watch", "w", false, "Keep polling the queue and print the tickets which arrive")
+	cmd.Flags().DurationVar(&o.interval, "interval", 2*time.Minute, "Polling interval of --watch")
+	cmd.Flags().BoolVar(&o.assignMe, "assign-me", false, "Assign the first unassigned ticket of the queue to yourself")
+	cmd.Flags().BoolVarP(&o.yes, "yes", "y", false, "Don't ask for confirmation before assigning a ticket")
+	cmd.MarkFlagsMutuallyExclusive("watch", "assign-me")
+}
+
+func (o *queueOptions) run(ctx context.Context, name string) error {
+	if o.watch && o.interval < minWatchInterval {
+		return fmt.Errorf("--interval must be at least %s", minWatchInterval)
+	}
+
+	queues, err := o.queues.Queues()
+	if err != nil {
+		return err
+	}
+	config, ok := queues[name]
+	if !ok {
+		return fmt.Errorf("unknown queue %q, known queues are %s", name, strings.Join(queueNames(queues), ", "))
+	}
+	q, err := newQueue(name, config)
+	if err != nil {
+		return err
+	}
+	jql, err := q.jql(o.vars)
+	if err != nil {
+		return err
+	}
+
+	if o.jiraClient == nil {
+		jiraClient, err := utils.NewJiraClient("")
+		if err != nil {
+			return fmt.Errorf("failed to get Jira client: %w", err)
+		}
+		o.jiraClient = jiraClient
+	}
+	jiraClient := o.jiraClient
+	issues, err := jiraClient.SearchIssues(jql)
+	if err != nil {
+		return fmt.Errorf("error fetching JIRA issues: %w", err)
+	}
+
+	q.header(o.Out, issues, o.clock.Now())
+	if err := q.print(o.Out, issues, o.clock.Now()); err != nil {
+		return err
+	}
+
+	switch {
+	case o.assignMe:
+		return o.assignNext(jiraClient, issues)
+	case o.watch:
+		return o.watchQueue(ctx, jiraClient, q, jql, issues)
+	}
+	return nil
+}
+
+// assignNext assigns the first unassigned ticket to the current user
+func (o *queueOptions) assignNext(jiraClient utils.JiraClientInterface, issues []jira.Issue) error {
+	i := slices.IndexFunc(issues, func(issue jira.Issue) bool {
+		return issue.Fields == nil || issue.Fields.Assignee == nil
+	})
+	if i < 0 {
+		return errors.New("the queue has no unassigned ticket")
+	}
+	next := issues[i]
+
+	self, err := jiraClient.GetSelf()
+	if err != nil {
+		return fmt.Errorf("failed to get the current Jira user: %w", err)
+	}
+	if !o.yes {
+		summary := ""
+		if next.Fields != nil {
+			summary = next.Fields.Summary
+		}
+		fmt.Fprintf(o.Out, "\nThis assigns %s to %s: %s\n", next.Key, self.DisplayName, summary)
+		if !utils.StreamConfirmPrompt(o.IOStreams) {
+			return nil
+		}
+	}
+
+	if err := jiraClient.AssignIssue(next.Key, self); err != nil {
+		return fmt.Errorf("failed to assign %s: %w", next.Key, err)
+	}
+	fmt.Fprintf(o.Out, "Assigned %s/browse/%s to %s\n", utils.JiraBaseURL, next.Key, self.DisplayName)
+	return nil
+}
+
+// watchQueue polls the queue until interrupted, printing the tickets which weren't in the queue before
+func (o *queueOptions) watchQueue(ctx context.Context, jiraClient utils.JiraClientInterface, q *queue, jql string, issues []jira.Issue) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	seen := map[string]bool{}
+	for _, issue := range issues {
+		seen[issue.Key] = true
+	}
+	fmt.Fprintf(o.Out, "\nWatching for new tickets every %s, press Ctrl+C to stop\n", o.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-o.clock.After(o.interval):
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		issues, err := jiraClient.SearchIssues(jql)
+		if err != nil {
+			fmt.Fprintf(o.Out, "Failed to refresh the queue: %v\n", err)
+			continue
+		}
+		var arrived []jira.Issue
+		for _, issue := range issues {
+			if !seen[issue.Key] {
+				seen[issue.Key] = true
+				arrived = append(arrived, issue)
+			}
+		}
+		if len(arrived) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(o.Out, "\n%s: %d new ticket(s)\n", o.clock.Now().Format(time.RFC3339), len(arrived))
+		if err := q.printArrived(o.Out, arrived, o.clock.Now()); err != nil {
+			return err
+		}
+	}
+}
+
+func queueNames(queues map[string]osdctlConfig.SwarmQueue) []string {
+	names := make([]string, 0, len(queues))
+	for name := range queues {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func newCmdQueue() *cobra.Command {
+	ops := newQueueOptions()
+	queueCmd := &cobra.Command{
+		Use:   "queue <name>",
+		Short: "List the tickets of a swarm queue",
+		Long: `List the tickets of a swarm queue.
+
+Queues are defined in the osdctl config under ` + osdctlConfig.SwarmQueuesKey + `. The jql of a queue is a Go template whose
+variables get their values from the variables of the queue or --var. Config keys are lower case, so are variable names.
+Tickets older than the sla of their priority are highlighted, tickets past ` + fmt.Sprintf("%.0f%%", slaAtRiskRatio*100) + ` of it are at risk.
+
+  swarm_queues:
+    hcp:
+      description: Unassigned HCP tickets
+      jql: project = {{.project}} AND component = "{{.component}}" AND assignee is EMPTY AND status = New
+      variables:
+        project: OHSS
+        component: HyperShift
+      columns: [key, priority, age, sla, summary]
+      sort: priority DESC, created ASC
+      sla:
+        blocker: 1h
+        critical: 4h
+        default: 24h
+
+The format of a queue is table, printing its columns, or jira, printing the Jira markup pasted in handovers.
+The built-in secondary queue uses the jira format and can be changed the same way, e.g. to only add an sla.`,
+		Example: `  # List the tickets of the hcp queue
+  osdctl swarm queue hcp
+
+  # Use another component and watch for new tickets
+  osdctl swarm queue hcp --var component=ROSA --watch
+
+  # Take the next ticket of the queue
+  osdctl swarm queue hcp --assign-me`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ops.run(cmd.Context(), args[0])
+		},
+	}
+	ops.addFlags(queueCmd)
+	return queueCmd
+}
+
+func newCmdQueues() *cobra.Command {
+	return queuesCmd(configQueues{}, os.Stdout)
+}
+
+func queuesCmd(loader QueueLoader, out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:               "queues",
+		Short:             "List the swarm queues",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			queues, err := loader.Queues()
+			if err != nil {
+				return err
+			}
+			p := printer.NewTablePrinter(out, 20, 1, 3, ' ')
+			p.AddRow([]string{"NAME", "VARIABLES", "DESCRIPTION"})
+			for _, name := range queueNames(queues) {
+				var variables []string
+				for variable, value := range queues[name].Variables {
+					variables = append(variables, variable+"="+value)
+				}
+				slices.Sort(variables)
+				if len(variables) == 0 {
+					variables = []string{"-"}
+				}
+				p.AddRow([]string{name, strings.Join(variables, ","), queues[name].Description})
+			}
+			return p.Flush()
+		},
+	}
+}
@@ -0,0 +1,293 @@
+package swarm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/openshift/osdctl/cmd/swarm/mock"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/utils"
+	mocks "github.com/openshift/osdctl/pkg/utils/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var testNow = time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+func testIssue(key string, priority string, age time.Duration) jira.Issue {
+	return jira.Issue{Key: key, Fields: &jira.IssueFields{
+		Summary:  "Summary of " + key,
+		Priority: &jira.Priority{Name: priority},
+		Status:   &jira.Status{Name: "New"},
+		Created:  jira.Time(testNow.Add(-age)),
+	}}
+}
+
+func testQueues() map[string]osdctlConfig.SwarmQueue {
+	return mergeQueues(map[string]osdctlConfig.SwarmQueue{
+		"hcp": {
+			JQL:       `project = {{.project}} AND component = "{{.component}}"`,
+			Variables: map[string]string{"project": "OHSS", "component": "HyperShift"},
+			Columns:   []string{"key", "priority", "sla"},
+			Sort:      "priority DESC",
+			SLA:       map[string]string{"critical": "4h", "default": "24h"},
+		},
+	})
+}
+
+// newTestQueueOptions returns queue options with the test queues, a clock stopped at testNow which doesn't
+// wait, and a yes to the confirmations
+func newTestQueueOptions(t *testing.T, client utils.JiraClientInterface, out *bytes.Buffer) *queueOptions {
+	ctrl := gomock.NewController(t)
+	loader := mock.NewMockQueueLoader(ctrl)
+	loader.EXPECT().Queues().Return(testQueues(), nil).AnyTimes()
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(testNow).AnyTimes()
+	clock.EXPECT().After(gomock.Any()).DoAndReturn(func(time.Duration) <-chan time.Time {
+		c := make(chan time.Time, 1)
+		c <- testNow
+		return c
+	}).AnyTimes()
+	return &queueOptions{
+		interval:   time.Minute,
+		queues:     loader,
+		jiraClient: client,
+		clock:      clock,
+		IOStreams:  genericclioptions.IOStreams{In: strings.NewReader("y\n"), Out: out, ErrOut: out},
+	}
+}
+
+func TestQueueJQL(t *testing.T) {
+	q, err := newQueue("hcp", testQueues()["hcp"])
+	require.NoError(t, err)
+
+	jql, err := q.jql(nil)
+	require.NoError(t, err)
+	assert.Equal(t, `project = OHSS AND component = "HyperShift" ORDER BY priority DESC`, jql)
+
+	jql, err = q.jql(map[string]string{"Component": "ROSA"})
+	require.NoError(t, err)
+	assert.Equal(t, `project = OHSS AND component = "ROSA" ORDER BY priority DESC`, jql)
+
+	q.Variables = nil
+	_, err = q.jql(nil)
+	assert.ErrorContains(t, err, "set the missing variable with --var")
+
+	// The sort of the queue doesn't replace the one of the JQL
+	q.JQL = "project = OHSS order by created"
+	jql, err = q.jql(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "project = OHSS order by created", jql)
+}
+
+func TestNewQueue(t *testing.T) {
+	tests := []struct {
+		name    string
+		queue   osdctlConfig.SwarmQueue
+		wantErr string
+	}{
+		{name: "defaults", queue: osdctlConfig.SwarmQueue{JQL: "project = OHSS"}},
+		{name: "no jql", queue: osdctlConfig.SwarmQueue{}, wantErr: "queue q has no jql"},
+		{name: "invalid column", queue: osdctlConfig.SwarmQueue{JQL: "x", Columns: []string{"key", "team"}}, wantErr: `invalid column "team"`},
+		{name: "invalid sla", queue: osdctlConfig.SwarmQueue{JQL: "x", SLA: map[string]string{"major": "2d"}}, wantErr: `invalid sla "2d" of priority major`},
+		{name: "invalid format", queue: osdctlConfig.SwarmQueue{JQL: "x", Format: "markdown"}, wantErr: `invalid format "markdown" of queue q, expecting table or jira`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := newQueue("q", tt.queue)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, defaultColumns, q.Columns)
+		})
+	}
+}
+
+func TestMergeQueues(t *testing.T) {
+	queues := mergeQueues(map[string]osdctlConfig.SwarmQueue{
+		"secondary": {SLA: map[string]string{"default": "8h"}},
+	})
+	assert.Equal(t, buildJQL(), queues["secondary"].JQL)
+	assert.Equal(t, map[string]string{"default": "8h"}, queues["secondary"].SLA)
+
+	queues = mergeQueues(map[string]osdctlConfig.SwarmQueue{"secondary": {JQL: "project = OHSS"}})
+	assert.Equal(t, "project = OHSS", queues["secondary"].JQL)
+	assert.Equal(t, formatJira, queues["secondary"].Format)
+}
+
+func TestSLAState(t *testing.T) {
+	q, err := newQueue("hcp", testQueues()["hcp"])
+	require.NoError(t, err)
+
+	assert.Equal(t, slaOK, q.slaState(testIssue("A", "Critical", time.Hour), testNow))
+	assert.Equal(t, slaAtRisk, q.slaState(testIssue("A", "Critical", 3*time.Hour), testNow))
+	assert.Equal(t, slaBreached, q.slaState(testIssue("A", "Critical", 4*time.Hour), testNow))
+	// Priorities without their own SLA use the default one
+	assert.Equal(t, slaOK, q.slaState(testIssue("A", "Major", 4*time.Hour), testNow))
+	assert.Equal(t, slaBreached, q.slaState(testIssue("A", "Major", 25*time.Hour), testNow))
+
+	q.sla = map[string]time.Duration{}
+	assert.Equal(t, "", q.slaState(testIssue("A", "Critical", 4*time.Hour), testNow))
+}
+
+func TestFormatAge(t *testing.T) {
+	assert.Equal(t, "5m", formatAge(5*time.Minute))
+	assert.Equal(t, "2h30m", formatAge(150*time.Minute))
+	assert.Equal(t, "1d3h", formatAge(27*time.Hour))
+}
+
+func TestQueueRun(t *testing.T) {
+	t.Run("unknown queue", func(t *testing.T) {
+		o := newTestQueueOptions(t, nil, &bytes.Buffer{})
+		assert.EqualError(t, o.run(context.Background(), "tertiary"), `unknown queue "tertiary", known queues are hcp, secondary`)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		client.EXPECT().SearchIssues(`project = OHSS AND component = "HyperShift" ORDER BY priority DESC`).
+			Return([]jira.Issue{testIssue("OHSS-1", "Critical", 5*time.Hour), testIssue("OHSS-2", "Major", time.Hour)}, nil)
+
+		var out bytes.Buffer
+		require.NoError(t, newTestQueueOptions(t, client, &out).run(context.Background(), "hcp"))
+
+		assert.Contains(t, out.String(), "Queue: hcp (2 tickets)")
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Equal(t, []string{"KEY", "PRIORITY", "SLA"}, strings.Fields(lines[len(lines)-3]))
+		assert.Equal(t, []string{"OHSS-1", "Critical", "BREACHED"}, strings.Fields(lines[len(lines)-2]))
+		assert.Equal(t, []string{"OHSS-2", "Major", "OK"}, strings.Fields(lines[len(lines)-1]))
+	})
+
+	t.Run("secondary in jira markup", func(t *testing.T) {
+		issue := testIssue("OHSS-1", "Critical", 5*time.Hour)
+		issue.Fields.Type = jira.IssueType{Name: "Story"}
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		client.EXPECT().SearchIssues(buildJQL()).Return([]jira.Issue{issue}, nil)
+
+		var out bytes.Buffer
+		require.NoError(t, newTestQueueOptions(t, client, &out).run(context.Background(), "secondary"))
+		assert.Equal(t, "\nTimestamp:  "+testNow.String()+"\nTitle 🠒 :Swarm: Secondary. \n\n"+
+			">> OHSS Issues\n"+
+			"[OHSS-1|"+utils.JiraBaseURL+"/browse/OHSS-1](Story/Critical): Summary of OHSS-1\n"+
+			"- Created: 2026-01-10 07:00\tStatus: New\n", out.String())
+	})
+}
+
+func TestQueueAssignMe(t *testing.T) {
+	assigned := testIssue("OHSS-1", "Critical", time.Hour)
+	assigned.Fields.Assignee = &jira.User{DisplayName: "Someone"}
+	issues := []jira.Issue{assigned, testIssue("OHSS-2", "Major", time.Hour)}
+	me := &jira.User{Name: "me", DisplayName: "Me"}
+
+	t.Run("first unassigned ticket", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		client.EXPECT().SearchIssues(gomock.Any()).Return(issues, nil)
+		client.EXPECT().GetSelf().Return(me, nil)
+		client.EXPECT().AssignIssue("OHSS-2", me).Return(nil)
+
+		var out bytes.Buffer
+		o := newTestQueueOptions(t, client, &out)
+		o.assignMe = true
+		require.NoError(t, o.run(context.Background(), "hcp"))
+		assert.Contains(t, out.String(), "This assigns OHSS-2 to Me: Summary of OHSS-2")
+		assert.Contains(t, out.String(), "Assigned "+utils.JiraBaseURL+"/browse/OHSS-2 to Me")
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		client.EXPECT().SearchIssues(gomock.Any()).Return(issues, nil)
+		client.EXPECT().GetSelf().Return(me, nil)
+
+		o := newTestQueueOptions(t, client, &bytes.Buffer{})
+		o.assignMe, o.In = true, strings.NewReader("n\n")
+		require.NoError(t, o.run(context.Background(), "hcp"))
+	})
+
+	t.Run("nothing to assign", func(t *testing.T) {
+		client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+		client.EXPECT().SearchIssues(gomock.Any()).Return(issues[:1], nil)
+
+		o := newTestQueueOptions(t, client, &bytes.Buffer{})
+		o.assignMe = true
+		assert.EqualError(t, o.run(context.Background(), "hcp"), "the queue has no unassigned ticket")
+	})
+}
+
+func TestQueueWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+	gomock.InOrder(
+		client.EXPECT().SearchIssues(gomock.Any()).Return([]jira.Issue{testIssue("OHSS-1", "Major", time.Hour)}, nil),
+		client.EXPECT().SearchIssues(gomock.Any()).Return(nil, errors.New("timeout")),
+		client.EXPECT().SearchIssues(gomock.Any()).Return([]jira.Issue{testIssue("OHSS-1", "Major", time.Hour), testIssue("OHSS-3", "Critical", time.Minute)}, nil),
+		client.EXPECT().SearchIssues(gomock.Any()).DoAndReturn(func(string) ([]jira.Issue, error) {
+			cancel()
+			return []jira.Issue{testIssue("OHSS-1", "Major", time.Hour)}, nil
+		}),
+	)
+
+	var out bytes.Buffer
+	o := newTestQueueOptions(t, client, &out)
+	o.watch = true
+	require.NoError(t, o.run(ctx, "hcp"))
+
+	assert.Contains(t, out.String(), "Failed to refresh the queue: timeout")
+	assert.Contains(t, out.String(), "2026-01-10T12:00:00Z: 1 new ticket(s)")
+	assert.Equal(t, 1, strings.Count(out.String(), "new ticket(s)"))
+	assert.Equal(t, 1, strings.Count(out.String(), "OHSS-3"))
+
+	o.interval = time.Second
+	assert.ErrorContains(t, o.run(ctx, "hcp"), "--interval must be at least")
+}
+
+func TestQueueWatchJira(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	issue := func(key string) jira.Issue {
+		i := testIssue(key, "Major", time.Hour)
+		i.Fields.Type = jira.IssueType{Name: "Story"}
+		return i
+	}
+	client := mocks.NewMockJiraClientInterface(gomock.NewController(t))
+	gomock.InOrder(
+		client.EXPECT().SearchIssues(buildJQL()).Return([]jira.Issue{issue("OHSS-1")}, nil),
+		client.EXPECT().SearchIssues(buildJQL()).DoAndReturn(func(string) ([]jira.Issue, error) {
+			cancel()
+			return []jira.Issue{issue("OHSS-1"), issue("OHSS-2")}, nil
+		}),
+	)
+
+	var out bytes.Buffer
+	o := newTestQueueOptions(t, client, &out)
+	o.watch = true
+	require.NoError(t, o.run(ctx, "secondary"))
+
+	// The arrivals continue the list of issues, which has a single header
+	assert.Equal(t, 1, strings.Count(out.String(), ">> OHSS Issues"))
+	assert.Contains(t, out.String(), "1 new ticket(s)\n[OHSS-2|")
+}
+
+func TestQueuesCmd(t *testing.T) {
+	var out bytes.Buffer
+	loader := mock.NewMockQueueLoader(gomock.NewController(t))
+	loader.EXPECT().Queues().Return(testQueues(), nil)
+	cmd := queuesCmd(loader, &out)
+	cmd.SetArgs(nil)
+	require.NoError(t, cmd.Execute())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"hcp", "component=HyperShift,project=OHSS"}, strings.Fields(lines[1]))
+	assert.Equal(t, "secondary", strings.Fields(lines[2])[0])
+}
@@ -3,9 +3,7 @@ package swarm
 import (
 	"fmt"
 	"strings"
-	"time"
 
-	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -17,10 +15,12 @@ var (
 	products = []string{"\"Openshift Dedicated\"", "\"Openshift Online Pro\"", "\"OpenShift Online Starter\"", "\"Red Hat OpenShift Service on AWS\"", "\"HyperShift Preview\""}
 )
 
-var secondaryCmd = &cobra.Command{
-	Use:   "secondary",
-	Short: "List unassigned JIRA issues based on criteria",
-	Long: `Lists unassigned Jira issues from the 'OHSS' project
+func newCmdSecondary() *cobra.Command {
+	ops := newQueueOptions()
+	secondaryCmd := &cobra.Command{
+		Use:   "secondary",
+		Short: "List unassigned JIRA issues based on criteria",
+		Long: `Lists unassigned Jira issues from the 'OHSS' project
 		for the following Products
 		- OpenShift Dedicated
 		- Openshift Online Pro
@@ -30,34 +30,19 @@ var secondaryCmd = &cobra.Command{
 		- Empty 'Products' field in Jira
 		with the 'Summary' field  of the new ticket not matching the following
 		- Compliance Alert
-		and the 'Work Type' is not one of the RFE or Change Request `,
-	Example: `#Collect tickets for secondary swarm
-		osdctl swarm secondary`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-
-		jiraClient, err := utils.NewJiraClient("")
-		if err != nil {
-			return fmt.Errorf("failed to get Jira client: %w", err)
-		}
-
-		// Print Jira IDs
-		dt := time.Now()
-		fmt.Print("\n")
-		fmt.Println("Timestamp: ", dt.String())
-		fmt.Println("Title 🠒 :Swarm: Secondary. ")
-		fmt.Print("\n")
-		// Build JQL query
-		jql := buildJQL()
+		and the 'Work Type' is not one of the RFE or Change Request
 
-		// Search jira issues
-		issues, err := jiraClient.SearchIssues(jql)
-
-		if err != nil {
-			return fmt.Errorf("error fetching JIRA issues: %w", err)
-		}
-		utils.PrintJiraIssues(issues)
-		return nil
-	},
+		This is the built-in secondary queue of "osdctl swarm queue", which swarm_queues.secondary in the
+		osdctl config can change. It is printed in the Jira markup pasted in handovers unless its format is table`,
+		Example: `#Collect tickets for secondary swarm
+		osdctl swarm secondary`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ops.run(cmd.Context(), "secondary")
+		},
+	}
+	ops.addFlags(secondaryCmd)
+	return secondaryCmd
 }
 
 func buildJQL() string {
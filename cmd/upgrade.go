@@ -2,7 +2,17 @@ package cmd
 
 import (
 	"archive/tar"
+	"bufio"
+	"bytes"
 	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,55 +27,276 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var upgradeCmd = &cobra.Command{
-	Use:           "upgrade",
-	Short:         "Upgrade osdctl",
-	Long:          "Fetch latest osdctl from GitHub and replace the running binary",
-	RunE:          upgrade,
-	SilenceErrors: true,
+const (
+	stableChannel     = "stable"
+	prereleaseChannel = "prerelease"
+
+	// maxReleaseSize bounds downloads, release archives are around 30MB
+	maxReleaseSize = 256 << 20
+)
+
+type upgradeOptions struct {
+	version   string
+	channel   string
+	rollback  bool
+	publicKey string
+
+	client *http.Client
+	// assetTemplate is the download URL of a release asset, see utils.ReleaseAssetTemplate
+	assetTemplate string
+}
+
+func newCmdUpgrade() *cobra.Command {
+	ops := &upgradeOptions{
+		client:        &http.Client{Timeout: time.Second * 60},
+		assetTemplate: utils.ReleaseAssetTemplate,
+	}
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade osdctl",
+		Long: `Fetch osdctl from GitHub and replace the running binary.
+
+The release archive is verified against the release's sha256sum.txt before anything is replaced. With --public-key,
+the checksums file is also verified against its sha256sum.txt.sig signature, created with "cosign sign-blob".
+
+The previous binary is kept next to the running one and can be restored with --rollback.`,
+		Example: `
+  # Upgrade to the latest stable release
+  osdctl upgrade
+
+  # Install a specific release, also to downgrade
+  osdctl upgrade --version 0.40.0
+
+  # Upgrade to the latest release including release candidates
+  osdctl upgrade --channel prerelease
+
+  # Restore the binary replaced by the last upgrade
+  osdctl upgrade --rollback`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ops.run(cmd)
+		},
+		SilenceErrors: true,
+	}
+
+	upgradeCmd.Flags().StringVar(&ops.version, "version", "", "Install this release instead of the latest one, e.g. 0.40.0")
+	upgradeCmd.Flags().StringVar(&ops.channel, "channel", stableChannel, "Release channel to upgrade from: stable or prerelease")
+	upgradeCmd.Flags().BoolVar(&ops.rollback, "rollback", false, "Restore the binary replaced by the last upgrade")
+	upgradeCmd.Flags().StringVar(&ops.publicKey, "public-key", "", "PEM public key to verify the signature of the release checksums")
+	upgradeCmd.MarkFlagsMutuallyExclusive("rollback", "version")
+	upgradeCmd.MarkFlagsMutuallyExclusive("rollback", "channel")
+	upgradeCmd.MarkFlagsMutuallyExclusive("version", "channel")
+	_ = upgradeCmd.RegisterFlagCompletionFunc("channel", cobra.FixedCompletions([]string{stableChannel, prereleaseChannel}, cobra.ShellCompDirectiveNoFileComp))
+
+	return upgradeCmd
 }
 
-func upgrade(cmd *cobra.Command, args []string) error {
+func (o *upgradeOptions) run(cmd *cobra.Command) error {
 	// rootName ensures that the upgrade will fail if we ever decide to rename osdctl
 	// between releases :-)
 	rootName := cmd.Root().Name()
 
-	latest, err := utils.GetLatestVersion()
+	exe, err := executablePath()
 	if err != nil {
 		return err
 	}
-	latestWithoutPrefix := strings.TrimPrefix(latest, "v")
-	currentSemVer := semver.New(utils.Version)
-	latestSemVer := semver.New(latestWithoutPrefix)
-	if !currentSemVer.LessThan(*latestSemVer) {
-		fmt.Println("Already up to date, nothing to do!")
+
+	if o.rollback {
+		if err := rollbackBinary(exe); err != nil {
+			return err
+		}
+		fmt.Printf("Restored the previous %s binary, run '%s upgrade --rollback' again to undo\n", rootName, rootName)
 		return nil
 	}
-	// upgrade necessary
-	client := http.Client{
-		Timeout: time.Second * 60,
+
+	target, err := o.targetVersion()
+	if err != nil {
+		return err
+	}
+	upgrade, err := o.needsUpgrade(target)
+	if err != nil {
+		return err
+	}
+	if !upgrade {
+		fmt.Println("Already up to date, nothing to do!")
+		return nil
 	}
 
-	addr := fmt.Sprintf(utils.VersionAddressTemplate,
-		latestWithoutPrefix,
-		latestWithoutPrefix,
-		parseGOOS(runtime.GOOS),
-		parseGOARCH(runtime.GOARCH))
+	archiveName := fmt.Sprintf("%s_%s_%s_%s.tar.gz", rootName, target, parseGOOS(runtime.GOOS), parseGOARCH(runtime.GOARCH))
+	archive, err := o.download(target, archiveName)
+	if err != nil {
+		return err
+	}
+	if err := o.verify(target, archiveName, archive); err != nil {
+		return err
+	}
 
-	req, err := http.NewRequest(http.MethodGet, addr, nil)
+	binary, err := extractBinary(archive, rootName)
 	if err != nil {
 		return err
 	}
+	if err := installBinary(exe, binary); err != nil {
+		return err
+	}
 
-	res, err := client.Do(req)
+	fmt.Printf("Upgraded %s from %s to %s, run '%s upgrade --rollback' to restore %s\n", rootName, utils.Version, target, rootName, utils.Version)
+	return nil
+}
+
+// targetVersion returns the version to install, without the v prefix
+func (o *upgradeOptions) targetVersion() (string, error) {
+	if o.version != "" {
+		version := strings.TrimPrefix(o.version, "v")
+		if _, err := semver.NewVersion(version); err != nil {
+			return "", fmt.Errorf("invalid version %q: %w", o.version, err)
+		}
+		return version, nil
+	}
+
+	var latest string
+	var err error
+	switch o.channel {
+	case stableChannel:
+		latest, err = utils.GetLatestVersion()
+	case prereleaseChannel:
+		latest, err = utils.GetLatestPrerelease()
+	default:
+		return "", fmt.Errorf("invalid channel %q, valid channels are %s and %s", o.channel, stableChannel, prereleaseChannel)
+	}
+	if err != nil {
+		return "", err
+	}
+	if latest == "" {
+		return "", errors.New("failed to find the latest osdctl release")
+	}
+	return strings.TrimPrefix(latest, "v"), nil
+}
+
+// needsUpgrade returns whether the target version should be installed. A pinned version is
+// installed unless it is already running, to allow downgrades.
+func (o *upgradeOptions) needsUpgrade(target string) (bool, error) {
+	if o.version != "" {
+		return target != utils.Version, nil
+	}
+	current, err := semver.NewVersion(utils.Version)
+	if err != nil {
+		// Development builds have no version
+		return true, nil
+	}
+	targetVersion, err := semver.NewVersion(target)
+	if err != nil {
+		return false, fmt.Errorf("invalid release version %q: %v", target, err)
+	}
+	return current.LessThan(*targetVersion), nil
+}
+
+func (o *upgradeOptions) download(version, asset string) ([]byte, error) {
+	addr := fmt.Sprintf(o.assetTemplate, version, asset)
+	res, err := o.client.Get(addr)
 	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", addr, res.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxReleaseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", addr, err)
+	}
+	if len(body) > maxReleaseSize {
+		return nil, fmt.Errorf("failed to download %s: larger than %d bytes", addr, maxReleaseSize)
+	}
+	return body, nil
+}
+
+// verify checks the archive against the checksums file of the release, and the checksums
+// file against its signature when a public key is given
+func (o *upgradeOptions) verify(version, archiveName string, archive []byte) error {
+	checksums, err := o.download(version, utils.ChecksumsAssetName)
+	if err != nil {
+		return err
+	}
+
+	if o.publicKey != "" {
+		key, err := os.ReadFile(o.publicKey)
+		if err != nil {
+			return err
+		}
+		signature, err := o.download(version, utils.ChecksumsAssetName+".sig")
+		if err != nil {
+			return err
+		}
+		if err := verifySignature(key, checksums, signature); err != nil {
+			return fmt.Errorf("failed to verify %s: %w", utils.ChecksumsAssetName, err)
+		}
+	}
+
+	return verifyChecksum(checksums, archiveName, archive)
+}
+
+// verifyChecksum checks data against its entry in a goreleaser checksums file
+func verifyChecksum(checksums []byte, name string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[1] != name {
+			continue
+		}
+		expected, err := hex.DecodeString(fields[0])
+		if err != nil {
+			return fmt.Errorf("invalid checksum of %s: %w", name, err)
+		}
+		actual := sha256.Sum256(data)
+		if !bytes.Equal(expected, actual[:]) {
+			return fmt.Errorf("checksum mismatch for %s: expected %x, got %x", name, expected, actual)
+		}
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
 		return err
 	}
+	return fmt.Errorf("no checksum found for %s", name)
+}
 
-	gzf, err := gzip.NewReader(res.Body)
+// verifySignature checks a base64 signature created by "cosign sign-blob" with an ECDSA or ed25519 key
+func verifySignature(publicKeyPEM, data, signature []byte) error {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return errors.New("no PEM public key found")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
 	if err != nil {
 		return err
 	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(key, digest[:], sig) {
+			return errors.New("invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, sig) {
+			return errors.New("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+	return nil
+}
+
+// extractBinary returns the file called name from a tar.gz archive
+func extractBinary(archive []byte, name string) ([]byte, error) {
+	gzf, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
 
 	tr := tar.NewReader(gzf)
 	for {
@@ -74,59 +305,116 @@ func upgrade(cmd *cobra.Command, args []string) error {
 			break
 		}
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if f.Name != rootName {
+		if f.Name != name {
 			continue
 		}
-		// For replacing a running executable we have to use the syscall "rename".
-		// "rename" can only be called on executables (old/new destination/name)
-		// that are stored on the same filesystem. This is the reason, why we cannot
-		// use a directory on ramfs here (f.e. /tmp/). Instead, we are creating a
-		// temp dir in the user's $HOME.
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return err
-		}
+		return io.ReadAll(io.LimitReader(tr, maxReleaseSize)) //#nosec G110 -- the archive checksum is verified, so decompression bomb is unlikely
+	}
+	return nil, fmt.Errorf("%s not found in the release archive", name)
+}
 
-		dir, err := os.MkdirTemp(homeDir, ".*")
-		if err != nil {
-			return err
-		}
+// executablePath returns the path of the running binary, resolving symlinks so they are kept
+func executablePath() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
 
-		defer func(path string) {
-			err := os.RemoveAll(path)
-			if err != nil {
-				fmt.Println("Error removing directory ", path)
-			}
-		}(dir)
+// backupPath returns where the binary replaced by an upgrade is kept
+func backupPath(exe string) string {
+	return filepath.Join(filepath.Dir(exe), "."+filepath.Base(exe)+".previous")
+}
 
-		tmpFilePath := filepath.Join(dir, rootName)
+// installBinary replaces exe with binary, keeping a backup of exe. The binary is written
+// next to exe so it can be renamed over it: "rename" replaces a running executable
+// atomically, but only within the same filesystem, so exe is never left half-written.
+func installBinary(exe string, binary []byte) error {
+	tmpFilePath, err := writeTempBinary(filepath.Dir(exe), filepath.Base(exe), bytes.NewReader(binary))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFilePath)
 
-		tmpFile, err := os.OpenFile(tmpFilePath, os.O_CREATE|os.O_RDWR, 0700) //#nosec G304|G302 -- tmpFilePath cannot be constant
-		if err != nil {
-			return err
-		}
+	if err := copyBinary(exe, backupPath(exe)); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", exe, err)
+	}
+	return os.Rename(tmpFilePath, exe)
+}
 
-		_, err = io.Copy(tmpFile, tr) //#nosec G110 -- source is trusted, so decompression bomb is unlikely
-		if err != nil {
-			return err
+// rollbackBinary swaps exe with its backup, so a second rollback restores the upgrade
+func rollbackBinary(exe string) error {
+	backup := backupPath(exe)
+	if _, err := os.Stat(backup); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no previous binary found at %s", backup)
 		}
+		return err
+	}
 
-		// get path of current executable
-		exe, err := os.Executable()
-		if err != nil {
-			return err
-		}
+	current, err := os.Open(exe)
+	if err != nil {
+		return err
+	}
+	tmpFilePath, err := writeTempBinary(filepath.Dir(exe), filepath.Base(exe), current)
+	current.Close()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFilePath)
 
-		err = os.Rename(tmpFilePath, filepath.Join(filepath.Dir(exe), rootName))
-		if err != nil {
-			return err
-		}
+	if err := os.Rename(backup, exe); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilePath, backup)
+}
+
+func copyBinary(src, dst string) error {
+	in, err := os.Open(src) //#nosec G304 -- src is the running executable
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmpFilePath, err := writeTempBinary(filepath.Dir(dst), filepath.Base(dst), in)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFilePath, dst); err != nil {
+		os.Remove(tmpFilePath)
+		return err
 	}
 	return nil
 }
 
+// writeTempBinary writes an executable file in dir and returns its path
+func writeTempBinary(dir, name string, content io.Reader) (string, error) {
+	tmpFile, err := os.CreateTemp(dir, "."+name+"-*")
+	if err != nil {
+		return "", err
+	}
+	tmpFilePath := tmpFile.Name()
+
+	_, err = io.Copy(tmpFile, content)
+	if err == nil {
+		err = tmpFile.Sync()
+	}
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpFilePath, 0755) //#nosec G302 -- the file is an executable
+	}
+	if err != nil {
+		os.Remove(tmpFilePath)
+		return "", err
+	}
+	return tmpFilePath, nil
+}
+
 func parseGOOS(goos string) string {
 	switch goos {
 	case "linux":
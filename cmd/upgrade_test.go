@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func releaseArchive(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return buf.Bytes()
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	archive := []byte("archive")
+	checksums := []byte(fmt.Sprintf("%x  osdctl_0.40.0_Darwin_arm64.tar.gz\n%x  osdctl_0.40.0_Linux_x86_64.tar.gz\n",
+		sha256.Sum256([]byte("other")), sha256.Sum256(archive)))
+
+	assert.NoError(t, verifyChecksum(checksums, "osdctl_0.40.0_Linux_x86_64.tar.gz", archive))
+	assert.ErrorContains(t, verifyChecksum(checksums, "osdctl_0.40.0_Darwin_arm64.tar.gz", archive), "checksum mismatch for osdctl_0.40.0_Darwin_arm64.tar.gz")
+	assert.EqualError(t, verifyChecksum(checksums, "osdctl_0.40.0_Linux_arm64.tar.gz", archive), "no checksum found for osdctl_0.40.0_Linux_arm64.tar.gz")
+}
+
+func TestVerifySignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	checksums := []byte("abc  osdctl.tar.gz\n")
+	digest := sha256.Sum256(checksums)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	signature := []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+
+	assert.NoError(t, verifySignature(publicKey, checksums, signature))
+	assert.EqualError(t, verifySignature(publicKey, []byte("tampered"), signature), "invalid signature")
+	assert.EqualError(t, verifySignature([]byte("not a key"), checksums, signature), "no PEM public key found")
+}
+
+func TestExtractBinary(t *testing.T) {
+	archive := releaseArchive(t, map[string]string{"README.md": "readme", "osdctl": "binary"})
+
+	binary, err := extractBinary(archive, "osdctl")
+	require.NoError(t, err)
+	assert.Equal(t, "binary", string(binary))
+
+	_, err = extractBinary(archive, "ocm")
+	assert.EqualError(t, err, "ocm not found in the release archive")
+}
+
+func TestInstallAndRollbackBinary(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "osdctl")
+	require.NoError(t, os.WriteFile(exe, []byte("old"), 0755))
+
+	assert.ErrorContains(t, rollbackBinary(exe), "no previous binary found")
+
+	require.NoError(t, installBinary(exe, []byte("new")))
+	assertFile(t, exe, "new")
+	assertFile(t, backupPath(exe), "old")
+
+	info, err := os.Stat(exe)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	require.NoError(t, rollbackBinary(exe))
+	assertFile(t, exe, "old")
+	assertFile(t, backupPath(exe), "new")
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(exe))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func assertFile(t *testing.T, path, expected string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+}
+
+func TestUpgradeVerify(t *testing.T) {
+	archive := releaseArchive(t, map[string]string{"osdctl": "binary"})
+	archiveName := "osdctl_0.40.0_Linux_x86_64.tar.gz"
+	assets := map[string][]byte{
+		"/v0.40.0/" + archiveName:              archive,
+		"/v0.40.0/" + utils.ChecksumsAssetName: []byte(fmt.Sprintf("%x  %s\n", sha256.Sum256(archive), archiveName)),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asset, ok := assets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(asset)
+	}))
+	defer server.Close()
+
+	o := &upgradeOptions{client: server.Client(), assetTemplate: server.URL + "/v%s/%s"}
+
+	downloaded, err := o.download("0.40.0", archiveName)
+	require.NoError(t, err)
+	assert.NoError(t, o.verify("0.40.0", archiveName, downloaded))
+
+	// A truncated download is rejected
+	assert.ErrorContains(t, o.verify("0.40.0", archiveName, downloaded[:10]), "checksum mismatch")
+
+	_, err = o.download("0.41.0", archiveName)
+	assert.ErrorContains(t, err, "404 Not Found")
+
+	// Signatures are required when a public key is given
+	o.publicKey = filepath.Join(t.TempDir(), "cosign.pub")
+	require.NoError(t, os.WriteFile(o.publicKey, []byte("key"), 0600))
+	assert.ErrorContains(t, o.verify("0.40.0", archiveName, downloaded), "sha256sum.txt.sig: 404 Not Found")
+}
+
+func TestNeedsUpgrade(t *testing.T) {
+	current := utils.Version
+	defer func() { utils.Version = current }()
+	utils.Version = "0.40.0"
+
+	needsUpgrade := func(o *upgradeOptions, target string) bool {
+		upgrade, err := o.needsUpgrade(target)
+		require.NoError(t, err)
+		return upgrade
+	}
+
+	assert.True(t, needsUpgrade(&upgradeOptions{}, "0.41.0"))
+	assert.True(t, needsUpgrade(&upgradeOptions{}, "0.41.0-rc.1"))
+	assert.False(t, needsUpgrade(&upgradeOptions{}, "0.40.0"))
+	assert.False(t, needsUpgrade(&upgradeOptions{}, "0.39.0"))
+
+	// Pinned versions allow downgrades
+	assert.True(t, needsUpgrade(&upgradeOptions{version: "0.39.0"}, "0.39.0"))
+	assert.False(t, needsUpgrade(&upgradeOptions{version: "0.40.0"}, "0.40.0"))
+
+	// Malformed release tags are reported instead of panicking
+	_, err := (&upgradeOptions{}).needsUpgrade("0.41")
+	assert.ErrorContains(t, err, `invalid release version "0.41"`)
+
+	utils.Version = ""
+	assert.True(t, needsUpgrade(&upgradeOptions{}, "0.41.0"))
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// defaultVersionCacheTTL bounds how often GitHub is asked for the latest release, to stay below
+// its rate limits when osdctl runs in loops
+const defaultVersionCacheTTL = 12 * time.Hour
+
+// errVersionCheckDeclined is returned when the user chose not to continue with an outdated osdctl
+var errVersionCheckDeclined = errors.New("declined to continue with an outdated osdctl")
+
+type versionChecker struct {
+	current string
+	policy  string
+	minimum string
+	blocked []string
+	// interactive is set when stdin is a terminal, prompts are skipped otherwise so scripts don't stall
+	interactive bool
+
+	latest  func() (string, error)
+	confirm func() bool
+	out     io.Writer
+}
+
+func newVersionChecker() *versionChecker {
+	policy := viper.GetString(osdctlConfig.VersionCheckPolicyKey)
+	if policy == "" {
+		policy = osdctlConfig.VersionCheckPrompt
+	}
+	if _, err := osdctlConfig.ValidateVersionCheckPolicy(policy); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "WARN: %v, using %s\n", err, osdctlConfig.VersionCheckPrompt)
+		policy = osdctlConfig.VersionCheckPrompt
+	}
+
+	ttl := defaultVersionCacheTTL
+	if value := viper.GetString(osdctlConfig.VersionCheckCacheTTLKey); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "WARN: invalid %s %q, using %s\n", osdctlConfig.VersionCheckCacheTTLKey, value, defaultVersionCacheTTL)
+		} else {
+			ttl = parsed
+		}
+	}
+
+	return &versionChecker{
+		current:     utils.Version,
+		policy:      policy,
+		minimum:     viper.GetString(osdctlConfig.VersionCheckMinimumKey),
+		blocked:     viper.GetStringSlice(osdctlConfig.VersionCheckBlockedKey),
+		interactive: term.IsTerminal(int(os.Stdin.Fd())),
+		latest: func() (string, error) {
+			return utils.GetLatestVersionCached(ttl)
+		},
+		confirm: utils.ConfirmPrompt,
+		out:     os.Stderr,
+	}
+}
+
+func versionCheck() {
+	err := newVersionChecker().check()
+	if errors.Is(err, errVersionCheckDeclined) {
+		os.Exit(0)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// check warns when osdctl is outdated, prompting to continue or failing depending on the policy
+func (c *versionChecker) check() error {
+	if problem := c.disallowedVersion(); problem != "" {
+		if c.policy == osdctlConfig.VersionCheckEnforceMinimum {
+			return fmt.Errorf("%s, run 'osdctl upgrade' to install the latest version", problem)
+		}
+		_, _ = fmt.Fprintf(c.out, "WARN: %s, it is recommended that you run 'osdctl upgrade'.\n", problem)
+	}
+
+	latestVersion, err := c.latest()
+	if err != nil {
+		if latestVersion == "" {
+			_, _ = fmt.Fprintln(c.out, "WARN: Unable to verify that osdctl is running under the latest released version. Error trying to reach GitHub:")
+			_, _ = fmt.Fprintln(c.out, err)
+			_, _ = fmt.Fprintln(c.out, "Please be aware that you are possibly running an outdated or unreleased version.")
+			return nil
+		}
+		_, _ = fmt.Fprintf(c.out, "WARN: Unable to reach GitHub, using the last known latest version (%s): %v\n", latestVersion, err)
+	}
+
+	if !isOutdated(c.current, latestVersion) {
+		return nil
+	}
+	_, _ = fmt.Fprintf(c.out, "WARN: The current version (%s) is different than the latest released version (%s). It is recommended that you update to the latest released version to ensure that no known bugs or issues are hit.\n", c.current, latestVersion)
+
+	if c.policy == osdctlConfig.VersionCheckPrompt && c.interactive && !c.confirm() {
+		return errVersionCheckDeclined
+	}
+	return nil
+}
+
+// disallowedVersion describes why the current version is below the minimum or blocked, if it is
+func (c *versionChecker) disallowedVersion() string {
+	current, err := semver.NewVersion(c.current)
+	if err != nil {
+		// Development builds have no version
+		return ""
+	}
+
+	for _, blocked := range c.blocked {
+		if strings.TrimPrefix(strings.TrimSpace(blocked), "v") == c.current {
+			return fmt.Sprintf("osdctl %s is a known-bad release", c.current)
+		}
+	}
+
+	if c.minimum == "" {
+		return ""
+	}
+	minimum, err := semver.NewVersion(strings.TrimPrefix(c.minimum, "v"))
+	if err != nil {
+		_, _ = fmt.Fprintf(c.out, "WARN: invalid %s %q\n", osdctlConfig.VersionCheckMinimumKey, c.minimum)
+		return ""
+	}
+	if current.LessThan(*minimum) {
+		return fmt.Sprintf("osdctl %s is older than the minimum version %s", c.current, minimum)
+	}
+	return ""
+}
+
+// isOutdated returns whether current is older than latest. Versions which aren't semantic
+// versions, like development builds, are outdated unless they are equal.
+func isOutdated(current, latest string) bool {
+	latest = strings.TrimPrefix(latest, "v")
+	currentSemVer, err := semver.NewVersion(current)
+	if err != nil {
+		return current != latest
+	}
+	latestSemVer, err := semver.NewVersion(latest)
+	if err != nil {
+		return current != latest
+	}
+	return currentSemVer.LessThan(*latestSemVer)
+}
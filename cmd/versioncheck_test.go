@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		checker     versionChecker
+		latest      string
+		latestErr   error
+		confirm     bool
+		expectedErr error
+		errorMsg    string
+		prompted    bool
+		output      string
+	}{
+		{
+			name:    "up_to_date",
+			checker: versionChecker{current: "0.40.0", policy: osdctlConfig.VersionCheckPrompt, interactive: true},
+			latest:  "v0.40.0",
+		},
+		{
+			name:     "outdated_prompt_continue",
+			checker:  versionChecker{current: "0.39.0", policy: osdctlConfig.VersionCheckPrompt, interactive: true},
+			latest:   "v0.40.0",
+			confirm:  true,
+			prompted: true,
+			output:   "WARN: The current version (0.39.0) is different than the latest released version (v0.40.0)",
+		},
+		{
+			name:        "outdated_prompt_declined",
+			checker:     versionChecker{current: "0.39.0", policy: osdctlConfig.VersionCheckPrompt, interactive: true},
+			latest:      "v0.40.0",
+			prompted:    true,
+			expectedErr: errVersionCheckDeclined,
+			output:      "WARN: The current version (0.39.0)",
+		},
+		{
+			name:    "outdated_prompt_without_terminal",
+			checker: versionChecker{current: "0.39.0", policy: osdctlConfig.VersionCheckPrompt},
+			latest:  "v0.40.0",
+			output:  "WARN: The current version (0.39.0)",
+		},
+		{
+			name:    "outdated_warn",
+			checker: versionChecker{current: "0.39.0", policy: osdctlConfig.VersionCheckWarn, interactive: true},
+			latest:  "v0.40.0",
+			output:  "WARN: The current version (0.39.0)",
+		},
+		{
+			name:    "newer_than_latest",
+			checker: versionChecker{current: "0.41.0-rc.1", policy: osdctlConfig.VersionCheckPrompt, interactive: true},
+			latest:  "v0.40.0",
+		},
+		{
+			name:      "github_unreachable",
+			checker:   versionChecker{current: "0.39.0", policy: osdctlConfig.VersionCheckPrompt, interactive: true},
+			latestErr: errors.New("no route to host"),
+			output:    "WARN: Unable to verify that osdctl is running under the latest released version",
+		},
+		{
+			name:      "github_unreachable_cached",
+			checker:   versionChecker{current: "0.39.0", policy: osdctlConfig.VersionCheckWarn},
+			latest:    "v0.40.0",
+			latestErr: errors.New("no route to host"),
+			output:    "WARN: Unable to reach GitHub, using the last known latest version (v0.40.0): no route to host",
+		},
+		{
+			name:     "below_minimum_enforced",
+			checker:  versionChecker{current: "0.39.0", policy: osdctlConfig.VersionCheckEnforceMinimum, minimum: "v0.39.5"},
+			latest:   "v0.40.0",
+			errorMsg: "osdctl 0.39.0 is older than the minimum version 0.39.5, run 'osdctl upgrade' to install the latest version",
+		},
+		{
+			name:     "blocked_enforced",
+			checker:  versionChecker{current: "0.39.0", policy: osdctlConfig.VersionCheckEnforceMinimum, blocked: []string{"v0.39.0"}},
+			latest:   "v0.39.0",
+			errorMsg: "osdctl 0.39.0 is a known-bad release, run 'osdctl upgrade' to install the latest version",
+		},
+		{
+			name:    "above_minimum_enforced",
+			checker: versionChecker{current: "0.39.0", policy: osdctlConfig.VersionCheckEnforceMinimum, minimum: "0.38.0", interactive: true},
+			latest:  "v0.40.0",
+			output:  "WARN: The current version (0.39.0)",
+		},
+		{
+			name:    "below_minimum_warned",
+			checker: versionChecker{current: "0.39.0", policy: osdctlConfig.VersionCheckWarn, minimum: "0.40.0"},
+			latest:  "v0.40.0",
+			output:  "WARN: osdctl 0.39.0 is older than the minimum version 0.40.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			prompted := false
+			c := tt.checker
+			c.out = out
+			c.latest = func() (string, error) { return tt.latest, tt.latestErr }
+			c.confirm = func() bool {
+				prompted = true
+				return tt.confirm
+			}
+
+			err := c.check()
+			switch {
+			case tt.expectedErr != nil:
+				assert.ErrorIs(t, err, tt.expectedErr)
+			case tt.errorMsg != "":
+				assert.EqualError(t, err, tt.errorMsg)
+			default:
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.prompted, prompted)
+			if tt.output == "" {
+				assert.Empty(t, out.String())
+			} else {
+				assert.Contains(t, out.String(), tt.output)
+			}
+		})
+	}
+}
+
+func TestIsOutdated(t *testing.T) {
+	assert.True(t, isOutdated("0.39.0", "v0.40.0"))
+	assert.False(t, isOutdated("0.40.0", "v0.40.0"))
+	assert.False(t, isOutdated("0.41.0", "v0.40.0"))
+	assert.True(t, isOutdated("", "v0.40.0"))
+	assert.False(t, isOutdated("dev", "dev"))
+}
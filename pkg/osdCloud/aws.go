@@ -53,6 +53,29 @@ func GenerateOrganizationAccountAccessCredentials(client aws.Client, accountId,
 
 }
 
+// GenerateOrganizationAccountAWSClient returns a client of a linked account of the organization of the provided client,
+// through its OrganizationAccountAccessRole
+func GenerateOrganizationAccountAWSClient(client aws.Client, accountId, region string) (aws.Client, error) {
+	partition, err := aws.GetAwsPartition(client)
+	if err != nil {
+		return nil, fmt.Errorf("could not get AWS partition: %w", err)
+	}
+	sessionName, err := GenerateRoleSessionName(client)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate Session Name: %w", err)
+	}
+	creds, err := GenerateOrganizationAccountAccessCredentials(client, accountId, sessionName, partition)
+	if err != nil {
+		return nil, fmt.Errorf("could not assume OrganizationAccountAccessRole: %w", err)
+	}
+	return aws.NewAwsClientWithInput(&aws.ClientInput{
+		AccessKeyID:     *creds.AccessKeyId,
+		SecretAccessKey: *creds.SecretAccessKey,
+		SessionToken:    *creds.SessionToken,
+		Region:          region,
+	})
+}
+
 // GenerateSupportRoleCredentials Uses the provided IAM Client to perform the Assume Role chain needed to get to a cluster's Support Role
 func GenerateSupportRoleCredentials(client aws.Client, region, sessionName, targetRole string) (*stsTypes.Credentials, error) {
 
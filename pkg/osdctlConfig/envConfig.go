@@ -0,0 +1,73 @@
+package osdctlConfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// EnvConfigFileName is the file in the home directory holding the login scripts and profiles of osdctl env
+	EnvConfigFileName = ".osdctl.yaml"
+)
+
+// EnvConfig is the content of the osdctl env config file
+type EnvConfig struct {
+	LoginScripts map[string]string     `yaml:"loginScripts"`
+	Profiles     map[string]EnvProfile `yaml:"profiles"`
+}
+
+// EnvProfile is a named set of defaults for environments created by "osdctl env --profile"
+type EnvProfile struct {
+	// OcmEnv is the OCM environment alias: production, staging or integration
+	OcmEnv       string `yaml:"ocmEnv"`
+	BackplaneURL string `yaml:"backplaneUrl"`
+	AWSProfile   string `yaml:"awsProfile"`
+	// ElevationReason is used by the oce command to run commands with elevated privileges
+	ElevationReason string `yaml:"elevationReason"`
+	// LoginScript overrides loginScripts for environments using this profile
+	LoginScript string `yaml:"loginScript"`
+	// Hooks are sourced by bash and zsh when entering the environment, FishHooks by fish
+	Hooks     []string `yaml:"hooks"`
+	FishHooks []string `yaml:"fishHooks"`
+}
+
+// EnvConfigFilePath returns the path of the osdctl env config file, ~/.osdctl.yaml
+func EnvConfigFilePath() (string, error) {
+	homePath, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homePath, EnvConfigFileName), nil
+}
+
+// ReadEnvConfig reads the osdctl env config file, which is optional. Keys are read as is rather than through
+// viper, which would lowercase the profile names.
+func ReadEnvConfig() (EnvConfig, error) {
+	config := EnvConfig{
+		LoginScripts: map[string]string{},
+		Profiles:     map[string]EnvProfile{},
+	}
+
+	configFilePath, err := EnvConfigFilePath()
+	if err != nil {
+		return config, err
+	}
+
+	data, err := os.ReadFile(configFilePath) //#nosec G304 -- the path is derived from the home directory
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("failed to read config yaml %s: %v", configFilePath, err)
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to unmarshal config yaml %s: %v", configFilePath, err)
+	}
+
+	return config, nil
+}
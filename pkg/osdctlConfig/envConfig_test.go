@@ -0,0 +1,38 @@
+package osdctlConfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadEnvConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config, err := ReadEnvConfig()
+	require.NoError(t, err)
+	assert.Empty(t, config.Profiles)
+	assert.NotNil(t, config.Profiles)
+
+	content := `loginScripts:
+  https://api.openshift.com: ocm login --use-auth-code
+profiles:
+  Stage:
+    ocmEnv: staging
+    awsProfile: osd-staging
+`
+	require.NoError(t, os.WriteFile(filepath.Join(home, EnvConfigFileName), []byte(content), 0600))
+
+	config, err = ReadEnvConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "ocm login --use-auth-code", config.LoginScripts["https://api.openshift.com"])
+	assert.Equal(t, EnvProfile{OcmEnv: "staging", AWSProfile: "osd-staging"}, config.Profiles["Stage"])
+
+	require.NoError(t, os.WriteFile(filepath.Join(home, EnvConfigFileName), []byte("profiles: ["), 0600))
+	_, err = ReadEnvConfig()
+	assert.ErrorContains(t, err, "failed to unmarshal config yaml")
+}
@@ -3,6 +3,7 @@ package osdctlConfig
 import (
 	"errors"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/viper"
 )
@@ -11,13 +12,21 @@ const (
 	ConfigFileName = "osdctl"
 )
 
-func EnsureConfigFile() error {
+// ConfigFilePath returns the path of the osdctl config file, ~/.config/osdctl
+func ConfigFilePath() (string, error) {
 	configHomePath, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configHomePath, ".config", ConfigFileName), nil
+}
+
+func EnsureConfigFile() error {
+	configFilePath, err := ConfigFilePath()
 	if err != nil {
 		return err
 	}
-	configFileDir := configHomePath + "/.config/"
-	configFilePath := configFileDir + ConfigFileName
+	configFileDir := filepath.Dir(configFilePath)
 	if _, err := os.Stat(configFilePath); errors.Is(err, os.ErrNotExist) {
 		err = os.MkdirAll(configFileDir, 0750)
 		if err != nil {
@@ -38,15 +47,14 @@ func EnsureConfigFile() error {
 	return nil
 }
 
-// GetConfigValues reads the osdctl config file using a dedicated viper instance,
+// ReadConfig reads the osdctl config file using a dedicated viper instance,
 // avoiding the global viper which backplane-cli overwrites concurrently.
 // TODO: Remove this workaround once backplane-cli stops overwriting the global viper instance.
-func GetConfigValues(keys ...string) (map[string]string, error) {
-	configHomePath, err := os.UserHomeDir()
+func ReadConfig() (*viper.Viper, error) {
+	configFilePath, err := ConfigFilePath()
 	if err != nil {
 		return nil, err
 	}
-	configFilePath := configHomePath + "/.config/" + ConfigFileName
 
 	v := viper.New()
 	v.SetConfigFile(configFilePath)
@@ -54,6 +62,15 @@ func GetConfigValues(keys ...string) (map[string]string, error) {
 	if err := v.ReadInConfig(); err != nil {
 		return nil, err
 	}
+	return v, nil
+}
+
+// GetConfigValues reads the given keys from the osdctl config file, see ReadConfig
+func GetConfigValues(keys ...string) (map[string]string, error) {
+	v, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
 
 	values := make(map[string]string, len(keys))
 	for _, k := range keys {
@@ -0,0 +1,391 @@
+package osdctlConfig
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// Keys of the osdctl config file
+const (
+	ProdJumproleAccountIDKey  = "prod_jumprole_account_id"
+	StageJumproleAccountIDKey = "stage_jumprole_account_id"
+	AWSProxyKey               = "aws_proxy"
+	VaultAddressKey           = "vault_address"
+	DtVaultPathKey            = "dt_vault_path"
+	DtDocumentVaultPathKey    = "dt_document_vault_path"
+	PdUserTokenKey            = "pd_user_token"  // #nosec G101
+	PdOauthTokenKey           = "pd_oauth_token" // #nosec G101
+	PdTeamIDsKey              = "team_ids"
+	JiraTokenKey              = "jira_token" // #nosec G101
+	JiraEmailKey              = "jira_email"
+	JiraTeamKey               = "jira_team"
+	JiraTeamLabelKey          = "jira_team_label"
+	JiraBoardIDKey            = "jira_board_id"
+	JiraProductsKey           = "products"
+	GitLabTokenKey            = "gitlab_access" // #nosec G101
+	CloudTrailCmdListsKey     = "cloudtrail_cmd_lists"
+	CADGrafanaURLKey          = "cad_grafana_url"
+	CADAWSAccountIDKey        = "cad_aws_account_id"
+	HiveOCMURLKey             = "hive_ocm_url"
+	VersionCheckPolicyKey     = "version_check_policy"
+	VersionCheckCacheTTLKey   = "version_check_cache_ttl"
+	VersionCheckMinimumKey    = "version_check_minimum"
+	VersionCheckBlockedKey    = "version_check_blocked"
+	SwarmQueuesKey            = "swarm_queues"
+)
+
+// Policies of the version check run before every command
+const (
+	// VersionCheckWarn only warns when osdctl is outdated
+	VersionCheckWarn = "warn"
+	// VersionCheckPrompt warns and asks to continue when osdctl is outdated and stdin is a terminal
+	VersionCheckPrompt = "prompt"
+	// VersionCheckEnforceMinimum fails when osdctl is older than the minimum version or a blocked version
+	VersionCheckEnforceMinimum = "enforce-minimum"
+)
+
+const (
+	AwsAccountRegex  = "^[0-9]{12}$"
+	AWSProxyRegex    = `^http:\/\/[a-zA-Z0-9.-]+(:\d+)?$`
+	VaultURLRegex    = `^https:\/\/[a-zA-Z0-9.-]+\/?$`
+	DtVaultPathRegex = `^[a-zA-Z0-9\-/]+$`
+	PdTokenRegex     = "^[a-zA-Z0-9+_-]{20}$" // #nosec G101
+	URLRegex         = `^https?:\/\/[a-zA-Z0-9.-]+(:\d+)?$`
+)
+
+// Config is the typed content of the osdctl config file
+type Config struct {
+	ProdJumproleAccountID  string   `mapstructure:"prod_jumprole_account_id"`
+	StageJumproleAccountID string   `mapstructure:"stage_jumprole_account_id"`
+	AWSProxy               string   `mapstructure:"aws_proxy"`
+	VaultAddress           string   `mapstructure:"vault_address"`
+	DtVaultPath            string   `mapstructure:"dt_vault_path"`
+	DtDocumentVaultPath    string   `mapstructure:"dt_document_vault_path"`
+	PdUserToken            string   `mapstructure:"pd_user_token"`
+	PdOauthToken           string   `mapstructure:"pd_oauth_token"`
+	PdTeamIDs              []string `mapstructure:"team_ids"`
+	JiraToken              string   `mapstructure:"jira_token"`
+	JiraEmail              string   `mapstructure:"jira_email"`
+	JiraTeam               string   `mapstructure:"jira_team"`
+	JiraTeamLabel          string   `mapstructure:"jira_team_label"`
+	JiraBoardID            int      `mapstructure:"jira_board_id"`
+	JiraProducts           string   `mapstructure:"products"`
+	GitLabToken            string   `mapstructure:"gitlab_access"`
+	CloudTrailCmdLists     struct {
+		FilterPatternList []string `mapstructure:"filter_regex_patterns"`
+	} `mapstructure:"cloudtrail_cmd_lists"`
+	CADGrafanaURL   string `mapstructure:"cad_grafana_url"`
+	CADAWSAccountID string `mapstructure:"cad_aws_account_id"`
+	HiveOCMURL      string `mapstructure:"hive_ocm_url"`
+
+	VersionCheckPolicy   string   `mapstructure:"version_check_policy"`
+	VersionCheckCacheTTL string   `mapstructure:"version_check_cache_ttl"`
+	VersionCheckMinimum  string   `mapstructure:"version_check_minimum"`
+	VersionCheckBlocked  []string `mapstructure:"version_check_blocked"`
+
+	SwarmQueues map[string]SwarmQueue `mapstructure:"swarm_queues"`
+}
+
+// SwarmQueue is a named Jira queue of "osdctl swarm queue". Config keys are lower case, so the names of
+// variables and SLA priorities are too.
+type SwarmQueue struct {
+	Description string `mapstructure:"description"`
+	// JQL is a text/template, e.g. project = {{.project}} AND assignee is EMPTY
+	JQL string `mapstructure:"jql"`
+	// Variables are the default values of the template variables
+	Variables map[string]string `mapstructure:"variables"`
+	Columns   []string          `mapstructure:"columns"`
+	// Sort is appended as ORDER BY when the JQL has none
+	Sort string `mapstructure:"sort"`
+	// SLA is the age after which tickets of a priority breach their SLA, e.g. critical: 4h, with a default entry
+	SLA map[string]string `mapstructure:"sla"`
+	// Format is table, the default, or jira for the Jira markup pasted in handovers
+	Format string `mapstructure:"format"`
+}
+
+// ValueType is the type of a config value
+type ValueType string
+
+const (
+	StringType     ValueType = "string"
+	IntType        ValueType = "int"
+	StringListType ValueType = "list"
+	ObjectType     ValueType = "object"
+)
+
+// Key describes a key of the osdctl config file
+type Key struct {
+	Name        string
+	Description string
+	Type        ValueType
+	// Required keys are needed by common commands and reported as missing by "osdctl config validate"
+	Required bool
+	// Secret values are masked when printed
+	Secret bool
+	// Validate checks and normalizes a value, it is nil for values which are not checked
+	Validate func(string) (string, error)
+}
+
+// Keys lists every key osdctl reads from its config file
+var Keys = []Key{
+	{Name: ProdJumproleAccountIDKey, Description: "AWS account ID of the production jump role", Type: StringType, Required: true, Validate: ValidateAWSAccount},
+	{Name: AWSProxyKey, Description: "HTTP proxy used for AWS API calls", Type: StringType, Required: true, Validate: ValidateAWSProxy},
+	{Name: StageJumproleAccountIDKey, Description: "AWS account ID of the staging jump role", Type: StringType, Required: true, Validate: ValidateAWSAccount},
+	{Name: VaultAddressKey, Description: "Vault address used to read Dynatrace credentials", Type: StringType, Validate: ValidateVaultAddress},
+	{Name: DtVaultPathKey, Description: "Vault path of the Dynatrace logs credentials", Type: StringType, Validate: ValidateDtVaultPath},
+	{Name: DtDocumentVaultPathKey, Description: "Vault path of the Dynatrace documents credentials", Type: StringType, Validate: ValidateDtVaultPath},
+	{Name: PdUserTokenKey, Description: "PagerDuty user API token", Type: StringType, Secret: true, Validate: ValidatePDToken},
+	{Name: PdOauthTokenKey, Description: "PagerDuty OAuth token, used instead of the user token", Type: StringType, Secret: true},
+	{Name: PdTeamIDsKey, Description: "PagerDuty team IDs to search for incidents", Type: StringListType},
+	{Name: JiraTokenKey, Description: "Jira API token", Type: StringType, Secret: true},
+	{Name: JiraEmailKey, Description: "Email address the Jira API token belongs to", Type: StringType, Validate: ValidateEmail},
+	{Name: JiraTeamKey, Description: "Jira team of quick tasks", Type: StringType},
+	{Name: JiraTeamLabelKey, Description: "Jira label of quick tasks", Type: StringType},
+	{Name: JiraBoardIDKey, Description: "Jira board ID of quick tasks", Type: IntType},
+	{Name: JiraProductsKey, Description: "Comma-separated products of handover announcements", Type: StringType},
+	{Name: GitLabTokenKey, Description: "GitLab access token for gitlab.cee.redhat.com", Type: StringType, Secret: true},
+	{Name: CloudTrailCmdListsKey, Description: "CloudTrail filters, with a filter_regex_patterns list", Type: ObjectType},
+	{Name: CADGrafanaURLKey, Description: "Grafana URL of configuration anomaly detection", Type: StringType, Validate: ValidateURL},
+	{Name: CADAWSAccountIDKey, Description: "AWS account ID of configuration anomaly detection", Type: StringType, Validate: ValidateAWSAccount},
+	{Name: HiveOCMURLKey, Description: "OCM environment of hive shards: production, staging or integration", Type: StringType},
+	{Name: VersionCheckPolicyKey, Description: "Version check policy: warn, prompt (default) or enforce-minimum", Type: StringType, Validate: ValidateVersionCheckPolicy},
+	{Name: VersionCheckCacheTTLKey, Description: "How long the latest osdctl version is cached, e.g. 12h, 0 disables the cache", Type: StringType, Validate: ValidateDuration},
+	{Name: VersionCheckMinimumKey, Description: "Oldest osdctl version allowed to run", Type: StringType, Validate: ValidateVersion},
+	{Name: VersionCheckBlockedKey, Description: "Known-bad osdctl versions", Type: StringListType},
+	{Name: SwarmQueuesKey, Description: "Named Jira queues of \"osdctl swarm queue\", with a jql template, variables, columns, sort, sla and format", Type: ObjectType},
+}
+
+// LookupKey returns the schema of a config key
+func LookupKey(name string) (Key, bool) {
+	for _, key := range Keys {
+		if key.Name == name {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// EnvVar returns the environment variable which can provide the value of a key, e.g. OSDCTL_JIRA_TOKEN
+func EnvVar(name string) string {
+	return "OSDCTL_" + strings.ToUpper(name)
+}
+
+// Parse converts a string value to the key's type
+func (k Key) Parse(value string) (interface{}, error) {
+	value = strings.TrimSpace(value)
+	if k.Validate != nil && value != "" {
+		validated, err := k.Validate(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k.Name, err)
+		}
+		value = validated
+	}
+
+	switch k.Type {
+	case IntType:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %q is not a number", k.Name, value)
+		}
+		return i, nil
+	case StringListType:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	case ObjectType:
+		return nil, fmt.Errorf("%s holds nested values, edit the config file to change it", k.Name)
+	default:
+		return value, nil
+	}
+}
+
+// Format returns a value of the key for printing, masking secrets unless showSecrets is set
+func (k Key) Format(value interface{}, showSecrets bool) string {
+	if value == nil {
+		return ""
+	}
+
+	var s string
+	switch v := value.(type) {
+	case []interface{}, []string:
+		s = strings.Join(cast.ToStringSlice(v), ",")
+	case map[string]interface{}:
+		parts := make([]string, 0, len(v))
+		for name, nested := range v {
+			parts = append(parts, fmt.Sprintf("%s=%v", name, nested))
+		}
+		sort.Strings(parts)
+		s = strings.Join(parts, " ")
+	default:
+		s = cast.ToString(v)
+	}
+
+	if k.Secret && !showSecrets && s != "" {
+		if len(s) <= 4 {
+			return "****"
+		}
+		return s[:2] + strings.Repeat("*", 8) + s[len(s)-2:]
+	}
+	return s
+}
+
+// Problem is an issue found by Validate
+type Problem struct {
+	Key     string
+	Message string
+	// Warning problems do not make the config invalid
+	Warning bool
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Key, p.Message)
+}
+
+// Validate checks every value of the config against the schema
+func Validate(v *viper.Viper) []Problem {
+	var problems []Problem
+
+	for _, key := range Keys {
+		if !v.IsSet(key.Name) {
+			if key.Required {
+				problems = append(problems, Problem{Key: key.Name, Message: fmt.Sprintf("required key is missing, set it with \"osdctl config set %s <value>\"", key.Name)})
+			}
+			continue
+		}
+
+		if msg := validateValue(key, v.Get(key.Name)); msg != "" {
+			problems = append(problems, Problem{Key: key.Name, Message: msg})
+		}
+	}
+
+	for _, name := range v.AllKeys() {
+		// Nested keys are reported as parent.child
+		if _, ok := LookupKey(strings.SplitN(name, ".", 2)[0]); !ok {
+			problems = append(problems, Problem{Key: name, Message: "unknown key, osdctl does not use it", Warning: true})
+		}
+	}
+
+	return problems
+}
+
+func validateValue(key Key, value interface{}) string {
+	switch key.Type {
+	case IntType:
+		if _, err := cast.ToIntE(value); err != nil {
+			return fmt.Sprintf("expected a number, got %v", value)
+		}
+	case StringListType:
+		switch value.(type) {
+		case []interface{}, []string:
+		default:
+			return fmt.Sprintf("expected a list, got %v", value)
+		}
+	case ObjectType:
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fmt.Sprintf("expected nested values, got %v", value)
+		}
+	default:
+		s, err := cast.ToStringE(value)
+		if err != nil {
+			return fmt.Sprintf("expected a string, got %v", value)
+		}
+		if key.Validate != nil && strings.TrimSpace(s) != "" {
+			if _, err := key.Validate(s); err != nil {
+				return err.Error()
+			}
+		}
+	}
+	return ""
+}
+
+// Decode returns the typed config
+func Decode(v *viper.Viper) (*Config, error) {
+	config := &Config{}
+	if err := v.Unmarshal(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func matchRegex(value, regex, msg string) (string, error) {
+	value = strings.TrimSpace(value)
+	match, err := regexp.MatchString(regex, value)
+	if err != nil {
+		return "", err
+	}
+	if !match {
+		return "", errors.New(msg)
+	}
+	return value, nil
+}
+
+func ValidateAWSAccount(account string) (string, error) {
+	return matchRegex(account, AwsAccountRegex, "invalid AWS account number")
+}
+
+func ValidateAWSProxy(proxyURL string) (string, error) {
+	return matchRegex(proxyURL, AWSProxyRegex, "invalid AWS proxy URL")
+}
+
+func ValidateVaultAddress(vaultURL string) (string, error) {
+	return matchRegex(vaultURL, VaultURLRegex, "invalid Vault URL")
+}
+
+func ValidateDtVaultPath(dtVaultPath string) (string, error) {
+	return matchRegex(dtVaultPath, DtVaultPathRegex, "invalid DtVault Path")
+}
+
+func ValidatePDToken(token string) (string, error) {
+	return matchRegex(token, PdTokenRegex, "invalid pd token")
+}
+
+func ValidateURL(url string) (string, error) {
+	return matchRegex(strings.TrimSuffix(strings.TrimSpace(url), "/"), URLRegex, "invalid URL")
+}
+
+func ValidateEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if _, err := mail.ParseAddress(email); err != nil {
+		return "", errors.New("invalid email address")
+	}
+	return email, nil
+}
+
+func ValidateVersionCheckPolicy(policy string) (string, error) {
+	policy = strings.TrimSpace(policy)
+	switch policy {
+	case VersionCheckWarn, VersionCheckPrompt, VersionCheckEnforceMinimum:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid version check policy %q, valid policies are %s, %s and %s", policy, VersionCheckWarn, VersionCheckPrompt, VersionCheckEnforceMinimum)
+}
+
+func ValidateDuration(duration string) (string, error) {
+	duration = strings.TrimSpace(duration)
+	if _, err := time.ParseDuration(duration); err != nil {
+		return "", errors.New("invalid duration, e.g. 30m or 12h")
+	}
+	return duration, nil
+}
+
+func ValidateVersion(version string) (string, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if _, err := semver.NewVersion(version); err != nil {
+		return "", errors.New("invalid version, e.g. 0.40.0")
+	}
+	return version, nil
+}
@@ -0,0 +1,109 @@
+package osdctlConfig
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysMatchConfig(t *testing.T) {
+	fields := map[string]bool{}
+	configType := reflect.TypeOf(Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		fields[configType.Field(i).Tag.Get("mapstructure")] = true
+	}
+
+	assert.Len(t, Keys, len(fields))
+	for _, key := range Keys {
+		assert.True(t, fields[key.Name], "%s has no Config field", key.Name)
+		assert.NotEmpty(t, key.Description, "%s has no description", key.Name)
+	}
+}
+
+func TestKeyParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		expected interface{}
+		errorMsg string
+	}{
+		{name: "string", key: JiraTeamKey, value: " SREP ", expected: "SREP"},
+		{name: "validated", key: CADGrafanaURLKey, value: "https://grafana.example.com/", expected: "https://grafana.example.com"},
+		{name: "invalid", key: ProdJumproleAccountIDKey, value: "1234", errorMsg: "prod_jumprole_account_id: invalid AWS account number"},
+		{name: "int", key: JiraBoardIDKey, value: "123", expected: 123},
+		{name: "invalid_int", key: JiraBoardIDKey, value: "abc", errorMsg: `jira_board_id: "abc" is not a number`},
+		{name: "list", key: PdTeamIDsKey, value: "PAAAAAA, PBBBBBB,", expected: []string{"PAAAAAA", "PBBBBBB"}},
+		{name: "object", key: CloudTrailCmdListsKey, value: "- .*", errorMsg: "edit the config file"},
+		{name: "jira_token", key: JiraTokenKey, value: "ATATT3xFfGF0aBcD-eFgH_iJkL=12AB34CD", expected: "ATATT3xFfGF0aBcD-eFgH_iJkL=12AB34CD"},
+		{name: "gitlab_token", key: GitLabTokenKey, value: "glpat-aBcD1234_eFgH-5678", expected: "glpat-aBcD1234_eFgH-5678"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := LookupKey(tt.key)
+			require.True(t, ok)
+			value, err := key.Parse(tt.value)
+			if tt.errorMsg != "" {
+				assert.ErrorContains(t, err, tt.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestKeyFormat(t *testing.T) {
+	token, _ := LookupKey(PdUserTokenKey)
+	assert.Equal(t, "ab********st", token.Format("abcdEFGHijklMNOPqrst", false))
+	assert.Equal(t, "abcdEFGHijklMNOPqrst", token.Format("abcdEFGHijklMNOPqrst", true))
+	assert.Equal(t, "****", token.Format("abc", false))
+	assert.Equal(t, "", token.Format(nil, false))
+
+	teams, _ := LookupKey(PdTeamIDsKey)
+	assert.Equal(t, "PAAAAAA,PBBBBBB", teams.Format([]interface{}{"PAAAAAA", "PBBBBBB"}, false))
+
+	cloudtrail, _ := LookupKey(CloudTrailCmdListsKey)
+	assert.Equal(t, "filter_regex_patterns=[.*Get.*]", cloudtrail.Format(map[string]interface{}{"filter_regex_patterns": []interface{}{".*Get.*"}}, false))
+}
+
+func TestValidate(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(bytes.NewBufferString(`
+prod_jumprole_account_id: "123456789012"
+stage_jumprole_account_id: "1234"
+jira_board_id: abc
+team_ids: PAAAAAA
+cloudtrail_cmd_lists:
+  filter_regex_patterns:
+    - .*Get.*
+cad_grafana_url: ""
+jira_token: ATATT3xFfGF0aBcD-eFgH_iJkL=12AB34CD
+gitlab_access: glpat-aBcD1234_eFgH-5678
+unused_key: true
+`)))
+
+	assert.ElementsMatch(t, []Problem{
+		{Key: AWSProxyKey, Message: `required key is missing, set it with "osdctl config set aws_proxy <value>"`},
+		{Key: StageJumproleAccountIDKey, Message: "invalid AWS account number"},
+		{Key: JiraBoardIDKey, Message: "expected a number, got abc"},
+		{Key: PdTeamIDsKey, Message: "expected a list, got PAAAAAA"},
+		{Key: "unused_key", Message: "unknown key, osdctl does not use it", Warning: true},
+	}, Validate(v))
+
+	_, err := Decode(v)
+	assert.ErrorContains(t, err, "jira_board_id")
+
+	v.Set(JiraBoardIDKey, 42)
+	config, err := Decode(v)
+	require.NoError(t, err)
+	assert.Equal(t, "123456789012", config.ProdJumproleAccountID)
+	assert.Equal(t, 42, config.JiraBoardID)
+	assert.Equal(t, []string{".*Get.*"}, config.CloudTrailCmdLists.FilterPatternList)
+}
@@ -121,6 +121,10 @@ type Client interface {
 	GetCostAndUsage(input *costexplorer.GetCostAndUsageInput) (*costexplorer.GetCostAndUsageOutput, error)
 	CreateCostCategoryDefinition(input *costexplorer.CreateCostCategoryDefinitionInput) (*costexplorer.CreateCostCategoryDefinitionOutput, error)
 	ListCostCategoryDefinitions(input *costexplorer.ListCostCategoryDefinitionsInput) (*costexplorer.ListCostCategoryDefinitionsOutput, error)
+	DescribeCostCategoryDefinition(input *costexplorer.DescribeCostCategoryDefinitionInput) (*costexplorer.DescribeCostCategoryDefinitionOutput, error)
+	UpdateCostCategoryDefinition(input *costexplorer.UpdateCostCategoryDefinitionInput) (*costexplorer.UpdateCostCategoryDefinitionOutput, error)
+	DeleteCostCategoryDefinition(input *costexplorer.DeleteCostCategoryDefinitionInput) (*costexplorer.DeleteCostCategoryDefinitionOutput, error)
+	ListCostCategoryTags(input *costexplorer.ListTagsForResourceInput) (*costexplorer.ListTagsForResourceOutput, error)
 
 	// Cloudtrail
 	LookupEvents(input *cloudtrail.LookupEventsInput) (*cloudtrail.LookupEventsOutput, error)
@@ -477,6 +481,24 @@ func (c *AwsClient) ListCostCategoryDefinitions(input *costexplorer.ListCostCate
 	return c.ceClient.ListCostCategoryDefinitions(context.TODO(), input)
 }
 
+func (c *AwsClient) DescribeCostCategoryDefinition(input *costexplorer.DescribeCostCategoryDefinitionInput) (*costexplorer.DescribeCostCategoryDefinitionOutput, error) {
+	return c.ceClient.DescribeCostCategoryDefinition(context.TODO(), input)
+}
+
+func (c *AwsClient) UpdateCostCategoryDefinition(input *costexplorer.UpdateCostCategoryDefinitionInput) (*costexplorer.UpdateCostCategoryDefinitionOutput, error) {
+	return c.ceClient.UpdateCostCategoryDefinition(context.TODO(), input)
+}
+
+func (c *AwsClient) DeleteCostCategoryDefinition(input *costexplorer.DeleteCostCategoryDefinitionInput) (*costexplorer.DeleteCostCategoryDefinitionOutput, error) {
+	return c.ceClient.DeleteCostCategoryDefinition(context.TODO(), input)
+}
+
+// ListCostCategoryTags lists the tags of a Cost Explorer resource, it is named apart from ListTagsForResource of
+// Organizations
+func (c *AwsClient) ListCostCategoryTags(input *costexplorer.ListTagsForResourceInput) (*costexplorer.ListTagsForResourceOutput, error) {
+	return c.ceClient.ListTagsForResource(context.TODO(), input)
+}
+
 func (c *AwsClient) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
 	return c.ec2Client.DescribeInstances(context.TODO(), input)
 }
@@ -35,6 +35,40 @@ func CheckIAMUserExists(awsClient Client, username *string) (bool, error) {
 	return true, nil
 }
 
+// ListIAMUsers returns the IAM users of the account of the client
+func ListIAMUsers(awsClient Client) ([]types.User, error) {
+	var users []types.User
+	var marker *string
+	for {
+		output, err := awsClient.ListUsers(&iam.ListUsersInput{Marker: marker})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list IAM users: %w", err)
+		}
+		users = append(users, output.Users...)
+		if !output.IsTruncated {
+			return users, nil
+		}
+		marker = output.Marker
+	}
+}
+
+// ListIAMUserAccessKeys returns the access keys of an IAM user
+func ListIAMUserAccessKeys(awsClient Client, username string) ([]types.AccessKeyMetadata, error) {
+	var keys []types.AccessKeyMetadata
+	var marker *string
+	for {
+		output, err := awsClient.ListAccessKeys(&iam.ListAccessKeysInput{UserName: &username, Marker: marker})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the access keys of %s: %w", username, err)
+		}
+		keys = append(keys, output.AccessKeyMetadata...)
+		if !output.IsTruncated {
+			return keys, nil
+		}
+		marker = output.Marker
+	}
+}
+
 func DeleteUserAccessKeys(awsClient Client, username *string) error {
 	accessKeys, err := awsClient.ListAccessKeys(&iam.ListAccessKeysInput{UserName: username})
 	if err != nil {
@@ -247,3 +247,21 @@ func TestCreateIAMUserAndAttachPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestListIAMUsers(t *testing.T) {
+	g := NewGomegaWithT(t)
+	mocks := setupDefaultMocks(t)
+	defer mocks.mockCtrl.Finish()
+
+	gomock.InOrder(
+		mocks.mockAWSClient.EXPECT().ListUsers(&iam.ListUsersInput{}).
+			Return(&iam.ListUsersOutput{Users: []iamTypes.User{{UserName: awsSdk.String("alice")}}, IsTruncated: true, Marker: awsSdk.String("next")}, nil),
+		mocks.mockAWSClient.EXPECT().ListUsers(&iam.ListUsersInput{Marker: awsSdk.String("next")}).
+			Return(&iam.ListUsersOutput{Users: []iamTypes.User{{UserName: awsSdk.String("bob")}}}, nil),
+	)
+
+	users, err := ListIAMUsers(mocks.mockAWSClient)
+	g.Expect(err).Should(Not(HaveOccurred()))
+	g.Expect(users).Should(HaveLen(2))
+	g.Expect(*users[1].UserName).Should(Equal("bob"))
+}
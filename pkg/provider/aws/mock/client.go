@@ -201,6 +201,21 @@ func (mr *MockClientMockRecorder) DeleteBucket(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucket", reflect.TypeOf((*MockClient)(nil).DeleteBucket), arg0)
 }
 
+// DeleteCostCategoryDefinition mocks base method.
+func (m *MockClient) DeleteCostCategoryDefinition(input *costexplorer.DeleteCostCategoryDefinitionInput) (*costexplorer.DeleteCostCategoryDefinitionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCostCategoryDefinition", input)
+	ret0, _ := ret[0].(*costexplorer.DeleteCostCategoryDefinitionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCostCategoryDefinition indicates an expected call of DeleteCostCategoryDefinition.
+func (mr *MockClientMockRecorder) DeleteCostCategoryDefinition(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCostCategoryDefinition", reflect.TypeOf((*MockClient)(nil).DeleteCostCategoryDefinition), input)
+}
+
 // DeleteLoginProfile mocks base method.
 func (m *MockClient) DeleteLoginProfile(arg0 *iam.DeleteLoginProfileInput) (*iam.DeleteLoginProfileOutput, error) {
 	m.ctrl.T.Helper()
@@ -321,6 +336,21 @@ func (mr *MockClientMockRecorder) DescribeAccount(input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAccount", reflect.TypeOf((*MockClient)(nil).DescribeAccount), input)
 }
 
+// DescribeCostCategoryDefinition mocks base method.
+func (m *MockClient) DescribeCostCategoryDefinition(input *costexplorer.DescribeCostCategoryDefinitionInput) (*costexplorer.DescribeCostCategoryDefinitionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeCostCategoryDefinition", input)
+	ret0, _ := ret[0].(*costexplorer.DescribeCostCategoryDefinitionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeCostCategoryDefinition indicates an expected call of DescribeCostCategoryDefinition.
+func (mr *MockClientMockRecorder) DescribeCostCategoryDefinition(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCostCategoryDefinition", reflect.TypeOf((*MockClient)(nil).DescribeCostCategoryDefinition), input)
+}
+
 // DescribeCreateAccountStatus mocks base method.
 func (m *MockClient) DescribeCreateAccountStatus(input *organizations.DescribeCreateAccountStatusInput) (*organizations.DescribeCreateAccountStatusOutput, error) {
 	m.ctrl.T.Helper()
@@ -756,6 +786,21 @@ func (mr *MockClientMockRecorder) ListCostCategoryDefinitions(input any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCostCategoryDefinitions", reflect.TypeOf((*MockClient)(nil).ListCostCategoryDefinitions), input)
 }
 
+// ListCostCategoryTags mocks base method.
+func (m *MockClient) ListCostCategoryTags(input *costexplorer.ListTagsForResourceInput) (*costexplorer.ListTagsForResourceOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCostCategoryTags", input)
+	ret0, _ := ret[0].(*costexplorer.ListTagsForResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCostCategoryTags indicates an expected call of ListCostCategoryTags.
+func (mr *MockClientMockRecorder) ListCostCategoryTags(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCostCategoryTags", reflect.TypeOf((*MockClient)(nil).ListCostCategoryTags), input)
+}
+
 // ListGroupsForUser mocks base method.
 func (m *MockClient) ListGroupsForUser(arg0 *iam.ListGroupsForUserInput) (*iam.ListGroupsForUserOutput, error) {
 	m.ctrl.T.Helper()
@@ -1070,3 +1115,18 @@ func (mr *MockClientMockRecorder) UntagResource(input any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagResource", reflect.TypeOf((*MockClient)(nil).UntagResource), input)
 }
+
+// UpdateCostCategoryDefinition mocks base method.
+func (m *MockClient) UpdateCostCategoryDefinition(input *costexplorer.UpdateCostCategoryDefinitionInput) (*costexplorer.UpdateCostCategoryDefinitionOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCostCategoryDefinition", input)
+	ret0, _ := ret[0].(*costexplorer.UpdateCostCategoryDefinitionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCostCategoryDefinition indicates an expected call of UpdateCostCategoryDefinition.
+func (mr *MockClientMockRecorder) UpdateCostCategoryDefinition(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCostCategoryDefinition", reflect.TypeOf((*MockClient)(nil).UpdateCostCategoryDefinition), input)
+}
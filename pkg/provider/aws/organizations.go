@@ -0,0 +1,75 @@
+package aws
+
+import (
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// ListAccountTags returns the tags of an account of the organization
+func ListAccountTags(awsClient Client, accountID string) ([]types.Tag, error) {
+	var tags []types.Tag
+	var nextToken *string
+	for {
+		output, err := awsClient.ListTagsForResource(&organizations.ListTagsForResourceInput{
+			ResourceId: &accountID,
+			NextToken:  nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, output.Tags...)
+		if output.NextToken == nil {
+			return tags, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// ListAccountsUnderOU returns the sorted IDs of the accounts under an OU and its child OUs
+func ListAccountsUnderOU(awsClient Client, ouID string) ([]string, error) {
+	var accountIDs []string
+	var nextToken *string
+	for {
+		accounts, err := awsClient.ListAccountsForParent(&organizations.ListAccountsForParentInput{
+			ParentId:  &ouID,
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range accounts.Accounts {
+			accountIDs = append(accountIDs, *account.Id)
+		}
+		if accounts.NextToken == nil {
+			break
+		}
+		nextToken = accounts.NextToken
+	}
+
+	nextToken = nil
+	for {
+		OUs, err := awsClient.ListOrganizationalUnitsForParent(&organizations.ListOrganizationalUnitsForParentInput{
+			ParentId:  &ouID,
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, childOU := range OUs.OrganizationalUnits {
+			childAccountIDs, err := ListAccountsUnderOU(awsClient, *childOU.Id)
+			if err != nil {
+				return nil, err
+			}
+			accountIDs = append(accountIDs, childAccountIDs...)
+		}
+		if OUs.NextToken == nil {
+			break
+		}
+		nextToken = OUs.NextToken
+	}
+
+	slices.Sort(accountIDs)
+	return accountIDs, nil
+}
@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"testing"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListAccountTags(t *testing.T) {
+	g := NewGomegaWithT(t)
+	mocks := setupDefaultMocks(t)
+	defer mocks.mockCtrl.Finish()
+
+	gomock.InOrder(
+		mocks.mockAWSClient.EXPECT().ListTagsForResource(&organizations.ListTagsForResourceInput{ResourceId: awsSdk.String("111")}).
+			Return(&organizations.ListTagsForResourceOutput{Tags: []types.Tag{{Key: awsSdk.String("team")}}, NextToken: awsSdk.String("next")}, nil),
+		mocks.mockAWSClient.EXPECT().ListTagsForResource(&organizations.ListTagsForResourceInput{ResourceId: awsSdk.String("111"), NextToken: awsSdk.String("next")}).
+			Return(&organizations.ListTagsForResourceOutput{Tags: []types.Tag{{Key: awsSdk.String("owner")}}}, nil),
+	)
+
+	tags, err := ListAccountTags(mocks.mockAWSClient, "111")
+	g.Expect(err).Should(Not(HaveOccurred()))
+	g.Expect(tags).Should(HaveLen(2))
+	g.Expect(*tags[1].Key).Should(Equal("owner"))
+}
+
+func TestListAccountsUnderOU(t *testing.T) {
+	g := NewGomegaWithT(t)
+	mocks := setupDefaultMocks(t)
+	defer mocks.mockCtrl.Finish()
+
+	accounts := map[string][]types.Account{
+		"ou-root":  {{Id: awsSdk.String("333")}},
+		"ou-child": {{Id: awsSdk.String("222")}, {Id: awsSdk.String("111")}},
+	}
+	mocks.mockAWSClient.EXPECT().ListAccountsForParent(gomock.Any()).DoAndReturn(func(input *organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error) {
+		return &organizations.ListAccountsForParentOutput{Accounts: accounts[*input.ParentId]}, nil
+	}).Times(2)
+	mocks.mockAWSClient.EXPECT().ListOrganizationalUnitsForParent(gomock.Any()).DoAndReturn(func(input *organizations.ListOrganizationalUnitsForParentInput) (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+		if *input.ParentId == "ou-root" {
+			return &organizations.ListOrganizationalUnitsForParentOutput{OrganizationalUnits: []types.OrganizationalUnit{{Id: awsSdk.String("ou-child")}}}, nil
+		}
+		return &organizations.ListOrganizationalUnitsForParentOutput{}, nil
+	}).Times(2)
+
+	accountIDs, err := ListAccountsUnderOU(mocks.mockAWSClient, "ou-root")
+	g.Expect(err).Should(Not(HaveOccurred()))
+	g.Expect(accountIDs).Should(Equal([]string{"111", "222", "333"}))
+}
@@ -12,11 +12,181 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	pagerduty "github.com/PagerDuty/go-pagerduty"
 	gomock "go.uber.org/mock/gomock"
 )
 
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+	isgomock struct{}
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// AcknowledgeIncidents mocks base method.
+func (m *MockClient) AcknowledgeIncidents(incidentIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcknowledgeIncidents", incidentIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcknowledgeIncidents indicates an expected call of AcknowledgeIncidents.
+func (mr *MockClientMockRecorder) AcknowledgeIncidents(incidentIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcknowledgeIncidents", reflect.TypeOf((*MockClient)(nil).AcknowledgeIncidents), incidentIDs)
+}
+
+// AddIncidentNote mocks base method.
+func (m *MockClient) AddIncidentNote(incidentID, content string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddIncidentNote", incidentID, content)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddIncidentNote indicates an expected call of AddIncidentNote.
+func (mr *MockClientMockRecorder) AddIncidentNote(incidentID, content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddIncidentNote", reflect.TypeOf((*MockClient)(nil).AddIncidentNote), incidentID, content)
+}
+
+// GetAcknowledgeTimes mocks base method.
+func (m *MockClient) GetAcknowledgeTimes(since, until time.Time) (map[string]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAcknowledgeTimes", since, until)
+	ret0, _ := ret[0].(map[string]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAcknowledgeTimes indicates an expected call of GetAcknowledgeTimes.
+func (mr *MockClientMockRecorder) GetAcknowledgeTimes(since, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAcknowledgeTimes", reflect.TypeOf((*MockClient)(nil).GetAcknowledgeTimes), since, until)
+}
+
+// GetFiringAlertsForCluster mocks base method.
+func (m *MockClient) GetFiringAlertsForCluster(pdServiceIDs []string) (map[string][]pagerduty.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFiringAlertsForCluster", pdServiceIDs)
+	ret0, _ := ret[0].(map[string][]pagerduty.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFiringAlertsForCluster indicates an expected call of GetFiringAlertsForCluster.
+func (mr *MockClientMockRecorder) GetFiringAlertsForCluster(pdServiceIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFiringAlertsForCluster", reflect.TypeOf((*MockClient)(nil).GetFiringAlertsForCluster), pdServiceIDs)
+}
+
+// GetIncidentAcknowledgeTimes mocks base method.
+func (m *MockClient) GetIncidentAcknowledgeTimes(incidentIDs []string) (map[string]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentAcknowledgeTimes", incidentIDs)
+	ret0, _ := ret[0].(map[string]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidentAcknowledgeTimes indicates an expected call of GetIncidentAcknowledgeTimes.
+func (mr *MockClientMockRecorder) GetIncidentAcknowledgeTimes(incidentIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentAcknowledgeTimes", reflect.TypeOf((*MockClient)(nil).GetIncidentAcknowledgeTimes), incidentIDs)
+}
+
+// GetIncidents mocks base method.
+func (m *MockClient) GetIncidents(pdServiceIDs []string, since, until time.Time) ([]pagerduty.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidents", pdServiceIDs, since, until)
+	ret0, _ := ret[0].([]pagerduty.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncidents indicates an expected call of GetIncidents.
+func (mr *MockClientMockRecorder) GetIncidents(pdServiceIDs, since, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidents", reflect.TypeOf((*MockClient)(nil).GetIncidents), pdServiceIDs, since, until)
+}
+
+// GetPDServiceIDs mocks base method.
+func (m *MockClient) GetPDServiceIDs() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPDServiceIDs")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPDServiceIDs indicates an expected call of GetPDServiceIDs.
+func (mr *MockClientMockRecorder) GetPDServiceIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPDServiceIDs", reflect.TypeOf((*MockClient)(nil).GetPDServiceIDs))
+}
+
+// MergeIncidents mocks base method.
+func (m *MockClient) MergeIncidents(targetIncidentID string, sourceIncidentIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeIncidents", targetIncidentID, sourceIncidentIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeIncidents indicates an expected call of MergeIncidents.
+func (mr *MockClientMockRecorder) MergeIncidents(targetIncidentID, sourceIncidentIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeIncidents", reflect.TypeOf((*MockClient)(nil).MergeIncidents), targetIncidentID, sourceIncidentIDs)
+}
+
+// ReassignIncidents mocks base method.
+func (m *MockClient) ReassignIncidents(incidentIDs, userEmails []string, escalationPolicyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignIncidents", incidentIDs, userEmails, escalationPolicyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReassignIncidents indicates an expected call of ReassignIncidents.
+func (mr *MockClientMockRecorder) ReassignIncidents(incidentIDs, userEmails, escalationPolicyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignIncidents", reflect.TypeOf((*MockClient)(nil).ReassignIncidents), incidentIDs, userEmails, escalationPolicyID)
+}
+
+// ResolveIncidents mocks base method.
+func (m *MockClient) ResolveIncidents(incidentIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveIncidents", incidentIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveIncidents indicates an expected call of ResolveIncidents.
+func (mr *MockClientMockRecorder) ResolveIncidents(incidentIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveIncidents", reflect.TypeOf((*MockClient)(nil).ResolveIncidents), incidentIDs)
+}
+
 // MockpdClientInterface is a mock of pdClientInterface interface.
 type MockpdClientInterface struct {
 	ctrl     *gomock.Controller
@@ -41,6 +211,51 @@ func (m *MockpdClientInterface) EXPECT() *MockpdClientInterfaceMockRecorder {
 	return m.recorder
 }
 
+// CreateIncidentNoteWithContext mocks base method.
+func (m *MockpdClientInterface) CreateIncidentNoteWithContext(arg0 context.Context, arg1 string, arg2 pagerduty.IncidentNote) (*pagerduty.IncidentNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIncidentNoteWithContext", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*pagerduty.IncidentNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIncidentNoteWithContext indicates an expected call of CreateIncidentNoteWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) CreateIncidentNoteWithContext(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncidentNoteWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).CreateIncidentNoteWithContext), arg0, arg1, arg2)
+}
+
+// GetCurrentUserWithContext mocks base method.
+func (m *MockpdClientInterface) GetCurrentUserWithContext(arg0 context.Context, arg1 pagerduty.GetCurrentUserOptions) (*pagerduty.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentUserWithContext", arg0, arg1)
+	ret0, _ := ret[0].(*pagerduty.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentUserWithContext indicates an expected call of GetCurrentUserWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) GetCurrentUserWithContext(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentUserWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).GetCurrentUserWithContext), arg0, arg1)
+}
+
+// ListIncidentLogEntriesWithContext mocks base method.
+func (m *MockpdClientInterface) ListIncidentLogEntriesWithContext(arg0 context.Context, arg1 string, arg2 pagerduty.ListIncidentLogEntriesOptions) (*pagerduty.ListIncidentLogEntriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncidentLogEntriesWithContext", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*pagerduty.ListIncidentLogEntriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncidentLogEntriesWithContext indicates an expected call of ListIncidentLogEntriesWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) ListIncidentLogEntriesWithContext(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentLogEntriesWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).ListIncidentLogEntriesWithContext), arg0, arg1, arg2)
+}
+
 // ListIncidentsWithContext mocks base method.
 func (m *MockpdClientInterface) ListIncidentsWithContext(arg0 context.Context, arg1 pagerduty.ListIncidentsOptions) (*pagerduty.ListIncidentsResponse, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +271,21 @@ func (mr *MockpdClientInterfaceMockRecorder) ListIncidentsWithContext(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentsWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).ListIncidentsWithContext), arg0, arg1)
 }
 
+// ListLogEntriesWithContext mocks base method.
+func (m *MockpdClientInterface) ListLogEntriesWithContext(arg0 context.Context, arg1 pagerduty.ListLogEntriesOptions) (*pagerduty.ListLogEntryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLogEntriesWithContext", arg0, arg1)
+	ret0, _ := ret[0].(*pagerduty.ListLogEntryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLogEntriesWithContext indicates an expected call of ListLogEntriesWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) ListLogEntriesWithContext(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLogEntriesWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).ListLogEntriesWithContext), arg0, arg1)
+}
+
 // ListServicesWithContext mocks base method.
 func (m *MockpdClientInterface) ListServicesWithContext(arg0 context.Context, arg1 pagerduty.ListServiceOptions) (*pagerduty.ListServiceResponse, error) {
 	m.ctrl.T.Helper()
@@ -70,3 +300,48 @@ func (mr *MockpdClientInterfaceMockRecorder) ListServicesWithContext(arg0, arg1
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServicesWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).ListServicesWithContext), arg0, arg1)
 }
+
+// ListUsersWithContext mocks base method.
+func (m *MockpdClientInterface) ListUsersWithContext(arg0 context.Context, arg1 pagerduty.ListUsersOptions) (*pagerduty.ListUsersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsersWithContext", arg0, arg1)
+	ret0, _ := ret[0].(*pagerduty.ListUsersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsersWithContext indicates an expected call of ListUsersWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) ListUsersWithContext(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsersWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).ListUsersWithContext), arg0, arg1)
+}
+
+// ManageIncidentsWithContext mocks base method.
+func (m *MockpdClientInterface) ManageIncidentsWithContext(arg0 context.Context, arg1 string, arg2 []pagerduty.ManageIncidentsOptions) (*pagerduty.ListIncidentsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManageIncidentsWithContext", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*pagerduty.ListIncidentsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ManageIncidentsWithContext indicates an expected call of ManageIncidentsWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) ManageIncidentsWithContext(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManageIncidentsWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).ManageIncidentsWithContext), arg0, arg1, arg2)
+}
+
+// MergeIncidentsWithContext mocks base method.
+func (m *MockpdClientInterface) MergeIncidentsWithContext(arg0 context.Context, arg1, arg2 string, arg3 []pagerduty.MergeIncidentsOptions) (*pagerduty.Incident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeIncidentsWithContext", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*pagerduty.Incident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeIncidentsWithContext indicates an expected call of MergeIncidentsWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) MergeIncidentsWithContext(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeIncidentsWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).MergeIncidentsWithContext), arg0, arg1, arg2, arg3)
+}
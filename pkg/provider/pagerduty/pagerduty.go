@@ -6,17 +6,35 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	pd "github.com/PagerDuty/go-pagerduty"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
 	PagerDutyUserTokenConfigKey  = "pd_user_token"  // #nosec G101
 	PagerDutyOauthTokenConfigKey = "pd_oauth_token" // #nosec G101
 	PagerDutyTeamIDsKey          = "team_ids"
+
+	// maxServiceIDsPerRequest keeps the incident list URLs short when querying many services
+	maxServiceIDsPerRequest = 50
+
+	// maxPaginationOffset is the highest offset PagerDuty's classic pagination accepts
+	maxPaginationOffset = 10000
+
+	// maxConcurrentRequests bounds the requests sent at once when looking up incidents one by one
+	maxConcurrentRequests = 10
+)
+
+var (
+	// Parts of incident titles which differ between occurrences of the same alert
+	titleGroupRe      = regexp.MustCompile(`\([^)]*\)|\[[^\]]*\]|\{[^}]*\}`)
+	titleIdentifierRe = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b|\b[0-9a-z]{32}\b|\S+\.\S+\.\S+|\b[0-9][0-9.:]*%?`)
 )
 
 type IncidentOccurrenceTracker struct {
@@ -25,9 +43,32 @@ type IncidentOccurrenceTracker struct {
 	LastOccurrence string
 }
 
+// Client is the part of the PagerDuty provider used by the pagerduty commands
+type Client interface {
+	GetPDServiceIDs() ([]string, error)
+	GetFiringAlertsForCluster(pdServiceIDs []string) (map[string][]pd.Incident, error)
+	GetIncidents(pdServiceIDs []string, since time.Time, until time.Time) ([]pd.Incident, error)
+	GetAcknowledgeTimes(since time.Time, until time.Time) (map[string]time.Time, error)
+	GetIncidentAcknowledgeTimes(incidentIDs []string) (map[string]time.Time, error)
+	AcknowledgeIncidents(incidentIDs []string) error
+	ResolveIncidents(incidentIDs []string) error
+	ReassignIncidents(incidentIDs []string, userEmails []string, escalationPolicyID string) error
+	AddIncidentNote(incidentID string, content string) error
+	MergeIncidents(targetIncidentID string, sourceIncidentIDs []string) error
+}
+
+var _ Client = &client{}
+
 type pdClientInterface interface {
 	ListIncidentsWithContext(context.Context, pd.ListIncidentsOptions) (*pd.ListIncidentsResponse, error)
 	ListServicesWithContext(context.Context, pd.ListServiceOptions) (*pd.ListServiceResponse, error)
+	ListLogEntriesWithContext(context.Context, pd.ListLogEntriesOptions) (*pd.ListLogEntryResponse, error)
+	ListIncidentLogEntriesWithContext(context.Context, string, pd.ListIncidentLogEntriesOptions) (*pd.ListIncidentLogEntriesResponse, error)
+	GetCurrentUserWithContext(context.Context, pd.GetCurrentUserOptions) (*pd.User, error)
+	ListUsersWithContext(context.Context, pd.ListUsersOptions) (*pd.ListUsersResponse, error)
+	ManageIncidentsWithContext(context.Context, string, []pd.ManageIncidentsOptions) (*pd.ListIncidentsResponse, error)
+	CreateIncidentNoteWithContext(context.Context, string, pd.IncidentNote) (*pd.IncidentNote, error)
+	MergeIncidentsWithContext(context.Context, string, string, []pd.MergeIncidentsOptions) (*pd.Incident, error)
 }
 
 type client struct {
@@ -36,6 +77,20 @@ type client struct {
 	teamIds    []string
 	userToken  string
 	oauthToken string
+	// fromEmail is the email of the token's user, looked up on the first change
+	fromEmail string
+}
+
+// AlertName returns the name incidents are grouped by. It strips the parts of the title which
+// differ between occurrences of the same alert, like cluster names, ids and counters.
+func AlertName(title string) string {
+	name := titleGroupRe.ReplaceAllString(title, " ")
+	name = titleIdentifierRe.ReplaceAllString(name, " ")
+	name = strings.Trim(strings.Join(strings.Fields(name), " "), " -:,")
+	if name == "" {
+		return strings.TrimSpace(title)
+	}
+	return name
 }
 
 func NewClient() *client {
@@ -166,7 +221,7 @@ func (c *client) GetHistoricalAlertsForCluster(pdServiceIDs []string) (map[strin
 		incidentCounter := make(map[string]*IncidentOccurrenceTracker)
 
 		for _, incident := range incidents {
-			title := strings.Split(incident.Title, " ")[0]
+			title := AlertName(incident.Title)
 			if _, found := incidentCounter[title]; found {
 				incidentCounter[title].Count++
 
@@ -213,3 +268,298 @@ func (c *client) GetHistoricalAlertsForCluster(pdServiceIDs []string) (map[strin
 	return incidentMap, nil
 
 }
+
+// GetIncidents returns the incidents created between since and until, on the given services, or
+// on the services of the client's teams when no service is given
+func (c *client) GetIncidents(pdServiceIDs []string, since time.Time, until time.Time) ([]pd.Incident, error) {
+	var incidents []pd.Incident
+
+	var serviceIDBatches [][]string
+	for start := 0; start < len(pdServiceIDs); start += maxServiceIDsPerRequest {
+		serviceIDBatches = append(serviceIDBatches, pdServiceIDs[start:min(start+maxServiceIDsPerRequest, len(pdServiceIDs))])
+	}
+	if len(serviceIDBatches) == 0 {
+		serviceIDBatches = [][]string{nil}
+	}
+
+	for _, serviceIDs := range serviceIDBatches {
+		batchIncidents, err := c.listIncidents(serviceIDs, since, until)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, batchIncidents...)
+	}
+
+	return incidents, nil
+}
+
+// listIncidents returns the incidents of the services created between since and until. Windows with more
+// incidents than PagerDuty can paginate through are split in halves.
+func (c *client) listIncidents(serviceIDs []string, since time.Time, until time.Time) ([]pd.Incident, error) {
+	var incidents []pd.Incident
+	var limit uint = 100
+	options := pd.ListIncidentsOptions{
+		ServiceIDs: serviceIDs,
+		Since:      since.UTC().Format(time.RFC3339),
+		Until:      until.UTC().Format(time.RFC3339),
+		Statuses:   []string{"resolved", "triggered", "acknowledged"},
+		SortBy:     "created_at:asc",
+		Limit:      limit,
+	}
+	if len(serviceIDs) == 0 {
+		options.TeamIDs = c.teamIds
+	}
+
+	for ; ; options.Offset += limit {
+		if options.Offset+limit > maxPaginationOffset {
+			// The incidents found so far are listed again by the first half
+			middle := since.Add(until.Sub(since) / 2).Truncate(time.Second)
+			if !middle.After(since) {
+				return nil, fmt.Errorf("too many incidents at %s to page through", options.Since)
+			}
+			first, err := c.listIncidents(serviceIDs, since, middle)
+			if err != nil {
+				return nil, err
+			}
+			second, err := c.listIncidents(serviceIDs, middle, until)
+			if err != nil {
+				return nil, err
+			}
+			// Incidents created at the middle may be in both halves
+			seen := map[string]bool{}
+			for _, incident := range first {
+				seen[incident.ID] = true
+			}
+			for _, incident := range second {
+				if incident.ID == "" || !seen[incident.ID] {
+					first = append(first, incident)
+				}
+			}
+			return first, nil
+		}
+
+		liResponse, err := c.pdclient.ListIncidentsWithContext(context.TODO(), options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to ListIncidentsWithContext: %w", err)
+		}
+		incidents = append(incidents, liResponse.Incidents...)
+		if !liResponse.More {
+			return incidents, nil
+		}
+	}
+}
+
+// GetAcknowledgeTimes returns when each incident was first acknowledged, looking at the log
+// entries of the client's teams between since and until
+func (c *client) GetAcknowledgeTimes(since time.Time, until time.Time) (map[string]time.Time, error) {
+	if len(c.teamIds) == 0 {
+		return nil, fmt.Errorf("no team to look up the acknowledgements of, the log entries of the whole account would be listed")
+	}
+
+	acknowledgedAt := map[string]time.Time{}
+	if err := c.listAcknowledgements(since, until, acknowledgedAt); err != nil {
+		return nil, err
+	}
+	return acknowledgedAt, nil
+}
+
+// listAcknowledgements adds the acknowledgements between since and until to acknowledgedAt. Windows with
+// more log entries than PagerDuty can paginate through are split in halves.
+func (c *client) listAcknowledgements(since time.Time, until time.Time, acknowledgedAt map[string]time.Time) error {
+	var limit uint = 100
+	options := pd.ListLogEntriesOptions{
+		Since:      since.UTC().Format(time.RFC3339),
+		Until:      until.UTC().Format(time.RFC3339),
+		TeamIDs:    c.teamIds,
+		IsOverview: true,
+		Limit:      limit,
+	}
+
+	for ; ; options.Offset += limit {
+		if options.Offset+limit > maxPaginationOffset {
+			// The acknowledgements found so far are kept, adding them again is harmless
+			middle := since.Add(until.Sub(since) / 2).Truncate(time.Second)
+			if !middle.After(since) {
+				return fmt.Errorf("too many log entries at %s to page through", options.Since)
+			}
+			if err := c.listAcknowledgements(since, middle, acknowledgedAt); err != nil {
+				return err
+			}
+			return c.listAcknowledgements(middle, until, acknowledgedAt)
+		}
+
+		leResponse, err := c.pdclient.ListLogEntriesWithContext(context.TODO(), options)
+		if err != nil {
+			return fmt.Errorf("failed to ListLogEntriesWithContext: %w", err)
+		}
+		if err := addAcknowledgements(acknowledgedAt, leResponse.LogEntries); err != nil {
+			return err
+		}
+		if !leResponse.More {
+			return nil
+		}
+	}
+}
+
+// GetIncidentAcknowledgeTimes returns when each of the given incidents was first acknowledged, looking at
+// the log entries of every incident. Unlike GetAcknowledgeTimes it doesn't need teams.
+func (c *client) GetIncidentAcknowledgeTimes(incidentIDs []string) (map[string]time.Time, error) {
+	var mu sync.Mutex
+	acknowledgedAt := map[string]time.Time{}
+
+	g, ctx := errgroup.WithContext(context.TODO())
+	g.SetLimit(maxConcurrentRequests)
+	for _, id := range incidentIDs {
+		g.Go(func() error {
+			var limit uint = 100
+			options := pd.ListIncidentLogEntriesOptions{IsOverview: true, Limit: limit}
+			for ; ; options.Offset += limit {
+				leResponse, err := c.pdclient.ListIncidentLogEntriesWithContext(ctx, id, options)
+				if err != nil {
+					return fmt.Errorf("failed to ListIncidentLogEntriesWithContext for incident %s: %w", id, err)
+				}
+
+				mu.Lock()
+				err = addAcknowledgements(acknowledgedAt, leResponse.LogEntries)
+				mu.Unlock()
+				if err != nil {
+					return err
+				}
+				if !leResponse.More {
+					return nil
+				}
+			}
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return acknowledgedAt, nil
+}
+
+// addAcknowledgements records the earliest acknowledgement of each incident found in entries
+func addAcknowledgements(acknowledgedAt map[string]time.Time, entries []pd.LogEntry) error {
+	for _, entry := range entries {
+		if entry.Type != "acknowledge_log_entry" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, entry.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to parse time %q: %w", entry.CreatedAt, err)
+		}
+		if previous, found := acknowledgedAt[entry.Incident.ID]; !found || at.Before(previous) {
+			acknowledgedAt[entry.Incident.ID] = at
+		}
+	}
+	return nil
+}
+
+// from returns the email of the token's user, which PagerDuty records as the author of every change
+func (c *client) from(ctx context.Context) (string, error) {
+	if c.fromEmail != "" {
+		return c.fromEmail, nil
+	}
+	user, err := c.pdclient.GetCurrentUserWithContext(ctx, pd.GetCurrentUserOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to GetCurrentUserWithContext: %w", err)
+	}
+	c.fromEmail = user.Email
+	return c.fromEmail, nil
+}
+
+// manageIncidents applies the same change to every incident
+func (c *client) manageIncidents(incidentIDs []string, change func(*pd.ManageIncidentsOptions)) error {
+	ctx := context.TODO()
+	from, err := c.from(ctx)
+	if err != nil {
+		return err
+	}
+
+	options := make([]pd.ManageIncidentsOptions, 0, len(incidentIDs))
+	for _, id := range incidentIDs {
+		option := pd.ManageIncidentsOptions{ID: id}
+		change(&option)
+		options = append(options, option)
+	}
+	if _, err := c.pdclient.ManageIncidentsWithContext(ctx, from, options); err != nil {
+		return fmt.Errorf("failed to ManageIncidentsWithContext: %w", err)
+	}
+	return nil
+}
+
+func (c *client) AcknowledgeIncidents(incidentIDs []string) error {
+	return c.manageIncidents(incidentIDs, func(o *pd.ManageIncidentsOptions) {
+		o.Status = "acknowledged"
+	})
+}
+
+func (c *client) ResolveIncidents(incidentIDs []string) error {
+	return c.manageIncidents(incidentIDs, func(o *pd.ManageIncidentsOptions) {
+		o.Status = "resolved"
+	})
+}
+
+// ReassignIncidents assigns the incidents to the users with the given emails, or to an escalation policy
+func (c *client) ReassignIncidents(incidentIDs []string, userEmails []string, escalationPolicyID string) error {
+	var assignments []pd.Assignee
+	for _, email := range userEmails {
+		userID, err := c.userID(email)
+		if err != nil {
+			return err
+		}
+		assignments = append(assignments, pd.Assignee{Assignee: pd.APIObject{ID: userID, Type: "user_reference"}})
+	}
+
+	return c.manageIncidents(incidentIDs, func(o *pd.ManageIncidentsOptions) {
+		o.Assignments = assignments
+		if escalationPolicyID != "" {
+			o.EscalationPolicy = &pd.APIReference{ID: escalationPolicyID, Type: "escalation_policy_reference"}
+		}
+	})
+}
+
+// userID returns the id of the PagerDuty user with the given email
+func (c *client) userID(email string) (string, error) {
+	luResponse, err := c.pdclient.ListUsersWithContext(context.TODO(), pd.ListUsersOptions{Query: email})
+	if err != nil {
+		return "", fmt.Errorf("failed to ListUsersWithContext: %w", err)
+	}
+	for _, user := range luResponse.Users {
+		if strings.EqualFold(user.Email, email) {
+			return user.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no PagerDuty user found with email %s", email)
+}
+
+func (c *client) AddIncidentNote(incidentID string, content string) error {
+	ctx := context.TODO()
+	from, err := c.from(ctx)
+	if err != nil {
+		return err
+	}
+	// The go-pagerduty client sends the note's user summary as the From header
+	note := pd.IncidentNote{Content: content, User: pd.APIObject{Summary: from}}
+	if _, err := c.pdclient.CreateIncidentNoteWithContext(ctx, incidentID, note); err != nil {
+		return fmt.Errorf("failed to CreateIncidentNoteWithContext: %w", err)
+	}
+	return nil
+}
+
+// MergeIncidents merges the source incidents into the target incident, resolving them
+func (c *client) MergeIncidents(targetIncidentID string, sourceIncidentIDs []string) error {
+	ctx := context.TODO()
+	from, err := c.from(ctx)
+	if err != nil {
+		return err
+	}
+
+	sources := make([]pd.MergeIncidentsOptions, 0, len(sourceIncidentIDs))
+	for _, id := range sourceIncidentIDs {
+		sources = append(sources, pd.MergeIncidentsOptions{ID: id, Type: "incident_reference"})
+	}
+	if _, err := c.pdclient.MergeIncidentsWithContext(ctx, from, targetIncidentID, sources); err != nil {
+		return fmt.Errorf("failed to MergeIncidentsWithContext: %w", err)
+	}
+	return nil
+}
@@ -1,7 +1,9 @@
 package pagerduty
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	pd "github.com/PagerDuty/go-pagerduty"
 	. "github.com/onsi/ginkgo"
@@ -63,6 +65,26 @@ var _ = Describe("Tests the Pagerduty Provider", func() {
 		})
 	})
 
+	Describe("AlertName", func() {
+		It("Strips the parts of the title which differ between occurrences of an alert", func() {
+			titles := map[string]string{
+				"ClusterOperatorDown CRITICAL (1)":                              "ClusterOperatorDown CRITICAL",
+				"[FIRING:2] KubePersistentVolumeFillingUp openshift-monitoring": "KubePersistentVolumeFillingUp openshift-monitoring",
+				"KubeNodeNotReady ip-10-0-1-2.ec2.internal CRITICAL":            "KubeNodeNotReady CRITICAL",
+				"cluster-x.ab12.p1.openshiftapps.com has gone missing":          "has gone missing",
+				"ClusterHasGoneMissing - 2a6b0c8e-8d3f-4b4c-9f7a-6a1c0e2b3d4f":  "ClusterHasGoneMissing",
+				"NodeFilesystemSpaceFillingUp 95% on node 3":                    "NodeFilesystemSpaceFillingUp on node",
+				"(1)": "(1)",
+			}
+			for title, name := range titles {
+				Expect(AlertName(title)).To(Equal(name), title)
+			}
+		})
+		It("Doesn't merge alerts sharing their first word", func() {
+			Expect(AlertName("Cluster provisioning delay")).NotTo(Equal(AlertName("Cluster has gone missing")))
+		})
+	})
+
 	Describe("Provider Functionality", func() {
 		ctrl := gomock.NewController(GinkgoT())
 
@@ -191,5 +213,262 @@ var _ = Describe("Tests the Pagerduty Provider", func() {
 				})
 			})
 		})
+
+		Context("GetIncidents", func() {
+			since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			until := since.Add(7 * 24 * time.Hour)
+
+			It("Queries the client's teams when no service is given", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListIncidentsWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, options pd.ListIncidentsOptions) (*pd.ListIncidentsResponse, error) {
+						Expect(options.TeamIDs).To(Equal([]string{"team"}))
+						Expect(options.ServiceIDs).To(BeEmpty())
+						Expect(options.Since).To(Equal("2026-01-01T00:00:00Z"))
+						Expect(options.Until).To(Equal("2026-01-08T00:00:00Z"))
+						return &pd.ListIncidentsResponse{Incidents: []pd.Incident{generateIncident()}}, nil
+					})
+				pdProvider.WithTeamIdList([]string{"team"}).pdclient = m
+
+				incs, err := pdProvider.GetIncidents(nil, since, until)
+				Expect(err).To(BeNil())
+				Expect(incs).To(HaveLen(1))
+			})
+
+			It("Pages through the incidents and batches the service ids", func() {
+				serviceIDs := make([]string, maxServiceIDsPerRequest+1)
+				for i := range serviceIDs {
+					serviceIDs[i] = fmt.Sprintf("service-%d", i)
+				}
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				gomock.InOrder(
+					m.EXPECT().ListIncidentsWithContext(gomock.Any(), gomock.Any()).Return(&pd.ListIncidentsResponse{APIListObject: pd.APIListObject{More: true}, Incidents: []pd.Incident{generateIncident()}}, nil),
+					m.EXPECT().ListIncidentsWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(_ context.Context, options pd.ListIncidentsOptions) (*pd.ListIncidentsResponse, error) {
+							Expect(options.Offset).To(Equal(uint(100)))
+							Expect(options.ServiceIDs).To(HaveLen(maxServiceIDsPerRequest))
+							return &pd.ListIncidentsResponse{Incidents: []pd.Incident{generateIncident()}}, nil
+						}),
+					m.EXPECT().ListIncidentsWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(_ context.Context, options pd.ListIncidentsOptions) (*pd.ListIncidentsResponse, error) {
+							Expect(options.Offset).To(BeZero())
+							Expect(options.ServiceIDs).To(Equal([]string{serviceIDs[maxServiceIDsPerRequest]}))
+							return &pd.ListIncidentsResponse{Incidents: []pd.Incident{generateIncident()}}, nil
+						}),
+				)
+				pdProvider.pdclient = m
+
+				incs, err := pdProvider.GetIncidents(serviceIDs, since, until)
+				Expect(err).To(BeNil())
+				Expect(incs).To(HaveLen(3))
+			})
+
+			It("Splits windows with more incidents than can be paginated", func() {
+				until := since.Add(48 * time.Hour)
+				var windows [][2]string
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListIncidentsWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, options pd.ListIncidentsOptions) (*pd.ListIncidentsResponse, error) {
+						if options.Offset == 0 {
+							windows = append(windows, [2]string{options.Since, options.Until})
+						}
+						// The whole window has too many incidents, its halves fit in a page. The incident created
+						// at the middle is returned by both halves.
+						more := options.Since == "2026-01-01T00:00:00Z" && options.Until == "2026-01-03T00:00:00Z"
+						incidents := []pd.Incident{{APIObject: pd.APIObject{ID: "middle"}}}
+						if !more {
+							incidents = append(incidents, pd.Incident{APIObject: pd.APIObject{ID: options.Since}})
+						}
+						return &pd.ListIncidentsResponse{APIListObject: pd.APIListObject{More: more}, Incidents: incidents}, nil
+					}).AnyTimes()
+				pdProvider.pdclient = m
+
+				incs, err := pdProvider.GetIncidents([]string{"service"}, since, until)
+				Expect(err).To(BeNil())
+				Expect(windows).To(Equal([][2]string{
+					{"2026-01-01T00:00:00Z", "2026-01-03T00:00:00Z"},
+					{"2026-01-01T00:00:00Z", "2026-01-02T00:00:00Z"},
+					{"2026-01-02T00:00:00Z", "2026-01-03T00:00:00Z"},
+				}))
+				var ids []string
+				for _, inc := range incs {
+					ids = append(ids, inc.ID)
+				}
+				Expect(ids).To(Equal([]string{"middle", "2026-01-01T00:00:00Z", "2026-01-02T00:00:00Z"}))
+			})
+		})
+
+		Context("GetAcknowledgeTimes", func() {
+			logEntry := func(entryType string, incidentID string, createdAt string) pd.LogEntry {
+				return pd.LogEntry{
+					CommonLogEntryField: pd.CommonLogEntryField{APIObject: pd.APIObject{Type: entryType}, CreatedAt: createdAt},
+					Incident:            pd.Incident{APIObject: pd.APIObject{ID: incidentID}},
+				}
+			}
+
+			It("Returns the first acknowledgement of each incident", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListLogEntriesWithContext(gomock.Any(), gomock.Any()).Return(&pd.ListLogEntryResponse{
+					APIListObject: pd.APIListObject{More: true},
+					LogEntries: []pd.LogEntry{
+						logEntry("trigger_log_entry", "A", "2026-01-01T00:00:00Z"),
+						logEntry("acknowledge_log_entry", "A", "2026-01-01T00:20:00Z"),
+					},
+				}, nil)
+				m.EXPECT().ListLogEntriesWithContext(gomock.Any(), gomock.Any()).Return(&pd.ListLogEntryResponse{
+					LogEntries: []pd.LogEntry{
+						logEntry("acknowledge_log_entry", "A", "2026-01-01T00:10:00Z"),
+						logEntry("acknowledge_log_entry", "B", "2026-01-02T00:00:00Z"),
+					},
+				}, nil)
+				pdProvider.pdclient = m
+				pdProvider.teamIds = []string{"TEAM"}
+
+				acks, err := pdProvider.GetAcknowledgeTimes(time.Time{}, time.Now())
+				Expect(err).To(BeNil())
+				Expect(acks).To(HaveLen(2))
+				Expect(acks["A"]).To(Equal(time.Date(2026, 1, 1, 0, 10, 0, 0, time.UTC)))
+				Expect(acks["B"]).To(Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)))
+			})
+
+			It("Refuses to list the log entries of the whole account", func() {
+				_, err := pdProvider.GetAcknowledgeTimes(time.Time{}, time.Now())
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("Splits windows with more log entries than can be paginated", func() {
+				since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+				until := since.Add(48 * time.Hour)
+				var windows [][2]string
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListLogEntriesWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, options pd.ListLogEntriesOptions) (*pd.ListLogEntryResponse, error) {
+						if options.Offset == 0 {
+							windows = append(windows, [2]string{options.Since, options.Until})
+						}
+						// The whole window has too many entries, its halves fit in a page
+						more := options.Since == "2026-01-01T00:00:00Z" && options.Until == "2026-01-03T00:00:00Z"
+						return &pd.ListLogEntryResponse{
+							APIListObject: pd.APIListObject{More: more},
+							LogEntries:    []pd.LogEntry{logEntry("acknowledge_log_entry", options.Since, options.Since)},
+						}, nil
+					}).AnyTimes()
+				pdProvider.pdclient = m
+				pdProvider.teamIds = []string{"TEAM"}
+
+				acks, err := pdProvider.GetAcknowledgeTimes(since, until)
+				Expect(err).To(BeNil())
+				Expect(windows).To(Equal([][2]string{
+					{"2026-01-01T00:00:00Z", "2026-01-03T00:00:00Z"},
+					{"2026-01-01T00:00:00Z", "2026-01-02T00:00:00Z"},
+					{"2026-01-02T00:00:00Z", "2026-01-03T00:00:00Z"},
+				}))
+				Expect(acks).To(HaveKey("2026-01-02T00:00:00Z"))
+			})
+		})
+
+		Context("GetIncidentAcknowledgeTimes", func() {
+			It("Looks up the log entries of every incident", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListIncidentLogEntriesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, id string, _ pd.ListIncidentLogEntriesOptions) (*pd.ListIncidentLogEntriesResponse, error) {
+						if id == "B" {
+							return &pd.ListIncidentLogEntriesResponse{}, nil
+						}
+						return &pd.ListIncidentLogEntriesResponse{LogEntries: []pd.LogEntry{{
+							CommonLogEntryField: pd.CommonLogEntryField{APIObject: pd.APIObject{Type: "acknowledge_log_entry"}, CreatedAt: "2026-01-01T00:10:00Z"},
+							Incident:            pd.Incident{APIObject: pd.APIObject{ID: id}},
+						}}}, nil
+					}).Times(2)
+				pdProvider.pdclient = m
+
+				acks, err := pdProvider.GetIncidentAcknowledgeTimes([]string{"A", "B"})
+				Expect(err).To(BeNil())
+				Expect(acks).To(Equal(map[string]time.Time{"A": time.Date(2026, 1, 1, 0, 10, 0, 0, time.UTC)}))
+			})
+		})
+
+		Context("Incident actions", func() {
+			var m *pdMock.MockpdClientInterface
+
+			BeforeEach(func() {
+				m = pdMock.NewMockpdClientInterface(ctrl)
+				pdProvider.pdclient = m
+			})
+
+			It("Acknowledges incidents as the token's user, looking the user up once", func() {
+				m.EXPECT().GetCurrentUserWithContext(gomock.Any(), gomock.Any()).Return(&pd.User{Email: "me@example.com"}, nil).Times(1)
+				m.EXPECT().ManageIncidentsWithContext(gomock.Any(), "me@example.com", []pd.ManageIncidentsOptions{
+					{ID: "A", Status: "acknowledged"},
+					{ID: "B", Status: "acknowledged"},
+				}).Return(&pd.ListIncidentsResponse{}, nil)
+				m.EXPECT().ManageIncidentsWithContext(gomock.Any(), "me@example.com", []pd.ManageIncidentsOptions{
+					{ID: "A", Status: "resolved"},
+				}).Return(&pd.ListIncidentsResponse{}, nil)
+
+				Expect(pdProvider.AcknowledgeIncidents([]string{"A", "B"})).To(Succeed())
+				Expect(pdProvider.ResolveIncidents([]string{"A"})).To(Succeed())
+			})
+
+			It("Doesn't change incidents when the user can't be looked up", func() {
+				m.EXPECT().GetCurrentUserWithContext(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("unauthorized"))
+
+				err := pdProvider.AcknowledgeIncidents([]string{"A"})
+				Expect(err).To(MatchError(ContainSubstring("unauthorized")))
+			})
+
+			It("Reassigns incidents to users found by email", func() {
+				m.EXPECT().GetCurrentUserWithContext(gomock.Any(), gomock.Any()).Return(&pd.User{Email: "me@example.com"}, nil)
+				m.EXPECT().ListUsersWithContext(gomock.Any(), pd.ListUsersOptions{Query: "oncall@example.com"}).Return(&pd.ListUsersResponse{
+					Users: []pd.User{
+						{APIObject: pd.APIObject{ID: "OTHER"}, Email: "oncall-backup@example.com"},
+						{APIObject: pd.APIObject{ID: "ONCALL"}, Email: "OnCall@example.com"},
+					},
+				}, nil)
+				m.EXPECT().ManageIncidentsWithContext(gomock.Any(), "me@example.com", []pd.ManageIncidentsOptions{{
+					ID:          "A",
+					Assignments: []pd.Assignee{{Assignee: pd.APIObject{ID: "ONCALL", Type: "user_reference"}}},
+				}}).Return(&pd.ListIncidentsResponse{}, nil)
+
+				Expect(pdProvider.ReassignIncidents([]string{"A"}, []string{"oncall@example.com"}, "")).To(Succeed())
+			})
+
+			It("Reassigns incidents to an escalation policy", func() {
+				m.EXPECT().GetCurrentUserWithContext(gomock.Any(), gomock.Any()).Return(&pd.User{Email: "me@example.com"}, nil)
+				m.EXPECT().ManageIncidentsWithContext(gomock.Any(), "me@example.com", []pd.ManageIncidentsOptions{{
+					ID:               "A",
+					EscalationPolicy: &pd.APIReference{ID: "EP", Type: "escalation_policy_reference"},
+				}}).Return(&pd.ListIncidentsResponse{}, nil)
+
+				Expect(pdProvider.ReassignIncidents([]string{"A"}, nil, "EP")).To(Succeed())
+			})
+
+			It("Fails to reassign to an unknown user", func() {
+				m.EXPECT().ListUsersWithContext(gomock.Any(), gomock.Any()).Return(&pd.ListUsersResponse{}, nil)
+
+				err := pdProvider.ReassignIncidents([]string{"A"}, []string{"nobody@example.com"}, "")
+				Expect(err).To(MatchError(ContainSubstring("no PagerDuty user found with email nobody@example.com")))
+			})
+
+			It("Adds notes authored by the token's user", func() {
+				m.EXPECT().GetCurrentUserWithContext(gomock.Any(), gomock.Any()).Return(&pd.User{Email: "me@example.com"}, nil)
+				m.EXPECT().CreateIncidentNoteWithContext(gomock.Any(), "A", pd.IncidentNote{
+					Content: "investigating",
+					User:    pd.APIObject{Summary: "me@example.com"},
+				}).Return(&pd.IncidentNote{}, nil)
+
+				Expect(pdProvider.AddIncidentNote("A", "investigating")).To(Succeed())
+			})
+
+			It("Merges incidents into the target", func() {
+				m.EXPECT().GetCurrentUserWithContext(gomock.Any(), gomock.Any()).Return(&pd.User{Email: "me@example.com"}, nil)
+				m.EXPECT().MergeIncidentsWithContext(gomock.Any(), "me@example.com", "A", []pd.MergeIncidentsOptions{
+					{ID: "B", Type: "incident_reference"},
+					{ID: "C", Type: "incident_reference"},
+				}).Return(&pd.Incident{}, nil)
+
+				Expect(pdProvider.MergeIncidents("A", []string{"B", "C"})).To(Succeed())
+			})
+		})
 	})
 })
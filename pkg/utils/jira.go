@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/andygrunwald/go-jira"
@@ -20,6 +21,13 @@ type JiraClientInterface interface {
 	SearchIssues(jql string) ([]jira.Issue, error)
 	CreateIssue(issue *jira.Issue) (*jira.Issue, error)
 	CreateVersion(version *jira.Version) (*jira.Version, error)
+	AddComment(issueKey string, body string) (*jira.Comment, error)
+	GetTransitions(issueKey string) ([]jira.Transition, error)
+	DoTransition(issueKey string, transitionID string) error
+	AddLink(link *jira.IssueLink) error
+	AddAttachment(issueKey string, name string, content io.Reader) error
+	GetSelf() (*jira.User, error)
+	AssignIssue(issueKey string, user *jira.User) error
 	User() *jira.UserService
 	Issue() *jira.IssueService
 	Board() *jira.BoardService
@@ -56,6 +64,41 @@ func (j *jiraClientWrapper) CreateVersion(version *jira.Version) (*jira.Version,
 	return createdVersion, nil
 }
 
+func (j *jiraClientWrapper) AddComment(issueKey string, body string) (*jira.Comment, error) {
+	comment, _, err := j.client.Issue.AddComment(issueKey, &jira.Comment{Body: body})
+	return comment, err
+}
+
+func (j *jiraClientWrapper) GetTransitions(issueKey string) ([]jira.Transition, error) {
+	transitions, _, err := j.client.Issue.GetTransitions(issueKey)
+	return transitions, err
+}
+
+func (j *jiraClientWrapper) DoTransition(issueKey string, transitionID string) error {
+	_, err := j.client.Issue.DoTransition(issueKey, transitionID)
+	return err
+}
+
+func (j *jiraClientWrapper) AddLink(link *jira.IssueLink) error {
+	_, err := j.client.Issue.AddLink(link)
+	return err
+}
+
+func (j *jiraClientWrapper) AddAttachment(issueKey string, name string, content io.Reader) error {
+	_, _, err := j.client.Issue.PostAttachment(issueKey, content, name)
+	return err
+}
+
+func (j *jiraClientWrapper) GetSelf() (*jira.User, error) {
+	user, _, err := j.client.User.GetSelf()
+	return user, err
+}
+
+func (j *jiraClientWrapper) AssignIssue(issueKey string, user *jira.User) error {
+	_, err := j.client.Issue.UpdateAssignee(issueKey, user)
+	return err
+}
+
 func (j *jiraClientWrapper) User() *jira.UserService {
 	return j.client.User
 }
@@ -10,6 +10,7 @@
 package utils
 
 import (
+	io "io"
 	reflect "reflect"
 
 	jira "github.com/andygrunwald/go-jira"
@@ -40,6 +41,63 @@ func (m *MockJiraClientInterface) EXPECT() *MockJiraClientInterfaceMockRecorder
 	return m.recorder
 }
 
+// AddAttachment mocks base method.
+func (m *MockJiraClientInterface) AddAttachment(issueKey, name string, content io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAttachment", issueKey, name, content)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAttachment indicates an expected call of AddAttachment.
+func (mr *MockJiraClientInterfaceMockRecorder) AddAttachment(issueKey, name, content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAttachment", reflect.TypeOf((*MockJiraClientInterface)(nil).AddAttachment), issueKey, name, content)
+}
+
+// AddComment mocks base method.
+func (m *MockJiraClientInterface) AddComment(issueKey, body string) (*jira.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddComment", issueKey, body)
+	ret0, _ := ret[0].(*jira.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddComment indicates an expected call of AddComment.
+func (mr *MockJiraClientInterfaceMockRecorder) AddComment(issueKey, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockJiraClientInterface)(nil).AddComment), issueKey, body)
+}
+
+// AddLink mocks base method.
+func (m *MockJiraClientInterface) AddLink(link *jira.IssueLink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLink", link)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddLink indicates an expected call of AddLink.
+func (mr *MockJiraClientInterfaceMockRecorder) AddLink(link any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLink", reflect.TypeOf((*MockJiraClientInterface)(nil).AddLink), link)
+}
+
+// AssignIssue mocks base method.
+func (m *MockJiraClientInterface) AssignIssue(issueKey string, user *jira.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignIssue", issueKey, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignIssue indicates an expected call of AssignIssue.
+func (mr *MockJiraClientInterfaceMockRecorder) AssignIssue(issueKey, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignIssue", reflect.TypeOf((*MockJiraClientInterface)(nil).AssignIssue), issueKey, user)
+}
+
 // Board mocks base method.
 func (m *MockJiraClientInterface) Board() *jira.BoardService {
 	m.ctrl.T.Helper()
@@ -84,6 +142,50 @@ func (mr *MockJiraClientInterfaceMockRecorder) CreateVersion(version any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVersion", reflect.TypeOf((*MockJiraClientInterface)(nil).CreateVersion), version)
 }
 
+// DoTransition mocks base method.
+func (m *MockJiraClientInterface) DoTransition(issueKey, transitionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoTransition", issueKey, transitionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DoTransition indicates an expected call of DoTransition.
+func (mr *MockJiraClientInterfaceMockRecorder) DoTransition(issueKey, transitionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoTransition", reflect.TypeOf((*MockJiraClientInterface)(nil).DoTransition), issueKey, transitionID)
+}
+
+// GetSelf mocks base method.
+func (m *MockJiraClientInterface) GetSelf() (*jira.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSelf")
+	ret0, _ := ret[0].(*jira.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSelf indicates an expected call of GetSelf.
+func (mr *MockJiraClientInterfaceMockRecorder) GetSelf() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSelf", reflect.TypeOf((*MockJiraClientInterface)(nil).GetSelf))
+}
+
+// GetTransitions mocks base method.
+func (m *MockJiraClientInterface) GetTransitions(issueKey string) ([]jira.Transition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransitions", issueKey)
+	ret0, _ := ret[0].([]jira.Transition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransitions indicates an expected call of GetTransitions.
+func (mr *MockJiraClientInterfaceMockRecorder) GetTransitions(issueKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransitions", reflect.TypeOf((*MockJiraClientInterface)(nil).GetTransitions), issueKey)
+}
+
 // Issue mocks base method.
 func (m *MockJiraClientInterface) Issue() *jira.IssueService {
 	m.ctrl.T.Helper()
@@ -0,0 +1,83 @@
+package utils
+
+//go:generate mockgen -source=osdctl.go -destination=./mocks/osdctl_mock.go -package=utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+)
+
+// ClusterIDPlaceholder is replaced by the cluster id in osdctl commands run on behalf of the user
+const ClusterIDPlaceholder = "{cluster_id}"
+
+// ExpandClusterID replaces ClusterIDPlaceholder in an osdctl command with the cluster id, which must be set when the command uses it
+func ExpandClusterID(command string, clusterID string) (string, error) {
+	if !strings.Contains(command, ClusterIDPlaceholder) {
+		return command, nil
+	}
+	if clusterID == "" {
+		return "", fmt.Errorf("%s can only be used with --cluster-id", ClusterIDPlaceholder)
+	}
+	return strings.ReplaceAll(command, ClusterIDPlaceholder, clusterID), nil
+}
+
+// SplitOsdctlCommand splits an osdctl command line like a shell would, dropping the leading osdctl
+func SplitOsdctlCommand(command string) ([]string, error) {
+	args, err := shellquote.Split(command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse command %q: %w", command, err)
+	}
+	if len(args) > 0 && args[0] == "osdctl" {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return nil, errors.New("the osdctl command can't be empty")
+	}
+	return args, nil
+}
+
+// OsdctlRunner runs osdctl commands on behalf of the user, like the commands whose output is attached to notes
+type OsdctlRunner interface {
+	Run(ctx context.Context, args []string) (string, error)
+}
+
+type osdctlRunner struct{}
+
+// NewOsdctlRunner returns an OsdctlRunner running the current osdctl binary
+func NewOsdctlRunner() OsdctlRunner {
+	return osdctlRunner{}
+}
+
+func (osdctlRunner) Run(ctx context.Context, args []string) (string, error) {
+	return RunOsdctl(ctx, args)
+}
+
+// RunOsdctl runs the current osdctl binary with the given arguments, returning its combined output.
+// The version check is skipped so that its warnings don't end up in the output.
+func RunOsdctl(ctx context.Context, args []string) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	output, err := exec.CommandContext(ctx, executable, skipVersionCheck(args)...).CombinedOutput() //#nosec G204 -- runs osdctl itself with the user's arguments
+	return string(output), err
+}
+
+// skipVersionCheck adds --skip-version-check to osdctl arguments, before any -- so it stays a flag
+func skipVersionCheck(args []string) []string {
+	for i, arg := range args {
+		if arg == "--" {
+			return append(append(append([]string{}, args[:i]...), "--skip-version-check"), args[i:]...)
+		}
+	}
+	return append(append([]string{}, args...), "--skip-version-check")
+}
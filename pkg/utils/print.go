@@ -3,6 +3,7 @@ package utils
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"strconv"
@@ -87,16 +88,26 @@ func PrintPDAlerts(incidents map[string][]pd.Incident, serviceIDs []string) {
 }
 
 func PrintJiraIssues(issues []jira.Issue) {
+	FprintJiraIssues(os.Stdout, issues)
+}
+
+// FprintJiraIssues writes the issues to w in Jira markup, as pasted in handovers
+func FprintJiraIssues(w io.Writer, issues []jira.Issue) {
 	var name = "OHSS Issues"
-	fmt.Println(delimiter + name)
+	fmt.Fprintln(w, delimiter+name)
 
-	for _, i := range issues {
-		fmt.Printf("[%s|%s/browse/%s](%s/%s): %+v\n", i.Key, JiraBaseURL, i.Key, i.Fields.Type.Name, i.Fields.Priority.Name, i.Fields.Summary)
-		fmt.Printf("- Created: %s\tStatus: %s\n", time.Time(i.Fields.Created).Format("2006-01-02 15:04"), i.Fields.Status.Name)
-	}
+	FprintJiraIssueLines(w, issues)
 
 	if len(issues) == 0 {
-		fmt.Println("None")
+		fmt.Fprintln(w, "None")
+	}
+}
+
+// FprintJiraIssueLines prints the issues like FprintJiraIssues, without its header
+func FprintJiraIssueLines(w io.Writer, issues []jira.Issue) {
+	for _, i := range issues {
+		fmt.Fprintf(w, "[%s|%s/browse/%s](%s/%s): %+v\n", i.Key, JiraBaseURL, i.Key, i.Fields.Type.Name, i.Fields.Priority.Name, i.Fields.Summary)
+		fmt.Fprintf(w, "- Created: %s\tStatus: %s\n", time.Time(i.Fields.Created).Format("2006-01-02 15:04"), i.Fields.Status.Name)
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"os"
 	"regexp"
 	"runtime/debug"
 	"strings"
@@ -278,10 +279,15 @@ func GetRegistryCredentials(connection *sdk.Connection, accountId string) ([]*am
 }
 
 func ConfirmPrompt() bool {
-	fmt.Print("Continue? (y/N): ")
+	return StreamConfirmPrompt(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+}
+
+// StreamConfirmPrompt asks for confirmation using the provided IOStreams
+func StreamConfirmPrompt(stream genericclioptions.IOStreams) bool {
+	StreamPrint(stream, "Continue? (y/N): ")
 
 	var response = "n"
-	_, _ = fmt.Scanln(&response) // Erroneous input will be handled by the default case below
+	_, _ = fmt.Fscanln(stream.In, &response) // Erroneous input will be handled by the default case below
 
 	switch strings.ToLower(response) {
 	case "y", "yes":
@@ -289,8 +295,8 @@ func ConfirmPrompt() bool {
 	case "n", "no":
 		return false
 	default:
-		fmt.Println("Invalid input. Expecting (y)es or (N)o")
-		return ConfirmPrompt()
+		StreamPrintln(stream, "Invalid input. Expecting (y)es or (N)o")
+		return StreamConfirmPrompt(stream)
 	}
 }
 
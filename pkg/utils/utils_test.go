@@ -1,11 +1,14 @@
 package utils
 
 import (
+	"bytes"
+	"reflect"
 	"runtime/debug"
 	"strings"
 	"testing"
 
 	"github.com/andygrunwald/go-jira"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
 func mockReadBuildInfo(parseBuildInfoError bool) func() (info *debug.BuildInfo, ok bool) {
@@ -168,3 +171,64 @@ func TestIsValidMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitOsdctlCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{command: "cluster health -C abc", want: []string{"cluster", "health", "-C", "abc"}},
+		{command: "osdctl cluster context -C 'abc def'", want: []string{"cluster", "context", "-C", "abc def"}},
+		{command: "osdctl", wantErr: true},
+		{command: "", wantErr: true},
+		{command: "cluster 'unterminated", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := SplitOsdctlCommand(tt.command)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SplitOsdctlCommand(%q) error = %v, wantErr %v", tt.command, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitOsdctlCommand(%q) = %q; want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestSkipVersionCheck(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{args: []string{"cluster", "health", "-C", "abc"}, want: []string{"cluster", "health", "-C", "abc", "--skip-version-check"}},
+		{args: []string{"cluster", "context", "--", "-abc"}, want: []string{"cluster", "context", "--skip-version-check", "--", "-abc"}},
+	}
+
+	for _, tt := range tests {
+		if got := skipVersionCheck(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("skipVersionCheck(%q) = %q; want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestStreamConfirmPrompt(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "y\n", want: true},
+		{input: "YES\n", want: true},
+		{input: "n\n", want: false},
+		{input: "", want: false},
+		{input: "maybe\nyes\n", want: true},
+	}
+
+	for _, tt := range tests {
+		out := &bytes.Buffer{}
+		if got := StreamConfirmPrompt(genericclioptions.IOStreams{In: strings.NewReader(tt.input), Out: out, ErrOut: out}); got != tt.want {
+			t.Errorf("StreamConfirmPrompt(%q) = %v; want %v, output %q", tt.input, got, tt.want, out.String())
+		}
+	}
+}
@@ -2,14 +2,28 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/coreos/go-semver/semver"
 )
 
 const (
 	VersionAPIEndpoint     = "https://api.github.com/repos/openshift/osdctl/releases/latest"
+	ReleasesAPIEndpoint    = "https://api.github.com/repos/openshift/osdctl/releases"
 	VersionAddressTemplate = "https://github.com/openshift/osdctl/releases/download/v%s/osdctl_%s_%s_%s.tar.gz" // version, version, GOOS, GOARCH
+	ReleaseAssetTemplate   = "https://github.com/openshift/osdctl/releases/download/v%s/%s"                     // version, asset name
+	ChecksumsAssetName     = "sha256sum.txt"
+
+	// versionRetryInterval is how long a failure to get the latest version from GitHub is cached, when
+	// shorter than the cache TTL
+	versionRetryInterval = time.Hour
 )
 
 var (
@@ -27,7 +41,9 @@ var (
 // githubResponse is a necessary struct for the JSON unmarshalling that is happening
 // in the getLatestVersion().
 type gitHubResponse struct {
-	TagName string `json:"tag_name"`
+	TagName    string `json:"tag_name"`
+	Prerelease bool   `json:"prerelease"`
+	Draft      bool   `json:"draft"`
 }
 
 // getLatestVersion connects to the GitHub API and returns the latest osdctl tag name
@@ -47,6 +63,11 @@ func GetLatestVersion() (latest string, err error) {
 	if err != nil {
 		return latest, err
 	}
+	defer res.Body.Close()
+	// GitHub answers 403 when rate limiting
+	if res.StatusCode != http.StatusOK {
+		return latest, fmt.Errorf("failed to get the latest osdctl release: %s", res.Status)
+	}
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
@@ -61,3 +82,118 @@ func GetLatestVersion() (latest string, err error) {
 
 	return githubResp.TagName, nil
 }
+
+// versionCache is the latest osdctl version stored by GetLatestVersionCached
+type versionCache struct {
+	Latest    string    `json:"latest"`
+	CheckedAt time.Time `json:"checkedAt"`
+	// Error is set when GitHub couldn't be asked at CheckedAt, Latest is then the last known latest version
+	Error string `json:"error,omitempty"`
+}
+
+// GetLatestVersionCached returns the latest osdctl tag name, asking GitHub at most once per ttl.
+// When GitHub can't be reached, the last known latest version is returned with the error, and GitHub
+// isn't asked again for an hour, or ttl when shorter.
+func GetLatestVersionCached(ttl time.Duration) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return GetLatestVersion()
+	}
+	return getLatestVersionCached(filepath.Join(cacheDir, "osdctl", "latest-version.json"), ttl, time.Now(), GetLatestVersion)
+}
+
+func getLatestVersionCached(cachePath string, ttl time.Duration, now time.Time, fetch func() (string, error)) (string, error) {
+	cache := readVersionCache(cachePath)
+	if cache != nil {
+		age := now.Sub(cache.CheckedAt)
+		if cache.Error == "" && age < ttl {
+			return cache.Latest, nil
+		}
+		if cache.Error != "" && age < min(ttl, versionRetryInterval) {
+			return cache.Latest, errors.New(cache.Error)
+		}
+	}
+
+	latest, err := fetch()
+	if err == nil && latest == "" {
+		err = fmt.Errorf("no latest osdctl release found")
+	}
+	if err != nil {
+		known := ""
+		if cache != nil {
+			known = cache.Latest
+		}
+		_ = writeVersionCache(cachePath, versionCache{Latest: known, CheckedAt: now, Error: err.Error()})
+		return known, err
+	}
+
+	// The cache is best effort, failing to write it only means asking GitHub again next time
+	_ = writeVersionCache(cachePath, versionCache{Latest: latest, CheckedAt: now})
+	return latest, nil
+}
+
+func readVersionCache(cachePath string) *versionCache {
+	content, err := os.ReadFile(cachePath) //#nosec G304 -- cachePath is under the user cache dir
+	if err != nil {
+		return nil
+	}
+	cache := &versionCache{}
+	if err := json.Unmarshal(content, cache); err != nil || (cache.Latest == "" && cache.Error == "") {
+		return nil
+	}
+	return cache
+}
+
+func writeVersionCache(cachePath string, cache versionCache) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0750); err != nil {
+		return err
+	}
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cachePath, content, 0600)
+}
+
+// GetLatestPrerelease returns the highest osdctl tag name including pre-releases like *-rc.1
+func GetLatestPrerelease() (latest string, err error) {
+	client := http.Client{
+		Timeout: time.Second * 10,
+	}
+
+	res, err := client.Get(ReleasesAPIEndpoint)
+	if err != nil {
+		return latest, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return latest, fmt.Errorf("failed to list osdctl releases: %s", res.Status)
+	}
+
+	releases := []gitHubResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&releases); err != nil {
+		return latest, err
+	}
+	return latestRelease(releases)
+}
+
+func latestRelease(releases []gitHubResponse) (string, error) {
+	var latestSemVer *semver.Version
+	latest := ""
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+		v, err := semver.NewVersion(strings.TrimPrefix(release.TagName, "v"))
+		if err != nil {
+			continue
+		}
+		if latestSemVer == nil || latestSemVer.LessThan(*v) {
+			latestSemVer, latest = v, release.TagName
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no osdctl release found")
+	}
+	return latest, nil
+}
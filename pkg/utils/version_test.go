@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatestRelease(t *testing.T) {
+	latest, err := latestRelease([]gitHubResponse{
+		{TagName: "v0.40.0"},
+		{TagName: "v0.42.0-rc.1", Prerelease: true},
+		{TagName: "v0.43.0", Draft: true},
+		{TagName: "v0.41.0"},
+		{TagName: "nightly"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.42.0-rc.1", latest)
+
+	_, err = latestRelease(nil)
+	assert.EqualError(t, err, "no osdctl release found")
+}
+
+func TestGetLatestVersionCached(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "osdctl", "latest-version.json")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fetches := 0
+	fetch := func(latest string, err error) func() (string, error) {
+		return func() (string, error) {
+			fetches++
+			return latest, err
+		}
+	}
+
+	// Nothing cached and GitHub unreachable, the failure is cached for the retry interval
+	_, err := getLatestVersionCached(cachePath, 24*time.Hour, now, fetch("", errors.New("offline")))
+	assert.EqualError(t, err, "offline")
+	_, err = getLatestVersionCached(cachePath, 24*time.Hour, now.Add(30*time.Minute), fetch("v0.40.0", nil))
+	assert.EqualError(t, err, "offline")
+	assert.Equal(t, 1, fetches)
+
+	latest, err := getLatestVersionCached(cachePath, 24*time.Hour, now.Add(time.Hour), fetch("v0.40.0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.40.0", latest)
+
+	// Cached within the TTL
+	latest, err = getLatestVersionCached(cachePath, 24*time.Hour, now.Add(12*time.Hour), fetch("v0.41.0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.40.0", latest)
+	assert.Equal(t, 2, fetches)
+
+	// Expired and GitHub unreachable or rate limited, the stale version is returned with the error,
+	// without asking GitHub again until the retry interval passed
+	for _, at := range []time.Duration{26 * time.Hour, 26*time.Hour + 59*time.Minute} {
+		latest, err = getLatestVersionCached(cachePath, 24*time.Hour, now.Add(at), fetch("", nil))
+		assert.EqualError(t, err, "no latest osdctl release found")
+		assert.Equal(t, "v0.40.0", latest)
+	}
+	assert.Equal(t, 3, fetches)
+
+	// Retried once the retry interval passed
+	latest, err = getLatestVersionCached(cachePath, 24*time.Hour, now.Add(27*time.Hour), fetch("v0.41.0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.41.0", latest)
+
+	// A TTL shorter than the retry interval also applies to failures
+	_, err = getLatestVersionCached(cachePath, time.Minute, now.Add(28*time.Hour), fetch("", errors.New("offline")))
+	assert.EqualError(t, err, "offline")
+	latest, err = getLatestVersionCached(cachePath, time.Minute, now.Add(28*time.Hour+time.Minute), fetch("v0.41.0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.41.0", latest)
+
+	// A zero TTL disables the cache
+	_, err = getLatestVersionCached(cachePath, 0, now.Add(28*time.Hour+time.Minute), fetch("v0.41.0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 7, fetches)
+}